@@ -78,6 +78,16 @@ func (s Supervisor) Validate() error {
 		return err
 	}
 
+	if s.Capabilities.AcceptsPackages {
+		if s.Agent.Packages.PublicKeyFile == "" {
+			return errors.New("agent::packages::public_key_file must be specified when capabilities::accepts_packages is enabled")
+		}
+
+		if s.Agent.Packages.HealthTimeout <= 0 {
+			return errors.New("agent::packages::health_timeout must be positive")
+		}
+	}
+
 	return nil
 }
 
@@ -95,6 +105,7 @@ type Capabilities struct {
 	ReportsOwnMetrics              bool `mapstructure:"reports_own_metrics"`
 	ReportsHealth                  bool `mapstructure:"reports_health"`
 	ReportsRemoteConfig            bool `mapstructure:"reports_remote_config"`
+	AcceptsPackages                bool `mapstructure:"accepts_packages"`
 }
 
 func (c Capabilities) SupportedCapabilities() protobufs.AgentCapabilities {
@@ -128,6 +139,11 @@ func (c Capabilities) SupportedCapabilities() protobufs.AgentCapabilities {
 		supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsOpAMPConnectionSettings
 	}
 
+	if c.AcceptsPackages {
+		supportedCapabilities |= protobufs.AgentCapabilities_AgentCapabilities_AcceptsPackages |
+			protobufs.AgentCapabilities_AgentCapabilities_ReportsPackageStatuses
+	}
+
 	return supportedCapabilities
 }
 
@@ -170,6 +186,8 @@ type Agent struct {
 	HealthCheckPort         int              `mapstructure:"health_check_port"`
 	OpAMPServerPort         int              `mapstructure:"opamp_server_port"`
 	PassthroughLogs         bool             `mapstructure:"passthrough_logs"`
+	ConfigRollback          ConfigRollback   `mapstructure:"config_rollback"`
+	Packages                AgentPackages    `mapstructure:"packages"`
 }
 
 func (a Agent) Validate() error {
@@ -202,9 +220,34 @@ func (a Agent) Validate() error {
 		return errors.New("agent::config_apply_timeout must be valid duration")
 	}
 
+	if a.ConfigRollback.GracePeriod < 0 {
+		return errors.New("agent::config_rollback::grace_period must not be negative")
+	}
+
 	return nil
 }
 
+// AgentPackages configures how the agent executable is updated from the packages
+// offered by the OpAMP server.
+type AgentPackages struct {
+	// PublicKeyFile is the path to the PEM encoded ECDSA or RSA public key the
+	// signatures of the offered packages are verified with.
+	PublicKeyFile string `mapstructure:"public_key_file"`
+	// HealthTimeout is the time the agent has to report healthy after being restarted
+	// with a new executable. Past it, the previous executable is restored.
+	HealthTimeout time.Duration `mapstructure:"health_timeout"`
+}
+
+// ConfigRollback configures the rollback to the last known good remote config
+// when a new remote config fails to apply.
+type ConfigRollback struct {
+	Enabled bool `mapstructure:"enabled"`
+	// GracePeriod is the time after a remote config has been applied during which
+	// the agent becoming unhealthy or exiting rolls the config back. Once it passes,
+	// the config becomes the last known good one.
+	GracePeriod time.Duration `mapstructure:"grace_period"`
+}
+
 type AgentDescription struct {
 	IdentifyingAttributes    map[string]string `mapstructure:"identifying_attributes"`
 	NonIdentifyingAttributes map[string]string `mapstructure:"non_identifying_attributes"`
@@ -241,6 +284,7 @@ func DefaultSupervisor() Supervisor {
 			AcceptsRemoteConfig:            false,
 			AcceptsRestartCommand:          false,
 			AcceptsOpAMPConnectionSettings: false,
+			AcceptsPackages:                false,
 			ReportsEffectiveConfig:         true,
 			ReportsOwnMetrics:              true,
 			ReportsHealth:                  true,
@@ -254,6 +298,13 @@ func DefaultSupervisor() Supervisor {
 			ConfigApplyTimeout:      5 * time.Second,
 			BootstrapTimeout:        3 * time.Second,
 			PassthroughLogs:         false,
+			ConfigRollback: ConfigRollback{
+				Enabled:     false,
+				GracePeriod: 30 * time.Second,
+			},
+			Packages: AgentPackages{
+				HealthTimeout: 30 * time.Second,
+			},
 		},
 		Telemetry: Telemetry{
 			Logs: Logs{
@@ -422,6 +422,91 @@ func TestValidate(t *testing.T) {
 			},
 			expectedError: "agent::config_apply_timeout must be valid duration",
 		},
+		{
+			name: "Invalid config rollback grace period",
+			config: Supervisor{
+				Server: OpAMPServer{
+					Endpoint: "wss://localhost:9090/opamp",
+					Headers: http.Header{
+						"Header1": []string{"HeaderValue"},
+					},
+					TLSSetting: configtls.ClientConfig{
+						Insecure: true,
+					},
+				},
+				Agent: Agent{
+					Executable:              "${file_path}",
+					OrphanDetectionInterval: 5 * time.Second,
+					ConfigApplyTimeout:      2 * time.Second,
+					BootstrapTimeout:        5 * time.Second,
+					ConfigRollback: ConfigRollback{
+						Enabled:     true,
+						GracePeriod: -1 * time.Second,
+					},
+				},
+				Capabilities: Capabilities{
+					AcceptsRemoteConfig: true,
+				},
+				Storage: Storage{
+					Directory: "/etc/opamp-supervisor/storage",
+				},
+			},
+			expectedError: "agent::config_rollback::grace_period must not be negative",
+		},
+		{
+			name: "Accepts packages without public key file",
+			config: Supervisor{
+				Server: OpAMPServer{
+					Endpoint: "wss://localhost:9090/opamp",
+					TLSSetting: configtls.ClientConfig{
+						Insecure: true,
+					},
+				},
+				Agent: Agent{
+					Executable:              "${file_path}",
+					OrphanDetectionInterval: 5 * time.Second,
+					ConfigApplyTimeout:      2 * time.Second,
+					BootstrapTimeout:        5 * time.Second,
+					Packages: AgentPackages{
+						HealthTimeout: 30 * time.Second,
+					},
+				},
+				Capabilities: Capabilities{
+					AcceptsPackages: true,
+				},
+				Storage: Storage{
+					Directory: "/etc/opamp-supervisor/storage",
+				},
+			},
+			expectedError: "agent::packages::public_key_file must be specified when capabilities::accepts_packages is enabled",
+		},
+		{
+			name: "Invalid packages health timeout",
+			config: Supervisor{
+				Server: OpAMPServer{
+					Endpoint: "wss://localhost:9090/opamp",
+					TLSSetting: configtls.ClientConfig{
+						Insecure: true,
+					},
+				},
+				Agent: Agent{
+					Executable:              "${file_path}",
+					OrphanDetectionInterval: 5 * time.Second,
+					ConfigApplyTimeout:      2 * time.Second,
+					BootstrapTimeout:        5 * time.Second,
+					Packages: AgentPackages{
+						PublicKeyFile: "/etc/opamp-supervisor/packages.pem",
+					},
+				},
+				Capabilities: Capabilities{
+					AcceptsPackages: true,
+				},
+				Storage: Storage{
+					Directory: "/etc/opamp-supervisor/storage",
+				},
+			},
+			expectedError: "agent::packages::health_timeout must be positive",
+		},
 	}
 
 	// create some fake files for validating agent config
@@ -540,6 +625,8 @@ agent:
 						OrphanDetectionInterval: DefaultSupervisor().Agent.OrphanDetectionInterval,
 						ConfigApplyTimeout:      DefaultSupervisor().Agent.ConfigApplyTimeout,
 						BootstrapTimeout:        DefaultSupervisor().Agent.BootstrapTimeout,
+						ConfigRollback:          DefaultSupervisor().Agent.ConfigRollback,
+						Packages:                DefaultSupervisor().Agent.Packages,
 					},
 					Telemetry: DefaultSupervisor().Telemetry,
 				}
@@ -565,6 +652,7 @@ capabilities:
   reports_remote_config: true
   accepts_restart_command: true
   accepts_opamp_connection_settings: true
+  accepts_packages: true
 
 storage:
   directory: %s
@@ -582,6 +670,12 @@ agent:
   health_check_port: 8089
   opamp_server_port: 8090
   passthrough_logs: true
+  config_rollback:
+    enabled: true
+    grace_period: 1m
+  packages:
+    public_key_file: /etc/opamp-supervisor/packages.pem
+    health_timeout: 10s
 
 telemetry:
   logs:
@@ -605,6 +699,7 @@ telemetry:
 						ReportsRemoteConfig:            true,
 						AcceptsRestartCommand:          true,
 						AcceptsOpAMPConnectionSettings: true,
+						AcceptsPackages:                true,
 					},
 					Storage: Storage{
 						Directory: filepath.Join(tmpDir, "storage"),
@@ -625,6 +720,14 @@ telemetry:
 						HealthCheckPort:         8089,
 						OpAMPServerPort:         8090,
 						PassthroughLogs:         true,
+						ConfigRollback: ConfigRollback{
+							Enabled:     true,
+							GracePeriod: time.Minute,
+						},
+						Packages: AgentPackages{
+							PublicKeyFile: "/etc/opamp-supervisor/packages.pem",
+							HealthTimeout: 10 * time.Second,
+						},
 					},
 					Telemetry: Telemetry{
 						Logs: Logs{
@@ -659,6 +762,8 @@ agent:
 						OrphanDetectionInterval: DefaultSupervisor().Agent.OrphanDetectionInterval,
 						ConfigApplyTimeout:      DefaultSupervisor().Agent.ConfigApplyTimeout,
 						BootstrapTimeout:        DefaultSupervisor().Agent.BootstrapTimeout,
+						ConfigRollback:          DefaultSupervisor().Agent.ConfigRollback,
+						Packages:                DefaultSupervisor().Agent.Packages,
 					},
 					Telemetry: DefaultSupervisor().Telemetry,
 				}
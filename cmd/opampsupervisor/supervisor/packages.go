@@ -0,0 +1,402 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	packagesStateFileName               = "packages_state.yaml"
+	lastReportedPackageStatusesFileName = "last_reported_package_statuses.dat"
+
+	// agentPackageName is the name of the top-level package, which is the agent executable.
+	agentPackageName = ""
+)
+
+var _ types.PackagesStateProvider = (*packageManager)(nil)
+
+// packageState is the persisted state of a package.
+type packageState struct {
+	Type    protobufs.PackageType `yaml:"type"`
+	Hash    string                `yaml:"hash"`
+	Version string                `yaml:"version"`
+}
+
+// packagesState is the persisted state of all the packages.
+type packagesState struct {
+	AllPackagesHash string                  `yaml:"all_packages_hash"`
+	Packages        map[string]packageState `yaml:"packages"`
+	// BadContentHashes are the content hashes of the executables the agent was not healthy with.
+	BadContentHashes []string `yaml:"bad_content_hashes,omitempty"`
+}
+
+// packageManager keeps the local state of the packages offered by the OpAMP server.
+// The only supported package is the top-level one, the agent executable: a new
+// executable is verified, swapped in place of the current one and kept only if the
+// agent becomes healthy with it.
+type packageManager struct {
+	logger       *zap.Logger
+	storageDir   string
+	agentExePath string
+	publicKey    crypto.PublicKey
+	// restartAgent restarts the agent and returns an error if it does not become healthy.
+	restartAgent func(ctx context.Context) error
+
+	mux   sync.Mutex
+	state *packagesState
+}
+
+func newPackageManager(
+	logger *zap.Logger,
+	storageDir string,
+	agentExePath string,
+	publicKeyFile string,
+	restartAgent func(ctx context.Context) error,
+) (*packageManager, error) {
+	publicKey, err := loadPublicKey(publicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &packageManager{
+		logger:       logger,
+		storageDir:   storageDir,
+		agentExePath: agentExePath,
+		publicKey:    publicKey,
+		restartAgent: restartAgent,
+		state:        &packagesState{Packages: map[string]packageState{}},
+	}
+
+	by, err := os.ReadFile(p.stateFilePath())
+	switch {
+	case err == nil:
+		if err = yaml.Unmarshal(by, p.state); err != nil {
+			return nil, fmt.Errorf("cannot parse packages state: %w", err)
+		}
+		if p.state.Packages == nil {
+			p.state.Packages = map[string]packageState{}
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("cannot read packages state: %w", err)
+	}
+
+	return p, nil
+}
+
+// loadPublicKey loads the PEM encoded ECDSA or RSA public key package signatures are verified with.
+func loadPublicKey(file string) (crypto.PublicKey, error) {
+	by, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read packages public key: %w", err)
+	}
+
+	block, _ := pem.Decode(by)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in packages public key file %s", file)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse packages public key: %w", err)
+	}
+
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		return publicKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported packages public key type %T, must be ECDSA or RSA", publicKey)
+	}
+}
+
+func (p *packageManager) AllPackagesHash() ([]byte, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return hex.DecodeString(p.state.AllPackagesHash)
+}
+
+func (p *packageManager) SetAllPackagesHash(hash []byte) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.state.AllPackagesHash = hex.EncodeToString(hash)
+	return p.writeState()
+}
+
+func (p *packageManager) Packages() ([]string, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	names := make([]string, 0, len(p.state.Packages))
+	for name := range p.state.Packages {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (p *packageManager) PackageState(packageName string) (types.PackageState, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	state, ok := p.state.Packages[packageName]
+	if !ok {
+		return types.PackageState{Exists: false}, nil
+	}
+
+	hash, err := hex.DecodeString(state.Hash)
+	if err != nil {
+		return types.PackageState{}, fmt.Errorf("invalid hash for package %q: %w", packageName, err)
+	}
+
+	return types.PackageState{
+		Exists:  true,
+		Type:    state.Type,
+		Hash:    hash,
+		Version: state.Version,
+	}, nil
+}
+
+func (p *packageManager) SetPackageState(packageName string, state types.PackageState) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	current, ok := p.state.Packages[packageName]
+	if !ok {
+		return fmt.Errorf("package %q does not exist", packageName)
+	}
+	if current.Type != state.Type {
+		return fmt.Errorf("package %q is of type %s, cannot set the state of a %s package", packageName, current.Type, state.Type)
+	}
+
+	p.state.Packages[packageName] = packageState{
+		Type:    state.Type,
+		Hash:    hex.EncodeToString(state.Hash),
+		Version: state.Version,
+	}
+	return p.writeState()
+}
+
+func (p *packageManager) CreatePackage(packageName string, typ protobufs.PackageType) error {
+	if packageName != agentPackageName || typ != protobufs.PackageType_PackageType_TopLevel {
+		return fmt.Errorf("only the top-level agent package is supported, got %s package %q", typ, packageName)
+	}
+
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	if _, ok := p.state.Packages[packageName]; ok {
+		return fmt.Errorf("package %q already exists", packageName)
+	}
+
+	p.state.Packages[packageName] = packageState{Type: typ}
+	return p.writeState()
+}
+
+func (p *packageManager) FileContentHash(packageName string) ([]byte, error) {
+	if packageName != agentPackageName {
+		return nil, nil
+	}
+
+	f, err := os.Open(p.agentExePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// UpdateContent stages the new agent executable next to the current one, verifies its
+// content hash and signature, then swaps it in and restarts the agent. The previous
+// executable is restored if the agent does not become healthy with the new one.
+func (p *packageManager) UpdateContent(ctx context.Context, packageName string, data io.Reader, contentHash, signature []byte) error {
+	if packageName != agentPackageName {
+		return fmt.Errorf("only the top-level agent package is supported, got package %q", packageName)
+	}
+
+	if p.isBad(contentHash) {
+		return fmt.Errorf("agent executable %x was not healthy before, not installing it again", contentHash)
+	}
+
+	stagedPath := p.agentExePath + ".staged"
+	if err := p.stage(stagedPath, data, contentHash, signature); err != nil {
+		_ = os.Remove(stagedPath)
+		return err
+	}
+
+	backupPath := p.agentExePath + ".bak"
+	if err := os.Remove(backupPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		_ = os.Remove(stagedPath)
+		return fmt.Errorf("cannot remove the previous agent executable backup: %w", err)
+	}
+	if err := os.Link(p.agentExePath, backupPath); err != nil {
+		_ = os.Remove(stagedPath)
+		return fmt.Errorf("cannot back up the agent executable: %w", err)
+	}
+	if err := os.Rename(stagedPath, p.agentExePath); err != nil {
+		_ = os.Remove(stagedPath)
+		return fmt.Errorf("cannot replace the agent executable: %w", err)
+	}
+
+	p.logger.Info("Restarting the agent with the new executable")
+	if err := p.restartAgent(ctx); err != nil {
+		p.logger.Error("Agent is not healthy with the new executable, restoring the previous one", zap.Error(err))
+		if markErr := p.markBad(contentHash); markErr != nil {
+			p.logger.Error("Could not mark the agent executable as bad", zap.Error(markErr))
+		}
+		if restoreErr := os.Rename(backupPath, p.agentExePath); restoreErr != nil {
+			return fmt.Errorf("agent is not healthy with the new executable: %w, and the previous executable cannot be restored: %w", err, restoreErr)
+		}
+		if restartErr := p.restartAgent(ctx); restartErr != nil {
+			p.logger.Error("Agent is not healthy with the previous executable", zap.Error(restartErr))
+		}
+		return fmt.Errorf("agent is not healthy with the new executable, the previous executable was restored: %w", err)
+	}
+
+	if err := os.Remove(backupPath); err != nil {
+		p.logger.Warn("Could not remove the previous agent executable", zap.Error(err))
+	}
+	return nil
+}
+
+func (p *packageManager) isBad(contentHash []byte) bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	return slices.Contains(p.state.BadContentHashes, hex.EncodeToString(contentHash))
+}
+
+func (p *packageManager) markBad(contentHash []byte) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	p.state.BadContentHashes = append(p.state.BadContentHashes, hex.EncodeToString(contentHash))
+	return p.writeState()
+}
+
+// stage writes the new agent executable to path and verifies it.
+func (p *packageManager) stage(path string, data io.Reader, contentHash, signature []byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o700)
+	if err != nil {
+		return fmt.Errorf("cannot create the staged agent executable: %w", err)
+	}
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write the staged agent executable: %w", err)
+	}
+
+	digest := h.Sum(nil)
+	if !bytes.Equal(digest, contentHash) {
+		return fmt.Errorf("content hash mismatch: expected %x, got %x", contentHash, digest)
+	}
+
+	return p.verifySignature(digest, signature)
+}
+
+// verifySignature verifies the signature of the SHA-256 digest of the package content.
+// The signature must be standard base64 encoded, as produced by `cosign sign-blob`.
+func (p *packageManager) verifySignature(digest, encodedSignature []byte) error {
+	if len(encodedSignature) == 0 {
+		return errors.New("package is not signed")
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(string(encodedSignature))
+	if err != nil {
+		return fmt.Errorf("package signature is not base64 encoded: %w", err)
+	}
+
+	switch publicKey := p.publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(publicKey, digest, signature) {
+			return errors.New("invalid package signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest, signature); err != nil {
+			return fmt.Errorf("invalid package signature: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *packageManager) DeletePackage(packageName string) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+
+	// The agent executable itself is kept, only the package state is removed.
+	delete(p.state.Packages, packageName)
+	return p.writeState()
+}
+
+func (p *packageManager) LastReportedStatuses() (*protobufs.PackageStatuses, error) {
+	by, err := os.ReadFile(filepath.Join(p.storageDir, lastReportedPackageStatusesFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := &protobufs.PackageStatuses{}
+	if err = proto.Unmarshal(by, statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func (p *packageManager) SetLastReportedStatuses(statuses *protobufs.PackageStatuses) error {
+	by, err := proto.Marshal(statuses)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(p.storageDir, lastReportedPackageStatusesFileName), by, 0o600)
+}
+
+func (p *packageManager) writeState() error {
+	by, err := yaml.Marshal(p.state)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(p.stateFilePath(), by, 0o600)
+}
+
+func (p *packageManager) stateFilePath() string {
+	return filepath.Join(p.storageDir, packagesStateFileName)
+}
@@ -0,0 +1,246 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+func writePublicKey(t *testing.T, dir string, publicKey any) string {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "packages.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+	return path
+}
+
+func newTestPackageManager(t *testing.T, restartAgent func(ctx context.Context) error) (*packageManager, *ecdsa.PrivateKey) {
+	dir := t.TempDir()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	exePath := filepath.Join(dir, "otelcol")
+	require.NoError(t, os.WriteFile(exePath, []byte("old executable"), 0o700))
+
+	p, err := newPackageManager(zap.NewNop(), dir, exePath, writePublicKey(t, dir, &privateKey.PublicKey), restartAgent)
+	require.NoError(t, err)
+	return p, privateKey
+}
+
+func signContent(t *testing.T, privateKey *ecdsa.PrivateKey, content []byte) (hash, signature []byte) {
+	digest := sha256.Sum256(content)
+	sig, err := ecdsa.SignASN1(rand.Reader, privateKey, digest[:])
+	require.NoError(t, err)
+	return digest[:], []byte(base64.StdEncoding.EncodeToString(sig))
+}
+
+func TestPackageManager_UpdateContent(t *testing.T) {
+	newContent := []byte("new executable")
+
+	t.Run("Installs the new executable", func(t *testing.T) {
+		restarts := 0
+		p, privateKey := newTestPackageManager(t, func(_ context.Context) error {
+			restarts++
+			return nil
+		})
+		hash, signature := signContent(t, privateKey, newContent)
+
+		require.NoError(t, p.UpdateContent(context.Background(), agentPackageName, bytes.NewReader(newContent), hash, signature))
+
+		got, err := os.ReadFile(p.agentExePath)
+		require.NoError(t, err)
+		assert.Equal(t, newContent, got)
+		assert.NoFileExists(t, p.agentExePath+".bak")
+		assert.NoFileExists(t, p.agentExePath+".staged")
+		assert.Equal(t, 1, restarts)
+
+		fileHash, err := p.FileContentHash(agentPackageName)
+		require.NoError(t, err)
+		assert.Equal(t, hash, fileHash)
+	})
+
+	t.Run("Restores the previous executable when the agent is not healthy", func(t *testing.T) {
+		restarts := 0
+		p, privateKey := newTestPackageManager(t, func(_ context.Context) error {
+			restarts++
+			if restarts == 1 {
+				return errors.New("agent did not report healthy")
+			}
+			return nil
+		})
+		hash, signature := signContent(t, privateKey, newContent)
+
+		err := p.UpdateContent(context.Background(), agentPackageName, bytes.NewReader(newContent), hash, signature)
+		require.ErrorContains(t, err, "agent did not report healthy")
+
+		got, err := os.ReadFile(p.agentExePath)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("old executable"), got)
+		assert.NoFileExists(t, p.agentExePath+".bak")
+		assert.Equal(t, 2, restarts)
+
+		// The executable is not installed again when offered again.
+		err = p.UpdateContent(context.Background(), agentPackageName, bytes.NewReader(newContent), hash, signature)
+		require.ErrorContains(t, err, "was not healthy before")
+		assert.Equal(t, 2, restarts)
+	})
+
+	testCases := []struct {
+		name          string
+		sign          func(t *testing.T, privateKey *ecdsa.PrivateKey) (hash, signature []byte)
+		expectedError string
+	}{
+		{
+			name: "Rejects a content hash mismatch",
+			sign: func(t *testing.T, privateKey *ecdsa.PrivateKey) ([]byte, []byte) {
+				return signContent(t, privateKey, []byte("other executable"))
+			},
+			expectedError: "content hash mismatch",
+		},
+		{
+			name: "Rejects an invalid signature",
+			sign: func(t *testing.T, privateKey *ecdsa.PrivateKey) ([]byte, []byte) {
+				hash, _ := signContent(t, privateKey, newContent)
+				_, signature := signContent(t, privateKey, []byte("other executable"))
+				return hash, signature
+			},
+			expectedError: "invalid package signature",
+		},
+		{
+			name: "Rejects a signature that is not base64 encoded",
+			sign: func(t *testing.T, privateKey *ecdsa.PrivateKey) ([]byte, []byte) {
+				digest := sha256.Sum256(newContent)
+				signature, err := ecdsa.SignASN1(rand.Reader, privateKey, digest[:])
+				require.NoError(t, err)
+				return digest[:], signature
+			},
+			expectedError: "package signature is not base64 encoded",
+		},
+		{
+			name: "Rejects an unsigned package",
+			sign: func(t *testing.T, privateKey *ecdsa.PrivateKey) ([]byte, []byte) {
+				hash, _ := signContent(t, privateKey, newContent)
+				return hash, nil
+			},
+			expectedError: "package is not signed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, privateKey := newTestPackageManager(t, func(_ context.Context) error {
+				require.Fail(t, "agent must not be restarted")
+				return nil
+			})
+			hash, signature := tc.sign(t, privateKey)
+
+			err := p.UpdateContent(context.Background(), agentPackageName, bytes.NewReader(newContent), hash, signature)
+			require.ErrorContains(t, err, tc.expectedError)
+
+			got, err := os.ReadFile(p.agentExePath)
+			require.NoError(t, err)
+			assert.Equal(t, []byte("old executable"), got)
+			assert.NoFileExists(t, p.agentExePath+".staged")
+		})
+	}
+}
+
+func TestPackageManager_state(t *testing.T) {
+	p, _ := newTestPackageManager(t, nil)
+
+	require.ErrorContains(t, p.CreatePackage("addon", protobufs.PackageType_PackageType_Addon), "only the top-level agent package is supported")
+	require.NoError(t, p.CreatePackage(agentPackageName, protobufs.PackageType_PackageType_TopLevel))
+	require.Error(t, p.CreatePackage(agentPackageName, protobufs.PackageType_PackageType_TopLevel))
+
+	require.NoError(t, p.SetPackageState(agentPackageName, types.PackageState{
+		Exists:  true,
+		Type:    protobufs.PackageType_PackageType_TopLevel,
+		Hash:    []byte("hash"),
+		Version: "v0.116.0",
+	}))
+	require.NoError(t, p.SetAllPackagesHash([]byte("all")))
+
+	statuses := &protobufs.PackageStatuses{
+		Packages: map[string]*protobufs.PackageStatus{
+			agentPackageName: {
+				AgentHasVersion: "v0.116.0",
+				Status:          protobufs.PackageStatusEnum_PackageStatusEnum_Installed,
+			},
+		},
+	}
+	require.NoError(t, p.SetLastReportedStatuses(statuses))
+
+	// The state survives a supervisor restart.
+	reloaded, err := newPackageManager(zap.NewNop(), p.storageDir, p.agentExePath, filepath.Join(p.storageDir, "packages.pem"), nil)
+	require.NoError(t, err)
+
+	names, err := reloaded.Packages()
+	require.NoError(t, err)
+	assert.Equal(t, []string{agentPackageName}, names)
+
+	state, err := reloaded.PackageState(agentPackageName)
+	require.NoError(t, err)
+	assert.Equal(t, types.PackageState{
+		Exists:  true,
+		Type:    protobufs.PackageType_PackageType_TopLevel,
+		Hash:    []byte("hash"),
+		Version: "v0.116.0",
+	}, state)
+
+	allHash, err := reloaded.AllPackagesHash()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("all"), allHash)
+
+	gotStatuses, err := reloaded.LastReportedStatuses()
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(statuses, gotStatuses))
+
+	require.NoError(t, reloaded.DeletePackage(agentPackageName))
+	state, err = reloaded.PackageState(agentPackageName)
+	require.NoError(t, err)
+	assert.False(t, state.Exists)
+	assert.FileExists(t, reloaded.agentExePath)
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = loadPublicKey(writePublicKey(t, dir, &rsaKey.PublicKey))
+	require.NoError(t, err)
+
+	ed25519Key, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = loadPublicKey(writePublicKey(t, dir, ed25519Key))
+	require.ErrorContains(t, err, "unsupported packages public key type")
+
+	notPEM := filepath.Join(dir, "not.pem")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a key"), 0o600))
+	_, err = loadPublicKey(notPEM)
+	require.ErrorContains(t, err, "no PEM data found")
+}
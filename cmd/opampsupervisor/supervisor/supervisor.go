@@ -57,6 +57,7 @@ var (
 	ownTelemetryTpl string
 
 	lastRecvRemoteConfigFile     = "last_recv_remote_config.dat"
+	lastGoodRemoteConfigFile     = "last_good_remote_config.dat"
 	lastRecvOwnMetricsConfigFile = "last_recv_own_metrics_config.dat"
 )
 
@@ -124,11 +125,21 @@ type Supervisor struct {
 	// Final effective config of the Collector.
 	effectiveConfig *atomic.Value
 
+	// remoteConfigMu guards remoteConfig and lastGoodConfig, which are updated both by
+	// the OpAMP client callbacks and by the agent process loop.
+	remoteConfigMu sync.Mutex
 	// Last received remote config.
 	remoteConfig *protobufs.AgentRemoteConfig
+	// Last remote config the agent stayed healthy with, used to roll back a failing remote config.
+	lastGoodConfig *protobufs.AgentRemoteConfig
 
 	// A channel to indicate there is a new config to apply.
 	hasNewConfig chan struct{}
+	// A channel to request a restart of the agent with a new executable.
+	// The result channel receives an error if the agent does not become healthy.
+	agentUpgrades chan chan error
+	// packageManager keeps the state of the packages offered by the server.
+	packageManager *packageManager
 	// configApplyTimeout is the maximum time to wait for the agent to apply a new config.
 	// After this time passes without the agent reporting health as OK, the agent is considered unhealthy.
 	configApplyTimeout time.Duration
@@ -163,6 +174,7 @@ func NewSupervisor(logger *zap.Logger, cfg config.Supervisor) (*Supervisor, erro
 		logger:                       logger,
 		pidProvider:                  defaultPIDProvider{},
 		hasNewConfig:                 make(chan struct{}, 1),
+		agentUpgrades:                make(chan chan error),
 		agentConfigOwnMetricsSection: &atomic.Value{},
 		cfgState:                     &atomic.Value{},
 		effectiveConfig:              &atomic.Value{},
@@ -218,6 +230,19 @@ func (s *Supervisor) Start() error {
 		return fmt.Errorf("failed loading initial config: %w", err)
 	}
 
+	if s.config.Capabilities.AcceptsPackages {
+		s.packageManager, err = newPackageManager(
+			s.logger,
+			s.config.Storage.Directory,
+			s.config.Agent.Executable,
+			s.config.Agent.Packages.PublicKeyFile,
+			s.restartAgentWithNewExecutable,
+		)
+		if err != nil {
+			return fmt.Errorf("could not create package manager: %w", err)
+		}
+	}
+
 	if err = s.startOpAMP(); err != nil {
 		return fmt.Errorf("cannot start OpAMP client: %w", err)
 	}
@@ -440,6 +465,9 @@ func (s *Supervisor) startOpAMPClient() error {
 		},
 		Capabilities: s.config.Capabilities.SupportedCapabilities(),
 	}
+	if s.packageManager != nil {
+		settings.PackagesStateProvider = s.packageManager
+	}
 	ad := s.agentDescription.Load().(*protobufs.AgentDescription)
 	if err = s.opampClient.SetAgentDescription(ad); err != nil {
 		return err
@@ -796,6 +824,10 @@ func (s *Supervisor) loadAndWriteInitialMergedConfig() error {
 		default:
 			s.logger.Error("error while reading last received config", zap.Error(err))
 		}
+
+		if s.config.Agent.ConfigRollback.Enabled {
+			s.loadLastGoodConfig()
+		}
 	} else {
 		s.logger.Debug("Remote config is not supported, will not attempt to load config from fil")
 	}
@@ -816,7 +848,7 @@ func (s *Supervisor) loadAndWriteInitialMergedConfig() error {
 		s.logger.Debug("Own metrics is not supported, will not attempt to load config from file")
 	}
 
-	_, err = s.composeMergedConfig(s.remoteConfig)
+	_, err = s.composeMergedRemoteConfig()
 	if err != nil {
 		return fmt.Errorf("could not compose initial merged config: %w", err)
 	}
@@ -883,7 +915,7 @@ func (s *Supervisor) setupOwnMetrics(_ context.Context, settings *protobufs.Tele
 	s.agentConfigOwnMetricsSection.Store(cfg.String())
 
 	// Need to recalculate the Agent config so that the metric config is included in it.
-	configChanged, err := s.composeMergedConfig(s.remoteConfig)
+	configChanged, err := s.composeMergedRemoteConfig()
 	if err != nil {
 		s.logger.Error("Error composing merged config for own metrics. Ignoring agent self metrics config", zap.Error(err))
 		return
@@ -892,6 +924,21 @@ func (s *Supervisor) setupOwnMetrics(_ context.Context, settings *protobufs.Tele
 	return configChanged
 }
 
+// composeMergedRemoteConfig composes the merged config with the current remote config.
+func (s *Supervisor) composeMergedRemoteConfig() (configChanged bool, err error) {
+	s.remoteConfigMu.Lock()
+	defer s.remoteConfigMu.Unlock()
+	return s.composeMergedConfig(s.remoteConfig)
+}
+
+// currentRemoteConfig returns the last received remote config, or the one the agent
+// was rolled back to.
+func (s *Supervisor) currentRemoteConfig() *protobufs.AgentRemoteConfig {
+	s.remoteConfigMu.Lock()
+	defer s.remoteConfigMu.Unlock()
+	return s.remoteConfig
+}
+
 // composeMergedConfig composes the merged config from multiple sources:
 // 1) the remote config from OpAMP Server
 // 2) the own metrics config section
@@ -1099,6 +1146,16 @@ func (s *Supervisor) runAgentProcess() {
 	configApplyTimeoutTimer := time.NewTimer(0)
 	configApplyTimeoutTimer.Stop()
 
+	agentUpgradeTimer := time.NewTimer(0)
+	agentUpgradeTimer.Stop()
+	var agentUpgradeResult chan error
+
+	// rollbackGraceTimer fires once an applied remote config has kept the agent
+	// healthy for the rollback grace period.
+	rollbackGraceTimer := time.NewTimer(0)
+	rollbackGraceTimer.Stop()
+	inRollbackGracePeriod := false
+
 	for {
 		select {
 		case <-s.hasNewConfig:
@@ -1110,6 +1167,8 @@ func (s *Supervisor) runAgentProcess() {
 				}
 			}
 			configApplyTimeoutTimer.Reset(s.config.Agent.ConfigApplyTimeout)
+			rollbackGraceTimer.Stop()
+			inRollbackGracePeriod = false
 
 			s.logger.Debug("Restarting agent due to new config")
 			restartTimer.Stop()
@@ -1122,6 +1181,15 @@ func (s *Supervisor) runAgentProcess() {
 				continue
 			}
 
+			if inRollbackGracePeriod {
+				rollbackGraceTimer.Stop()
+				inRollbackGracePeriod = false
+				reason := fmt.Sprintf("Agent process exited with code %d after applying the config", s.commander.ExitCode())
+				if s.rollbackConfig(reason) {
+					continue
+				}
+			}
+
 			s.logger.Debug("Agent process exited unexpectedly. Will restart in a bit...", zap.Int("pid", s.commander.Pid()), zap.Int("exit_code", s.commander.ExitCode()))
 			errMsg := fmt.Sprintf(
 				"Agent process PID=%d exited unexpectedly, exit code=%d. Will restart in a bit...",
@@ -1150,14 +1218,67 @@ func (s *Supervisor) runAgentProcess() {
 
 		case <-configApplyTimeoutTimer.C:
 			if s.lastHealthFromClient == nil || !s.lastHealthFromClient.Healthy {
-				s.reportConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, "Config apply timeout exceeded")
+				if !s.rollbackConfig("Config apply timeout exceeded") {
+					s.reportConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, "Config apply timeout exceeded")
+				}
+				continue
+			}
+
+			s.reportConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, "")
+			if s.config.Agent.ConfigRollback.Enabled {
+				if s.config.Agent.ConfigRollback.GracePeriod == 0 {
+					s.saveLastGoodConfig()
+					continue
+				}
+				rollbackGraceTimer.Reset(s.config.Agent.ConfigRollback.GracePeriod)
+				inRollbackGracePeriod = true
+			}
+
+		case result := <-s.agentUpgrades:
+			if agentUpgradeResult != nil {
+				// The pending upgrade is still waiting for the agent to report healthy.
+				result <- errors.New("another agent upgrade is in progress")
+				continue
+			}
+
+			s.lastHealthFromClient = nil
+			if !agentUpgradeTimer.Stop() {
+				select {
+				case <-agentUpgradeTimer.C: // Try to drain the channel
+				default:
+				}
+			}
+			agentUpgradeTimer.Reset(s.config.Agent.Packages.HealthTimeout)
+			agentUpgradeResult = result
+
+			s.logger.Debug("Restarting agent due to new executable")
+			restartTimer.Stop()
+			if err := s.commander.Stop(context.Background()); err != nil {
+				s.logger.Error("Could not stop agent process", zap.Error(err))
+			}
+			s.startAgent()
+
+		case <-agentUpgradeTimer.C:
+			if s.lastHealthFromClient == nil || !s.lastHealthFromClient.Healthy {
+				agentUpgradeResult <- fmt.Errorf("agent did not report healthy within %s", s.config.Agent.Packages.HealthTimeout)
 			} else {
-				s.reportConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, "")
+				agentUpgradeResult <- nil
 			}
+			agentUpgradeResult = nil
+
+		case <-rollbackGraceTimer.C:
+			inRollbackGracePeriod = false
+			s.saveLastGoodConfig()
 
 		case <-s.healthCheckTicker.C:
 			s.healthCheck()
 
+			if inRollbackGracePeriod && s.agentHasStarted && !s.lastHealth.Healthy {
+				rollbackGraceTimer.Stop()
+				inRollbackGracePeriod = false
+				s.rollbackConfig(fmt.Sprintf("Agent became unhealthy after applying the config: %s", s.lastHealth.LastError))
+			}
+
 		case <-s.doneChan:
 			err := s.commander.Stop(context.Background())
 			if err != nil {
@@ -1168,6 +1289,28 @@ func (s *Supervisor) runAgentProcess() {
 	}
 }
 
+// restartAgentWithNewExecutable restarts the agent after its executable has been replaced
+// and waits for it to become healthy.
+func (s *Supervisor) restartAgentWithNewExecutable(ctx context.Context) error {
+	result := make(chan error, 1)
+	select {
+	case s.agentUpgrades <- result:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.doneChan:
+		return errors.New("supervisor is shutting down")
+	}
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.doneChan:
+		return errors.New("supervisor is shutting down")
+	}
+}
+
 func (s *Supervisor) stopAgentApplyConfig() {
 	s.logger.Debug("Stopping the agent to apply new config")
 	cfgState := s.cfgState.Load().(*configState)
@@ -1232,6 +1375,113 @@ func (s *Supervisor) saveLastReceivedConfig(config *protobufs.AgentRemoteConfig)
 	return os.WriteFile(filepath.Join(s.config.Storage.Directory, lastRecvRemoteConfigFile), cfg, 0o600)
 }
 
+// loadLastGoodConfig loads the last known good remote config from the storage directory, if any.
+func (s *Supervisor) loadLastGoodConfig() {
+	lastGoodConfig, err := os.ReadFile(filepath.Join(s.config.Storage.Directory, lastGoodRemoteConfigFile))
+	switch {
+	case err == nil:
+		config := &protobufs.AgentRemoteConfig{}
+		if err = proto.Unmarshal(lastGoodConfig, config); err != nil {
+			s.logger.Error("Cannot parse last known good remote config", zap.Error(err))
+			return
+		}
+		s.remoteConfigMu.Lock()
+		s.lastGoodConfig = config
+		s.remoteConfigMu.Unlock()
+	case errors.Is(err, os.ErrNotExist):
+		s.logger.Info("No last known good remote config found")
+	default:
+		s.logger.Error("error while reading last known good config", zap.Error(err))
+	}
+}
+
+// saveLastGoodConfig records the current remote config as the last known good one.
+func (s *Supervisor) saveLastGoodConfig() {
+	s.remoteConfigMu.Lock()
+	defer s.remoteConfigMu.Unlock()
+
+	if s.remoteConfig == nil {
+		return
+	}
+
+	cfg, err := proto.Marshal(s.remoteConfig)
+	if err != nil {
+		s.logger.Error("Could not marshal last known good remote config", zap.Error(err))
+		return
+	}
+
+	if err = os.WriteFile(filepath.Join(s.config.Storage.Directory, lastGoodRemoteConfigFile), cfg, 0o600); err != nil {
+		s.logger.Error("Could not save last known good remote config", zap.Error(err))
+		return
+	}
+
+	s.lastGoodConfig = s.remoteConfig
+	s.logger.Debug("Saved last known good remote config", zap.String("hash", fmt.Sprintf("%x", s.remoteConfig.GetConfigHash())))
+}
+
+// rollbackConfig restarts the agent with the last known good remote config after the
+// current remote config failed, and reports the failure to the server. Without a last
+// known good config, the agent falls back to the local config. It returns false if the
+// rollback is disabled or there is no other config to roll back to.
+func (s *Supervisor) rollbackConfig(reason string) bool {
+	if !s.config.Agent.ConfigRollback.Enabled {
+		return false
+	}
+
+	s.remoteConfigMu.Lock()
+	failedConfig, goodConfig := s.remoteConfig, s.lastGoodConfig
+	failedHash := failedConfig.GetConfigHash()
+	goodHash := goodConfig.GetConfigHash()
+	if failedConfig == nil || bytes.Equal(failedHash, goodHash) {
+		s.remoteConfigMu.Unlock()
+		return false
+	}
+
+	s.logger.Warn("Rolling back to the last known good remote config",
+		zap.String("reason", reason),
+		zap.String("failed_hash", fmt.Sprintf("%x", failedHash)),
+		zap.String("good_hash", fmt.Sprintf("%x", goodHash)),
+	)
+
+	// A nil config composes the local config only.
+	if _, err := s.composeMergedConfig(goodConfig); err != nil {
+		s.remoteConfigMu.Unlock()
+		s.logger.Error("Could not compose the last known good remote config", zap.Error(err))
+		return false
+	}
+	s.remoteConfig = goodConfig
+	s.remoteConfigMu.Unlock()
+
+	// Restart from the good config if the supervisor restarts.
+	rolledBackTo := fmt.Sprintf("the last known good config %x", goodHash)
+	if goodConfig == nil {
+		rolledBackTo = "the local config"
+		if err := os.Remove(filepath.Join(s.config.Storage.Directory, lastRecvRemoteConfigFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.logger.Error("Could not remove last received remote config", zap.Error(err))
+		}
+	} else if err := s.saveLastReceivedConfig(goodConfig); err != nil {
+		s.logger.Error("Could not save last received remote config", zap.Error(err))
+	}
+
+	// The failed config stays the last one the server sees reported so it is not sent again.
+	err := s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
+		LastRemoteConfigHash: failedHash,
+		Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED,
+		ErrorMessage:         fmt.Sprintf("%s, rolled back to %s", reason, rolledBackTo),
+	})
+	if err != nil {
+		s.logger.Error("Could not report OpAMP remote config status", zap.Error(err))
+	}
+
+	if err = s.opampClient.UpdateEffectiveConfig(context.Background()); err != nil {
+		s.logger.Error("The OpAMP client failed to update the effective config", zap.Error(err))
+	}
+
+	s.stopAgentApplyConfig()
+	s.startAgent()
+	return true
+}
+
 func (s *Supervisor) saveLastReceivedOwnTelemetrySettings(set *protobufs.TelemetryConnectionSettings, filePath string) error {
 	cfg, err := proto.Marshal(set)
 	if err != nil {
@@ -1243,7 +1493,7 @@ func (s *Supervisor) saveLastReceivedOwnTelemetrySettings(set *protobufs.Telemet
 
 func (s *Supervisor) reportConfigStatus(status protobufs.RemoteConfigStatuses, errorMessage string) {
 	err := s.opampClient.SetRemoteConfigStatus(&protobufs.RemoteConfigStatus{
-		LastRemoteConfigHash: s.remoteConfig.GetConfigHash(),
+		LastRemoteConfigHash: s.currentRemoteConfig().GetConfigHash(),
 		Status:               status,
 		ErrorMessage:         errorMessage,
 	})
@@ -1267,6 +1517,14 @@ func (s *Supervisor) onMessage(ctx context.Context, msg *types.MessageData) {
 		configChanged = s.processOwnMetricsConnSettingsMessage(ctx, msg.OwnMetricsConnSettings) || configChanged
 	}
 
+	if msg.PackagesAvailable != nil && msg.PackageSyncer != nil {
+		s.logger.Debug("Received packages from server, syncing them")
+		// The syncing continues in the background, after the message is processed.
+		if err := msg.PackageSyncer.Sync(context.Background()); err != nil {
+			s.logger.Error("Could not sync packages", zap.Error(err))
+		}
+	}
+
 	// Update the agent config if any messages have touched the config
 	if configChanged {
 		err := s.opampClient.UpdateEffectiveConfig(ctx)
@@ -1316,11 +1574,12 @@ func (s *Supervisor) processRemoteConfigMessage(msg *protobufs.AgentRemoteConfig
 		s.logger.Error("Could not save last received remote config", zap.Error(err))
 	}
 
-	s.remoteConfig = msg
-	s.logger.Debug("Received remote config from server", zap.String("hash", fmt.Sprintf("%x", s.remoteConfig.ConfigHash)))
+	s.logger.Debug("Received remote config from server", zap.String("hash", fmt.Sprintf("%x", msg.ConfigHash)))
 
-	var err error
-	configChanged, err := s.composeMergedConfig(s.remoteConfig)
+	s.remoteConfigMu.Lock()
+	s.remoteConfig = msg
+	configChanged, err := s.composeMergedConfig(msg)
+	s.remoteConfigMu.Unlock()
 	if err != nil {
 		s.logger.Error("Error composing merged config. Reporting failed remote config status.", zap.Error(err))
 		s.reportConfigStatus(protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, err.Error())
@@ -1362,7 +1621,7 @@ func (s *Supervisor) processAgentIdentificationMessage(msg *protobufs.AgentIdent
 	}
 
 	// Need to recalculate the Agent config so that the new agent identification is included in it.
-	configChanged, err := s.composeMergedConfig(s.remoteConfig)
+	configChanged, err := s.composeMergedRemoteConfig()
 	if err != nil {
 		s.logger.Error("Error composing merged config with new instance ID", zap.Error(err))
 		return false
@@ -13,6 +13,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -27,6 +28,7 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/opampsupervisor/supervisor/commander"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/opampsupervisor/supervisor/config"
 )
 
@@ -1326,6 +1328,253 @@ service:
 	})
 }
 
+func TestSupervisor_rollbackConfig(t *testing.T) {
+	goodCfg := &protobufs.AgentRemoteConfig{
+		Config: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"": {Body: []byte("receivers:\n  debug/good:\n")},
+			},
+		},
+		ConfigHash: []byte("good"),
+	}
+	badCfg := &protobufs.AgentRemoteConfig{
+		Config: &protobufs.AgentConfigMap{
+			ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"": {Body: []byte("receivers:\n  debug/bad:\n")},
+			},
+		},
+		ConfigHash: []byte("bad"),
+	}
+
+	newSupervisor := func(t *testing.T, enabled bool, statuses *[]*protobufs.RemoteConfigStatus) *Supervisor {
+		var statusesMu sync.Mutex
+		storageDir := t.TempDir()
+		agentCfg := config.Agent{
+			Executable: filepath.Join(storageDir, "nonexistent-agent"),
+			ConfigRollback: config.ConfigRollback{
+				Enabled: enabled,
+			},
+		}
+		cmd, err := commander.NewCommander(zap.NewNop(), storageDir, agentCfg)
+		require.NoError(t, err)
+
+		agentDesc := &atomic.Value{}
+		agentDesc.Store(&protobufs.AgentDescription{})
+		s := &Supervisor{
+			logger:      zap.NewNop(),
+			pidProvider: staticPIDProvider(1234),
+			config: config.Supervisor{
+				Agent: agentCfg,
+				Storage: config.Storage{
+					Directory: storageDir,
+				},
+			},
+			commander:                    cmd,
+			persistentState:              &persistentState{InstanceID: uuid.MustParse("018fee23-4a51-7303-a441-73faed7d9deb")},
+			agentDescription:             agentDesc,
+			agentConfigOwnMetricsSection: &atomic.Value{},
+			cfgState:                     &atomic.Value{},
+			effectiveConfig:              &atomic.Value{},
+			opampClient: &mockOpAMPClient{
+				updateEffectiveConfigFunc: func(_ context.Context) error {
+					return nil
+				},
+				setRemoteConfigStatusFunc: func(rcs *protobufs.RemoteConfigStatus) error {
+					statusesMu.Lock()
+					defer statusesMu.Unlock()
+					*statuses = append(*statuses, rcs)
+					return nil
+				},
+			},
+		}
+		require.NoError(t, s.createTemplates())
+		return s
+	}
+
+	t.Run("Rolls back to the last known good config", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+
+		s.remoteConfig = goodCfg
+		s.saveLastGoodConfig()
+		s.remoteConfig = badCfg
+		_, err := s.composeMergedConfig(badCfg)
+		require.NoError(t, err)
+
+		require.True(t, s.rollbackConfig("Config apply timeout exceeded"))
+
+		require.Len(t, statuses, 1)
+		assert.Equal(t, []byte("bad"), statuses[0].LastRemoteConfigHash)
+		assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, statuses[0].Status)
+		assert.Equal(t, "Config apply timeout exceeded, rolled back to the last known good config 676f6f64", statuses[0].ErrorMessage)
+
+		assert.Equal(t, goodCfg, s.remoteConfig)
+		effectiveConfig, err := os.ReadFile(s.agentConfigFilePath())
+		require.NoError(t, err)
+		assert.Contains(t, string(effectiveConfig), "debug/good")
+		assert.NotContains(t, string(effectiveConfig), "debug/bad")
+
+		lastRecv, err := os.ReadFile(filepath.Join(s.config.Storage.Directory, lastRecvRemoteConfigFile))
+		require.NoError(t, err)
+		gotLastRecv := &protobufs.AgentRemoteConfig{}
+		require.NoError(t, proto.Unmarshal(lastRecv, gotLastRecv))
+		assert.True(t, proto.Equal(goodCfg, gotLastRecv))
+	})
+
+	t.Run("Last known good config is loaded on start", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+
+		s.remoteConfig = goodCfg
+		s.saveLastGoodConfig()
+		s.lastGoodConfig = nil
+
+		s.loadLastGoodConfig()
+		assert.True(t, proto.Equal(goodCfg, s.lastGoodConfig))
+	})
+
+	t.Run("No rollback when disabled", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, false, &statuses)
+
+		s.lastGoodConfig = goodCfg
+		s.remoteConfig = badCfg
+
+		assert.False(t, s.rollbackConfig("Config apply timeout exceeded"))
+		assert.Empty(t, statuses)
+		assert.Equal(t, badCfg, s.remoteConfig)
+	})
+
+	t.Run("Falls back to the local config without a last known good config", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+
+		s.processRemoteConfigMessage(badCfg)
+		statuses = nil
+
+		require.True(t, s.rollbackConfig("Config apply timeout exceeded"))
+
+		require.Len(t, statuses, 1)
+		assert.Equal(t, []byte("bad"), statuses[0].LastRemoteConfigHash)
+		assert.Equal(t, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, statuses[0].Status)
+		assert.Equal(t, "Config apply timeout exceeded, rolled back to the local config", statuses[0].ErrorMessage)
+
+		assert.Nil(t, s.remoteConfig)
+		effectiveConfig, err := os.ReadFile(s.agentConfigFilePath())
+		require.NoError(t, err)
+		assert.Contains(t, string(effectiveConfig), "nop")
+		assert.NotContains(t, string(effectiveConfig), "debug/bad")
+
+		_, err = os.Stat(filepath.Join(s.config.Storage.Directory, lastRecvRemoteConfigFile))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("No rollback without a remote config", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+
+		assert.False(t, s.rollbackConfig("Config apply timeout exceeded"))
+		assert.Empty(t, statuses)
+	})
+
+	t.Run("Remote config received during a rollback", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+
+		s.remoteConfig = goodCfg
+		s.saveLastGoodConfig()
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				s.processRemoteConfigMessage(badCfg)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				s.rollbackConfig("Config apply timeout exceeded")
+				s.saveLastGoodConfig()
+			}
+		}()
+		wg.Wait()
+
+		s.processRemoteConfigMessage(badCfg)
+		assert.Equal(t, badCfg, s.currentRemoteConfig())
+	})
+
+	t.Run("No rollback when the failed config is the last known good one", func(t *testing.T) {
+		var statuses []*protobufs.RemoteConfigStatus
+		s := newSupervisor(t, true, &statuses)
+
+		s.remoteConfig = goodCfg
+		s.saveLastGoodConfig()
+
+		assert.False(t, s.rollbackConfig("Config apply timeout exceeded"))
+		assert.Empty(t, statuses)
+	})
+}
+
+func TestSupervisor_restartAgentWithNewExecutable(t *testing.T) {
+	t.Run("Rejects a concurrent agent upgrade", func(t *testing.T) {
+		storageDir := t.TempDir()
+		agentCfg := config.Agent{
+			Executable: filepath.Join(storageDir, "nonexistent-agent"),
+			Packages: config.AgentPackages{
+				HealthTimeout: time.Hour,
+			},
+		}
+		cmd, err := commander.NewCommander(zap.NewNop(), storageDir, agentCfg)
+		require.NoError(t, err)
+
+		s := &Supervisor{
+			logger: zap.NewNop(),
+			config: config.Supervisor{
+				Agent: agentCfg,
+				Storage: config.Storage{
+					Directory: storageDir,
+				},
+			},
+			commander:     cmd,
+			cfgState:      &atomic.Value{},
+			hasNewConfig:  make(chan struct{}, 1),
+			agentUpgrades: make(chan chan error),
+			doneChan:      make(chan struct{}),
+			opampClient:   &mockOpAMPClient{},
+		}
+		s.cfgState.Store(&configState{configMapIsEmpty: true})
+		s.startHealthCheckTicker()
+		defer s.healthCheckTicker.Stop()
+
+		s.agentWG.Add(1)
+		go func() {
+			defer s.agentWG.Done()
+			s.runAgentProcess()
+		}()
+
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				errs <- s.restartAgentWithNewExecutable(context.Background())
+			}()
+		}
+
+		// One upgrade waits for the agent to report healthy, the other one is rejected.
+		select {
+		case err := <-errs:
+			require.EqualError(t, err, "another agent upgrade is in progress")
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "the concurrent agent upgrade was not rejected")
+		}
+
+		close(s.doneChan)
+		s.agentWG.Wait()
+		require.EqualError(t, <-errs, "supervisor is shutting down")
+	})
+}
+
 func TestSupervisor_composeNoopConfig(t *testing.T) {
 	const expectedConfig = `exporters:
     nop: null
@@ -23,6 +23,15 @@ const (
 	defaultHTTPEndpoint = "localhost:4318"
 )
 
+// The outputs of the generated telemetry.
+const (
+	OutputOTLP                  = "otlp"
+	OutputKafka                 = "kafka"
+	OutputZipkin                = "zipkin"
+	OutputPrometheusRemoteWrite = "prometheusremotewrite"
+	OutputSyslog                = "syslog"
+)
+
 type KeyValue map[string]any
 
 var _ pflag.Value = (*KeyValue)(nil)
@@ -79,6 +88,11 @@ type Config struct {
 
 	// OTLP mTLS configuration
 	ClientAuth ClientAuth
+
+	// Output config
+	Output       string
+	KafkaBrokers []string
+	KafkaTopic   string
 }
 
 type ClientAuth struct {
@@ -177,6 +191,12 @@ func (c *Config) CommonFlags(fs *pflag.FlagSet) {
 		"Note you may need to escape the quotes when using the tool from a cli. "+
 		`Flag may be repeated to set multiple attributes (e.g --telemetry-attributes key1=\"value1\" --telemetry-attributes key2=\"value2\" --telemetry-attributes key3=true)`)
 
+	fs.StringVar(&c.Output, "output", OutputOTLP, "Protocol and wire format of the generated telemetry. "+
+		"One of 'otlp' or 'kafka' for all the signals, 'zipkin' for traces, 'prometheusremotewrite' for metrics, or 'syslog' for logs. "+
+		"The outputs other than 'otlp' and 'kafka' send to the `--otlp-endpoint`")
+	fs.StringSliceVar(&c.KafkaBrokers, "kafka-brokers", []string{"localhost:9092"}, "Kafka brokers to produce the OTLP protobuf messages to, with the 'kafka' output")
+	fs.StringVar(&c.KafkaTopic, "kafka-topic", "", "Kafka topic to produce the OTLP protobuf messages to, with the 'kafka' output (default otlp_spans, otlp_metrics or otlp_logs)")
+
 	// TLS CA configuration
 	fs.StringVar(&c.CaFile, "ca-cert", "", "Trusted Certificate Authority to verify server certificate")
 
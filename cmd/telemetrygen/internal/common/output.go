@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/IBM/sarama"
+)
+
+// HTTPSender posts the payloads to an HTTP endpoint, for the outputs other than OTLP.
+type HTTPSender struct {
+	client  *http.Client
+	url     string
+	headers map[string]string
+}
+
+// NewHTTPSender creates a sender posting to the path of the endpoint, or of the default endpoint of the output, with
+// the headers of the configuration in addition to the given ones.
+func NewHTTPSender(cfg *Config, defaultEndpoint, path string, headers map[string]string) (*HTTPSender, error) {
+	endpoint := cfg.CustomEndpoint
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+
+	scheme := "http"
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.Insecure {
+		tlsCfg, err := GetTLSCredentialsForHTTPExporter(cfg.CaFile, cfg.ClientAuth, cfg.InsecureSkipVerify)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get TLS credentials: %w", err)
+		}
+		transport.TLSClientConfig = tlsCfg
+		scheme = "https"
+	}
+
+	allHeaders := cfg.GetHeaders()
+	for k, v := range headers {
+		allHeaders[k] = v
+	}
+
+	return &HTTPSender{
+		client:  &http.Client{Transport: transport},
+		url:     fmt.Sprintf("%s://%s%s", scheme, endpoint, path),
+		headers: allHeaders,
+	}, nil
+}
+
+// Send posts the payload, and fails when the response isn't a success.
+func (s *HTTPSender) Send(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to send the payload to %s: %s", s.url, resp.Status)
+	}
+	return nil
+}
+
+// KafkaSender produces the payloads to a Kafka topic, in the encoding expected by default by the Kafka receiver.
+type KafkaSender struct {
+	producer sarama.SyncProducer
+	topic    string
+}
+
+// NewKafkaSender creates a sender producing to the topic of the configuration, or to the default topic of the signal.
+func NewKafkaSender(cfg *Config, defaultTopic string) (*KafkaSender, error) {
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Producer.Return.Successes = true
+	saramaCfg.Producer.RequiredAcks = sarama.WaitForLocal
+
+	producer, err := sarama.NewSyncProducer(cfg.KafkaBrokers, saramaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Kafka producer: %w", err)
+	}
+
+	topic := cfg.KafkaTopic
+	if topic == "" {
+		topic = defaultTopic
+	}
+	return &KafkaSender{producer: producer, topic: topic}, nil
+}
+
+// Send produces the payload as a single message.
+func (s *KafkaSender) Send(_ context.Context, payload []byte) error {
+	_, _, err := s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: s.topic,
+		Value: sarama.ByteEncoder(payload),
+	})
+	return err
+}
+
+// Close closes the producer.
+func (s *KafkaSender) Close() error {
+	return s.producer.Close()
+}
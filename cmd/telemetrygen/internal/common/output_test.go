@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSender(t *testing.T) {
+	var body []byte
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/spans", r.URL.Path)
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		CustomEndpoint: strings.TrimPrefix(srv.URL, "http://"),
+		Insecure:       true,
+		Headers:        KeyValue{"authorization": "token"},
+	}
+	sender, err := NewHTTPSender(cfg, "localhost:9411", "/api/v2/spans", map[string]string{"Content-Type": "application/json"})
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send(context.Background(), []byte(`[]`)))
+	assert.Equal(t, `[]`, string(body))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "token", header.Get("Authorization"))
+}
+
+func TestHTTPSenderError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		CustomEndpoint: strings.TrimPrefix(srv.URL, "http://"),
+		Insecure:       true,
+	}
+	sender, err := NewHTTPSender(cfg, "localhost:9090", "/api/v1/write", nil)
+	require.NoError(t, err)
+
+	assert.ErrorContains(t, sender.Send(context.Background(), []byte("payload")), "400 Bad Request")
+}
+
+func TestKafkaSender(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("otlp_spans", 0, broker.BrokerID()).
+			SetLeader("custom", 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+
+	for _, topic := range []string{"", "custom"} {
+		cfg := &Config{
+			KafkaBrokers: []string{broker.Addr()},
+			KafkaTopic:   topic,
+		}
+		sender, err := NewKafkaSender(cfg, "otlp_spans")
+		require.NoError(t, err)
+		if topic == "" {
+			assert.Equal(t, "otlp_spans", sender.topic)
+		} else {
+			assert.Equal(t, topic, sender.topic)
+		}
+
+		require.NoError(t, sender.Send(context.Background(), []byte("payload")))
+		require.NoError(t, sender.Close())
+	}
+
+	var produced int
+	for _, rr := range broker.History() {
+		if _, ok := rr.Request.(*sarama.ProduceRequest); ok {
+			produced++
+		}
+	}
+	assert.Equal(t, 2, produced)
+}
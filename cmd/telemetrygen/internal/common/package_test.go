@@ -10,5 +10,8 @@ import (
 )
 
 func TestMain(m *testing.M) {
-	goleak.VerifyTestMain(m)
+	// The Kafka output tested in output_test.go creates a sarama producer, which registers its rcrowley/go-metrics
+	// meters (e.g. "incoming-byte-rate"). The first meter starts the global meterArbiter goroutine, which go-metrics
+	// never stops, even once the producer is closed.
+	goleak.VerifyTestMain(m, goleak.IgnoreTopFunction("github.com/rcrowley/go-metrics.(*meterArbiter).tick"))
 }
@@ -6,6 +6,7 @@ package common
 import (
 	"encoding/hex"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -39,3 +40,18 @@ func ValidateSpanID(spanID string) error {
 
 	return nil
 }
+
+// ValidateOutput checks that the output is supported by the signal, in addition to the OTLP and Kafka outputs.
+// The empty output is the default OTLP one.
+func ValidateOutput(output string, signalOutputs ...string) error {
+	if output == "" {
+		return nil
+	}
+	outputs := append([]string{OutputOTLP, OutputKafka}, signalOutputs...)
+	for _, o := range outputs {
+		if output == o {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected `output` to be one of (%s), got %q instead", strings.Join(outputs, ", "), output)
+}
@@ -68,3 +68,11 @@ func TestValidateSpanID(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateOutput(t *testing.T) {
+	assert.NoError(t, ValidateOutput(""))
+	assert.NoError(t, ValidateOutput(OutputOTLP))
+	assert.NoError(t, ValidateOutput(OutputKafka, OutputZipkin))
+	assert.NoError(t, ValidateOutput(OutputZipkin, OutputZipkin))
+	assert.EqualError(t, ValidateOutput(OutputSyslog, OutputZipkin), `expected `+"`output`"+` to be one of (otlp, kafka, zipkin), got "syslog" instead`)
+}
@@ -20,6 +20,9 @@ type Config struct {
 	SeverityNumber int32
 	TraceID        string
 	SpanID         string
+
+	SyslogTransport     string
+	SyslogOctetCounting bool
 }
 
 // Flags registers config flags.
@@ -34,6 +37,9 @@ func (c *Config) Flags(fs *pflag.FlagSet) {
 	fs.Int32Var(&c.SeverityNumber, "severity-number", 9, "Severity number of the log, range from 1 to 24 (inclusive)")
 	fs.StringVar(&c.TraceID, "trace-id", "", "TraceID of the log")
 	fs.StringVar(&c.SpanID, "span-id", "", "SpanID of the log")
+
+	fs.StringVar(&c.SyslogTransport, "syslog-transport", syslogTransportTCP, "Transport of the 'syslog' output, one of 'tcp' or 'udp'")
+	fs.BoolVar(&c.SyslogOctetCounting, "syslog-octet-counting", false, "Whether to frame the TCP syslog messages with octet counting, instead of a trailing newline")
 }
 
 // Validate validates the test scenario parameters.
@@ -54,5 +60,9 @@ func (c *Config) Validate() error {
 		}
 	}
 
-	return nil
+	if c.Output == common.OutputSyslog && c.SyslogTransport != syslogTransportTCP && c.SyslogTransport != syslogTransportUDP {
+		return fmt.Errorf("expected `syslog-transport` to be one of (tcp, udp), got %q instead", c.SyslogTransport)
+	}
+
+	return common.ValidateOutput(c.Output, common.OutputSyslog)
 }
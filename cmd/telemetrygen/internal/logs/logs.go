@@ -31,7 +31,14 @@ func Start(cfg *Config) error {
 	}
 	expFunc := func() (sdklog.Exporter, error) {
 		var exp sdklog.Exporter
-		if cfg.UseHTTP {
+		switch {
+		case cfg.Output != "" && cfg.Output != common.OutputOTLP:
+			logger.Info("starting the exporter", zap.String("output", cfg.Output))
+			exp, err = newOutputExporter(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to obtain the %s exporter: %w", cfg.Output, err)
+			}
+		case cfg.UseHTTP:
 			var exporterOpts []otlploghttp.Option
 
 			logger.Info("starting HTTP exporter")
@@ -43,7 +50,7 @@ func Start(cfg *Config) error {
 			if err != nil {
 				return nil, fmt.Errorf("failed to obtain OTLP HTTP exporter: %w", err)
 			}
-		} else {
+		default:
 			var exporterOpts []otlploggrpc.Option
 
 			logger.Info("starting gRPC exporter")
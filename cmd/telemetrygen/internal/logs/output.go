@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+const (
+	defaultKafkaTopic     = "otlp_logs"
+	defaultSyslogEndpoint = "localhost:54526"
+
+	syslogTransportTCP = "tcp"
+	syslogTransportUDP = "udp"
+
+	// the user-level messages facility
+	syslogFacility = 1
+	// the example private enterprise number of RFC 5424, identifying the structured data of the attributes
+	syslogEnterpriseID = 32473
+)
+
+// newOutputExporter creates an exporter sending the logs with the output of the configuration, other than OTLP.
+func newOutputExporter(cfg *Config) (sdklog.Exporter, error) {
+	switch cfg.Output {
+	case common.OutputKafka:
+		sender, err := common.NewKafkaSender(&cfg.Config, defaultKafkaTopic)
+		if err != nil {
+			return nil, err
+		}
+		marshaler := &plog.ProtoMarshaler{}
+		return &pdataExporter{
+			push: func(ctx context.Context, ld plog.Logs) error {
+				payload, err := marshaler.MarshalLogs(ld)
+				if err != nil {
+					return err
+				}
+				return sender.Send(ctx, payload)
+			},
+			close: sender.Close,
+		}, nil
+	case common.OutputSyslog:
+		endpoint := cfg.CustomEndpoint
+		if endpoint == "" {
+			endpoint = defaultSyslogEndpoint
+		}
+		conn, err := net.Dial(cfg.SyslogTransport, endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to the syslog endpoint: %w", err)
+		}
+		return &pdataExporter{
+			push: func(_ context.Context, ld plog.Logs) error {
+				for _, msg := range syslogMessages(ld) {
+					// each UDP datagram holds a single message, the TCP messages need to be framed
+					if cfg.SyslogTransport == syslogTransportTCP {
+						if cfg.SyslogOctetCounting {
+							msg = strconv.Itoa(len(msg)) + " " + msg
+						} else {
+							msg += "\n"
+						}
+					}
+					if _, err := conn.Write([]byte(msg)); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			close: conn.Close,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output %q", cfg.Output)
+	}
+}
+
+var _ sdklog.Exporter = (*pdataExporter)(nil)
+
+// pdataExporter pushes the log records as pdata, to encode them in another wire format than OTLP.
+type pdataExporter struct {
+	push  func(context.Context, plog.Logs) error
+	close func() error
+}
+
+func (e *pdataExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	return e.push(ctx, toPdata(records))
+}
+
+func (e *pdataExporter) Shutdown(context.Context) error {
+	if e.close == nil {
+		return nil
+	}
+	return e.close()
+}
+
+func (e *pdataExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+// syslogMessages formats the log records as RFC 5424 messages, the application being the `service.name` resource
+// attribute and the host the `host.name` one, with the attributes of the records as structured data.
+func syslogMessages(ld plog.Logs) []string {
+	var messages []string
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		appName, hostname := "telemetrygen", "-"
+		if v, ok := rl.Resource().Attributes().Get("service.name"); ok {
+			appName = v.AsString()
+		}
+		if v, ok := rl.Resource().Attributes().Get("host.name"); ok {
+			hostname = v.AsString()
+		}
+
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				lr := sl.LogRecords().At(k)
+				timestamp := lr.Timestamp().AsTime()
+				if lr.Timestamp() == 0 {
+					timestamp = time.Now()
+				}
+
+				structuredData := "-"
+				if lr.Attributes().Len() > 0 {
+					var sd strings.Builder
+					fmt.Fprintf(&sd, "[attributes@%d", syslogEnterpriseID)
+					lr.Attributes().Range(func(k string, v pcommon.Value) bool {
+						fmt.Fprintf(&sd, " %s=\"%s\"", syslogParamName(k), syslogParamValue(v.AsString()))
+						return true
+					})
+					sd.WriteString("]")
+					structuredData = sd.String()
+				}
+
+				messages = append(messages, fmt.Sprintf("<%d>1 %s %s %s - - %s %s",
+					syslogFacility*8+syslogSeverity(lr.SeverityNumber()),
+					timestamp.UTC().Format(time.RFC3339Nano),
+					syslogHeaderField(hostname),
+					syslogHeaderField(appName),
+					structuredData,
+					lr.Body().AsString(),
+				))
+			}
+		}
+	}
+	return messages
+}
+
+// syslogSeverity maps the severity number of the log record to the syslog severity.
+func syslogSeverity(severity plog.SeverityNumber) int {
+	switch {
+	case severity >= plog.SeverityNumberFatal:
+		return 2 // critical
+	case severity >= plog.SeverityNumberError:
+		return 3 // error
+	case severity >= plog.SeverityNumberWarn:
+		return 4 // warning
+	case severity >= plog.SeverityNumberInfo, severity == plog.SeverityNumberUnspecified:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
+
+// syslogHeaderField replaces the spaces of the header fields, which separate them.
+func syslogHeaderField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(s, " ", "_")
+}
+
+// syslogParamName replaces the characters forbidden in the names of the structured data parameters.
+func syslogParamName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '=' || r == ' ' || r == ']' || r == '"' || r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// syslogParamValue escapes the characters of the values of the structured data parameters.
+func syslogParamValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
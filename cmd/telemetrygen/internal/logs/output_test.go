@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/log/logtest"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+func testRecord(t *testing.T) sdklog.Record {
+	res, err := resource.New(context.Background(), resource.WithAttributes(
+		attribute.String("service.name", "telemetrygen"),
+		attribute.String("host.name", "host"),
+	))
+	require.NoError(t, err)
+
+	return logtest.RecordFactory{
+		Timestamp:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Severity:     log.SeverityWarn,
+		SeverityText: "Warn",
+		Body:         log.StringValue("the message"),
+		Attributes:   []log.KeyValue{log.String("app", "server"), log.String("quote", `a "b"]`)},
+		TraceID:      trace.TraceID{1},
+		SpanID:       trace.SpanID{2},
+		Resource:     res,
+	}.NewRecord()
+}
+
+func TestToPdata(t *testing.T) {
+	ld := toPdata([]sdklog.Record{testRecord(t)})
+
+	require.Equal(t, 1, ld.LogRecordCount())
+	rl := ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{"service.name": "telemetrygen", "host.name": "host"}, rl.Resource().Attributes().AsRaw())
+	lr := rl.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, plog.SeverityNumberWarn, lr.SeverityNumber())
+	assert.Equal(t, "Warn", lr.SeverityText())
+	assert.Equal(t, "the message", lr.Body().Str())
+	assert.Equal(t, map[string]any{"app": "server", "quote": `a "b"]`}, lr.Attributes().AsRaw())
+	assert.Equal(t, [16]byte{1}, [16]byte(lr.TraceID()))
+	assert.Equal(t, [8]byte{2}, [8]byte(lr.SpanID()))
+}
+
+func TestSyslogMessages(t *testing.T) {
+	messages := syslogMessages(toPdata([]sdklog.Record{testRecord(t)}))
+
+	require.Len(t, messages, 1)
+	assert.Equal(t, `<12>1 2024-01-02T03:04:05Z host telemetrygen - - [attributes@32473 app="server" quote="a \"b\"\]"] the message`, messages[0])
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	assert.Equal(t, 7, syslogSeverity(plog.SeverityNumberTrace))
+	assert.Equal(t, 7, syslogSeverity(plog.SeverityNumberDebug4))
+	assert.Equal(t, 6, syslogSeverity(plog.SeverityNumberUnspecified))
+	assert.Equal(t, 6, syslogSeverity(plog.SeverityNumberInfo))
+	assert.Equal(t, 4, syslogSeverity(plog.SeverityNumberWarn2))
+	assert.Equal(t, 3, syslogSeverity(plog.SeverityNumberError))
+	assert.Equal(t, 2, syslogSeverity(plog.SeverityNumberFatal4))
+}
+
+func TestSyslogOutput(t *testing.T) {
+	for _, octetCounting := range []bool{false, true} {
+		ln, err := net.Listen("tcp", "localhost:0")
+		require.NoError(t, err)
+
+		received := make(chan string, 1)
+		go func() {
+			conn, err := ln.Accept()
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if octetCounting {
+				// the messages aren't delimited, read until the connection is closed
+				assert.ErrorContains(t, err, "EOF")
+			} else {
+				assert.NoError(t, err)
+			}
+			received <- line
+		}()
+
+		cfg := &Config{
+			Config: common.Config{
+				CustomEndpoint: ln.Addr().String(),
+				Output:         common.OutputSyslog,
+			},
+			SyslogTransport:     syslogTransportTCP,
+			SyslogOctetCounting: octetCounting,
+		}
+		exp, err := newOutputExporter(cfg)
+		require.NoError(t, err)
+		require.NoError(t, exp.Export(context.Background(), []sdklog.Record{testRecord(t)}))
+		require.NoError(t, exp.Shutdown(context.Background()))
+
+		msg := <-received
+		if octetCounting {
+			assert.Regexp(t, `^\d+ <12>1 .* the message$`, msg)
+		} else {
+			assert.Regexp(t, `^<12>1 .* the message\n$`, msg)
+		}
+		require.NoError(t, ln.Close())
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	cfg := &Config{
+		Config: common.Config{
+			Output: common.OutputSyslog,
+		},
+		NumLogs:         1,
+		SyslogTransport: "quic",
+	}
+	assert.EqualError(t, cfg.Validate(), `expected `+"`syslog-transport`"+` to be one of (tcp, udp), got "quic" instead`)
+
+	cfg.Output = common.OutputPrometheusRemoteWrite
+	assert.EqualError(t, cfg.Validate(), `expected `+"`output`"+` to be one of (otlp, kafka, syslog), got "prometheusremotewrite" instead`)
+}
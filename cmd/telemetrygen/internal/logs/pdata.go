@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logs
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// toPdata converts the log records generated by the workers to pdata, to encode them in another wire format than OTLP.
+func toPdata(records []sdklog.Record) plog.Logs {
+	ld := plog.NewLogs()
+	for i := range records {
+		r := &records[i]
+		rl := ld.ResourceLogs().AppendEmpty()
+		res := r.Resource()
+		rl.SetSchemaUrl(res.SchemaURL())
+		for iter := res.Iter(); iter.Next(); {
+			kv := iter.Attribute()
+			switch kv.Value.Type() {
+			case attribute.BOOL:
+				rl.Resource().Attributes().PutBool(string(kv.Key), kv.Value.AsBool())
+			case attribute.INT64:
+				rl.Resource().Attributes().PutInt(string(kv.Key), kv.Value.AsInt64())
+			case attribute.FLOAT64:
+				rl.Resource().Attributes().PutDouble(string(kv.Key), kv.Value.AsFloat64())
+			default:
+				rl.Resource().Attributes().PutStr(string(kv.Key), kv.Value.Emit())
+			}
+		}
+
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName(r.InstrumentationScope().Name)
+		sl.Scope().SetVersion(r.InstrumentationScope().Version)
+
+		lr := sl.LogRecords().AppendEmpty()
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(r.Timestamp()))
+		lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(r.ObservedTimestamp()))
+		lr.SetSeverityNumber(plog.SeverityNumber(r.Severity()))
+		lr.SetSeverityText(r.SeverityText())
+		putValue(lr.Body(), r.Body())
+		r.WalkAttributes(func(kv log.KeyValue) bool {
+			putValue(lr.Attributes().PutEmpty(kv.Key), kv.Value)
+			return true
+		})
+		lr.SetDroppedAttributesCount(uint32(r.DroppedAttributes()))
+		lr.SetTraceID(pcommon.TraceID(r.TraceID()))
+		lr.SetSpanID(pcommon.SpanID(r.SpanID()))
+		lr.SetFlags(plog.LogRecordFlags(r.TraceFlags()))
+	}
+	return ld
+}
+
+func putValue(dest pcommon.Value, v log.Value) {
+	switch v.Kind() {
+	case log.KindBool:
+		dest.SetBool(v.AsBool())
+	case log.KindFloat64:
+		dest.SetDouble(v.AsFloat64())
+	case log.KindInt64:
+		dest.SetInt(v.AsInt64())
+	case log.KindString:
+		dest.SetStr(v.AsString())
+	case log.KindBytes:
+		dest.SetEmptyBytes().FromRaw(v.AsBytes())
+	case log.KindSlice:
+		s := dest.SetEmptySlice()
+		for _, item := range v.AsSlice() {
+			putValue(s.AppendEmpty(), item)
+		}
+	case log.KindMap:
+		m := dest.SetEmptyMap()
+		for _, kv := range v.AsMap() {
+			putValue(m.PutEmpty(kv.Key), kv.Value)
+		}
+	}
+}
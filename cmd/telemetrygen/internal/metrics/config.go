@@ -4,6 +4,7 @@
 package metrics
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/pflag"
@@ -19,6 +20,23 @@ type Config struct {
 	MetricType metricType
 	SpanID     string
 	TraceID    string
+
+	AggregationTemporality aggregationTemporality
+	NumSeries              int
+	SeriesChurn            float64
+	ResetEvery             int
+
+	Distribution       distribution
+	DistributionMean   float64
+	DistributionStdDev float64
+	Observations       int
+
+	HistogramBuckets             []float64
+	ExponentialHistogramMaxSize  int
+	ExponentialHistogramMaxScale int
+	SummaryQuantiles             []float64
+
+	PrometheusRemoteWriteVersion remoteWriteVersion
 }
 
 // Flags registers config flags.
@@ -26,16 +44,36 @@ func (c *Config) Flags(fs *pflag.FlagSet) {
 	// Use Gauge as default metric type.
 	c.MetricName = "gen"
 	c.MetricType = metricTypeGauge
+	c.AggregationTemporality = temporalityCumulative
+	c.Distribution = distributionNormal
+	c.PrometheusRemoteWriteVersion = remoteWriteVersion1
 
 	c.CommonFlags(fs)
 
 	fs.StringVar(&c.HTTPPath, "otlp-http-url-path", "/v1/metrics", "Which URL path to write to")
 
-	fs.Var(&c.MetricType, "metric-type", "Metric type enum. must be one of 'Gauge' or 'Sum'")
+	fs.Var(&c.MetricType, "metric-type", "Metric type enum. must be one of 'Gauge', 'Sum', 'Histogram', 'ExponentialHistogram' or 'Summary'")
 	fs.IntVar(&c.NumMetrics, "metrics", 1, "Number of metrics to generate in each worker (ignored if duration is provided)")
 
 	fs.StringVar(&c.TraceID, "trace-id", "", "TraceID to use as exemplar")
 	fs.StringVar(&c.SpanID, "span-id", "", "SpanID to use as exemplar")
+
+	fs.Var(&c.AggregationTemporality, "aggregation-temporality", "Aggregation temporality of the Sum, Histogram and ExponentialHistogram metrics. must be one of 'cumulative' or 'delta'")
+	fs.IntVar(&c.NumSeries, "series", 1, "Number of series of the metric generated by each worker, told apart by their `series.id` attribute")
+	fs.Float64Var(&c.SeriesChurn, "series-churn", 0, "Fraction of the series, between 0 and 1, replaced by new series at each export")
+	fs.IntVar(&c.ResetEvery, "reset-every", 0, "Restart the cumulative series, with a new start time, every N exports (0 never restarts them)")
+
+	fs.Var(&c.Distribution, "distribution", "Distribution of the values observed by the Histogram, ExponentialHistogram and Summary metrics. must be one of 'uniform', 'normal' or 'exponential'")
+	fs.Float64Var(&c.DistributionMean, "distribution-mean", 100, "Mean of the distribution of the observed values")
+	fs.Float64Var(&c.DistributionStdDev, "distribution-stddev", 25, "Standard deviation of the normal distribution of the observed values")
+	fs.IntVar(&c.Observations, "observations", 10, "Number of values observed by each series of the Histogram, ExponentialHistogram and Summary metrics at each export")
+
+	fs.Float64SliceVar(&c.HistogramBuckets, "histogram-buckets", []float64{0, 5, 10, 25, 50, 75, 100, 250, 500, 750, 1000, 2500, 5000, 7500, 10000}, "Explicit bucket boundaries of the Histogram metrics")
+	fs.IntVar(&c.ExponentialHistogramMaxSize, "exponential-histogram-max-size", 160, "Maximum number of buckets of the ExponentialHistogram metrics")
+	fs.IntVar(&c.ExponentialHistogramMaxScale, "exponential-histogram-max-scale", 20, "Maximum scale of the ExponentialHistogram metrics, between -10 and 20")
+	fs.Float64SliceVar(&c.SummaryQuantiles, "summary-quantiles", []float64{0.5, 0.9, 0.99}, "Quantiles of the Summary metrics")
+
+	fs.Var(&c.PrometheusRemoteWriteVersion, "prometheus-remote-write-version", "Version of the Prometheus remote write protocol of the 'prometheusremotewrite' output. must be one of '1.0' or '2.0'")
 }
 
 // Validate validates the test scenario parameters.
@@ -56,5 +94,45 @@ func (c *Config) Validate() error {
 		}
 	}
 
-	return nil
+	if c.NumSeries < 0 {
+		return errors.New("`series` must not be negative")
+	}
+
+	if c.SeriesChurn < 0 || c.SeriesChurn > 1 {
+		return errors.New("`series-churn` must be between 0 and 1")
+	}
+
+	if c.ResetEvery < 0 {
+		return errors.New("`reset-every` must not be negative")
+	}
+
+	if c.DistributionMean < 0 || c.DistributionStdDev < 0 {
+		return errors.New("`distribution-mean` and `distribution-stddev` must not be negative")
+	}
+
+	if c.Observations < 0 {
+		return errors.New("`observations` must not be negative")
+	}
+
+	for i := 1; i < len(c.HistogramBuckets); i++ {
+		if c.HistogramBuckets[i] <= c.HistogramBuckets[i-1] {
+			return errors.New("`histogram-buckets` must be in strictly increasing order")
+		}
+	}
+
+	if c.ExponentialHistogramMaxScale < -10 || c.ExponentialHistogramMaxScale > 20 {
+		return errors.New("`exponential-histogram-max-scale` must be between -10 and 20")
+	}
+
+	if c.MetricType == metricTypeExponentialHistogram && c.ExponentialHistogramMaxSize < 2 {
+		return errors.New("`exponential-histogram-max-size` must be at least 2")
+	}
+
+	for _, q := range c.SummaryQuantiles {
+		if q < 0 || q > 1 {
+			return errors.New("`summary-quantiles` must be between 0 and 1")
+		}
+	}
+
+	return common.ValidateOutput(c.Output, common.OutputPrometheusRemoteWrite)
 }
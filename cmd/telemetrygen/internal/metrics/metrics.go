@@ -33,7 +33,14 @@ func Start(cfg *Config) error {
 
 	expFunc := func() (sdkmetric.Exporter, error) {
 		var exp sdkmetric.Exporter
-		if cfg.UseHTTP {
+		switch {
+		case cfg.Output != "" && cfg.Output != common.OutputOTLP:
+			logger.Info("starting the exporter", zap.String("output", cfg.Output))
+			exp, err = newOutputExporter(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to obtain the %s exporter: %w", cfg.Output, err)
+			}
+		case cfg.UseHTTP:
 			var exporterOpts []otlpmetrichttp.Option
 
 			logger.Info("starting HTTP exporter")
@@ -45,7 +52,7 @@ func Start(cfg *Config) error {
 			if err != nil {
 				return nil, fmt.Errorf("failed to obtain OTLP HTTP exporter: %w", err)
 			}
-		} else {
+		default:
 			var exporterOpts []otlpmetricgrpc.Option
 
 			logger.Info("starting gRPC exporter")
@@ -99,6 +106,7 @@ func Run(c *Config, exp func() (sdkmetric.Exporter, error), logger *zap.Logger)
 			metricName:     c.MetricName,
 			metricType:     c.MetricType,
 			exemplars:      exemplarsFromConfig(c),
+			series:         seriesOptionsFromConfig(c),
 			limitPerSecond: limit,
 			totalDuration:  c.TotalDuration,
 			running:        running,
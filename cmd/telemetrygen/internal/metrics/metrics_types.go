@@ -10,8 +10,11 @@ import (
 type metricType string
 
 const (
-	metricTypeGauge = "Gauge"
-	metricTypeSum   = "Sum"
+	metricTypeGauge                = "Gauge"
+	metricTypeSum                  = "Sum"
+	metricTypeHistogram            = "Histogram"
+	metricTypeExponentialHistogram = "ExponentialHistogram"
+	metricTypeSummary              = "Summary"
 )
 
 // String is used both by fmt.Print and by Cobra in help text
@@ -22,11 +25,11 @@ func (e *metricType) String() string {
 // Set must have pointer receiver so it doesn't change the value of a copy
 func (e *metricType) Set(v string) error {
 	switch v {
-	case "Gauge", "Sum":
+	case metricTypeGauge, metricTypeSum, metricTypeHistogram, metricTypeExponentialHistogram, metricTypeSummary:
 		*e = metricType(v)
 		return nil
 	default:
-		return errors.New(`must be one of "Gauge" or "Sum"`)
+		return errors.New(`must be one of "Gauge", "Sum", "Histogram", "ExponentialHistogram" or "Summary"`)
 	}
 }
 
@@ -34,3 +37,88 @@ func (e *metricType) Set(v string) error {
 func (e *metricType) Type() string {
 	return "metricType"
 }
+
+type aggregationTemporality string
+
+const (
+	temporalityCumulative = "cumulative"
+	temporalityDelta      = "delta"
+)
+
+// String is used both by fmt.Print and by Cobra in help text
+func (t *aggregationTemporality) String() string {
+	return string(*t)
+}
+
+// Set must have pointer receiver so it doesn't change the value of a copy
+func (t *aggregationTemporality) Set(v string) error {
+	switch v {
+	case temporalityCumulative, temporalityDelta:
+		*t = aggregationTemporality(v)
+		return nil
+	default:
+		return errors.New(`must be one of "cumulative" or "delta"`)
+	}
+}
+
+// Type is only used in help text
+func (t *aggregationTemporality) Type() string {
+	return "temporality"
+}
+
+type distribution string
+
+const (
+	distributionUniform     = "uniform"
+	distributionNormal      = "normal"
+	distributionExponential = "exponential"
+)
+
+// String is used both by fmt.Print and by Cobra in help text
+func (d *distribution) String() string {
+	return string(*d)
+}
+
+// Set must have pointer receiver so it doesn't change the value of a copy
+func (d *distribution) Set(v string) error {
+	switch v {
+	case distributionUniform, distributionNormal, distributionExponential:
+		*d = distribution(v)
+		return nil
+	default:
+		return errors.New(`must be one of "uniform", "normal" or "exponential"`)
+	}
+}
+
+// Type is only used in help text
+func (d *distribution) Type() string {
+	return "distribution"
+}
+
+type remoteWriteVersion string
+
+const (
+	remoteWriteVersion1 = "1.0"
+	remoteWriteVersion2 = "2.0"
+)
+
+// String is used both by fmt.Print and by Cobra in help text
+func (v *remoteWriteVersion) String() string {
+	return string(*v)
+}
+
+// Set must have pointer receiver so it doesn't change the value of a copy
+func (v *remoteWriteVersion) Set(s string) error {
+	switch s {
+	case remoteWriteVersion1, remoteWriteVersion2:
+		*v = remoteWriteVersion(s)
+		return nil
+	default:
+		return errors.New(`must be one of "1.0" or "2.0"`)
+	}
+}
+
+// Type is only used in help text
+func (v *remoteWriteVersion) Type() string {
+	return "remoteWriteVersion"
+}
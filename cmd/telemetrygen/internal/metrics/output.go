@@ -0,0 +1,281 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/snappy"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+const (
+	defaultKafkaTopic                    = "otlp_metrics"
+	defaultPrometheusRemoteWriteEndpoint = "localhost:9090"
+	prometheusRemoteWritePath            = "/api/v1/write"
+
+	// the highest schema of the Prometheus native histograms
+	maxNativeHistogramSchema = 8
+)
+
+// newOutputExporter creates an exporter sending the metrics with the output of the configuration, other than OTLP.
+func newOutputExporter(cfg *Config) (sdkmetric.Exporter, error) {
+	switch cfg.Output {
+	case common.OutputKafka:
+		sender, err := common.NewKafkaSender(&cfg.Config, defaultKafkaTopic)
+		if err != nil {
+			return nil, err
+		}
+		marshaler := &pmetric.ProtoMarshaler{}
+		return &pdataExporter{
+			push: func(ctx context.Context, md pmetric.Metrics) error {
+				payload, err := marshaler.MarshalMetrics(md)
+				if err != nil {
+					return err
+				}
+				return sender.Send(ctx, payload)
+			},
+			close: sender.Close,
+		}, nil
+	case common.OutputPrometheusRemoteWrite:
+		headers := map[string]string{
+			"Content-Encoding":                  "snappy",
+			"Content-Type":                      "application/x-protobuf",
+			"X-Prometheus-Remote-Write-Version": "0.1.0",
+		}
+		marshal := (*writeRequest).Marshal
+		if cfg.PrometheusRemoteWriteVersion == remoteWriteVersion2 {
+			headers["Content-Type"] = "application/x-protobuf;proto=io.prometheus.write.v2.Request"
+			headers["X-Prometheus-Remote-Write-Version"] = "2.0.0"
+			marshal = (*writeRequest).MarshalV2
+		}
+		sender, err := common.NewHTTPSender(&cfg.Config, defaultPrometheusRemoteWriteEndpoint, prometheusRemoteWritePath, headers)
+		if err != nil {
+			return nil, err
+		}
+		return &pdataExporter{
+			push: func(ctx context.Context, md pmetric.Metrics) error {
+				return sender.Send(ctx, snappy.Encode(nil, marshal(prometheusWriteRequest(md))))
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output %q", cfg.Output)
+	}
+}
+
+var _ sdkmetric.Exporter = (*pdataExporter)(nil)
+
+// pdataExporter pushes the metrics as pdata, to encode them in another wire format than OTLP.
+type pdataExporter struct {
+	push  func(context.Context, pmetric.Metrics) error
+	close func() error
+}
+
+func (e *pdataExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(kind)
+}
+
+func (e *pdataExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+func (e *pdataExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	return e.push(ctx, toPdata(rm))
+}
+
+func (e *pdataExporter) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (e *pdataExporter) Shutdown(context.Context) error {
+	if e.close == nil {
+		return nil
+	}
+	return e.close()
+}
+
+// prometheusWriteRequest converts the metrics to Prometheus time series: the histograms to classic buckets, the
+// exponential histograms to native histograms, and the summaries to quantiles. The `service.name` and
+// `service.instance.id` resource attributes are the `job` and `instance` labels.
+func prometheusWriteRequest(md pmetric.Metrics) *writeRequest {
+	req := &writeRequest{}
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		var resourceLabels []label
+		if v, ok := rm.Resource().Attributes().Get("service.name"); ok {
+			resourceLabels = append(resourceLabels, label{Name: "job", Value: v.AsString()})
+		}
+		if v, ok := rm.Resource().Attributes().Get("service.instance.id"); ok {
+			resourceLabels = append(resourceLabels, label{Name: "instance", Value: v.AsString()})
+		}
+
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				req.Timeseries = append(req.Timeseries, prometheusTimeSeries(sm.Metrics().At(k), resourceLabels)...)
+			}
+		}
+	}
+	return req
+}
+
+func prometheusTimeSeries(metric pmetric.Metric, resourceLabels []label) []timeSeries {
+	var series []timeSeries
+	name := sanitizePrometheusName(metric.Name(), true)
+	md := metadata{Help: metric.Description(), Unit: metric.Unit()}
+	addSample := func(name string, attrs pcommon.Map, value float64, ts pcommon.Timestamp, extra ...label) {
+		series = append(series, timeSeries{
+			Labels:   prometheusLabels(name, attrs, resourceLabels, extra...),
+			Samples:  []sample{{Value: value, Timestamp: ts.AsTime().UnixMilli()}},
+			Metadata: md,
+		})
+	}
+
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		md.Type = metadataTypeGauge
+		for i := 0; i < metric.Gauge().DataPoints().Len(); i++ {
+			dp := metric.Gauge().DataPoints().At(i)
+			addSample(name, dp.Attributes(), numberValue(dp), dp.Timestamp())
+		}
+	case pmetric.MetricTypeSum:
+		md.Type = metadataTypeGauge
+		if metric.Sum().IsMonotonic() {
+			md.Type = metadataTypeCounter
+			if !strings.HasSuffix(name, "_total") {
+				name += "_total"
+			}
+		}
+		for i := 0; i < metric.Sum().DataPoints().Len(); i++ {
+			dp := metric.Sum().DataPoints().At(i)
+			addSample(name, dp.Attributes(), numberValue(dp), dp.Timestamp())
+		}
+	case pmetric.MetricTypeHistogram:
+		md.Type = metadataTypeHistogram
+		for i := 0; i < metric.Histogram().DataPoints().Len(); i++ {
+			dp := metric.Histogram().DataPoints().At(i)
+			var cumulative uint64
+			for b := 0; b < dp.BucketCounts().Len(); b++ {
+				cumulative += dp.BucketCounts().At(b)
+				le := math.Inf(1)
+				if b < dp.ExplicitBounds().Len() {
+					le = dp.ExplicitBounds().At(b)
+				}
+				addSample(name+"_bucket", dp.Attributes(), float64(cumulative), dp.Timestamp(),
+					label{Name: "le", Value: strconv.FormatFloat(le, 'f', -1, 64)})
+			}
+			addSample(name+"_sum", dp.Attributes(), dp.Sum(), dp.Timestamp())
+			addSample(name+"_count", dp.Attributes(), float64(dp.Count()), dp.Timestamp())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		md.Type = metadataTypeHistogram
+		for i := 0; i < metric.ExponentialHistogram().DataPoints().Len(); i++ {
+			dp := metric.ExponentialHistogram().DataPoints().At(i)
+			series = append(series, timeSeries{
+				Labels:     prometheusLabels(name, dp.Attributes(), resourceLabels),
+				Histograms: []histogram{nativeHistogram(dp)},
+				Metadata:   md,
+			})
+		}
+	case pmetric.MetricTypeSummary:
+		md.Type = metadataTypeSummary
+		for i := 0; i < metric.Summary().DataPoints().Len(); i++ {
+			dp := metric.Summary().DataPoints().At(i)
+			for q := 0; q < dp.QuantileValues().Len(); q++ {
+				qv := dp.QuantileValues().At(q)
+				addSample(name, dp.Attributes(), qv.Value(), dp.Timestamp(),
+					label{Name: "quantile", Value: strconv.FormatFloat(qv.Quantile(), 'f', -1, 64)})
+			}
+			addSample(name+"_sum", dp.Attributes(), dp.Sum(), dp.Timestamp())
+			addSample(name+"_count", dp.Attributes(), float64(dp.Count()), dp.Timestamp())
+		}
+	}
+	return series
+}
+
+// nativeHistogram converts the positive buckets of the exponential histogram to a native histogram, merging them when
+// their scale is higher than the highest schema of the native histograms.
+func nativeHistogram(dp pmetric.ExponentialHistogramDataPoint) histogram {
+	scale := dp.Scale()
+	offset := dp.Positive().Offset()
+	counts := dp.Positive().BucketCounts().AsRaw()
+	for ; scale > maxNativeHistogramSchema; scale-- {
+		if len(counts) == 0 {
+			continue
+		}
+		merged := make([]uint64, (offset+int32(len(counts))-1)>>1-offset>>1+1)
+		for b, count := range counts {
+			merged[(offset+int32(b))>>1-offset>>1] += count
+		}
+		offset >>= 1
+		counts = merged
+	}
+
+	h := histogram{
+		Count:     dp.Count(),
+		Sum:       dp.Sum(),
+		Schema:    scale,
+		ZeroCount: dp.ZeroCount(),
+		Timestamp: dp.Timestamp().AsTime().UnixMilli(),
+	}
+	if len(counts) > 0 {
+		// the bucket of index i holds the values in (base^(i-1), base^i] in Prometheus, and in (base^i, base^(i+1)] in OTLP
+		h.PositiveSpans = []bucketSpan{{Offset: offset + 1, Length: uint32(len(counts))}}
+		var previous int64
+		for _, count := range counts {
+			h.PositiveDeltas = append(h.PositiveDeltas, int64(count)-previous)
+			previous = int64(count)
+		}
+	}
+	return h
+}
+
+func numberValue(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
+
+func prometheusLabels(name string, attrs pcommon.Map, resourceLabels []label, extra ...label) []label {
+	labels := make([]label, 0, attrs.Len()+len(resourceLabels)+len(extra)+1)
+	labels = append(labels, label{Name: "__name__", Value: name})
+	labels = append(labels, resourceLabels...)
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		labels = append(labels, label{Name: sanitizePrometheusName(k, false), Value: v.AsString()})
+		return true
+	})
+	labels = append(labels, extra...)
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
+
+// sanitizePrometheusName replaces the characters that are invalid in the metric names, or in the label names, by
+// underscores.
+func sanitizePrometheusName(name string, metricName bool) string {
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r == ':' && metricName:
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	if sanitized != "" && sanitized[0] >= '0' && sanitized[0] <= '9' {
+		sanitized = "_" + sanitized
+	}
+	return sanitized
+}
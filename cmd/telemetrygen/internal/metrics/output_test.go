@@ -0,0 +1,272 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+func TestToPdata(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	attrs := attribute.NewSet(attribute.String("k", "v"))
+	rm := &metricdata.ResourceMetrics{
+		Resource: resource.NewSchemaless(attribute.String("service.name", "telemetrygen")),
+		ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{
+			{
+				Name: "sum",
+				Data: metricdata.Sum[int64]{
+					IsMonotonic: true,
+					Temporality: metricdata.DeltaTemporality,
+					DataPoints:  []metricdata.DataPoint[int64]{{StartTime: now.Add(-time.Second), Time: now, Value: 3, Attributes: attrs}},
+				},
+			},
+			{
+				Name: "histogram",
+				Data: metricdata.Histogram[float64]{
+					Temporality: metricdata.CumulativeTemporality,
+					DataPoints: []metricdata.HistogramDataPoint[float64]{{
+						Time: now, Count: 3, Sum: 12, Bounds: []float64{5}, BucketCounts: []uint64{1, 2},
+						Min: metricdata.NewExtrema(1.0), Max: metricdata.NewExtrema(8.0),
+						Exemplars: []metricdata.Exemplar[float64]{{Value: 8, TraceID: make([]byte, 16), SpanID: make([]byte, 8)}},
+					}},
+				},
+			},
+			{
+				Name: "exponential",
+				Data: metricdata.ExponentialHistogram[float64]{
+					DataPoints: []metricdata.ExponentialHistogramDataPoint[float64]{{
+						Time: now, Count: 3, Scale: 2, ZeroCount: 1,
+						PositiveBucket: metricdata.ExponentialBucket{Offset: 4, Counts: []uint64{1, 1}},
+					}},
+				},
+			},
+			{
+				Name: "summary",
+				Data: metricdata.Summary{
+					DataPoints: []metricdata.SummaryDataPoint{{
+						Time: now, Count: 2, Sum: 3,
+						QuantileValues: []metricdata.QuantileValue{{Quantile: 0.5, Value: 1.5}},
+					}},
+				},
+			},
+		}}},
+	}
+
+	md := toPdata(rm)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	serviceName, _ := md.ResourceMetrics().At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "telemetrygen", serviceName.Str())
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 4, metrics.Len())
+
+	sum := metrics.At(0).Sum()
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, sum.AggregationTemporality())
+	assert.Equal(t, int64(3), sum.DataPoints().At(0).IntValue())
+	assert.Equal(t, pcommon.NewTimestampFromTime(now.Add(-time.Second)), sum.DataPoints().At(0).StartTimestamp())
+	assert.Equal(t, map[string]any{"k": "v"}, sum.DataPoints().At(0).Attributes().AsRaw())
+
+	hist := metrics.At(1).Histogram().DataPoints().At(0)
+	assert.Equal(t, []uint64{1, 2}, hist.BucketCounts().AsRaw())
+	assert.Equal(t, []float64{5}, hist.ExplicitBounds().AsRaw())
+	assert.Equal(t, 1.0, hist.Min())
+	assert.Equal(t, 8.0, hist.Max())
+	assert.Equal(t, 8.0, hist.Exemplars().At(0).DoubleValue())
+
+	exp := metrics.At(2).ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, int32(2), exp.Scale())
+	assert.Equal(t, int32(4), exp.Positive().Offset())
+	assert.Equal(t, uint64(1), exp.ZeroCount())
+
+	summary := metrics.At(3).Summary().DataPoints().At(0)
+	assert.Equal(t, 1.5, summary.QuantileValues().At(0).Value())
+}
+
+func TestPrometheusWriteRequest(t *testing.T) {
+	ts := pcommon.NewTimestampFromTime(time.UnixMilli(1700000000000))
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "telemetrygen")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	sum := metrics.AppendEmpty()
+	sum.SetName("gen.requests")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	dp := sum.Sum().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetIntValue(4)
+	dp.Attributes().PutStr("series.id", "1")
+
+	hist := metrics.AppendEmpty()
+	hist.SetName("latency")
+	hdp := hist.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(ts)
+	hdp.SetCount(3)
+	hdp.SetSum(12)
+	hdp.ExplicitBounds().FromRaw([]float64{5})
+	hdp.BucketCounts().FromRaw([]uint64{1, 2})
+
+	summary := metrics.AppendEmpty()
+	summary.SetName("size")
+	sdp := summary.SetEmptySummary().DataPoints().AppendEmpty()
+	sdp.SetTimestamp(ts)
+	sdp.SetCount(2)
+	sdp.SetSum(3)
+	q := sdp.QuantileValues().AppendEmpty()
+	q.SetQuantile(0.5)
+	q.SetValue(1.5)
+
+	req := prometheusWriteRequest(md)
+	samples := map[string]float64{}
+	types := map[string]metadataType{}
+	for _, series := range req.Timeseries {
+		var name []string
+		for _, l := range series.Labels {
+			name = append(name, l.Name+"="+l.Value)
+		}
+		require.Len(t, series.Samples, 1)
+		assert.Equal(t, int64(1700000000000), series.Samples[0].Timestamp)
+		samples[strings.Join(name, ",")] = series.Samples[0].Value
+		types[series.Labels[0].Value] = series.Metadata.Type
+	}
+	assert.Equal(t, map[string]float64{
+		"__name__=gen_requests_total,job=telemetrygen,series_id=1": 4,
+		"__name__=latency_bucket,job=telemetrygen,le=5":            1,
+		"__name__=latency_bucket,job=telemetrygen,le=+Inf":         3,
+		"__name__=latency_sum,job=telemetrygen":                    12,
+		"__name__=latency_count,job=telemetrygen":                  3,
+		"__name__=size,job=telemetrygen,quantile=0.5":              1.5,
+		"__name__=size_sum,job=telemetrygen":                       3,
+		"__name__=size_count,job=telemetrygen":                     2,
+	}, samples)
+	assert.Equal(t, map[string]metadataType{
+		"gen_requests_total": metadataTypeCounter,
+		"latency_bucket":     metadataTypeHistogram,
+		"latency_sum":        metadataTypeHistogram,
+		"latency_count":      metadataTypeHistogram,
+		"size":               metadataTypeSummary,
+		"size_sum":           metadataTypeSummary,
+		"size_count":         metadataTypeSummary,
+	}, types)
+}
+
+func TestNativeHistogram(t *testing.T) {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(10)
+	dp.SetCount(7)
+	dp.SetZeroCount(1)
+	dp.Positive().SetOffset(-3)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1, 2, 0, 3})
+
+	h := nativeHistogram(dp)
+	// the buckets of index -3 to 0 at the scale 10 merge into the buckets of index -1 and 0 at the schema 8
+	assert.Equal(t, int32(8), h.Schema)
+	assert.Equal(t, uint64(7), h.Count)
+	assert.Equal(t, uint64(1), h.ZeroCount)
+	assert.Equal(t, []bucketSpan{{Offset: 0, Length: 2}}, h.PositiveSpans)
+	assert.Equal(t, []int64{3, 0}, h.PositiveDeltas)
+}
+
+func TestPrometheusRemoteWriteOutput(t *testing.T) {
+	received := make(chan *writeRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, prometheusRemoteWritePath, r.URL.Path)
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		assert.Equal(t, "0.1.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		decoded, err := snappy.Decode(nil, body)
+		assert.NoError(t, err)
+		received <- unmarshalWriteRequest(t, decoded)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		Config: common.Config{
+			CustomEndpoint: strings.TrimPrefix(srv.URL, "http://"),
+			Insecure:       true,
+			Output:         common.OutputPrometheusRemoteWrite,
+		},
+		PrometheusRemoteWriteVersion: remoteWriteVersion1,
+	}
+	exp, err := newOutputExporter(cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, exp.Export(context.Background(), &metricdata.ResourceMetrics{
+		Resource: resource.Empty(),
+		ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{{
+			Name: "gen",
+			Data: metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{{Time: time.Now(), Value: 2}}},
+		}}}},
+	}))
+	require.NoError(t, exp.Shutdown(context.Background()))
+
+	req := <-received
+	require.Len(t, req.Timeseries, 1)
+	assert.Equal(t, []label{{Name: "__name__", Value: "gen"}}, req.Timeseries[0].Labels)
+	assert.Equal(t, 2.0, req.Timeseries[0].Samples[0].Value)
+}
+
+func TestPrometheusRemoteWriteOutputV2(t *testing.T) {
+	received := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, prometheusRemoteWritePath, r.URL.Path)
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "application/x-protobuf;proto=io.prometheus.write.v2.Request", r.Header.Get("Content-Type"))
+		assert.Equal(t, "2.0.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		decoded, err := snappy.Decode(nil, body)
+		assert.NoError(t, err)
+		received <- decoded
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		Config: common.Config{
+			CustomEndpoint: strings.TrimPrefix(srv.URL, "http://"),
+			Insecure:       true,
+			Output:         common.OutputPrometheusRemoteWrite,
+		},
+		PrometheusRemoteWriteVersion: remoteWriteVersion2,
+	}
+	exp, err := newOutputExporter(cfg)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, exp.Export(context.Background(), &metricdata.ResourceMetrics{
+		Resource: resource.Empty(),
+		ScopeMetrics: []metricdata.ScopeMetrics{{Metrics: []metricdata.Metrics{{
+			Name: "gen",
+			Data: metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{{Time: now, Value: 2}}},
+		}}}},
+	}))
+	require.NoError(t, exp.Shutdown(context.Background()))
+
+	expected := &writeRequest{Timeseries: []timeSeries{{
+		Labels:   []label{{Name: "__name__", Value: "gen"}},
+		Samples:  []sample{{Value: 2, Timestamp: now.UnixMilli()}},
+		Metadata: metadata{Type: metadataTypeGauge},
+	}}}
+	assert.Equal(t, expected.MarshalV2(), <-received)
+}
@@ -0,0 +1,166 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// toPdata converts the metrics generated by the workers to pdata, to encode them in another wire format than OTLP.
+func toPdata(rm *metricdata.ResourceMetrics) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rms := md.ResourceMetrics().AppendEmpty()
+	if rm.Resource != nil {
+		rms.SetSchemaUrl(rm.Resource.SchemaURL())
+		putAttributes(rms.Resource().Attributes(), rm.Resource.Iter())
+	}
+
+	for _, sm := range rm.ScopeMetrics {
+		sms := rms.ScopeMetrics().AppendEmpty()
+		sms.Scope().SetName(sm.Scope.Name)
+		sms.Scope().SetVersion(sm.Scope.Version)
+		for _, m := range sm.Metrics {
+			metric := sms.Metrics().AppendEmpty()
+			metric.SetName(m.Name)
+			metric.SetDescription(m.Description)
+			metric.SetUnit(m.Unit)
+
+			switch data := m.Data.(type) {
+			case metricdata.Gauge[int64]:
+				gauge := metric.SetEmptyGauge()
+				for _, dp := range data.DataPoints {
+					putNumberDataPoint(gauge.DataPoints().AppendEmpty(), dp)
+				}
+			case metricdata.Sum[int64]:
+				sum := metric.SetEmptySum()
+				sum.SetIsMonotonic(data.IsMonotonic)
+				sum.SetAggregationTemporality(toPdataTemporality(data.Temporality))
+				for _, dp := range data.DataPoints {
+					putNumberDataPoint(sum.DataPoints().AppendEmpty(), dp)
+				}
+			case metricdata.Histogram[float64]:
+				hist := metric.SetEmptyHistogram()
+				hist.SetAggregationTemporality(toPdataTemporality(data.Temporality))
+				for _, dp := range data.DataPoints {
+					pdp := hist.DataPoints().AppendEmpty()
+					putAttributes(pdp.Attributes(), dp.Attributes.Iter())
+					pdp.SetStartTimestamp(toTimestamp(dp.StartTime))
+					pdp.SetTimestamp(toTimestamp(dp.Time))
+					pdp.SetCount(dp.Count)
+					pdp.SetSum(dp.Sum)
+					if v, ok := dp.Min.Value(); ok {
+						pdp.SetMin(v)
+					}
+					if v, ok := dp.Max.Value(); ok {
+						pdp.SetMax(v)
+					}
+					pdp.ExplicitBounds().FromRaw(dp.Bounds)
+					pdp.BucketCounts().FromRaw(dp.BucketCounts)
+					putExemplars(pdp.Exemplars(), dp.Exemplars)
+				}
+			case metricdata.ExponentialHistogram[float64]:
+				hist := metric.SetEmptyExponentialHistogram()
+				hist.SetAggregationTemporality(toPdataTemporality(data.Temporality))
+				for _, dp := range data.DataPoints {
+					pdp := hist.DataPoints().AppendEmpty()
+					putAttributes(pdp.Attributes(), dp.Attributes.Iter())
+					pdp.SetStartTimestamp(toTimestamp(dp.StartTime))
+					pdp.SetTimestamp(toTimestamp(dp.Time))
+					pdp.SetCount(dp.Count)
+					pdp.SetSum(dp.Sum)
+					if v, ok := dp.Min.Value(); ok {
+						pdp.SetMin(v)
+					}
+					if v, ok := dp.Max.Value(); ok {
+						pdp.SetMax(v)
+					}
+					pdp.SetScale(dp.Scale)
+					pdp.SetZeroCount(dp.ZeroCount)
+					pdp.Positive().SetOffset(dp.PositiveBucket.Offset)
+					pdp.Positive().BucketCounts().FromRaw(dp.PositiveBucket.Counts)
+					pdp.Negative().SetOffset(dp.NegativeBucket.Offset)
+					pdp.Negative().BucketCounts().FromRaw(dp.NegativeBucket.Counts)
+					putExemplars(pdp.Exemplars(), dp.Exemplars)
+				}
+			case metricdata.Summary:
+				summary := metric.SetEmptySummary()
+				for _, dp := range data.DataPoints {
+					pdp := summary.DataPoints().AppendEmpty()
+					putAttributes(pdp.Attributes(), dp.Attributes.Iter())
+					pdp.SetStartTimestamp(toTimestamp(dp.StartTime))
+					pdp.SetTimestamp(toTimestamp(dp.Time))
+					pdp.SetCount(dp.Count)
+					pdp.SetSum(dp.Sum)
+					for _, q := range dp.QuantileValues {
+						pq := pdp.QuantileValues().AppendEmpty()
+						pq.SetQuantile(q.Quantile)
+						pq.SetValue(q.Value)
+					}
+				}
+			}
+		}
+	}
+	return md
+}
+
+func putNumberDataPoint(pdp pmetric.NumberDataPoint, dp metricdata.DataPoint[int64]) {
+	putAttributes(pdp.Attributes(), dp.Attributes.Iter())
+	if !dp.StartTime.IsZero() {
+		pdp.SetStartTimestamp(toTimestamp(dp.StartTime))
+	}
+	pdp.SetTimestamp(toTimestamp(dp.Time))
+	pdp.SetIntValue(dp.Value)
+	putExemplars(pdp.Exemplars(), dp.Exemplars)
+}
+
+func putExemplars[N int64 | float64](pes pmetric.ExemplarSlice, exemplars []metricdata.Exemplar[N]) {
+	for _, e := range exemplars {
+		pe := pes.AppendEmpty()
+		pe.SetTimestamp(toTimestamp(e.Time))
+		switch v := any(e.Value).(type) {
+		case int64:
+			pe.SetIntValue(v)
+		case float64:
+			pe.SetDoubleValue(v)
+		}
+		if len(e.TraceID) == 16 {
+			pe.SetTraceID(pcommon.TraceID(e.TraceID))
+		}
+		if len(e.SpanID) == 8 {
+			pe.SetSpanID(pcommon.SpanID(e.SpanID))
+		}
+	}
+}
+
+func putAttributes(m pcommon.Map, iter attribute.Iterator) {
+	for iter.Next() {
+		kv := iter.Attribute()
+		switch kv.Value.Type() {
+		case attribute.BOOL:
+			m.PutBool(string(kv.Key), kv.Value.AsBool())
+		case attribute.INT64:
+			m.PutInt(string(kv.Key), kv.Value.AsInt64())
+		case attribute.FLOAT64:
+			m.PutDouble(string(kv.Key), kv.Value.AsFloat64())
+		default:
+			m.PutStr(string(kv.Key), kv.Value.Emit())
+		}
+	}
+}
+
+func toPdataTemporality(t metricdata.Temporality) pmetric.AggregationTemporality {
+	if t == metricdata.DeltaTemporality {
+		return pmetric.AggregationTemporalityDelta
+	}
+	return pmetric.AggregationTemporalityCumulative
+}
+
+func toTimestamp(t time.Time) pcommon.Timestamp {
+	return pcommon.NewTimestampFromTime(t)
+}
@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The types below hold the messages of the Prometheus remote write protocol that telemetrygen sends, as defined in
+// https://github.com/prometheus/prometheus/blob/main/prompb/types.proto for the 1.0 version, and in
+// https://github.com/prometheus/prometheus/blob/main/prompb/io/prometheus/write/v2/types.proto for the 2.0 version.
+// They are encoded with protowire rather than with the generated Go types, which would make telemetrygen depend on the
+// whole Prometheus server module.
+
+type writeRequest struct {
+	Timeseries []timeSeries
+}
+
+type timeSeries struct {
+	Labels     []label
+	Samples    []sample
+	Histograms []histogram
+	// Metadata is only sent with the 2.0 version.
+	Metadata metadata
+}
+
+// metadata describes the metric of a time series.
+type metadata struct {
+	Type metadataType
+	Help string
+	Unit string
+}
+
+// metadataType is the type of a metric in the 2.0 version.
+type metadataType uint64
+
+const (
+	metadataTypeCounter   metadataType = 1
+	metadataTypeGauge     metadataType = 2
+	metadataTypeHistogram metadataType = 3
+	metadataTypeSummary   metadataType = 5
+)
+
+type label struct {
+	Name  string
+	Value string
+}
+
+type sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+// histogram is a native histogram with integer counts.
+type histogram struct {
+	Count          uint64
+	Sum            float64
+	Schema         int32
+	ZeroCount      uint64
+	PositiveSpans  []bucketSpan
+	PositiveDeltas []int64
+	Timestamp      int64
+}
+
+type bucketSpan struct {
+	Offset int32
+	Length uint32
+}
+
+func (r *writeRequest) Marshal() []byte {
+	var b []byte
+	for _, ts := range r.Timeseries {
+		b = appendMessage(b, 1, ts.marshal())
+	}
+	return b
+}
+
+// MarshalV2 encodes the request as an io.prometheus.write.v2.Request, where the strings of the labels and of the
+// metadata are references to a table of symbols.
+func (r *writeRequest) MarshalV2() []byte {
+	symbols := newSymbolsTable()
+	var series []byte
+	for _, ts := range r.Timeseries {
+		series = appendMessage(series, 5, ts.marshalV2(symbols))
+	}
+
+	var b []byte
+	for _, symbol := range symbols.symbols {
+		// the symbols are encoded even when empty, the first one must be the empty string
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendString(b, symbol)
+	}
+	return append(b, series...)
+}
+
+func (ts *timeSeries) marshalV2(symbols *symbolsTable) []byte {
+	var b []byte
+	if len(ts.Labels) > 0 {
+		var refs []byte
+		for _, l := range ts.Labels {
+			refs = protowire.AppendVarint(refs, uint64(symbols.ref(l.Name)))
+			refs = protowire.AppendVarint(refs, uint64(symbols.ref(l.Value)))
+		}
+		b = appendMessage(b, 1, refs)
+	}
+	for _, s := range ts.Samples {
+		b = appendMessage(b, 2, s.marshal())
+	}
+	for _, h := range ts.Histograms {
+		b = appendMessage(b, 3, h.marshal())
+	}
+
+	var mb []byte
+	mb = appendVarint(mb, 1, uint64(ts.Metadata.Type))
+	if ts.Metadata.Help != "" {
+		mb = appendVarint(mb, 3, uint64(symbols.ref(ts.Metadata.Help)))
+	}
+	if ts.Metadata.Unit != "" {
+		mb = appendVarint(mb, 4, uint64(symbols.ref(ts.Metadata.Unit)))
+	}
+	if len(mb) > 0 {
+		b = appendMessage(b, 5, mb)
+	}
+	return b
+}
+
+// symbolsTable assigns the references of the strings of a 2.0 request.
+type symbolsTable struct {
+	symbols []string
+	refs    map[string]uint32
+}
+
+func newSymbolsTable() *symbolsTable {
+	return &symbolsTable{
+		symbols: []string{""},
+		refs:    map[string]uint32{"": 0},
+	}
+}
+
+// ref returns the reference of the string, adding it to the table if needed.
+func (t *symbolsTable) ref(s string) uint32 {
+	if ref, ok := t.refs[s]; ok {
+		return ref
+	}
+	ref := uint32(len(t.symbols))
+	t.symbols = append(t.symbols, s)
+	t.refs[s] = ref
+	return ref
+}
+
+func (ts *timeSeries) marshal() []byte {
+	var b []byte
+	for _, l := range ts.Labels {
+		var lb []byte
+		lb = appendString(lb, 1, l.Name)
+		lb = appendString(lb, 2, l.Value)
+		b = appendMessage(b, 1, lb)
+	}
+	for _, s := range ts.Samples {
+		b = appendMessage(b, 2, s.marshal())
+	}
+	for _, h := range ts.Histograms {
+		b = appendMessage(b, 4, h.marshal())
+	}
+	return b
+}
+
+func (s *sample) marshal() []byte {
+	var b []byte
+	b = appendDouble(b, 1, s.Value)
+	return appendVarint(b, 2, uint64(s.Timestamp))
+}
+
+// marshal encodes the histogram, whose fields have the same numbers in the 1.0 and 2.0 versions.
+func (h *histogram) marshal() []byte {
+	var b []byte
+	// count_int and zero_count_int are part of oneofs, so they are encoded even when zero
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, h.Count)
+	b = appendDouble(b, 3, h.Sum)
+	b = appendVarint(b, 4, protowire.EncodeZigZag(int64(h.Schema)))
+	b = protowire.AppendTag(b, 6, protowire.VarintType)
+	b = protowire.AppendVarint(b, h.ZeroCount)
+	for _, span := range h.PositiveSpans {
+		var sb []byte
+		sb = appendVarint(sb, 1, protowire.EncodeZigZag(int64(span.Offset)))
+		sb = appendVarint(sb, 2, uint64(span.Length))
+		b = appendMessage(b, 11, sb)
+	}
+	if len(h.PositiveDeltas) > 0 {
+		var packed []byte
+		for _, delta := range h.PositiveDeltas {
+			packed = protowire.AppendVarint(packed, protowire.EncodeZigZag(delta))
+		}
+		b = appendMessage(b, 12, packed)
+	}
+	return appendVarint(b, 15, uint64(h.Timestamp))
+}
+
+// The append functions below skip the fields holding the zero value, as proto3 does.
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	if v == 0 && !math.Signbit(v) {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
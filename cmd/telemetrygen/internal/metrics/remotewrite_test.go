@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestWriteRequestMarshal(t *testing.T) {
+	req := &writeRequest{Timeseries: []timeSeries{
+		{
+			Labels:  []label{{Name: "__name__", Value: "gen"}, {Name: "job", Value: "telemetrygen"}},
+			Samples: []sample{{Value: 2.5, Timestamp: 1700000000000}},
+		},
+		{
+			Labels: []label{{Name: "__name__", Value: "exp"}},
+			Histograms: []histogram{{
+				Count:          7,
+				Sum:            12.5,
+				Schema:         -2,
+				PositiveSpans:  []bucketSpan{{Offset: -1, Length: 2}},
+				PositiveDeltas: []int64{3, -1},
+				Timestamp:      1700000000000,
+			}},
+		},
+	}}
+
+	// encoding of the same request by the Go types generated from the Prometheus remote write protocol
+	expected := "0a380a0f0a085f5f6e616d655f5f120367656e0a130a036a6f62120c74656c656d6574727967656e12100900000000000004401080d095ffbc31" +
+		"0a330a0f0a085f5f6e616d655f5f120365787022200807190000000000002940200330005a0408011002620206017880d095ffbc31"
+	assert.Equal(t, expected, hex.EncodeToString(req.Marshal()))
+}
+
+func TestWriteRequestMarshalV2(t *testing.T) {
+	req := &writeRequest{Timeseries: []timeSeries{
+		{
+			Labels:   []label{{Name: "__name__", Value: "gen_total"}, {Name: "job", Value: "telemetrygen"}},
+			Samples:  []sample{{Value: 2.5, Timestamp: 1700000000000}},
+			Metadata: metadata{Type: metadataTypeCounter, Help: "Generated", Unit: "s"},
+		},
+		{
+			Labels: []label{{Name: "__name__", Value: "exp"}, {Name: "job", Value: "telemetrygen"}},
+			Histograms: []histogram{{
+				Count:          7,
+				Sum:            12.5,
+				Schema:         -2,
+				PositiveSpans:  []bucketSpan{{Offset: -1, Length: 2}},
+				PositiveDeltas: []int64{3, -1},
+				Timestamp:      1700000000000,
+			}},
+			Metadata: metadata{Type: metadataTypeHistogram},
+		},
+	}}
+
+	// encoding of the same request by the Go types generated from the Prometheus remote write 2.0 protocol
+	expected := "220022085f5f6e616d655f5f220967656e5f746f74616c22036a6f62220c74656c656d6574727967656e220947656e6572617465642201732203657870" +
+		"2a200a040102030412100900000000000004401080d095ffbc312a060801180520062a2c0a04010703041a200807190000000000002940200330005a04080110026202" +
+		"06017880d095ffbc312a020803"
+	assert.Equal(t, expected, hex.EncodeToString(req.MarshalV2()))
+}
+
+// unmarshalWriteRequest decodes the labels and the samples of the time series of a remote write request.
+func unmarshalWriteRequest(t *testing.T, b []byte) *writeRequest {
+	req := &writeRequest{}
+	for _, tsBytes := range consumeMessages(t, b, 1) {
+		var ts timeSeries
+		for _, lb := range consumeMessages(t, tsBytes, 1) {
+			var l label
+			forEachField(t, lb, func(num protowire.Number, _ protowire.Type, field []byte) int {
+				value, n := protowire.ConsumeString(field)
+				if num == 1 {
+					l.Name = value
+				} else {
+					l.Value = value
+				}
+				return n
+			})
+			ts.Labels = append(ts.Labels, l)
+		}
+		for _, sb := range consumeMessages(t, tsBytes, 2) {
+			var s sample
+			forEachField(t, sb, func(num protowire.Number, _ protowire.Type, field []byte) int {
+				if num == 1 {
+					v, n := protowire.ConsumeFixed64(field)
+					s.Value = math.Float64frombits(v)
+					return n
+				}
+				v, n := protowire.ConsumeVarint(field)
+				s.Timestamp = int64(v)
+				return n
+			})
+			ts.Samples = append(ts.Samples, s)
+		}
+		req.Timeseries = append(req.Timeseries, ts)
+	}
+	return req
+}
+
+// consumeMessages returns the embedded messages of the given field number.
+func consumeMessages(t *testing.T, b []byte, want protowire.Number) [][]byte {
+	var messages [][]byte
+	forEachField(t, b, func(num protowire.Number, typ protowire.Type, field []byte) int {
+		if num == want && typ == protowire.BytesType {
+			msg, n := protowire.ConsumeBytes(field)
+			messages = append(messages, msg)
+			return n
+		}
+		return protowire.ConsumeFieldValue(num, typ, field)
+	})
+	return messages
+}
+
+func forEachField(t *testing.T, b []byte, fn func(num protowire.Number, typ protowire.Type, field []byte) int) {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+		n = fn(num, typ, b)
+		require.GreaterOrEqual(t, n, 0)
+		b = b[n:]
+	}
+}
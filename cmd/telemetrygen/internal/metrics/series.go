@@ -0,0 +1,336 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+const seriesIDKey = "series.id"
+
+// seriesOptions describes the series generated by each worker, and the values they observe.
+type seriesOptions struct {
+	temporality  aggregationTemporality
+	numSeries    int
+	churn        float64
+	resetEvery   int
+	distribution distribution
+	mean         float64
+	stdDev       float64
+	observations int
+	buckets      []float64
+	expMaxSize   int
+	expMaxScale  int32
+	quantiles    []float64
+}
+
+func seriesOptionsFromConfig(c *Config) seriesOptions {
+	return seriesOptions{
+		temporality:  c.AggregationTemporality,
+		numSeries:    max(c.NumSeries, 1),
+		churn:        c.SeriesChurn,
+		resetEvery:   c.ResetEvery,
+		distribution: c.Distribution,
+		mean:         c.DistributionMean,
+		stdDev:       c.DistributionStdDev,
+		observations: c.Observations,
+		buckets:      c.HistogramBuckets,
+		expMaxSize:   max(c.ExponentialHistogramMaxSize, 2),
+		expMaxScale:  int32(c.ExponentialHistogramMaxScale),
+		quantiles:    c.SummaryQuantiles,
+	}
+}
+
+func (o seriesOptions) delta() bool {
+	return o.temporality == temporalityDelta
+}
+
+func (o seriesOptions) metricdataTemporality() metricdata.Temporality {
+	if o.delta() {
+		return metricdata.DeltaTemporality
+	}
+	return metricdata.CumulativeTemporality
+}
+
+// series is the state of a series since its start time.
+type series struct {
+	attrs attribute.Set
+	start time.Time
+
+	exports uint64
+
+	count         uint64
+	sum, min, max float64
+	bucketCounts  []uint64
+	exponential   exponentialBuckets
+}
+
+func (s *series) restart(start time.Time, opts seriesOptions) {
+	s.start = start
+	s.exports = 0
+	s.count = 0
+	s.sum, s.min, s.max = 0, 0, 0
+	s.bucketCounts = make([]uint64, len(opts.buckets)+1)
+	s.exponential = newExponentialBuckets(opts.expMaxScale)
+}
+
+func (s *series) observe(v float64, opts seriesOptions) {
+	if s.count == 0 || v < s.min {
+		s.min = v
+	}
+	if s.count == 0 || v > s.max {
+		s.max = v
+	}
+	s.count++
+	s.sum += v
+	// the buckets are upper-inclusive
+	s.bucketCounts[sort.SearchFloat64s(opts.buckets, v)]++
+	s.exponential.record(v, opts.expMaxSize)
+}
+
+// seriesSet generates the data points of the series of a worker, replacing some of them at each export when they churn.
+type seriesSet struct {
+	opts      seriesOptions
+	rand      *rand.Rand
+	signal    []attribute.KeyValue
+	series    []*series
+	nextID    int
+	cursor    int
+	churnDebt float64
+}
+
+func newSeriesSet(opts seriesOptions, signalAttrs []attribute.KeyValue, seed uint64, now time.Time) *seriesSet {
+	s := &seriesSet{
+		opts:   opts,
+		rand:   rand.New(rand.NewPCG(seed, uint64(now.UnixNano()))),
+		signal: signalAttrs,
+	}
+	for i := 0; i < opts.numSeries; i++ {
+		s.series = append(s.series, s.newSeries(now))
+	}
+	return s
+}
+
+func (s *seriesSet) newSeries(now time.Time) *series {
+	attrs := s.signal
+	// a single series keeps the attributes of the signal only
+	if s.opts.numSeries > 1 || s.opts.churn > 0 {
+		attrs = append(slices.Clip(attrs), attribute.Int(seriesIDKey, s.nextID))
+	}
+	s.nextID++
+
+	ser := &series{attrs: attribute.NewSet(attrs...)}
+	ser.restart(now, s.opts)
+	return ser
+}
+
+// next prepares the series for the export following the given number of exports.
+func (s *seriesSet) next(exports int, now time.Time) {
+	if exports == 0 {
+		return
+	}
+
+	s.churnDebt += s.opts.churn * float64(len(s.series))
+	for ; s.churnDebt >= 1; s.churnDebt-- {
+		s.series[s.cursor] = s.newSeries(now)
+		s.cursor = (s.cursor + 1) % len(s.series)
+	}
+
+	if s.opts.resetEvery > 0 && exports%s.opts.resetEvery == 0 {
+		for _, ser := range s.series {
+			ser.restart(now, s.opts)
+		}
+	}
+}
+
+// exported updates the series after an export, restarting them when their temporality is delta.
+func (s *seriesSet) exported(now time.Time) {
+	for _, ser := range s.series {
+		ser.exports++
+		if s.opts.delta() {
+			ser.restart(now, s.opts)
+		}
+	}
+}
+
+// sample returns a value following the distribution, clamped to 0.
+func (s *seriesSet) sample() float64 {
+	var v float64
+	switch s.opts.distribution {
+	case distributionUniform:
+		v = s.rand.Float64() * 2 * s.opts.mean
+	case distributionExponential:
+		v = s.rand.ExpFloat64() * s.opts.mean
+	default:
+		v = s.opts.mean + s.rand.NormFloat64()*s.opts.stdDev
+	}
+	return math.Max(v, 0)
+}
+
+func (s *seriesSet) sumDataPoints(now time.Time, exemplars []metricdata.Exemplar[int64]) []metricdata.DataPoint[int64] {
+	dps := make([]metricdata.DataPoint[int64], 0, len(s.series))
+	for _, ser := range s.series {
+		value := int64(ser.exports)
+		if s.opts.delta() {
+			value = 1
+		}
+		dps = append(dps, metricdata.DataPoint[int64]{
+			StartTime:  ser.start,
+			Time:       now,
+			Value:      value,
+			Attributes: ser.attrs,
+			Exemplars:  exemplars,
+		})
+	}
+	return dps
+}
+
+func (s *seriesSet) histogramDataPoints(now time.Time, exemplars []metricdata.Exemplar[float64]) []metricdata.HistogramDataPoint[float64] {
+	dps := make([]metricdata.HistogramDataPoint[float64], 0, len(s.series))
+	for _, ser := range s.series {
+		for i := 0; i < s.opts.observations; i++ {
+			ser.observe(s.sample(), s.opts)
+		}
+		dp := metricdata.HistogramDataPoint[float64]{
+			StartTime:    ser.start,
+			Time:         now,
+			Attributes:   ser.attrs,
+			Count:        ser.count,
+			Sum:          ser.sum,
+			Bounds:       s.opts.buckets,
+			BucketCounts: slices.Clone(ser.bucketCounts),
+			Exemplars:    exemplars,
+		}
+		if ser.count > 0 {
+			dp.Min = metricdata.NewExtrema(ser.min)
+			dp.Max = metricdata.NewExtrema(ser.max)
+		}
+		dps = append(dps, dp)
+	}
+	return dps
+}
+
+func (s *seriesSet) exponentialHistogramDataPoints(now time.Time, exemplars []metricdata.Exemplar[float64]) []metricdata.ExponentialHistogramDataPoint[float64] {
+	dps := make([]metricdata.ExponentialHistogramDataPoint[float64], 0, len(s.series))
+	for _, ser := range s.series {
+		for i := 0; i < s.opts.observations; i++ {
+			ser.observe(s.sample(), s.opts)
+		}
+		offset, counts := ser.exponential.buckets()
+		dp := metricdata.ExponentialHistogramDataPoint[float64]{
+			StartTime:      ser.start,
+			Time:           now,
+			Attributes:     ser.attrs,
+			Count:          ser.count,
+			Sum:            ser.sum,
+			Scale:          ser.exponential.scale,
+			ZeroCount:      ser.exponential.zeroCount,
+			PositiveBucket: metricdata.ExponentialBucket{Offset: offset, Counts: counts},
+			Exemplars:      exemplars,
+		}
+		if ser.count > 0 {
+			dp.Min = metricdata.NewExtrema(ser.min)
+			dp.Max = metricdata.NewExtrema(ser.max)
+		}
+		dps = append(dps, dp)
+	}
+	return dps
+}
+
+func (s *seriesSet) summaryDataPoints(now time.Time) []metricdata.SummaryDataPoint {
+	dps := make([]metricdata.SummaryDataPoint, 0, len(s.series))
+	values := make([]float64, s.opts.observations)
+	for _, ser := range s.series {
+		for i := range values {
+			values[i] = s.sample()
+			ser.observe(values[i], s.opts)
+		}
+		dp := metricdata.SummaryDataPoint{
+			StartTime:  ser.start,
+			Time:       now,
+			Attributes: ser.attrs,
+			Count:      ser.count,
+			Sum:        ser.sum,
+		}
+		// the quantiles are computed over the values observed since the previous export
+		if len(values) > 0 {
+			slices.Sort(values)
+			for _, q := range s.opts.quantiles {
+				dp.QuantileValues = append(dp.QuantileValues, metricdata.QuantileValue{
+					Quantile: q,
+					Value:    values[int(math.Round(q*float64(len(values)-1)))],
+				})
+			}
+		}
+		dps = append(dps, dp)
+	}
+	return dps
+}
+
+// exponentialBuckets counts the values in base-2 exponential buckets, lowering the scale when the values span more
+// than the maximum number of buckets.
+type exponentialBuckets struct {
+	scale     int32
+	zeroCount uint64
+	counts    map[int32]uint64
+	low, high int32
+}
+
+func newExponentialBuckets(scale int32) exponentialBuckets {
+	return exponentialBuckets{scale: scale, counts: make(map[int32]uint64)}
+}
+
+func (b *exponentialBuckets) record(v float64, maxSize int) {
+	if v <= 0 {
+		b.zeroCount++
+		return
+	}
+
+	index := exponentialIndex(v, b.scale)
+	if len(b.counts) == 0 {
+		b.low, b.high = index, index
+	}
+	b.counts[index]++
+	b.low, b.high = min(b.low, index), max(b.high, index)
+
+	for int(b.high-b.low) >= maxSize {
+		b.downscale()
+	}
+}
+
+// downscale halves the scale, merging the buckets two by two.
+func (b *exponentialBuckets) downscale() {
+	counts := make(map[int32]uint64, len(b.counts))
+	for index, count := range b.counts {
+		counts[index>>1] += count
+	}
+	b.counts = counts
+	b.scale--
+	b.low >>= 1
+	b.high >>= 1
+}
+
+func (b *exponentialBuckets) buckets() (int32, []uint64) {
+	if len(b.counts) == 0 {
+		return 0, nil
+	}
+	counts := make([]uint64, b.high-b.low+1)
+	for index, count := range b.counts {
+		counts[index-b.low] = count
+	}
+	return b.low, counts
+}
+
+// exponentialIndex returns the index of the bucket (base^index, base^(index+1)] holding the value, where base is
+// 2^(2^-scale).
+func exponentialIndex(v float64, scale int32) int32 {
+	return int32(math.Ceil(math.Log2(v)*math.Exp2(float64(scale)))) - 1
+}
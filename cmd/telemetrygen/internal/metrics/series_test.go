@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialIndex(t *testing.T) {
+	tests := []struct {
+		value float64
+		scale int32
+		want  int32
+	}{
+		{value: 1, scale: 0, want: -1},
+		{value: 2, scale: 0, want: 0},
+		{value: 3, scale: 0, want: 1},
+		{value: 4, scale: 0, want: 1},
+		{value: 4, scale: 1, want: 3},
+		{value: 5, scale: 1, want: 4},
+		{value: 100, scale: -1, want: 3},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, exponentialIndex(tt.value, tt.scale), "value %v at scale %d", tt.value, tt.scale)
+	}
+}
+
+func TestExponentialBucketsDownscale(t *testing.T) {
+	b := newExponentialBuckets(3)
+	for _, v := range []float64{0, 1, 2, 4, 8, 1024} {
+		b.record(v, 4)
+	}
+
+	offset, counts := b.buckets()
+	assert.LessOrEqual(t, len(counts), 4)
+	assert.Equal(t, uint64(1), b.zeroCount)
+
+	var total uint64
+	for _, c := range counts {
+		total += c
+	}
+	assert.Equal(t, uint64(5), total)
+
+	// every value must be within the bounds of its bucket
+	base := math.Exp2(math.Exp2(-float64(b.scale)))
+	assert.Greater(t, 1.0, math.Pow(base, float64(offset)))
+	assert.LessOrEqual(t, 1024.0, math.Pow(base, float64(offset+int32(len(counts)))))
+}
@@ -22,6 +22,7 @@ type worker struct {
 	metricName     string                       // name of metric to generate
 	metricType     metricType                   // type of metric to generate
 	exemplars      []metricdata.Exemplar[int64] // exemplars to attach to the metric
+	series         seriesOptions                // series to generate, and values they observe
 	numMetrics     int                          // how many metrics the worker has to generate (only when duration==0)
 	totalDuration  time.Duration                // how long to run the test for (overrides `numMetrics`)
 	limitPerSecond rate.Limit                   // how many metrics per second to generate
@@ -46,23 +47,40 @@ func (w worker) simulateMetrics(res *resource.Resource, exporterFunc func() (sdk
 		}
 	}()
 
+	set := newSeriesSet(w.series, signalAttrs, uint64(w.index), time.Now())
+	var floatExemplars []metricdata.Exemplar[float64]
+	for _, e := range w.exemplars {
+		floatExemplars = append(floatExemplars, metricdata.Exemplar[float64]{
+			FilteredAttributes: e.FilteredAttributes,
+			Time:               e.Time,
+			Value:              float64(e.Value),
+			SpanID:             e.SpanID,
+			TraceID:            e.TraceID,
+		})
+	}
+
 	var i int64
 	for w.running.Load() {
 		var metrics []metricdata.Metrics
 
+		now := time.Now()
+		set.next(int(i), now)
+
 		switch w.metricType {
 		case metricTypeGauge:
+			dps := make([]metricdata.DataPoint[int64], 0, len(set.series))
+			for _, ser := range set.series {
+				dps = append(dps, metricdata.DataPoint[int64]{
+					Time:       now,
+					Value:      i,
+					Attributes: ser.attrs,
+					Exemplars:  w.exemplars,
+				})
+			}
 			metrics = append(metrics, metricdata.Metrics{
 				Name: w.metricName,
 				Data: metricdata.Gauge[int64]{
-					DataPoints: []metricdata.DataPoint[int64]{
-						{
-							Time:       time.Now(),
-							Value:      i,
-							Attributes: attribute.NewSet(signalAttrs...),
-							Exemplars:  w.exemplars,
-						},
-					},
+					DataPoints: dps,
 				},
 			})
 		case metricTypeSum:
@@ -70,21 +88,37 @@ func (w worker) simulateMetrics(res *resource.Resource, exporterFunc func() (sdk
 				Name: w.metricName,
 				Data: metricdata.Sum[int64]{
 					IsMonotonic: true,
-					Temporality: metricdata.CumulativeTemporality,
-					DataPoints: []metricdata.DataPoint[int64]{
-						{
-							StartTime:  time.Now().Add(-1 * time.Second),
-							Time:       time.Now(),
-							Value:      i,
-							Attributes: attribute.NewSet(signalAttrs...),
-							Exemplars:  w.exemplars,
-						},
-					},
+					Temporality: w.series.metricdataTemporality(),
+					DataPoints:  set.sumDataPoints(now, w.exemplars),
+				},
+			})
+		case metricTypeHistogram:
+			metrics = append(metrics, metricdata.Metrics{
+				Name: w.metricName,
+				Data: metricdata.Histogram[float64]{
+					Temporality: w.series.metricdataTemporality(),
+					DataPoints:  set.histogramDataPoints(now, floatExemplars),
+				},
+			})
+		case metricTypeExponentialHistogram:
+			metrics = append(metrics, metricdata.Metrics{
+				Name: w.metricName,
+				Data: metricdata.ExponentialHistogram[float64]{
+					Temporality: w.series.metricdataTemporality(),
+					DataPoints:  set.exponentialHistogramDataPoints(now, floatExemplars),
+				},
+			})
+		case metricTypeSummary:
+			metrics = append(metrics, metricdata.Metrics{
+				Name: w.metricName,
+				Data: metricdata.Summary{
+					DataPoints: set.summaryDataPoints(now),
 				},
 			})
 		default:
 			w.logger.Fatal("unknown metric type")
 		}
+		set.exported(now)
 
 		rm := metricdata.ResourceMetrics{
 			Resource:     res,
@@ -300,6 +300,181 @@ func TestGaugeMultipleTelemetryAttr(t *testing.T) {
 	}
 }
 
+func TestHistogram(t *testing.T) {
+	// arrange
+	qty := 3
+	cfg := configWithNoAttributes(metricTypeHistogram, qty)
+	cfg.HistogramBuckets = []float64{50, 100, 150}
+	cfg.Distribution = distributionUniform
+	cfg.DistributionMean = 100
+	cfg.Observations = 10
+	m := &mockExporter{}
+	expFunc := func() (sdkmetric.Exporter, error) {
+		return m, nil
+	}
+
+	// act
+	require.NoError(t, Run(cfg, expFunc, zap.NewNop()))
+
+	// assert
+	require.Len(t, m.rms, qty)
+	var start time.Time
+	for i := 0; i < qty; i++ {
+		hist := m.rms[i].ScopeMetrics[0].Metrics[0].Data.(metricdata.Histogram[float64])
+		assert.Equal(t, metricdata.CumulativeTemporality, hist.Temporality)
+		require.Len(t, hist.DataPoints, 1)
+		dp := hist.DataPoints[0]
+		assert.Equal(t, uint64(10*(i+1)), dp.Count, "the cumulative histogram should keep counting")
+		assert.Equal(t, []float64{50, 100, 150}, dp.Bounds)
+		require.Len(t, dp.BucketCounts, 4)
+		var total uint64
+		for _, c := range dp.BucketCounts {
+			total += c
+		}
+		assert.Equal(t, dp.Count, total)
+		minValue, _ := dp.Min.Value()
+		maxValue, _ := dp.Max.Value()
+		assert.GreaterOrEqual(t, minValue, 0.0)
+		assert.LessOrEqual(t, maxValue, 200.0)
+		if i == 0 {
+			start = dp.StartTime
+		}
+		assert.Equal(t, start, dp.StartTime)
+	}
+}
+
+func TestExponentialHistogramDelta(t *testing.T) {
+	// arrange
+	qty := 3
+	cfg := configWithNoAttributes(metricTypeExponentialHistogram, qty)
+	cfg.AggregationTemporality = temporalityDelta
+	cfg.Distribution = distributionExponential
+	cfg.DistributionMean = 100
+	cfg.Observations = 50
+	cfg.ExponentialHistogramMaxSize = 10
+	cfg.ExponentialHistogramMaxScale = 20
+	m := &mockExporter{}
+	expFunc := func() (sdkmetric.Exporter, error) {
+		return m, nil
+	}
+
+	// act
+	require.NoError(t, Run(cfg, expFunc, zap.NewNop()))
+
+	// assert
+	require.Len(t, m.rms, qty)
+	var previous time.Time
+	for i := 0; i < qty; i++ {
+		hist := m.rms[i].ScopeMetrics[0].Metrics[0].Data.(metricdata.ExponentialHistogram[float64])
+		assert.Equal(t, metricdata.DeltaTemporality, hist.Temporality)
+		require.Len(t, hist.DataPoints, 1)
+		dp := hist.DataPoints[0]
+		assert.Equal(t, uint64(50), dp.Count, "the delta histogram should only count the values of the interval")
+		assert.LessOrEqual(t, len(dp.PositiveBucket.Counts), 10)
+		assert.Less(t, dp.Scale, int32(20))
+		total := dp.ZeroCount
+		for _, c := range dp.PositiveBucket.Counts {
+			total += c
+		}
+		assert.Equal(t, dp.Count, total)
+		if i > 0 {
+			assert.Equal(t, previous, dp.StartTime, "the interval should start at the previous export")
+		}
+		previous = dp.Time
+	}
+}
+
+func TestSummary(t *testing.T) {
+	// arrange
+	qty := 2
+	cfg := configWithNoAttributes(metricTypeSummary, qty)
+	cfg.SummaryQuantiles = []float64{0, 0.5, 1}
+	cfg.Distribution = distributionNormal
+	cfg.DistributionMean = 100
+	cfg.DistributionStdDev = 10
+	cfg.Observations = 20
+	m := &mockExporter{}
+	expFunc := func() (sdkmetric.Exporter, error) {
+		return m, nil
+	}
+
+	// act
+	require.NoError(t, Run(cfg, expFunc, zap.NewNop()))
+
+	// assert
+	require.Len(t, m.rms, qty)
+	for i := 0; i < qty; i++ {
+		summary := m.rms[i].ScopeMetrics[0].Metrics[0].Data.(metricdata.Summary)
+		require.Len(t, summary.DataPoints, 1)
+		dp := summary.DataPoints[0]
+		assert.Equal(t, uint64(20*(i+1)), dp.Count)
+		require.Len(t, dp.QuantileValues, 3)
+		assert.LessOrEqual(t, dp.QuantileValues[0].Value, dp.QuantileValues[1].Value)
+		assert.LessOrEqual(t, dp.QuantileValues[1].Value, dp.QuantileValues[2].Value)
+	}
+}
+
+func TestSeriesChurn(t *testing.T) {
+	// arrange
+	qty := 4
+	cfg := configWithOneAttribute(metricTypeSum, qty)
+	cfg.NumSeries = 4
+	cfg.SeriesChurn = 0.5
+	m := &mockExporter{}
+	expFunc := func() (sdkmetric.Exporter, error) {
+		return m, nil
+	}
+
+	// act
+	require.NoError(t, Run(cfg, expFunc, zap.NewNop()))
+
+	// assert
+	require.Len(t, m.rms, qty)
+	ids := map[int64]bool{}
+	for i := 0; i < qty; i++ {
+		sum := m.rms[i].ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+		require.Len(t, sum.DataPoints, 4)
+		for _, dp := range sum.DataPoints {
+			id, ok := dp.Attributes.Value(seriesIDKey)
+			require.True(t, ok)
+			ids[id.AsInt64()] = true
+			value, _ := dp.Attributes.Value(telemetryAttrKeyOne)
+			assert.Equal(t, telemetryAttrValueOne, value.AsString())
+		}
+	}
+	// 4 series at first, then 2 new series at each of the 3 following exports
+	assert.Len(t, ids, 10)
+}
+
+func TestResetEvery(t *testing.T) {
+	// arrange
+	qty := 5
+	cfg := configWithNoAttributes(metricTypeSum, qty)
+	cfg.ResetEvery = 2
+	m := &mockExporter{}
+	expFunc := func() (sdkmetric.Exporter, error) {
+		return m, nil
+	}
+
+	// act
+	require.NoError(t, Run(cfg, expFunc, zap.NewNop()))
+
+	// assert
+	require.Len(t, m.rms, qty)
+	var values []int64
+	var starts []time.Time
+	for i := 0; i < qty; i++ {
+		dp := m.rms[i].ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64]).DataPoints[0]
+		values = append(values, dp.Value)
+		starts = append(starts, dp.StartTime)
+	}
+	assert.Equal(t, []int64{0, 1, 0, 1, 0}, values)
+	assert.Equal(t, starts[0], starts[1])
+	assert.True(t, starts[2].After(starts[1]))
+	assert.Equal(t, starts[2], starts[3])
+	assert.True(t, starts[4].After(starts[3]))
+}
+
 func TestValidate(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -342,6 +517,55 @@ func TestValidate(t *testing.T) {
 			},
 			wantErrMessage: "SpanID must be a 16 character hex string, like: '5828fa4960140870'",
 		},
+		{
+			name: "SeriesChurn invalid",
+			cfg: &Config{
+				Config: common.Config{
+					WorkerCount: 1,
+				},
+				NumMetrics:  5,
+				MetricType:  metricTypeSum,
+				SeriesChurn: 1.5,
+			},
+			wantErrMessage: "`series-churn` must be between 0 and 1",
+		},
+		{
+			name: "HistogramBuckets not increasing",
+			cfg: &Config{
+				Config: common.Config{
+					WorkerCount: 1,
+				},
+				NumMetrics:       5,
+				MetricType:       metricTypeHistogram,
+				HistogramBuckets: []float64{1, 10, 10},
+			},
+			wantErrMessage: "`histogram-buckets` must be in strictly increasing order",
+		},
+		{
+			name: "ExponentialHistogramMaxScale invalid",
+			cfg: &Config{
+				Config: common.Config{
+					WorkerCount: 1,
+				},
+				NumMetrics:                   5,
+				MetricType:                   metricTypeExponentialHistogram,
+				ExponentialHistogramMaxSize:  160,
+				ExponentialHistogramMaxScale: 21,
+			},
+			wantErrMessage: "`exponential-histogram-max-scale` must be between -10 and 20",
+		},
+		{
+			name: "SummaryQuantiles invalid",
+			cfg: &Config{
+				Config: common.Config{
+					WorkerCount: 1,
+				},
+				NumMetrics:       5,
+				MetricType:       metricTypeSummary,
+				SummaryQuantiles: []float64{0.5, 2},
+			},
+			wantErrMessage: "`summary-quantiles` must be between 0 and 1",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -22,6 +22,7 @@ type Config struct {
 	StatusCode       string
 	Batch            bool
 	LoadSize         int
+	ScenarioFile     string
 
 	SpanDuration time.Duration
 }
@@ -40,6 +41,7 @@ func (c *Config) Flags(fs *pflag.FlagSet) {
 	fs.BoolVar(&c.Batch, "batch", true, "Whether to batch traces")
 	fs.IntVar(&c.LoadSize, "size", 0, "Desired minimum size in MB of string data for each trace generated. This can be used to test traces with large payloads, i.e. when testing the OTLP receiver endpoint max receive size.")
 	fs.DurationVar(&c.SpanDuration, "span-duration", 123*time.Microsecond, "The duration of each generated span.")
+	fs.StringVar(&c.ScenarioFile, "scenario", "", "Path of a YAML file describing the call tree of the traces to replay, across services. When set, `rate` is the number of traces per second, and the options describing the spans are ignored.")
 }
 
 // Validate validates the test scenario parameters.
@@ -47,5 +49,5 @@ func (c *Config) Validate() error {
 	if c.TotalDuration <= 0 && c.NumTraces <= 0 {
 		return fmt.Errorf("either `traces` or `duration` must be greater than 0")
 	}
-	return nil
+	return common.ValidateOutput(c.Output, common.OutputZipkin)
 }
@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package traces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+const (
+	defaultKafkaTopic     = "otlp_spans"
+	defaultZipkinEndpoint = "localhost:9411"
+	zipkinPath            = "/api/v2/spans"
+)
+
+// newOutputExporter creates an exporter sending the spans with the output of the configuration, other than OTLP.
+func newOutputExporter(cfg *Config) (*otlptrace.Exporter, error) {
+	client := &pdataClient{}
+	switch cfg.Output {
+	case common.OutputKafka:
+		sender, err := common.NewKafkaSender(&cfg.Config, defaultKafkaTopic)
+		if err != nil {
+			return nil, err
+		}
+		marshaler := &ptrace.ProtoMarshaler{}
+		client.push = func(ctx context.Context, td ptrace.Traces) error {
+			payload, err := marshaler.MarshalTraces(td)
+			if err != nil {
+				return err
+			}
+			return sender.Send(ctx, payload)
+		}
+		client.close = sender.Close
+	case common.OutputZipkin:
+		sender, err := common.NewHTTPSender(&cfg.Config, defaultZipkinEndpoint, zipkinPath, map[string]string{"Content-Type": "application/json"})
+		if err != nil {
+			return nil, err
+		}
+		client.push = func(ctx context.Context, td ptrace.Traces) error {
+			payload, err := json.Marshal(zipkinSpans(td))
+			if err != nil {
+				return err
+			}
+			return sender.Send(ctx, payload)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported output %q", cfg.Output)
+	}
+	return otlptrace.New(context.Background(), client)
+}
+
+var _ otlptrace.Client = (*pdataClient)(nil)
+
+// pdataClient is an OTLP trace client pushing the spans as pdata, to encode them in another wire format.
+type pdataClient struct {
+	push  func(context.Context, ptrace.Traces) error
+	close func() error
+}
+
+func (c *pdataClient) Start(context.Context) error {
+	return nil
+}
+
+func (c *pdataClient) Stop(context.Context) error {
+	if c.close == nil {
+		return nil
+	}
+	return c.close()
+}
+
+func (c *pdataClient) UploadTraces(ctx context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	buf, err := proto.Marshal(&tracepb.TracesData{ResourceSpans: protoSpans})
+	if err != nil {
+		return err
+	}
+	td, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(buf)
+	if err != nil {
+		return err
+	}
+	return c.push(ctx, td)
+}
+
+// zipkinSpan is a span of the Zipkin v2 JSON API.
+type zipkinSpan struct {
+	TraceID        string             `json:"traceId"`
+	ParentID       string             `json:"parentId,omitempty"`
+	ID             string             `json:"id"`
+	Kind           string             `json:"kind,omitempty"`
+	Name           string             `json:"name"`
+	Timestamp      int64              `json:"timestamp"`
+	Duration       int64              `json:"duration"`
+	LocalEndpoint  zipkinEndpoint     `json:"localEndpoint"`
+	RemoteEndpoint *zipkinEndpoint    `json:"remoteEndpoint,omitempty"`
+	Annotations    []zipkinAnnotation `json:"annotations,omitempty"`
+	Tags           map[string]string  `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+	IPv4        string `json:"ipv4,omitempty"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+var zipkinKinds = map[ptrace.SpanKind]string{
+	ptrace.SpanKindClient:   "CLIENT",
+	ptrace.SpanKindServer:   "SERVER",
+	ptrace.SpanKindProducer: "PRODUCER",
+	ptrace.SpanKindConsumer: "CONSUMER",
+}
+
+// zipkinSpans converts the spans to the Zipkin v2 model, with the resource and span attributes as tags.
+func zipkinSpans(td ptrace.Traces) []zipkinSpan {
+	var spans []zipkinSpan
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		serviceName := "unknown_service"
+		if v, ok := rs.Resource().Attributes().Get("service.name"); ok {
+			serviceName = v.AsString()
+		}
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			ss := rs.ScopeSpans().At(j)
+			for k := 0; k < ss.Spans().Len(); k++ {
+				span := ss.Spans().At(k)
+				zs := zipkinSpan{
+					TraceID:       span.TraceID().String(),
+					ID:            span.SpanID().String(),
+					Kind:          zipkinKinds[span.Kind()],
+					Name:          span.Name(),
+					Timestamp:     span.StartTimestamp().AsTime().UnixMicro(),
+					Duration:      span.EndTimestamp().AsTime().Sub(span.StartTimestamp().AsTime()).Microseconds(),
+					LocalEndpoint: zipkinEndpoint{ServiceName: serviceName},
+					Tags:          map[string]string{},
+				}
+				if !span.ParentSpanID().IsEmpty() {
+					zs.ParentID = span.ParentSpanID().String()
+				}
+
+				putZipkinTags(zs.Tags, rs.Resource().Attributes())
+				delete(zs.Tags, "service.name")
+				putZipkinTags(zs.Tags, span.Attributes())
+				if ss.Scope().Name() != "" {
+					zs.Tags["otel.scope.name"] = ss.Scope().Name()
+				}
+
+				if peer, ok := span.Attributes().Get("peer.service"); ok {
+					zs.RemoteEndpoint = &zipkinEndpoint{ServiceName: peer.AsString()}
+					if ip, ok := span.Attributes().Get("net.peer.ip"); ok {
+						zs.RemoteEndpoint.IPv4 = ip.AsString()
+					}
+				}
+
+				switch span.Status().Code() {
+				case ptrace.StatusCodeError:
+					zs.Tags["otel.status_code"] = "ERROR"
+					zs.Tags["error"] = span.Status().Message()
+					if zs.Tags["error"] == "" {
+						zs.Tags["error"] = "true"
+					}
+				case ptrace.StatusCodeOk:
+					zs.Tags["otel.status_code"] = "OK"
+				}
+
+				for e := 0; e < span.Events().Len(); e++ {
+					event := span.Events().At(e)
+					zs.Annotations = append(zs.Annotations, zipkinAnnotation{
+						Timestamp: event.Timestamp().AsTime().UnixMicro(),
+						Value:     event.Name(),
+					})
+				}
+				spans = append(spans, zs)
+			}
+		}
+	}
+	return spans
+}
+
+func putZipkinTags(tags map[string]string, attrs pcommon.Map) {
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		tags[k] = v.AsString()
+		return true
+	})
+}
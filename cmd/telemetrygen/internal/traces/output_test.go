@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package traces
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+func TestZipkinSpans(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "frontend")
+	rs.Resource().Attributes().PutStr("host.name", "host")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("telemetrygen")
+	span := ss.Spans().AppendEmpty()
+	span.SetTraceID(pcommon.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	span.SetSpanID(pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8})
+	span.SetParentSpanID(pcommon.SpanID{8, 7, 6, 5, 4, 3, 2, 1})
+	span.SetName("lets-go")
+	span.SetKind(ptrace.SpanKindClient)
+	span.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	span.SetEndTimestamp(pcommon.NewTimestampFromTime(start.Add(123 * time.Microsecond)))
+	span.Attributes().PutStr("peer.service", "backend")
+	span.Attributes().PutStr("net.peer.ip", "1.2.3.4")
+	span.Status().SetCode(ptrace.StatusCodeError)
+	span.Events().AppendEmpty().SetName("retry")
+
+	spans := zipkinSpans(td)
+	require.Len(t, spans, 1)
+	assert.Equal(t, zipkinSpan{
+		TraceID:        "0102030405060708090a0b0c0d0e0f10",
+		ParentID:       "0807060504030201",
+		ID:             "0102030405060708",
+		Kind:           "CLIENT",
+		Name:           "lets-go",
+		Timestamp:      start.UnixMicro(),
+		Duration:       123,
+		LocalEndpoint:  zipkinEndpoint{ServiceName: "frontend"},
+		RemoteEndpoint: &zipkinEndpoint{ServiceName: "backend", IPv4: "1.2.3.4"},
+		Annotations:    []zipkinAnnotation{{Timestamp: 0, Value: "retry"}},
+		Tags: map[string]string{
+			"host.name":        "host",
+			"peer.service":     "backend",
+			"net.peer.ip":      "1.2.3.4",
+			"otel.scope.name":  "telemetrygen",
+			"otel.status_code": "ERROR",
+			"error":            "true",
+		},
+	}, spans[0])
+}
+
+func TestZipkinOutput(t *testing.T) {
+	received := make(chan []zipkinSpan, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, zipkinPath, r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var spans []zipkinSpan
+		assert.NoError(t, json.Unmarshal(body, &spans))
+		received <- spans
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	cfg := &Config{
+		Config: common.Config{
+			CustomEndpoint: strings.TrimPrefix(srv.URL, "http://"),
+			Insecure:       true,
+			Output:         common.OutputZipkin,
+		},
+	}
+	exp, err := newOutputExporter(cfg)
+	require.NoError(t, err)
+
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exp),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String("telemetrygen"))),
+	)
+	_, span := tracerProvider.Tracer("telemetrygen").Start(context.Background(), "lets-go", trace.WithSpanKind(trace.SpanKindServer))
+	span.SetStatus(codes.Error, "failed")
+	span.End()
+	require.NoError(t, tracerProvider.Shutdown(context.Background()))
+
+	spans := <-received
+	require.Len(t, spans, 1)
+	assert.Equal(t, "lets-go", spans[0].Name)
+	assert.Equal(t, "SERVER", spans[0].Kind)
+	assert.Equal(t, "telemetrygen", spans[0].LocalEndpoint.ServiceName)
+	assert.Equal(t, "failed", spans[0].Tags["error"])
+}
+
+func TestOutputValidate(t *testing.T) {
+	cfg := &Config{
+		Config: common.Config{
+			Output: common.OutputSyslog,
+		},
+		NumTraces: 1,
+	}
+	assert.EqualError(t, cfg.Validate(), `expected `+"`output`"+` to be one of (otlp, kafka, zipkin), got "syslog" instead`)
+}
@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package traces // import "github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/traces"
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario describes the call tree of the traces to generate.
+type Scenario struct {
+	// Root is the root span of the traces.
+	Root SpanSpec `yaml:"root"`
+}
+
+// SpanSpec describes a span of the scenario, and the spans it leads to.
+type SpanSpec struct {
+	// Service is the name of the service emitting the span, inherited from the parent span when empty.
+	Service string `yaml:"service"`
+	// Name is the name of the span.
+	Name string `yaml:"name"`
+	// Kind is the kind of the span, one of internal (default), server, client, producer or consumer.
+	Kind string `yaml:"kind"`
+	// Latency is the distribution of the time spent in the span itself, in addition to its children.
+	Latency Latency `yaml:"latency"`
+	// ErrorRate is the probability, between 0 and 1, of the span having an error status.
+	ErrorRate float64 `yaml:"error_rate"`
+	// Attributes are the attributes of the span.
+	Attributes map[string]any `yaml:"attributes"`
+	// Count is the number of times the span is repeated within its parent, 1 by default.
+	Count int `yaml:"count"`
+	// Parallel runs the children of the span concurrently instead of one after the other.
+	Parallel bool `yaml:"parallel"`
+	// Link starts a new trace linking to the parent span, as asynchronous consumers do, instead of a child span.
+	Link bool `yaml:"link"`
+	// Children are the spans started by the span.
+	Children []SpanSpec `yaml:"children"`
+}
+
+// Latency describes a distribution of durations.
+type Latency struct {
+	// Distribution is one of fixed (default), uniform, normal or exponential.
+	Distribution string `yaml:"distribution"`
+	// Mean is the duration of the fixed distribution, and the mean of the normal and exponential distributions.
+	Mean time.Duration `yaml:"mean"`
+	// StdDev is the standard deviation of the normal distribution.
+	StdDev time.Duration `yaml:"stddev"`
+	// Min and Max bound the durations of all the distributions, and are the range of the uniform distribution.
+	Min time.Duration `yaml:"min"`
+	Max time.Duration `yaml:"max"`
+}
+
+const (
+	fixedDistribution       = "fixed"
+	uniformDistribution     = "uniform"
+	normalDistribution      = "normal"
+	exponentialDistribution = "exponential"
+)
+
+var spanKinds = map[string]trace.SpanKind{
+	"":         trace.SpanKindInternal,
+	"internal": trace.SpanKindInternal,
+	"server":   trace.SpanKindServer,
+	"client":   trace.SpanKindClient,
+	"producer": trace.SpanKindProducer,
+	"consumer": trace.SpanKindConsumer,
+}
+
+// LoadScenario reads and validates the scenario file.
+func LoadScenario(path string) (*Scenario, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the scenario file: %w", err)
+	}
+
+	scenario := &Scenario{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse the scenario file: %w", err)
+	}
+	if err = scenario.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario file: %w", err)
+	}
+	return scenario, nil
+}
+
+// Validate validates the scenario.
+func (s *Scenario) Validate() error {
+	if s.Root.Service == "" {
+		return errors.New("the root span must have a `service`")
+	}
+	return s.Root.validate("root")
+}
+
+func (s *SpanSpec) validate(path string) error {
+	if s.Name == "" {
+		return fmt.Errorf("%s: `name` must be set", path)
+	}
+	path += "/" + s.Name
+	if _, ok := spanKinds[strings.ToLower(s.Kind)]; !ok {
+		return fmt.Errorf("%s: expected `kind` to be one of (internal, server, client, producer, consumer), got %q instead", path, s.Kind)
+	}
+	if s.ErrorRate < 0 || s.ErrorRate > 1 {
+		return fmt.Errorf("%s: `error_rate` must be between 0 and 1", path)
+	}
+	if s.Count < 0 {
+		return fmt.Errorf("%s: `count` must not be negative", path)
+	}
+	for k, v := range s.Attributes {
+		switch v.(type) {
+		case string, int, float64, bool:
+		default:
+			return fmt.Errorf("%s: unsupported type %T for the attribute %q", path, v, k)
+		}
+	}
+	if err := s.Latency.validate(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for i := range s.Children {
+		if err := s.Children[i].validate(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Latency) validate() error {
+	if l.Mean < 0 || l.StdDev < 0 || l.Min < 0 || l.Max < 0 {
+		return errors.New("latency durations must not be negative")
+	}
+	if l.Max > 0 && l.Min > l.Max {
+		return errors.New("latency `min` must not be greater than `max`")
+	}
+	switch strings.ToLower(l.Distribution) {
+	case "", fixedDistribution, normalDistribution, exponentialDistribution:
+		return nil
+	case uniformDistribution:
+		if l.Max == 0 {
+			return errors.New("the uniform latency distribution requires `max`")
+		}
+		return nil
+	default:
+		return fmt.Errorf("expected latency `distribution` to be one of (fixed, uniform, normal, exponential), got %q instead", l.Distribution)
+	}
+}
+
+// sample returns a duration following the distribution.
+func (l *Latency) sample(r *rand.Rand) time.Duration {
+	var d float64
+	switch strings.ToLower(l.Distribution) {
+	case uniformDistribution:
+		d = float64(l.Min) + r.Float64()*float64(l.Max-l.Min)
+	case normalDistribution:
+		d = float64(l.Mean) + r.NormFloat64()*float64(l.StdDev)
+	case exponentialDistribution:
+		d = r.ExpFloat64() * float64(l.Mean)
+	default:
+		d = float64(l.Mean)
+	}
+
+	d = math.Max(d, float64(l.Min))
+	if l.Max > 0 {
+		d = math.Min(d, float64(l.Max))
+	}
+	return time.Duration(d)
+}
+
+func (s *SpanSpec) kind() trace.SpanKind {
+	return spanKinds[strings.ToLower(s.Kind)]
+}
+
+func (s *SpanSpec) count() int {
+	return max(s.Count, 1)
+}
+
+func (s *SpanSpec) attributes() []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(s.Attributes))
+	for k, v := range s.Attributes {
+		switch value := v.(type) {
+		case string:
+			attrs = append(attrs, attribute.String(k, value))
+		case int:
+			attrs = append(attrs, attribute.Int(k, value))
+		case float64:
+			attrs = append(attrs, attribute.Float64(k, value))
+		case bool:
+			attrs = append(attrs, attribute.Bool(k, value))
+		}
+	}
+	return attrs
+}
+
+// services returns the names of the services of the scenario.
+func (s *Scenario) services() []string {
+	var services []string
+	seen := map[string]bool{}
+	var walk func(spec *SpanSpec, service string)
+	walk = func(spec *SpanSpec, service string) {
+		if spec.Service != "" {
+			service = spec.Service
+		}
+		if !seen[service] {
+			seen[service] = true
+			services = append(services, service)
+		}
+		for i := range spec.Children {
+			walk(&spec.Children[i], service)
+		}
+	}
+	walk(&s.Root, s.Root.Service)
+	return services
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package traces
+
+import (
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/cmd/telemetrygen/internal/common"
+)
+
+func TestLoadScenario(t *testing.T) {
+	scenario, err := LoadScenario(filepath.Join("testdata", "scenario.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"frontend", "orders", "shipping"}, scenario.services())
+	assert.Equal(t, trace.SpanKindServer, scenario.Root.kind())
+	assert.Equal(t, Latency{Distribution: "normal", Mean: 20 * time.Millisecond, StdDev: 5 * time.Millisecond, Min: time.Millisecond}, scenario.Root.Latency)
+	assert.ElementsMatch(t, []any{"GET", 200}, []any{scenario.Root.Attributes["http.request.method"], scenario.Root.Attributes["http.response.status_code"]})
+}
+
+func TestLoadScenarioErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		scenario string
+		err      string
+	}{
+		{
+			name:     "unknown field",
+			scenario: "root:\n  service: a\n  name: b\n  unknown: c\n",
+			err:      "failed to parse the scenario file",
+		},
+		{
+			name:     "no service",
+			scenario: "root:\n  name: b\n",
+			err:      "the root span must have a `service`",
+		},
+		{
+			name:     "no name",
+			scenario: "root:\n  service: a\n  name: b\n  children:\n    - kind: client\n",
+			err:      "root/b: `name` must be set",
+		},
+		{
+			name:     "invalid kind",
+			scenario: "root:\n  service: a\n  name: b\n  kind: remote\n",
+			err:      `root/b: expected ` + "`kind`" + ` to be one of (internal, server, client, producer, consumer), got "remote" instead`,
+		},
+		{
+			name:     "invalid error rate",
+			scenario: "root:\n  service: a\n  name: b\n  error_rate: 2\n",
+			err:      "root/b: `error_rate` must be between 0 and 1",
+		},
+		{
+			name:     "invalid attribute",
+			scenario: "root:\n  service: a\n  name: b\n  attributes:\n    c: [d]\n",
+			err:      `root/b: unsupported type []interface {} for the attribute "c"`,
+		},
+		{
+			name:     "invalid distribution",
+			scenario: "root:\n  service: a\n  name: b\n  latency:\n    distribution: pareto\n",
+			err:      `root/b: expected latency ` + "`distribution`" + ` to be one of (fixed, uniform, normal, exponential), got "pareto" instead`,
+		},
+		{
+			name:     "uniform without max",
+			scenario: "root:\n  service: a\n  name: b\n  latency:\n    distribution: uniform\n    min: 1ms\n",
+			err:      "root/b: the uniform latency distribution requires `max`",
+		},
+		{
+			name:     "min greater than max",
+			scenario: "root:\n  service: a\n  name: b\n  latency:\n    min: 2ms\n    max: 1ms\n",
+			err:      "root/b: latency `min` must not be greater than `max`",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scenario.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.scenario), 0o600))
+
+			_, err := LoadScenario(path)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestLatencySample(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, l := range []Latency{
+		{Mean: 5 * time.Millisecond},
+		{Distribution: "uniform", Min: time.Millisecond, Max: 5 * time.Millisecond},
+		{Distribution: "normal", Mean: 3 * time.Millisecond, StdDev: 2 * time.Millisecond, Min: time.Millisecond, Max: 5 * time.Millisecond},
+		{Distribution: "exponential", Mean: 3 * time.Millisecond, Min: time.Millisecond, Max: 5 * time.Millisecond},
+	} {
+		for i := 0; i < 100; i++ {
+			d := l.sample(r)
+			assert.GreaterOrEqual(t, d, l.Min)
+			assert.LessOrEqual(t, d, 5*time.Millisecond)
+		}
+	}
+}
+
+func TestRunScenario(t *testing.T) {
+	// prepare
+	syncer := &mockSyncer{}
+	sp := sdktrace.NewSimpleSpanProcessor(syncer)
+
+	scenario, err := LoadScenario(filepath.Join("testdata", "scenario.yaml"))
+	require.NoError(t, err)
+
+	tracerProviders := make(map[string]trace.TracerProvider)
+	for _, service := range scenario.services() {
+		tracerProvider := sdktrace.NewTracerProvider()
+		tracerProvider.RegisterSpanProcessor(sp)
+		tracerProviders[service] = tracerProvider
+	}
+
+	cfg := &Config{
+		Config: common.Config{
+			WorkerCount:         1,
+			TelemetryAttributes: common.KeyValue{telemetryAttrKeyOne: telemetryAttrValueOne},
+		},
+		NumTraces: 2,
+	}
+
+	// test
+	require.NoError(t, RunScenario(cfg, scenario, tracerProviders, zap.NewNop()))
+
+	// verify
+	require.Len(t, syncer.spans, 2*7)
+
+	spans := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range syncer.spans {
+		spans[span.Name()] = append(spans[span.Name()], span)
+		assert.Contains(t, span.Attributes(), attribute.String(telemetryAttrKeyOne, telemetryAttrValueOne))
+	}
+	assert.Len(t, spans["SELECT orders"], 4)
+
+	root := spans["GET /checkout"][0]
+	assert.Equal(t, trace.SpanKindServer, root.SpanKind())
+	assert.False(t, root.Parent().IsValid())
+
+	for _, server := range spans["POST /orders"] {
+		if server.SpanKind() != trace.SpanKindServer {
+			continue
+		}
+		assert.True(t, server.Parent().IsRemote(), "the server span must have a remote parent")
+		assert.True(t, server.Parent().IsValid())
+	}
+
+	for _, consumer := range spans["orders process"] {
+		assert.Equal(t, trace.SpanKindConsumer, consumer.SpanKind())
+		assert.False(t, consumer.Parent().IsValid(), "the linked span must start a new trace")
+		require.Len(t, consumer.Links(), 1)
+		assert.Equal(t, codes.Error, consumer.Status().Code)
+	}
+
+	for _, producer := range spans["orders publish"] {
+		assert.Equal(t, codes.Unset, producer.Status().Code)
+		assert.False(t, producer.EndTime().Before(producer.StartTime()))
+	}
+	assert.Equal(t, root.SpanContext().TraceID(), spans["orders publish"][0].SpanContext().TraceID())
+}
+
+func TestRunScenarioMissingTracerProvider(t *testing.T) {
+	scenario, err := LoadScenario(filepath.Join("testdata", "scenario.yaml"))
+	require.NoError(t, err)
+
+	cfg := &Config{
+		Config: common.Config{
+			WorkerCount: 1,
+		},
+		NumTraces: 1,
+	}
+
+	err = RunScenario(cfg, scenario, map[string]trace.TracerProvider{}, zap.NewNop())
+	assert.EqualError(t, err, `no tracer provider for the service "frontend"`)
+}
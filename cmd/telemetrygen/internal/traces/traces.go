@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 
@@ -33,8 +34,19 @@ func Start(cfg *Config) error {
 		return err
 	}
 
+	if err = cfg.Validate(); err != nil {
+		return err
+	}
+
 	var exp *otlptrace.Exporter
-	if cfg.UseHTTP {
+	switch {
+	case cfg.Output != "" && cfg.Output != common.OutputOTLP:
+		logger.Info("starting the exporter", zap.String("output", cfg.Output))
+		exp, err = newOutputExporter(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to obtain the %s exporter: %w", cfg.Output, err)
+		}
+	case cfg.UseHTTP:
 		var exporterOpts []otlptracehttp.Option
 
 		logger.Info("starting HTTP exporter")
@@ -46,7 +58,7 @@ func Start(cfg *Config) error {
 		if err != nil {
 			return fmt.Errorf("failed to obtain OTLP HTTP exporter: %w", err)
 		}
-	} else {
+	default:
 		var exporterOpts []otlptracegrpc.Option
 
 		logger.Info("starting gRPC exporter")
@@ -78,6 +90,33 @@ func Start(cfg *Config) error {
 		}()
 	}
 
+	if cfg.ScenarioFile != "" {
+		var scenario *Scenario
+		scenario, err = LoadScenario(cfg.ScenarioFile)
+		if err != nil {
+			return err
+		}
+
+		tracerProviders := make(map[string]trace.TracerProvider)
+		for _, service := range scenario.services() {
+			// the services of the scenario override `--otlp-attributes service.name="foo"`
+			attributes := append(cfg.GetAttributes(), semconv.ServiceNameKey.String(service))
+			tracerProvider := sdktrace.NewTracerProvider(
+				sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attributes...)),
+			)
+			if cfg.Batch {
+				tracerProvider.RegisterSpanProcessor(ssp)
+			}
+			tracerProviders[service] = tracerProvider
+		}
+
+		if err = RunScenario(cfg, scenario, tracerProviders, logger); err != nil {
+			logger.Error("failed to execute the test scenario.", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
 	var attributes []attribute.KeyValue
 	// may be overridden by `--otlp-attributes service.name="foo"`
 	attributes = append(attributes, semconv.ServiceNameKey.String(cfg.ServiceName))
@@ -106,18 +145,6 @@ func Run(c *Config, logger *zap.Logger) error {
 		return err
 	}
 
-	if c.TotalDuration > 0 {
-		c.NumTraces = 0
-	}
-
-	limit := rate.Limit(c.Rate)
-	if c.Rate == 0 {
-		limit = rate.Inf
-		logger.Info("generation of traces isn't being throttled")
-	} else {
-		logger.Info("generation of traces is limited", zap.Float64("per-second", float64(limit)))
-	}
-
 	var statusCode codes.Code
 
 	switch strings.ToLower(c.StatusCode) {
@@ -131,35 +158,79 @@ func Run(c *Config, logger *zap.Logger) error {
 		return fmt.Errorf("expected `status-code` to be one of (Unset, Error, Ok) or (0, 1, 2), got %q instead", c.StatusCode)
 	}
 
+	telemetryAttributes := c.GetTelemetryAttributes()
+
+	runWorkers(c, logger, func(w worker) {
+		w.numChildSpans = int(math.Max(1, float64(c.NumChildSpans)))
+		w.propagateContext = c.PropagateContext
+		w.statusCode = statusCode
+		w.loadSize = c.LoadSize
+		w.spanDuration = c.SpanDuration
+		w.simulateTraces(telemetryAttributes)
+	})
+	return nil
+}
+
+// RunScenario replays the scenario, emitting the spans of each service with its tracer provider.
+func RunScenario(c *Config, scenario *Scenario, tracerProviders map[string]trace.TracerProvider, logger *zap.Logger) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	tracers := make(map[string]trace.Tracer)
+	for _, service := range scenario.services() {
+		tp, ok := tracerProviders[service]
+		if !ok {
+			return fmt.Errorf("no tracer provider for the service %q", service)
+		}
+		tracers[service] = tp.Tracer("telemetrygen")
+	}
+
+	telemetryAttributes := c.GetTelemetryAttributes()
+
+	runWorkers(c, logger, func(w worker) {
+		w.scenario = scenario
+		w.tracers = tracers
+		w.simulateScenario(telemetryAttributes)
+	})
+	return nil
+}
+
+// runWorkers runs the workers until they generated their traces or the test duration is over.
+func runWorkers(c *Config, logger *zap.Logger, simulate func(w worker)) {
+	if c.TotalDuration > 0 {
+		c.NumTraces = 0
+	}
+
+	limit := rate.Limit(c.Rate)
+	if c.Rate == 0 {
+		limit = rate.Inf
+		logger.Info("generation of traces isn't being throttled")
+	} else {
+		logger.Info("generation of traces is limited", zap.Float64("per-second", float64(limit)))
+	}
+
 	wg := sync.WaitGroup{}
 
 	running := &atomic.Bool{}
 	running.Store(true)
 
-	telemetryAttributes := c.GetTelemetryAttributes()
-
 	for i := 0; i < c.WorkerCount; i++ {
 		wg.Add(1)
 		w := worker{
-			numTraces:        c.NumTraces,
-			numChildSpans:    int(math.Max(1, float64(c.NumChildSpans))),
-			propagateContext: c.PropagateContext,
-			statusCode:       statusCode,
-			limitPerSecond:   limit,
-			totalDuration:    c.TotalDuration,
-			running:          running,
-			wg:               &wg,
-			logger:           logger.With(zap.Int("worker", i)),
-			loadSize:         c.LoadSize,
-			spanDuration:     c.SpanDuration,
+			numTraces:      c.NumTraces,
+			limitPerSecond: limit,
+			totalDuration:  c.TotalDuration,
+			running:        running,
+			wg:             &wg,
+			logger:         logger.With(zap.Int("worker", i)),
 		}
 
-		go w.simulateTraces(telemetryAttributes)
+		go simulate(w)
 	}
 	if c.TotalDuration > 0 {
 		time.Sleep(c.TotalDuration)
 		running.Store(false)
 	}
 	wg.Wait()
-	return nil
 }
@@ -6,6 +6,7 @@ package traces // import "github.com/open-telemetry/opentelemetry-collector-cont
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -22,16 +23,18 @@ import (
 )
 
 type worker struct {
-	running          *atomic.Bool    // pointer to shared flag that indicates it's time to stop the test
-	numTraces        int             // how many traces the worker has to generate (only when duration==0)
-	numChildSpans    int             // how many child spans the worker has to generate per trace
-	propagateContext bool            // whether the worker needs to propagate the trace context via HTTP headers
-	statusCode       codes.Code      // the status code set for the child and parent spans
-	totalDuration    time.Duration   // how long to run the test for (overrides `numTraces`)
-	limitPerSecond   rate.Limit      // how many spans per second to generate
-	wg               *sync.WaitGroup // notify when done
-	loadSize         int             // desired minimum size in MB of string data for each generated trace
-	spanDuration     time.Duration   // duration of generated spans
+	running          *atomic.Bool            // pointer to shared flag that indicates it's time to stop the test
+	numTraces        int                     // how many traces the worker has to generate (only when duration==0)
+	numChildSpans    int                     // how many child spans the worker has to generate per trace
+	propagateContext bool                    // whether the worker needs to propagate the trace context via HTTP headers
+	statusCode       codes.Code              // the status code set for the child and parent spans
+	totalDuration    time.Duration           // how long to run the test for (overrides `numTraces`)
+	limitPerSecond   rate.Limit              // how many spans per second to generate
+	wg               *sync.WaitGroup         // notify when done
+	loadSize         int                     // desired minimum size in MB of string data for each generated trace
+	spanDuration     time.Duration           // duration of generated spans
+	scenario         *Scenario               // the call tree of the traces to replay, instead of the parent and child spans
+	tracers          map[string]trace.Tracer // the tracers of the services of the scenario
 	logger           *zap.Logger
 }
 
@@ -112,3 +115,88 @@ func (w worker) simulateTraces(telemetryAttributes []attribute.KeyValue) {
 	w.logger.Info("traces generated", zap.Int("traces", i))
 	w.wg.Done()
 }
+
+func (w worker) simulateScenario(telemetryAttributes []attribute.KeyValue) {
+	limiter := rate.NewLimiter(w.limitPerSecond, 1)
+	r := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0))
+	var mu sync.Mutex // guards r, shared by the children running in parallel
+	var i int
+
+	var emit func(ctx context.Context, spec *SpanSpec, service string, start time.Time) time.Time
+	emit = func(ctx context.Context, spec *SpanSpec, service string, start time.Time) time.Time {
+		if spec.Service != "" && spec.Service != service {
+			service = spec.Service
+			// simulates going remote
+			header := propagation.HeaderCarrier{}
+			propagation.TraceContext{}.Inject(ctx, header)
+			ctx = propagation.TraceContext{}.Extract(context.Background(), header)
+		}
+
+		opts := []trace.SpanStartOption{
+			trace.WithSpanKind(spec.kind()),
+			trace.WithTimestamp(start),
+			trace.WithAttributes(spec.attributes()...),
+		}
+		if spec.Link {
+			opts = append(opts, trace.WithNewRoot(), trace.WithLinks(trace.Link{SpanContext: trace.SpanContextFromContext(ctx)}))
+		}
+		ctx, sp := w.tracers[service].Start(ctx, spec.Name, opts...)
+		sp.SetAttributes(telemetryAttributes...)
+
+		end := start
+		if spec.Parallel {
+			var childrenWg sync.WaitGroup
+			var endMu sync.Mutex
+			for c := range spec.Children {
+				child := &spec.Children[c]
+				childrenWg.Add(1)
+				go func() {
+					defer childrenWg.Done()
+					childEnd := start
+					for n := 0; n < child.count(); n++ {
+						childEnd = emit(ctx, child, service, childEnd)
+					}
+					endMu.Lock()
+					if childEnd.After(end) {
+						end = childEnd
+					}
+					endMu.Unlock()
+				}()
+			}
+			childrenWg.Wait()
+		} else {
+			for c := range spec.Children {
+				for n := 0; n < spec.Children[c].count(); n++ {
+					end = emit(ctx, &spec.Children[c], service, end)
+				}
+			}
+		}
+
+		mu.Lock()
+		end = end.Add(spec.Latency.sample(r))
+		failed := r.Float64() < spec.ErrorRate
+		mu.Unlock()
+		if failed {
+			sp.SetStatus(codes.Error, "")
+		}
+		sp.End(trace.WithTimestamp(end))
+		return end
+	}
+
+	for w.running.Load() {
+		if err := limiter.Wait(context.Background()); err != nil {
+			w.logger.Fatal("limiter waited failed, retry", zap.Error(err))
+		}
+
+		emit(context.Background(), &w.scenario.Root, w.scenario.Root.Service, time.Now())
+
+		i++
+		if w.numTraces != 0 {
+			if i >= w.numTraces {
+				break
+			}
+		}
+	}
+	w.logger.Info("traces generated", zap.Int("traces", i))
+	w.wg.Done()
+}
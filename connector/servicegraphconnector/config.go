@@ -49,6 +49,36 @@ type Config struct {
 	// DatabaseNameAttribute is the attribute name used to identify the database name from span attributes.
 	// The default value is db.name.
 	DatabaseNameAttribute string `mapstructure:"database_name_attribute"`
+
+	// MessagingDestinationNodes adds the destinations of the messages, e.g. topics or queues, as intermediate nodes
+	// between the producers and the consumers.
+	MessagingDestinationNodes bool `mapstructure:"messaging_destination_nodes"`
+
+	// Exemplars configures the trace exemplars attached to the edge metrics.
+	Exemplars ExemplarsConfig `mapstructure:"exemplars"`
+
+	// TopologySnapshots configures the topology snapshots emitted when the connector is used in a logs pipeline.
+	TopologySnapshots TopologySnapshotsConfig `mapstructure:"topology_snapshots"`
+}
+
+type ExemplarsConfig struct {
+	// Enabled attaches the trace and span IDs of failed and slow requests to the
+	// request_failed_total and latency histogram data points of their edge.
+	Enabled bool `mapstructure:"enabled"`
+	// SlowThreshold is the latency from which successful requests are kept as exemplars.
+	// If set to 0, only failed requests are kept.
+	SlowThreshold time.Duration `mapstructure:"slow_threshold"`
+	// MaxPerDataPoint is the maximum number of exemplars attached to each data point between two flushes.
+	// The default value is 5.
+	MaxPerDataPoint int `mapstructure:"max_per_data_point"`
+}
+
+type TopologySnapshotsConfig struct {
+	// Interval is the interval at which the snapshots are emitted. The default value is 1m.
+	Interval time.Duration `mapstructure:"interval"`
+	// TTL is the time after which the nodes and edges no longer seen are removed from the snapshots.
+	// The default value is 15m.
+	TTL time.Duration `mapstructure:"ttl"`
 }
 
 type StoreConfig struct {
@@ -40,6 +40,15 @@ func TestLoadConfig(t *testing.T) {
 			CacheLoop:             time.Minute,
 			StoreExpirationLoop:   2 * time.Second,
 			DatabaseNameAttribute: "db.name",
+			Exemplars: ExemplarsConfig{
+				Enabled:         true,
+				SlowThreshold:   500 * time.Millisecond,
+				MaxPerDataPoint: 3,
+			},
+			TopologySnapshots: TopologySnapshotsConfig{
+				Interval: 30 * time.Second,
+				TTL:      10 * time.Minute,
+			},
 		},
 		cfg.Connectors[component.NewID(metadata.Type)],
 	)
@@ -49,8 +49,18 @@ var (
 	}
 
 	defaultDatabaseNameAttribute = semconv.AttributeDBName
+
+	defaultMaxExemplarsPerDataPoint = 5
 )
 
+type exemplarData struct {
+	traceID                      pcommon.TraceID
+	spanID                       pcommon.SpanID
+	timestamp                    pcommon.Timestamp
+	serverLatency, clientLatency float64
+	messagingSystemLatency       float64
+}
+
 type metricSeries struct {
 	dimensions  pcommon.Map
 	lastUpdated int64 // Used to remove stale series
@@ -62,25 +72,34 @@ type serviceGraphConnector struct {
 	config          *Config
 	logger          *zap.Logger
 	metricsConsumer consumer.Metrics
+	logsConsumer    consumer.Logs
 
 	store *store.Store
 
 	startTime time.Time
 
-	seriesMutex                          sync.Mutex
-	reqTotal                             map[string]int64
-	reqFailedTotal                       map[string]int64
-	reqClientDurationSecondsCount        map[string]uint64
-	reqClientDurationSecondsSum          map[string]float64
-	reqClientDurationSecondsBucketCounts map[string][]uint64
-	reqServerDurationSecondsCount        map[string]uint64
-	reqServerDurationSecondsSum          map[string]float64
-	reqServerDurationSecondsBucketCounts map[string][]uint64
-	reqDurationBounds                    []float64
+	seriesMutex                           sync.Mutex
+	reqTotal                              map[string]int64
+	reqFailedTotal                        map[string]int64
+	reqClientDurationSecondsCount         map[string]uint64
+	reqClientDurationSecondsSum           map[string]float64
+	reqClientDurationSecondsBucketCounts  map[string][]uint64
+	reqServerDurationSecondsCount         map[string]uint64
+	reqServerDurationSecondsSum           map[string]float64
+	reqServerDurationSecondsBucketCounts  map[string][]uint64
+	reqMessagingSystemSecondsCount        map[string]uint64
+	reqMessagingSystemSecondsSum          map[string]float64
+	reqMessagingSystemSecondsBucketCounts map[string][]uint64
+	reqDurationBounds                     []float64
+	reqExemplars                          map[string][]exemplarData
+	exemplarsSlowThreshold                float64
 
 	metricMutex sync.RWMutex
 	keyToMetric map[string]metricSeries
 
+	// topology is only tracked when the connector emits topology snapshots
+	topology *topology
+
 	telemetryBuilder *metadata.TelemetryBuilder
 
 	shutdownCh chan any
@@ -117,6 +136,18 @@ func newConnector(set component.TelemetrySettings, config component.Config, next
 		pConfig.DatabaseNameAttribute = defaultDatabaseNameAttribute
 	}
 
+	if pConfig.Exemplars.MaxPerDataPoint <= 0 {
+		pConfig.Exemplars.MaxPerDataPoint = defaultMaxExemplarsPerDataPoint
+	}
+
+	if pConfig.TopologySnapshots.Interval <= 0 {
+		pConfig.TopologySnapshots.Interval = time.Minute
+	}
+
+	if pConfig.TopologySnapshots.TTL <= 0 {
+		pConfig.TopologySnapshots.TTL = 15 * time.Minute
+	}
+
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(set)
 	if err != nil {
 		return nil, err
@@ -127,19 +158,24 @@ func newConnector(set component.TelemetrySettings, config component.Config, next
 		logger:          set.Logger,
 		metricsConsumer: next,
 
-		startTime:                            time.Now(),
-		reqTotal:                             make(map[string]int64),
-		reqFailedTotal:                       make(map[string]int64),
-		reqClientDurationSecondsCount:        make(map[string]uint64),
-		reqClientDurationSecondsSum:          make(map[string]float64),
-		reqClientDurationSecondsBucketCounts: make(map[string][]uint64),
-		reqServerDurationSecondsCount:        make(map[string]uint64),
-		reqServerDurationSecondsSum:          make(map[string]float64),
-		reqServerDurationSecondsBucketCounts: make(map[string][]uint64),
-		reqDurationBounds:                    bounds,
-		keyToMetric:                          make(map[string]metricSeries),
-		shutdownCh:                           make(chan any),
-		telemetryBuilder:                     telemetryBuilder,
+		startTime:                             time.Now(),
+		reqTotal:                              make(map[string]int64),
+		reqFailedTotal:                        make(map[string]int64),
+		reqClientDurationSecondsCount:         make(map[string]uint64),
+		reqClientDurationSecondsSum:           make(map[string]float64),
+		reqClientDurationSecondsBucketCounts:  make(map[string][]uint64),
+		reqServerDurationSecondsCount:         make(map[string]uint64),
+		reqServerDurationSecondsSum:           make(map[string]float64),
+		reqServerDurationSecondsBucketCounts:  make(map[string][]uint64),
+		reqMessagingSystemSecondsCount:        make(map[string]uint64),
+		reqMessagingSystemSecondsSum:          make(map[string]float64),
+		reqMessagingSystemSecondsBucketCounts: make(map[string][]uint64),
+		reqDurationBounds:                     bounds,
+		reqExemplars:                          make(map[string][]exemplarData),
+		exemplarsSlowThreshold:                durationToFloat(pConfig.Exemplars.SlowThreshold),
+		keyToMetric:                           make(map[string]metricSeries),
+		shutdownCh:                            make(chan any),
+		telemetryBuilder:                      telemetryBuilder,
 	}, nil
 }
 
@@ -152,6 +188,10 @@ func (p *serviceGraphConnector) Start(_ context.Context, _ component.Host) error
 
 	go p.storeExpirationLoop(p.config.StoreExpirationLoop)
 
+	if p.logsConsumer != nil {
+		go p.topologySnapshotLoop(p.config.TopologySnapshots.Interval)
+	}
+
 	p.logger.Info("Started servicegraphconnector")
 	return nil
 }
@@ -177,6 +217,11 @@ func (p *serviceGraphConnector) metricFlushLoop(flushInterval time.Duration) {
 }
 
 func (p *serviceGraphConnector) flushMetrics(ctx context.Context) error {
+	// The connector only emits topology snapshots in logs pipelines.
+	if p.metricsConsumer == nil {
+		return nil
+	}
+
 	md, err := p.buildMetrics()
 	if err != nil {
 		return fmt.Errorf("failed to build metrics: %w", err)
@@ -217,12 +262,7 @@ func (p *serviceGraphConnector) ConsumeTraces(ctx context.Context, td ptrace.Tra
 	return nil
 }
 
-func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.Traces) (err error) {
-	var (
-		isNew             bool
-		totalDroppedSpans int
-	)
-
+func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.Traces) error {
 	rss := td.ResourceSpans()
 	for i := 0; i < rss.Len(); i++ {
 		rSpans := rss.At(i)
@@ -251,13 +291,22 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 				case ptrace.SpanKindClient:
 					traceID := span.TraceID()
 					key := store.NewKey(traceID, span.SpanID())
-					isNew, err = p.store.UpsertEdge(key, func(e *store.Edge) {
+					err := p.upsertEdge(ctx, key, func(e *store.Edge) {
 						e.TraceID = traceID
 						e.ConnectionType = connectionType
 						e.ClientService = serviceName
 						e.ClientLatencySec = spanDuration(span)
+						e.ClientEndTimestamp = span.EndTimestamp()
 						e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
 						p.upsertDimensions(clientKind, e.Dimensions, rAttributes, span.Attributes())
+						upsertProtocol(e, span.Attributes())
+
+						if connectionType == store.MessagingSystem {
+							// the destination seen by the producer wins over the one seen by the consumer
+							if destination, ok := pdatautil.GetAttributeValue(semconv.AttributeMessagingDestinationName, span.Attributes()); ok {
+								e.MessagingDestination = destination
+							}
+						}
 
 						if virtualNodeFeatureGate.IsEnabled() {
 							p.upsertPeerAttributes(p.config.VirtualNodePeerAttributes, e.Peer, span.Attributes())
@@ -271,46 +320,80 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 							e.ServerLatencySec = spanDuration(span)
 						}
 					})
+					if err != nil {
+						return err
+					}
 				case ptrace.SpanKindConsumer:
 					// override connection type and continue processing as span kind server
 					connectionType = store.MessagingSystem
 					fallthrough
 				case ptrace.SpanKindServer:
-					traceID := span.TraceID()
-					key := store.NewKey(traceID, span.ParentSpanID())
-					isNew, err = p.store.UpsertEdge(key, func(e *store.Edge) {
-						e.TraceID = traceID
-						e.ConnectionType = connectionType
-						e.ServerService = serviceName
-						e.ServerLatencySec = spanDuration(span)
-						e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
-						p.upsertDimensions(serverKind, e.Dimensions, rAttributes, span.Attributes())
-					})
-				default:
-					// this span is not part of an edge
-					continue
+					for _, key := range serverEdgeKeys(span, connectionType) {
+						err := p.upsertEdge(ctx, key, func(e *store.Edge) {
+							// the trace of the producer is kept for the consumers linking to it
+							if e.TraceID.IsEmpty() {
+								e.TraceID = span.TraceID()
+							}
+							e.ConnectionType = connectionType
+							e.ServerService = serviceName
+							e.ServerLatencySec = spanDuration(span)
+							e.ServerStartTimestamp = span.StartTimestamp()
+							e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
+							p.upsertDimensions(serverKind, e.Dimensions, rAttributes, span.Attributes())
+							upsertProtocol(e, span.Attributes())
+
+							if connectionType == store.MessagingSystem && e.MessagingDestination == "" {
+								if destination, ok := pdatautil.GetAttributeValue(semconv.AttributeMessagingDestinationName, span.Attributes()); ok {
+									e.MessagingDestination = destination
+								}
+							}
+						})
+						if err != nil {
+							return err
+						}
+					}
 				}
+			}
+		}
+	}
+	return nil
+}
 
-				if errors.Is(err, store.ErrTooManyItems) {
-					totalDroppedSpans++
-					p.telemetryBuilder.ConnectorServicegraphDroppedSpans.Add(ctx, 1)
-					continue
-				}
+// upsertEdge adds a span to its edge, counting the new edges and the spans dropped when the store is full.
+func (p *serviceGraphConnector) upsertEdge(ctx context.Context, key store.Key, update store.Callback) error {
+	isNew, err := p.store.UpsertEdge(key, update)
+	if errors.Is(err, store.ErrTooManyItems) {
+		p.telemetryBuilder.ConnectorServicegraphDroppedSpans.Add(ctx, 1)
+		return nil
+	}
 
-				// UpsertEdge will only return ErrTooManyItems
-				if err != nil {
-					return err
-				}
+	// UpsertEdge will only return ErrTooManyItems
+	if err != nil {
+		return err
+	}
 
-				if isNew {
-					p.telemetryBuilder.ConnectorServicegraphTotalEdges.Add(ctx, 1)
-				}
-			}
-		}
+	if isNew {
+		p.telemetryBuilder.ConnectorServicegraphTotalEdges.Add(ctx, 1)
 	}
 	return nil
 }
 
+// serverEdgeKeys returns the keys of the edges of a server or consumer span. Consumers are paired
+// with the producer spans they link to, as batch consumers and consumers starting a new trace do,
+// and with their parent span otherwise.
+func serverEdgeKeys(span ptrace.Span, connectionType store.ConnectionType) []store.Key {
+	links := span.Links()
+	if connectionType != store.MessagingSystem || links.Len() == 0 {
+		return []store.Key{store.NewKey(span.TraceID(), span.ParentSpanID())}
+	}
+
+	keys := make([]store.Key, 0, links.Len())
+	for i := 0; i < links.Len(); i++ {
+		keys = append(keys, store.NewKey(links.At(i).TraceID(), links.At(i).SpanID()))
+	}
+	return keys
+}
+
 func (p *serviceGraphConnector) upsertDimensions(kind string, m map[string]string, resourceAttr pcommon.Map, spanAttr pcommon.Map) {
 	for _, dim := range p.config.Dimensions {
 		if v, ok := pdatautil.GetAttributeValue(dim, resourceAttr, spanAttr); ok {
@@ -319,6 +402,16 @@ func (p *serviceGraphConnector) upsertDimensions(kind string, m map[string]strin
 	}
 }
 
+// upsertProtocol sets the protocol of the edge, unless already found in the span of the other side.
+func upsertProtocol(e *store.Edge, spanAttr pcommon.Map) {
+	if e.Protocol != "" {
+		return
+	}
+	if protocol, ok := findProtocol(spanAttr); ok {
+		e.Protocol = protocol
+	}
+}
+
 func (p *serviceGraphConnector) upsertPeerAttributes(m []string, peers map[string]string, spanAttr pcommon.Map) {
 	for _, s := range m {
 		if v, ok := pdatautil.GetAttributeValue(s, spanAttr); ok {
@@ -336,7 +429,55 @@ func (p *serviceGraphConnector) onComplete(e *store.Edge) {
 		zap.String("connection_type", string(e.ConnectionType)),
 		zap.Stringer("trace_id", e.TraceID),
 	)
-	p.aggregateMetricsForEdge(e)
+	for _, edge := range p.splitMessagingEdge(e) {
+		if p.topology != nil {
+			p.topology.record(edge)
+		}
+		if p.metricsConsumer != nil {
+			p.aggregateMetricsForEdge(edge)
+		}
+	}
+}
+
+// splitMessagingEdge splits an edge between a producer and a consumer in two edges going through their messaging
+// destination node, when enabled. The edge from the destination to the consumer includes the time spent by the
+// message in the messaging system in its client latency.
+func (p *serviceGraphConnector) splitMessagingEdge(e *store.Edge) []*store.Edge {
+	if !p.config.MessagingDestinationNodes || e.ConnectionType != store.MessagingSystem || e.MessagingDestination == "" {
+		return []*store.Edge{e}
+	}
+
+	var edges []*store.Edge
+	if len(e.ClientService) != 0 {
+		producer := *e
+		producer.ServerService = e.MessagingDestination
+		producer.ServerLatencySec = e.ClientLatencySec
+		producer.ServerStartTimestamp = 0
+		producer.Dimensions = filterDimensions(e.Dimensions, clientKind)
+		edges = append(edges, &producer)
+	}
+	if len(e.ServerService) != 0 {
+		consumer := *e
+		consumer.ClientService = e.MessagingDestination
+		consumer.ClientLatencySec = e.ServerLatencySec
+		if latency, ok := messagingSystemLatency(e); ok {
+			consumer.ClientLatencySec += latency
+		}
+		consumer.Dimensions = filterDimensions(e.Dimensions, serverKind)
+		edges = append(edges, &consumer)
+	}
+	return edges
+}
+
+// filterDimensions returns the dimensions coming from the spans of the given kind.
+func filterDimensions(dimensions map[string]string, kind string) map[string]string {
+	filtered := make(map[string]string, len(dimensions))
+	for k, v := range dimensions {
+		if strings.HasPrefix(k, kind+"_") {
+			filtered[k] = v
+		}
+	}
+	return filtered
 }
 
 func (p *serviceGraphConnector) onExpire(e *store.Edge) {
@@ -350,21 +491,23 @@ func (p *serviceGraphConnector) onExpire(e *store.Edge) {
 
 	p.telemetryBuilder.ConnectorServicegraphExpiredEdges.Add(context.Background(), 1)
 
+	if p.config.MessagingDestinationNodes && e.ConnectionType == store.MessagingSystem && e.MessagingDestination != "" {
+		// the side of the edge seen is complete on its own, going to or coming from the destination node
+		p.onComplete(e)
+		return
+	}
+
 	if virtualNodeFeatureGate.IsEnabled() && len(p.config.VirtualNodePeerAttributes) > 0 {
 		e.ConnectionType = store.VirtualNode
 		if len(e.ClientService) == 0 && e.Key.SpanIDIsEmpty() {
 			e.ClientService = "user"
-			if p.config.VirtualNodeExtraLabel {
-				e.VirtualNodeLabel = store.ClientVirtualNode
-			}
+			e.VirtualNodeLabel = store.ClientVirtualNode
 			p.onComplete(e)
 		}
 
 		if len(e.ServerService) == 0 {
 			e.ServerService = p.getPeerHost(p.config.VirtualNodePeerAttributes, e.Peer)
-			if p.config.VirtualNodeExtraLabel {
-				e.VirtualNodeLabel = store.ServerVirtualNode
-			}
+			e.VirtualNodeLabel = store.ServerVirtualNode
 			p.onComplete(e)
 		}
 	}
@@ -386,6 +529,12 @@ func (p *serviceGraphConnector) aggregateMetricsForEdge(e *store.Edge) {
 		p.updateErrorMetrics(metricKey)
 	}
 	p.updateDurationMetrics(metricKey, e.ServerLatencySec, e.ClientLatencySec)
+	if latency, ok := messagingSystemLatency(e); ok {
+		p.updateMessagingSystemDurationMetrics(metricKey, latency)
+	}
+	if p.config.Exemplars.Enabled {
+		p.updateExemplars(metricKey, e)
+	}
 }
 
 func (p *serviceGraphConnector) updateSeries(key string, dimensions pcommon.Map) {
@@ -427,6 +576,16 @@ func (p *serviceGraphConnector) updateServerDurationMetrics(key string, duration
 	p.reqServerDurationSecondsBucketCounts[key][index]++
 }
 
+func (p *serviceGraphConnector) updateMessagingSystemDurationMetrics(key string, duration float64) {
+	index := sort.SearchFloat64s(p.reqDurationBounds, duration) // Search bucket index
+	if _, ok := p.reqMessagingSystemSecondsBucketCounts[key]; !ok {
+		p.reqMessagingSystemSecondsBucketCounts[key] = make([]uint64, len(p.reqDurationBounds)+1)
+	}
+	p.reqMessagingSystemSecondsSum[key] += duration
+	p.reqMessagingSystemSecondsCount[key]++
+	p.reqMessagingSystemSecondsBucketCounts[key][index]++
+}
+
 func (p *serviceGraphConnector) updateClientDurationMetrics(key string, duration float64) {
 	index := sort.SearchFloat64s(p.reqDurationBounds, duration) // Search bucket index
 	if _, ok := p.reqClientDurationSecondsBucketCounts[key]; !ok {
@@ -437,6 +596,34 @@ func (p *serviceGraphConnector) updateClientDurationMetrics(key string, duration
 	p.reqClientDurationSecondsBucketCounts[key][index]++
 }
 
+// updateExemplars keeps the failed and slow requests of the series as exemplars, until the next flush.
+func (p *serviceGraphConnector) updateExemplars(key string, e *store.Edge) {
+	slow := p.exemplarsSlowThreshold > 0 && max(e.ServerLatencySec, e.ClientLatencySec) >= p.exemplarsSlowThreshold
+	if !e.Failed && !slow {
+		return
+	}
+	if len(p.reqExemplars[key]) >= p.config.Exemplars.MaxPerDataPoint {
+		return
+	}
+	data := exemplarData{
+		traceID:       e.TraceID,
+		spanID:        e.Key.SpanID(),
+		timestamp:     pcommon.NewTimestampFromTime(time.Now()),
+		serverLatency: e.ServerLatencySec,
+		clientLatency: e.ClientLatencySec,
+	}
+	data.messagingSystemLatency, _ = messagingSystemLatency(e)
+	p.reqExemplars[key] = append(p.reqExemplars[key], data)
+}
+
+func appendExemplar(exemplars pmetric.ExemplarSlice, data exemplarData) pmetric.Exemplar {
+	exemplar := exemplars.AppendEmpty()
+	exemplar.SetTraceID(data.traceID)
+	exemplar.SetSpanID(data.spanID)
+	exemplar.SetTimestamp(data.timestamp)
+	return exemplar
+}
+
 func buildDimensions(e *store.Edge) pcommon.Map {
 	dims := pcommon.NewMap()
 	dims.PutStr("client", e.ClientService)
@@ -471,6 +658,9 @@ func (p *serviceGraphConnector) buildMetrics() (pmetric.Metrics, error) {
 		return m, err
 	}
 
+	// The exemplars are only attached to the data points of the flush following their request.
+	clear(p.reqExemplars)
+
 	return m, nil
 }
 
@@ -509,6 +699,9 @@ func (p *serviceGraphConnector) collectCountMetrics(ilm pmetric.ScopeMetrics) er
 			dpCalls.SetStartTimestamp(pcommon.NewTimestampFromTime(p.startTime))
 			dpCalls.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
 			dpCalls.SetIntValue(c)
+			for _, data := range p.reqExemplars[key] {
+				appendExemplar(dpCalls.Exemplars(), data).SetIntValue(1)
+			}
 
 			dimensions, ok := p.dimensionsForSeries(key)
 			if !ok {
@@ -532,7 +725,46 @@ func (p *serviceGraphConnector) collectLatencyMetrics(ilm pmetric.ScopeMetrics)
 		return err
 	}
 
-	return p.collectClientLatencyMetrics(ilm)
+	if err := p.collectClientLatencyMetrics(ilm); err != nil {
+		return err
+	}
+
+	return p.collectMessagingSystemLatencyMetrics(ilm)
+}
+
+func (p *serviceGraphConnector) collectMessagingSystemLatencyMetrics(ilm pmetric.ScopeMetrics) error {
+	if len(p.reqMessagingSystemSecondsCount) > 0 {
+		mDuration := ilm.Metrics().AppendEmpty()
+		mDuration.SetName("traces_service_graph_request_messaging_system")
+		mDuration.SetUnit(secondsUnit)
+		if legacyLatencyUnitMsFeatureGate.IsEnabled() {
+			mDuration.SetUnit(millisecondsUnit)
+		}
+		// TODO: Support other aggregation temporalities
+		mDuration.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+		for key := range p.reqMessagingSystemSecondsCount {
+			dpDuration := mDuration.Histogram().DataPoints().AppendEmpty()
+			dpDuration.SetStartTimestamp(pcommon.NewTimestampFromTime(p.startTime))
+			dpDuration.SetTimestamp(timestamp)
+			dpDuration.ExplicitBounds().FromRaw(p.reqDurationBounds)
+			dpDuration.BucketCounts().FromRaw(p.reqMessagingSystemSecondsBucketCounts[key])
+			dpDuration.SetCount(p.reqMessagingSystemSecondsCount[key])
+			dpDuration.SetSum(p.reqMessagingSystemSecondsSum[key])
+			for _, data := range p.reqExemplars[key] {
+				appendExemplar(dpDuration.Exemplars(), data).SetDoubleValue(data.messagingSystemLatency)
+			}
+
+			dimensions, ok := p.dimensionsForSeries(key)
+			if !ok {
+				return fmt.Errorf("failed to find dimensions for key %s", key)
+			}
+
+			dimensions.CopyTo(dpDuration.Attributes())
+		}
+	}
+	return nil
 }
 
 func (p *serviceGraphConnector) collectClientLatencyMetrics(ilm pmetric.ScopeMetrics) error {
@@ -555,8 +787,10 @@ func (p *serviceGraphConnector) collectClientLatencyMetrics(ilm pmetric.ScopeMet
 			dpDuration.BucketCounts().FromRaw(p.reqClientDurationSecondsBucketCounts[key])
 			dpDuration.SetCount(p.reqClientDurationSecondsCount[key])
 			dpDuration.SetSum(p.reqClientDurationSecondsSum[key])
+			for _, data := range p.reqExemplars[key] {
+				appendExemplar(dpDuration.Exemplars(), data).SetDoubleValue(data.clientLatency)
+			}
 
-			// TODO: Support exemplars
 			dimensions, ok := p.dimensionsForSeries(key)
 			if !ok {
 				return fmt.Errorf("failed to find dimensions for key %s", key)
@@ -588,8 +822,10 @@ func (p *serviceGraphConnector) collectServerLatencyMetrics(ilm pmetric.ScopeMet
 			dpDuration.BucketCounts().FromRaw(p.reqServerDurationSecondsBucketCounts[key])
 			dpDuration.SetCount(p.reqServerDurationSecondsCount[key])
 			dpDuration.SetSum(p.reqServerDurationSecondsSum[key])
+			for _, data := range p.reqExemplars[key] {
+				appendExemplar(dpDuration.Exemplars(), data).SetDoubleValue(data.serverLatency)
+			}
 
-			// TODO: Support exemplars
 			dimensions, ok := p.dimensionsForSeries(key)
 			if !ok {
 				return fmt.Errorf("failed to find dimensions for key %s", key)
@@ -629,6 +865,31 @@ func (p *serviceGraphConnector) storeExpirationLoop(d time.Duration) {
 	}
 }
 
+// topologySnapshotLoop periodically emits the snapshots of the topology.
+func (p *serviceGraphConnector) topologySnapshotLoop(d time.Duration) {
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := p.emitTopologySnapshot(context.Background()); err != nil {
+				p.logger.Error("failed to emit the topology snapshot", zap.Error(err))
+			}
+		case <-p.shutdownCh:
+			return
+		}
+	}
+}
+
+func (p *serviceGraphConnector) emitTopologySnapshot(ctx context.Context) error {
+	ld := p.topology.snapshot()
+	// Skip empty snapshots.
+	if ld.LogRecordCount() == 0 {
+		return nil
+	}
+	return p.logsConsumer.ConsumeLogs(ctx, ld)
+}
+
 func (p *serviceGraphConnector) getPeerHost(m []string, peers map[string]string) string {
 	peerStr := "unknown"
 	for _, s := range m {
@@ -674,6 +935,10 @@ func (p *serviceGraphConnector) cleanCache() {
 		delete(p.reqServerDurationSecondsCount, key)
 		delete(p.reqServerDurationSecondsSum, key)
 		delete(p.reqServerDurationSecondsBucketCounts, key)
+		delete(p.reqMessagingSystemSecondsCount, key)
+		delete(p.reqMessagingSystemSecondsSum, key)
+		delete(p.reqMessagingSystemSecondsBucketCounts, key)
+		delete(p.reqExemplars, key)
 	}
 	p.seriesMutex.Unlock()
 
@@ -686,10 +951,28 @@ func (p *serviceGraphConnector) cleanCache() {
 
 // spanDuration returns the duration of the given span in seconds (legacy ms).
 func spanDuration(span ptrace.Span) float64 {
+	return timestampsDuration(span.StartTimestamp(), span.EndTimestamp())
+}
+
+// messagingSystemLatency returns the time spent by a message in the messaging system, from the end of the producer
+// span to the start of the consumer span, in seconds (legacy ms).
+func messagingSystemLatency(e *store.Edge) (float64, bool) {
+	if e.ConnectionType != store.MessagingSystem || e.ClientEndTimestamp == 0 || e.ServerStartTimestamp == 0 {
+		return 0, false
+	}
+	// The clocks of the producer and the consumer might be skewed.
+	if e.ServerStartTimestamp < e.ClientEndTimestamp {
+		return 0, true
+	}
+	return timestampsDuration(e.ClientEndTimestamp, e.ServerStartTimestamp), true
+}
+
+// timestampsDuration returns the duration between the given timestamps in seconds (legacy ms).
+func timestampsDuration(start, end pcommon.Timestamp) float64 {
 	if legacyLatencyUnitMsFeatureGate.IsEnabled() {
-		return float64(span.EndTimestamp()-span.StartTimestamp()) / float64(time.Millisecond.Nanoseconds())
+		return float64(end-start) / float64(time.Millisecond.Nanoseconds())
 	}
-	return float64(span.EndTimestamp()-span.StartTimestamp()) / float64(time.Second.Nanoseconds())
+	return float64(end-start) / float64(time.Second.Nanoseconds())
 }
 
 // durationToFloat converts the given duration to the number of seconds (legacy ms) it represents.
@@ -739,3 +739,354 @@ func TestVirtualNodeClientLabels(t *testing.T) {
 	)
 	require.NoError(t, err)
 }
+
+func TestExemplars(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		exemplars         ExemplarsConfig
+		failed            bool
+		expectedExemplars int
+	}{
+		{
+			name:      "disabled",
+			exemplars: ExemplarsConfig{SlowThreshold: time.Second},
+			failed:    true,
+		},
+		{
+			name:              "failed request",
+			exemplars:         ExemplarsConfig{Enabled: true},
+			failed:            true,
+			expectedExemplars: 1,
+		},
+		{
+			name:              "slow request",
+			exemplars:         ExemplarsConfig{Enabled: true, SlowThreshold: 1500 * time.Millisecond},
+			expectedExemplars: 1,
+		},
+		{
+			name:      "fast request",
+			exemplars: ExemplarsConfig{Enabled: true, SlowThreshold: 5 * time.Second},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Prepare
+			cfg := &Config{
+				Store:     StoreConfig{MaxItems: 10, TTL: time.Nanosecond},
+				Exemplars: tc.exemplars,
+			}
+			set := componenttest.NewNopTelemetrySettings()
+			set.Logger = zaptest.NewLogger(t)
+			conn, err := newConnector(set, cfg, newMockMetricsExporter())
+			require.NoError(t, err)
+			require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() { require.NoError(t, conn.Shutdown(context.Background())) }()
+
+			td := buildSampleTrace(t, "val")
+			clientSpan := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+			if tc.failed {
+				clientSpan.Status().SetCode(ptrace.StatusCodeError)
+			}
+
+			// Test
+			require.NoError(t, conn.ConsumeTraces(context.Background(), td))
+
+			// Verify
+			flushed := conn.metricsConsumer.(*mockMetricsExporter).GetMetrics()
+			require.Len(t, flushed, 1)
+			metrics := flushed[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			for i := 0; i < metrics.Len(); i++ {
+				m := metrics.At(i)
+				var exemplars pmetric.ExemplarSlice
+				switch m.Name() {
+				case "traces_service_graph_request_total":
+					assert.Equal(t, 0, m.Sum().DataPoints().At(0).Exemplars().Len())
+					continue
+				case "traces_service_graph_request_failed_total":
+					exemplars = m.Sum().DataPoints().At(0).Exemplars()
+				default:
+					exemplars = m.Histogram().DataPoints().At(0).Exemplars()
+				}
+
+				require.Equal(t, tc.expectedExemplars, exemplars.Len(), m.Name())
+				if tc.expectedExemplars == 0 {
+					continue
+				}
+				exemplar := exemplars.At(0)
+				assert.Equal(t, clientSpan.TraceID(), exemplar.TraceID())
+				assert.Equal(t, clientSpan.SpanID(), exemplar.SpanID())
+				switch m.Name() {
+				case "traces_service_graph_request_failed_total":
+					assert.Equal(t, int64(1), exemplar.IntValue())
+				case "traces_service_graph_request_server":
+					assert.Equal(t, 2.0, exemplar.DoubleValue())
+				case "traces_service_graph_request_client":
+					assert.Equal(t, 1.0, exemplar.DoubleValue())
+				}
+			}
+
+			// the exemplars are only attached to the next flush
+			md, err := conn.buildMetrics()
+			require.NoError(t, err)
+			metrics = md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			for i := 0; i < metrics.Len(); i++ {
+				if metrics.At(i).Type() == pmetric.MetricTypeHistogram {
+					assert.Equal(t, 0, metrics.At(i).Histogram().DataPoints().At(0).Exemplars().Len())
+				}
+			}
+		})
+	}
+}
+
+func TestExemplarsMaxPerDataPoint(t *testing.T) {
+	// Prepare
+	cfg := &Config{
+		Store:     StoreConfig{MaxItems: 10, TTL: time.Nanosecond},
+		Exemplars: ExemplarsConfig{Enabled: true, SlowThreshold: time.Millisecond, MaxPerDataPoint: 2},
+	}
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zaptest.NewLogger(t)
+	conn, err := newConnector(set, cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, conn.Shutdown(context.Background())) }()
+
+	// Test
+	td := buildSampleTrace(t, "val")
+	for i := 0; i < 2; i++ {
+		buildSampleTrace(t, "val").ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	}
+	require.NoError(t, conn.ConsumeTraces(context.Background(), td))
+	flushed := conn.metricsConsumer.(*mockMetricsExporter).GetMetrics()
+	require.Len(t, flushed, 1)
+	md := flushed[0]
+
+	// Verify
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		if metrics.At(i).Type() == pmetric.MetricTypeHistogram {
+			dp := metrics.At(i).Histogram().DataPoints().At(0)
+			assert.Equal(t, uint64(3), dp.Count())
+			assert.Equal(t, 2, dp.Exemplars().Len())
+		}
+	}
+}
+
+func TestTopologySnapshots(t *testing.T) {
+	// Prepare
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Store.TTL = time.Nanosecond
+	cfg.TopologySnapshots.Interval = time.Millisecond
+
+	sink := new(consumertest.LogsSink)
+	traceConnector, err := factory.CreateTracesToLogs(context.Background(), connectortest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	conn := traceConnector.(*serviceGraphConnector)
+	require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, conn.Shutdown(context.Background())) }()
+
+	td := buildSampleTrace(t, "val")
+	td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().PutStr("rpc.system", "grpc")
+
+	// Test
+	require.NoError(t, conn.ConsumeTraces(context.Background(), td))
+
+	// Verify
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() > 0
+	}, 5*time.Second, time.Millisecond)
+	records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, records.Len())
+
+	node := records.At(0)
+	verifyAttr(t, node.Attributes(), "event.name", "servicegraph.node")
+	assert.Equal(t, "some-service", node.Body().Map().AsRaw()["name"])
+
+	edge := records.At(1)
+	verifyAttr(t, edge.Attributes(), "event.name", "servicegraph.edge")
+	body := edge.Body().Map().AsRaw()
+	assert.Equal(t, "some-service", body["client"])
+	assert.Equal(t, "some-service", body["server"])
+	assert.Equal(t, []any{"grpc"}, body["protocols"])
+	assert.Equal(t, int64(1), body["requests"])
+
+	// no metrics are built by the logs connector
+	assert.Empty(t, conn.reqTotal)
+}
+
+// buildMessagingTrace builds a producer span and a consumer span linking to it from another trace, the message
+// spending 3s in the messaging system.
+func buildMessagingTrace(t *testing.T, withConsumer bool) ptrace.Traces {
+	tStart := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	traces := ptrace.NewTraces()
+
+	var producerTraceID, consumerTraceID pcommon.TraceID
+	var producerSpanID, consumerSpanID pcommon.SpanID
+	for _, id := range [][]byte{producerTraceID[:], consumerTraceID[:], producerSpanID[:], consumerSpanID[:]} {
+		_, err := rand.Read(id)
+		require.NoError(t, err)
+	}
+
+	producerSpans := traces.ResourceSpans().AppendEmpty()
+	producerSpans.Resource().Attributes().PutStr(semconv.AttributeServiceName, "producer-service")
+	producer := producerSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	producer.SetName("orders publish")
+	producer.SetTraceID(producerTraceID)
+	producer.SetSpanID(producerSpanID)
+	producer.SetKind(ptrace.SpanKindProducer)
+	producer.SetStartTimestamp(pcommon.NewTimestampFromTime(tStart))
+	producer.SetEndTimestamp(pcommon.NewTimestampFromTime(tStart.Add(time.Second)))
+	producer.Attributes().PutStr(semconv.AttributeMessagingSystem, "kafka")
+	producer.Attributes().PutStr(semconv.AttributeMessagingDestinationName, "orders")
+
+	if !withConsumer {
+		return traces
+	}
+
+	consumerSpans := traces.ResourceSpans().AppendEmpty()
+	consumerSpans.Resource().Attributes().PutStr(semconv.AttributeServiceName, "consumer-service")
+	consumer := consumerSpans.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	consumer.SetName("orders process")
+	consumer.SetTraceID(consumerTraceID)
+	consumer.SetSpanID(consumerSpanID)
+	consumer.SetKind(ptrace.SpanKindConsumer)
+	consumer.SetStartTimestamp(pcommon.NewTimestampFromTime(tStart.Add(4 * time.Second)))
+	consumer.SetEndTimestamp(pcommon.NewTimestampFromTime(tStart.Add(6 * time.Second)))
+	consumer.Attributes().PutStr(semconv.AttributeMessagingSystem, "kafka")
+	link := consumer.Links().AppendEmpty()
+	link.SetTraceID(producerTraceID)
+	link.SetSpanID(producerSpanID)
+
+	return traces
+}
+
+func TestMessagingEdgesThroughLinks(t *testing.T) {
+	// Prepare
+	cfg := &Config{Store: StoreConfig{MaxItems: 10, TTL: time.Minute}}
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zaptest.NewLogger(t)
+	conn, err := newConnector(set, cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, conn.Shutdown(context.Background())) }()
+
+	// Test
+	require.NoError(t, conn.ConsumeTraces(context.Background(), buildMessagingTrace(t, true)))
+
+	// Verify
+	assert.Equal(t, 0, conn.store.Len())
+	md, err := conn.buildMetrics()
+	require.NoError(t, err)
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	sums := map[string]float64{}
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		if m.Type() == pmetric.MetricTypeHistogram {
+			sums[m.Name()] = m.Histogram().DataPoints().At(0).Sum()
+			continue
+		}
+		attrs := m.Sum().DataPoints().At(0).Attributes()
+		verifyAttr(t, attrs, "client", "producer-service")
+		verifyAttr(t, attrs, "server", "consumer-service")
+		verifyAttr(t, attrs, "connection_type", "messaging_system")
+	}
+	assert.Equal(t, map[string]float64{
+		"traces_service_graph_request_client":           1,
+		"traces_service_graph_request_server":           2,
+		"traces_service_graph_request_messaging_system": 3,
+	}, sums)
+}
+
+func TestMessagingDestinationNodes(t *testing.T) {
+	// Prepare
+	cfg := &Config{
+		Dimensions:                []string{semconv.AttributeMessagingSystem},
+		Store:                     StoreConfig{MaxItems: 10, TTL: time.Minute},
+		MessagingDestinationNodes: true,
+	}
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zaptest.NewLogger(t)
+	conn, err := newConnector(set, cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	conn.topology = newTopology(time.Minute)
+	require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, conn.Shutdown(context.Background())) }()
+
+	// Test
+	require.NoError(t, conn.ConsumeTraces(context.Background(), buildMessagingTrace(t, true)))
+
+	// Verify
+	md, err := conn.buildMetrics()
+	require.NoError(t, err)
+	sums := map[string]map[string]float64{}
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		if m.Type() != pmetric.MetricTypeHistogram {
+			continue
+		}
+		for j := 0; j < m.Histogram().DataPoints().Len(); j++ {
+			dp := m.Histogram().DataPoints().At(j)
+			client, _ := dp.Attributes().Get("client")
+			server, _ := dp.Attributes().Get("server")
+			edge := client.Str() + "->" + server.Str()
+			if sums[edge] == nil {
+				sums[edge] = map[string]float64{}
+			}
+			sums[edge][m.Name()] = dp.Sum()
+
+			// each edge only holds the dimensions of its own side
+			_, hasClientDim := dp.Attributes().Get("client_messaging.system")
+			_, hasServerDim := dp.Attributes().Get("server_messaging.system")
+			assert.Equal(t, edge == "producer-service->orders", hasClientDim, edge)
+			assert.Equal(t, edge == "orders->consumer-service", hasServerDim, edge)
+		}
+	}
+	assert.Equal(t, map[string]map[string]float64{
+		"producer-service->orders": {
+			"traces_service_graph_request_client": 1,
+			"traces_service_graph_request_server": 1,
+		},
+		"orders->consumer-service": {
+			// the time spent in the messaging system is included in the latency of the edge
+			"traces_service_graph_request_client":           5,
+			"traces_service_graph_request_server":           2,
+			"traces_service_graph_request_messaging_system": 3,
+		},
+	}, sums)
+
+	nodes, _ := snapshotBodies(t, conn.topology.snapshot())
+	require.Len(t, nodes, 3)
+	assert.Equal(t, "orders", nodes[1]["name"])
+	assert.Equal(t, "messaging_destination", nodes[1]["type"])
+}
+
+func TestMessagingDestinationNodesUnpairedProducer(t *testing.T) {
+	// Prepare
+	cfg := &Config{
+		Store:                     StoreConfig{MaxItems: 10, TTL: time.Nanosecond},
+		MessagingDestinationNodes: true,
+	}
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zaptest.NewLogger(t)
+	conn, err := newConnector(set, cfg, newMockMetricsExporter())
+	require.NoError(t, err)
+	require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, conn.Shutdown(context.Background())) }()
+
+	// Test
+	require.NoError(t, conn.ConsumeTraces(context.Background(), buildMessagingTrace(t, false)))
+	if runtime.GOOS == "windows" {
+		// On Windows timing doesn't tick forward quickly for the store data to expire, force a wait before expiring.
+		time.Sleep(time.Second)
+	}
+	conn.store.Expire()
+
+	// Verify
+	md, err := conn.buildMetrics()
+	require.NoError(t, err)
+	dps := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	verifyAttr(t, dps.At(0).Attributes(), "client", "producer-service")
+	verifyAttr(t, dps.At(0).Attributes(), "server", "orders")
+}
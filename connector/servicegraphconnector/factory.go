@@ -55,6 +55,7 @@ func NewFactory() connector.Factory {
 		metadata.Type,
 		createDefaultConfig,
 		connector.WithTracesToMetrics(createTracesToMetricsConnector, metadata.TracesToMetricsStability),
+		connector.WithTracesToLogs(createTracesToLogsConnector, metadata.TracesToLogsStability),
 	)
 }
 
@@ -72,3 +73,13 @@ func createDefaultConfig() component.Config {
 func createTracesToMetricsConnector(_ context.Context, params connector.Settings, cfg component.Config, nextConsumer consumer.Metrics) (connector.Traces, error) {
 	return newConnector(params.TelemetrySettings, cfg, nextConsumer)
 }
+
+func createTracesToLogsConnector(_ context.Context, params connector.Settings, cfg component.Config, nextConsumer consumer.Logs) (connector.Traces, error) {
+	c, err := newConnector(params.TelemetrySettings, cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.logsConsumer = nextConsumer
+	c.topology = newTopology(c.config.TopologySnapshots.TTL)
+	return c, nil
+}
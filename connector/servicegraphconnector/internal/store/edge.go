@@ -50,6 +50,16 @@ type Edge struct {
 
 	// VirtualNodeLabel is an optional label to be added to the spans
 	VirtualNodeLabel VirtualNodeLabel
+
+	// Protocol is the protocol of the request, when found in the attributes of the spans
+	Protocol string
+
+	// MessagingDestination is the destination of the message sent through a messaging system
+	MessagingDestination string
+
+	// ClientEndTimestamp and ServerStartTimestamp are used to measure the time spent
+	// by messages in the messaging system
+	ClientEndTimestamp, ServerStartTimestamp pcommon.Timestamp
 }
 
 func newEdge(key Key, ttl time.Duration) *Edge {
@@ -25,6 +25,11 @@ func (k *Key) SpanIDIsEmpty() bool {
 	return k.sid.IsEmpty()
 }
 
+// SpanID returns the ID of the client span of the edge.
+func (k *Key) SpanID() pcommon.SpanID {
+	return k.sid
+}
+
 func NewKey(tid pcommon.TraceID, sid pcommon.SpanID) Key {
 	return Key{tid: tid, sid: sid}
 }
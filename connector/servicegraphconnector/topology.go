@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package servicegraphconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector"
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector/internal/store"
+)
+
+const (
+	nodeEventName = "servicegraph.node"
+	edgeEventName = "servicegraph.edge"
+
+	serviceNodeType  = "service"
+	databaseNodeType = "database"
+	virtualNodeType  = "virtual_node"
+
+	messagingDestinationNodeType = "messaging_destination"
+)
+
+type topologyNode struct {
+	nodeType            string
+	firstSeen, lastSeen time.Time
+}
+
+type topologyEdgeKey struct {
+	client, server string
+	connectionType store.ConnectionType
+}
+
+type topologyEdge struct {
+	protocols           map[string]struct{}
+	requests, failed    int64
+	firstSeen, lastSeen time.Time
+}
+
+// topology keeps track of the nodes and edges of the service graph, to emit snapshots of it.
+type topology struct {
+	mu    sync.Mutex
+	nodes map[string]*topologyNode
+	edges map[topologyEdgeKey]*topologyEdge
+
+	ttl time.Duration
+	now func() time.Time
+}
+
+func newTopology(ttl time.Duration) *topology {
+	return &topology{
+		nodes: make(map[string]*topologyNode),
+		edges: make(map[topologyEdgeKey]*topologyEdge),
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+// record adds the completed edge to the topology.
+func (t *topology) record(e *store.Edge) {
+	clientType, serverType := serviceNodeType, serviceNodeType
+	switch {
+	case e.ConnectionType == store.Database:
+		serverType = databaseNodeType
+	case e.MessagingDestination != "" && e.ServerService == e.MessagingDestination:
+		serverType = messagingDestinationNodeType
+	case e.MessagingDestination != "" && e.ClientService == e.MessagingDestination:
+		clientType = messagingDestinationNodeType
+	case e.VirtualNodeLabel == store.ClientVirtualNode:
+		clientType = virtualNodeType
+	case e.VirtualNodeLabel == store.ServerVirtualNode:
+		serverType = virtualNodeType
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.recordNode(e.ClientService, clientType, now)
+	t.recordNode(e.ServerService, serverType, now)
+
+	key := topologyEdgeKey{client: e.ClientService, server: e.ServerService, connectionType: e.ConnectionType}
+	edge, ok := t.edges[key]
+	if !ok {
+		edge = &topologyEdge{protocols: make(map[string]struct{}), firstSeen: now}
+		t.edges[key] = edge
+	}
+	edge.lastSeen = now
+	edge.requests++
+	if e.Failed {
+		edge.failed++
+	}
+	if e.Protocol != "" {
+		edge.protocols[e.Protocol] = struct{}{}
+	}
+}
+
+func (t *topology) recordNode(name, nodeType string, now time.Time) {
+	node, ok := t.nodes[name]
+	if !ok {
+		node = &topologyNode{firstSeen: now}
+		t.nodes[name] = node
+	}
+	// a service seen as a client is not a virtual node, nor a database
+	if !ok || nodeType == serviceNodeType {
+		node.nodeType = nodeType
+	}
+	node.lastSeen = now
+}
+
+// snapshot returns a log record for each node and edge seen within the TTL, and forgets the others.
+func (t *topology) snapshot() plog.Logs {
+	logs := plog.NewLogs()
+	sl := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("traces_service_graph")
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	timestamp := pcommon.NewTimestampFromTime(now)
+
+	names := make([]string, 0, len(t.nodes))
+	for name, node := range t.nodes {
+		if now.Sub(node.lastSeen) > t.ttl {
+			delete(t.nodes, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		node := t.nodes[name]
+		body := newSnapshotRecord(sl.LogRecords(), nodeEventName, timestamp)
+		body.PutStr("name", name)
+		body.PutStr("type", node.nodeType)
+		body.PutStr("first_seen", node.firstSeen.UTC().Format(time.RFC3339Nano))
+		body.PutStr("last_seen", node.lastSeen.UTC().Format(time.RFC3339Nano))
+	}
+
+	keys := make([]topologyEdgeKey, 0, len(t.edges))
+	for key, edge := range t.edges {
+		if now.Sub(edge.lastSeen) > t.ttl {
+			delete(t.edges, key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].client != keys[j].client {
+			return keys[i].client < keys[j].client
+		}
+		if keys[i].server != keys[j].server {
+			return keys[i].server < keys[j].server
+		}
+		return keys[i].connectionType < keys[j].connectionType
+	})
+	for _, key := range keys {
+		edge := t.edges[key]
+		body := newSnapshotRecord(sl.LogRecords(), edgeEventName, timestamp)
+		body.PutStr("client", key.client)
+		body.PutStr("server", key.server)
+		body.PutStr("connection_type", string(key.connectionType))
+		protocols := make([]string, 0, len(edge.protocols))
+		for protocol := range edge.protocols {
+			protocols = append(protocols, protocol)
+		}
+		sort.Strings(protocols)
+		protocolsSlice := body.PutEmptySlice("protocols")
+		for _, protocol := range protocols {
+			protocolsSlice.AppendEmpty().SetStr(protocol)
+		}
+		body.PutInt("requests", edge.requests)
+		body.PutInt("failed_requests", edge.failed)
+		body.PutStr("first_seen", edge.firstSeen.UTC().Format(time.RFC3339Nano))
+		body.PutStr("last_seen", edge.lastSeen.UTC().Format(time.RFC3339Nano))
+	}
+
+	return logs
+}
+
+func newSnapshotRecord(records plog.LogRecordSlice, eventName string, timestamp pcommon.Timestamp) pcommon.Map {
+	record := records.AppendEmpty()
+	record.SetTimestamp(timestamp)
+	record.SetObservedTimestamp(timestamp)
+	record.Attributes().PutStr("event.name", eventName)
+	return record.Body().SetEmptyMap()
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package servicegraphconnector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector/internal/store"
+)
+
+func TestTopologySnapshot(t *testing.T) {
+	// Prepare
+	topo := newTopology(time.Minute)
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	now := start
+	topo.now = func() time.Time { return now }
+
+	// Test
+	topo.record(&store.Edge{ClientService: "frontend", ServerService: "checkout", Protocol: "http"})
+	now = now.Add(time.Second)
+	topo.record(&store.Edge{ClientService: "frontend", ServerService: "checkout", Protocol: "grpc", Failed: true})
+	topo.record(&store.Edge{ClientService: "checkout", ServerService: "orders", ConnectionType: store.Database, Protocol: "postgresql"})
+	topo.record(&store.Edge{ClientService: "user", ServerService: "frontend", ConnectionType: store.VirtualNode, VirtualNodeLabel: store.ClientVirtualNode})
+
+	// Verify
+	nodes, edges := snapshotBodies(t, topo.snapshot())
+	assert.Equal(t, []map[string]any{
+		{"name": "checkout", "type": "service", "first_seen": "2024-01-02T03:04:05Z", "last_seen": "2024-01-02T03:04:06Z"},
+		{"name": "frontend", "type": "service", "first_seen": "2024-01-02T03:04:05Z", "last_seen": "2024-01-02T03:04:06Z"},
+		{"name": "orders", "type": "database", "first_seen": "2024-01-02T03:04:06Z", "last_seen": "2024-01-02T03:04:06Z"},
+		{"name": "user", "type": "virtual_node", "first_seen": "2024-01-02T03:04:06Z", "last_seen": "2024-01-02T03:04:06Z"},
+	}, nodes)
+	assert.Equal(t, []map[string]any{
+		{
+			"client": "checkout", "server": "orders", "connection_type": "database", "protocols": []any{"postgresql"},
+			"requests": int64(1), "failed_requests": int64(0), "first_seen": "2024-01-02T03:04:06Z", "last_seen": "2024-01-02T03:04:06Z",
+		},
+		{
+			"client": "frontend", "server": "checkout", "connection_type": "", "protocols": []any{"grpc", "http"},
+			"requests": int64(2), "failed_requests": int64(1), "first_seen": "2024-01-02T03:04:05Z", "last_seen": "2024-01-02T03:04:06Z",
+		},
+		{
+			"client": "user", "server": "frontend", "connection_type": "virtual_node", "protocols": []any{},
+			"requests": int64(1), "failed_requests": int64(0), "first_seen": "2024-01-02T03:04:06Z", "last_seen": "2024-01-02T03:04:06Z",
+		},
+	}, edges)
+}
+
+func TestTopologySnapshotExpiresStaleElements(t *testing.T) {
+	// Prepare
+	topo := newTopology(time.Minute)
+	now := time.Now()
+	topo.now = func() time.Time { return now }
+	topo.record(&store.Edge{ClientService: "frontend", ServerService: "checkout"})
+	now = now.Add(30 * time.Second)
+	topo.record(&store.Edge{ClientService: "checkout", ServerService: "orders"})
+
+	// Test
+	now = now.Add(45 * time.Second)
+	nodes, edges := snapshotBodies(t, topo.snapshot())
+
+	// Verify
+	require.Len(t, nodes, 2)
+	assert.Equal(t, "checkout", nodes[0]["name"])
+	assert.Equal(t, "orders", nodes[1]["name"])
+	require.Len(t, edges, 1)
+	assert.Equal(t, "checkout", edges[0]["client"])
+	assert.Len(t, topo.edges, 1)
+
+	now = now.Add(time.Minute)
+	assert.Equal(t, 0, topo.snapshot().LogRecordCount())
+}
+
+func TestFindProtocol(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		attrs    map[string]any
+		expected string
+	}{
+		{name: "rpc", attrs: map[string]any{"rpc.system": "grpc", "network.protocol.name": "http"}, expected: "grpc"},
+		{name: "messaging", attrs: map[string]any{"messaging.system": "kafka"}, expected: "kafka"},
+		{name: "database", attrs: map[string]any{"db.system": "redis"}, expected: "redis"},
+		{name: "network protocol", attrs: map[string]any{"network.protocol.name": "amqp"}, expected: "amqp"},
+		{name: "http", attrs: map[string]any{"http.request.method": "GET"}, expected: "http"},
+		{name: "legacy http", attrs: map[string]any{"http.method": "GET"}, expected: "http"},
+		{name: "unknown", attrs: map[string]any{"foo": "bar"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			attrs := pcommon.NewMap()
+			require.NoError(t, attrs.FromRaw(tc.attrs))
+
+			protocol, ok := findProtocol(attrs)
+
+			assert.Equal(t, tc.expected != "", ok)
+			assert.Equal(t, tc.expected, protocol)
+		})
+	}
+}
+
+func snapshotBodies(t *testing.T, ld plog.Logs) (nodes, edges []map[string]any) {
+	records := ld.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	for i := 0; i < records.Len(); i++ {
+		eventName, ok := records.At(i).Attributes().Get("event.name")
+		require.True(t, ok)
+		switch eventName.Str() {
+		case nodeEventName:
+			nodes = append(nodes, records.At(i).Body().Map().AsRaw())
+		case edgeEventName:
+			edges = append(edges, records.At(i).Body().Map().AsRaw())
+		}
+	}
+	return nodes, edges
+}
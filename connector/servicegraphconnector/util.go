@@ -13,3 +13,24 @@ import (
 func findServiceName(attributes pcommon.Map) (string, bool) {
 	return pdatautil.GetAttributeValue(semconv.AttributeServiceName, attributes)
 }
+
+// protocolAttributes lists the span attributes holding the protocol of a request, by priority.
+var protocolAttributes = []string{
+	semconv.AttributeRPCSystem, semconv.AttributeMessagingSystem, semconv.AttributeDBSystem, semconv.AttributeNetworkProtocolName,
+}
+
+// findProtocol returns the protocol of the request represented by the span, falling back
+// to "http" for the HTTP spans not recording their protocol name.
+func findProtocol(attributes pcommon.Map) (string, bool) {
+	for _, attr := range protocolAttributes {
+		if protocol, ok := pdatautil.GetAttributeValue(attr, attributes); ok {
+			return protocol, true
+		}
+	}
+	for _, attr := range []string{semconv.AttributeHTTPRequestMethod, "http.method"} {
+		if _, ok := attributes.Get(attr); ok {
+			return "http", true
+		}
+	}
+	return "", false
+}
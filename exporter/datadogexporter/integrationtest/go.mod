@@ -463,3 +463,7 @@ replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/pdata
 replace github.com/open-telemetry/opentelemetry-collector-contrib/pkg/experimentalmetricmetadata => ../../../pkg/experimentalmetricmetadata
 
 replace github.com/open-telemetry/opentelemetry-collector-contrib/pkg/datadog => ../../../pkg/datadog/
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/sqlquery => ../../../internal/sqlquery
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup => ../../../internal/lookup
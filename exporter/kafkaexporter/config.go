@@ -4,6 +4,7 @@
 package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 )
 
 // Config defines configuration for Kafka exporter.
@@ -64,6 +66,41 @@ type Config struct {
 
 	// Authentication defines used authentication mechanism.
 	Authentication kafka.Authentication `mapstructure:"auth"`
+
+	// SchemaRegistry configures the "schema_registry" encoding of logs.
+	SchemaRegistry *SchemaRegistry `mapstructure:"schema_registry"`
+}
+
+// SchemaRegistry defines the schema registry and the schema the log bodies are
+// serialized with in the "schema_registry" encoding.
+type SchemaRegistry struct {
+	schemaregistry.ClientConfig `mapstructure:",squash"`
+
+	// Subject the schema is registered under (default "<topic>-value").
+	Subject string `mapstructure:"subject"`
+	// Type of the schema, "AVRO" or "PROTOBUF" (default "AVRO").
+	SchemaType string `mapstructure:"schema_type"`
+	// Schema the log bodies are serialized with.
+	Schema string `mapstructure:"schema"`
+	// Name of the Protobuf message the log bodies are serialized as (default
+	// the first message of the schema).
+	MessageName string `mapstructure:"message_name"`
+}
+
+// Validate checks the schema registry configuration is valid.
+func (cfg *SchemaRegistry) Validate() error {
+	if err := cfg.ClientConfig.Validate(); err != nil {
+		return err
+	}
+	switch cfg.SchemaType {
+	case "", schemaregistry.SchemaTypeAvro, schemaregistry.SchemaTypeProtobuf:
+	default:
+		return fmt.Errorf("schema_type should be one of 'AVRO' or 'PROTOBUF'. configured value %v", cfg.SchemaType)
+	}
+	if cfg.Schema == "" {
+		return errors.New("schema must be specified")
+	}
+	return nil
 }
 
 // Metadata defines configuration for retrieving metadata from the broker.
@@ -102,6 +139,18 @@ type Producer struct {
 	// broker request. Defaults to 0 for unlimited. Similar to
 	// `queue.buffering.max.messages` in the JVM producer.
 	FlushMaxMessages int `mapstructure:"flush_max_messages"`
+
+	// Idempotent makes the broker write each message exactly once even when the
+	// producer retries, and in order within a partition. Similar to
+	// `enable.idempotence` in the JVM producer. Requires RequiredAcks to be -1.
+	Idempotent bool `mapstructure:"idempotent"`
+
+	// TransactionalID makes the producer transactional: the messages of a batch are
+	// produced in a transaction, so consumers reading committed messages get either
+	// all of them or none. The ID must be unique to the exporter instance, and
+	// identifies the producer across restarts. The producer of each signal uses the
+	// ID suffixed with the signal name, e.g. `<id>-logs`. Requires Idempotent.
+	TransactionalID string `mapstructure:"transactional_id"`
 }
 
 // MetadataRetry defines retry configuration for Metadata.
@@ -127,9 +176,41 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if err := validateIdempotence(cfg); err != nil {
+		return err
+	}
+
+	if cfg.Encoding == schemaRegistryEncoding {
+		if cfg.SchemaRegistry == nil {
+			return errors.New("schema_registry must be configured with the schema_registry encoding")
+		}
+		if err := cfg.SchemaRegistry.Validate(); err != nil {
+			return fmt.Errorf("schema_registry: %w", err)
+		}
+	}
+
 	return validateSASLConfig(cfg.Authentication.SASL)
 }
 
+func validateIdempotence(cfg *Config) error {
+	if cfg.Producer.TransactionalID != "" && !cfg.Producer.Idempotent {
+		return fmt.Errorf("producer.transactional_id requires producer.idempotent to be true")
+	}
+	if !cfg.Producer.Idempotent {
+		return nil
+	}
+	if cfg.Producer.RequiredAcks != sarama.WaitForAll {
+		return fmt.Errorf("producer.idempotent requires producer.required_acks to be -1. configured value %v", cfg.Producer.RequiredAcks)
+	}
+	if cfg.ProtocolVersion != "" {
+		version, err := sarama.ParseKafkaVersion(cfg.ProtocolVersion)
+		if err == nil && !version.IsAtLeast(sarama.V0_11_0_0) {
+			return fmt.Errorf("producer.idempotent requires protocol_version to be at least 0.11.0. configured value %v", cfg.ProtocolVersion)
+		}
+	}
+	return nil
+}
+
 func validateSASLConfig(c *kafka.SASLConfig) error {
 	if c == nil {
 		return nil
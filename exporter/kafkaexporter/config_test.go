@@ -20,6 +20,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -221,6 +222,110 @@ func TestValidate_err_compression(t *testing.T) {
 	assert.EqualError(t, err, "producer.compression should be one of 'none', 'gzip', 'snappy', 'lz4', or 'zstd'. configured value idk")
 }
 
+func TestValidate_idempotence(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		producer Producer
+		err      string
+	}{
+		{
+			name:     "idempotent",
+			producer: Producer{Idempotent: true, RequiredAcks: sarama.WaitForAll},
+		},
+		{
+			name:     "transactional",
+			version:  "2.1.0",
+			producer: Producer{Idempotent: true, RequiredAcks: sarama.WaitForAll, TransactionalID: "relay"},
+		},
+		{
+			name:     "transactional not idempotent",
+			producer: Producer{RequiredAcks: sarama.WaitForAll, TransactionalID: "relay"},
+			err:      "producer.transactional_id requires producer.idempotent to be true",
+		},
+		{
+			name:     "idempotent without all acks",
+			producer: Producer{Idempotent: true, RequiredAcks: sarama.WaitForLocal},
+			err:      "producer.idempotent requires producer.required_acks to be -1. configured value 1",
+		},
+		{
+			name:     "idempotent with old protocol version",
+			version:  "0.10.2.0",
+			producer: Producer{Idempotent: true, RequiredAcks: sarama.WaitForAll},
+			err:      "producer.idempotent requires protocol_version to be at least 0.11.0. configured value 0.10.2.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.producer.Compression = "none"
+			config := &Config{ProtocolVersion: tt.version, Producer: tt.producer}
+			err := config.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestValidate_schema_registry(t *testing.T) {
+	tests := []struct {
+		name           string
+		schemaRegistry *SchemaRegistry
+		err            string
+	}{
+		{
+			name: "missing",
+			err:  "schema_registry must be configured with the schema_registry encoding",
+		},
+		{
+			name:           "no url",
+			schemaRegistry: &SchemaRegistry{Schema: `"string"`},
+			err:            "schema_registry: url must be specified",
+		},
+		{
+			name: "invalid schema type",
+			schemaRegistry: &SchemaRegistry{
+				ClientConfig: schemaregistry.ClientConfig{URL: "http://localhost:8081"},
+				SchemaType:   "JSON",
+				Schema:       "{}",
+			},
+			err: "schema_registry: schema_type should be one of 'AVRO' or 'PROTOBUF'. configured value JSON",
+		},
+		{
+			name: "no schema",
+			schemaRegistry: &SchemaRegistry{
+				ClientConfig: schemaregistry.ClientConfig{URL: "http://localhost:8081"},
+			},
+			err: "schema_registry: schema must be specified",
+		},
+		{
+			name: "valid",
+			schemaRegistry: &SchemaRegistry{
+				ClientConfig: schemaregistry.ClientConfig{URL: "http://localhost:8081"},
+				SchemaType:   "PROTOBUF",
+				Schema:       `syntax = "proto3"; message Log { string message = 1; }`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Encoding:       "schema_registry",
+				Producer:       Producer{Compression: "none"},
+				SchemaRegistry: tt.schemaRegistry,
+			}
+			err := config.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
 func TestValidate_sasl_username(t *testing.T) {
 	config := &Config{
 		Producer: Producer{
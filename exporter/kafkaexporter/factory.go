@@ -104,6 +104,7 @@ func (f *kafkaExporterFactory) createTracesExporter(
 	if oCfg.Encoding == "otlp_json" {
 		set.Logger.Info("otlp_json is considered experimental and should not be used in a production environment")
 	}
+	warnTransactionalQueue(oCfg, set)
 	exp := newTracesExporter(oCfg, set)
 	return exporterhelper.NewTraces(
 		ctx,
@@ -132,6 +133,7 @@ func (f *kafkaExporterFactory) createMetricsExporter(
 	if oCfg.Encoding == "otlp_json" {
 		set.Logger.Info("otlp_json is considered experimental and should not be used in a production environment")
 	}
+	warnTransactionalQueue(oCfg, set)
 	exp := newMetricsExporter(oCfg, set)
 	return exporterhelper.NewMetrics(
 		ctx,
@@ -160,6 +162,7 @@ func (f *kafkaExporterFactory) createLogsExporter(
 	if oCfg.Encoding == "otlp_json" {
 		set.Logger.Info("otlp_json is considered experimental and should not be used in a production environment")
 	}
+	warnTransactionalQueue(oCfg, set)
 	exp := newLogsExporter(oCfg, set)
 	return exporterhelper.NewLogs(
 		ctx,
@@ -175,3 +178,12 @@ func (f *kafkaExporterFactory) createLogsExporter(
 		exporterhelper.WithStart(exp.start),
 		exporterhelper.WithShutdown(exp.Close))
 }
+
+// warnTransactionalQueue warns that data is acknowledged to the pipeline before it is
+// produced when a transactional producer is used with the sending queue.
+func warnTransactionalQueue(cfg Config, set exporter.Settings) {
+	if cfg.Producer.TransactionalID != "" && cfg.QueueSettings.Enabled {
+		set.Logger.Warn("sending_queue is enabled with a transactional producer: data is acknowledged before its transaction is committed, " +
+			"disable sending_queue to mark the messages of a kafka receiver only once they are produced")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/IBM/sarama"
 	"go.opentelemetry.io/collector/component"
@@ -16,6 +17,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pipeline"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
@@ -30,6 +32,8 @@ type kafkaTracesProducer struct {
 	producer  sarama.SyncProducer
 	marshaler TracesMarshaler
 	logger    *zap.Logger
+	// txnMu serializes the transactions of a transactional producer.
+	txnMu sync.Mutex
 }
 
 type kafkaErrors struct {
@@ -46,7 +50,7 @@ func (e *kafkaTracesProducer) tracesPusher(ctx context.Context, td ptrace.Traces
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
-	err = e.producer.SendMessages(messages)
+	err = sendMessages(e.producer, &e.txnMu, messages)
 	if err != nil {
 		var prodErr sarama.ProducerErrors
 		if errors.As(err, &prodErr) {
@@ -83,7 +87,7 @@ func (e *kafkaTracesProducer) start(ctx context.Context, host component.Host) er
 	if e.marshaler == nil {
 		return errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(ctx, e.cfg)
+	producer, err := newSaramaProducer(ctx, e.cfg, pipeline.SignalTraces)
 	if err != nil {
 		return err
 	}
@@ -97,6 +101,8 @@ type kafkaMetricsProducer struct {
 	producer  sarama.SyncProducer
 	marshaler MetricsMarshaler
 	logger    *zap.Logger
+	// txnMu serializes the transactions of a transactional producer.
+	txnMu sync.Mutex
 }
 
 func (e *kafkaMetricsProducer) metricsDataPusher(ctx context.Context, md pmetric.Metrics) error {
@@ -104,7 +110,7 @@ func (e *kafkaMetricsProducer) metricsDataPusher(ctx context.Context, md pmetric
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
-	err = e.producer.SendMessages(messages)
+	err = sendMessages(e.producer, &e.txnMu, messages)
 	if err != nil {
 		var prodErr sarama.ProducerErrors
 		if errors.As(err, &prodErr) {
@@ -141,7 +147,7 @@ func (e *kafkaMetricsProducer) start(ctx context.Context, host component.Host) e
 	if e.marshaler == nil {
 		return errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(ctx, e.cfg)
+	producer, err := newSaramaProducer(ctx, e.cfg, pipeline.SignalMetrics)
 	if err != nil {
 		return err
 	}
@@ -155,6 +161,8 @@ type kafkaLogsProducer struct {
 	producer  sarama.SyncProducer
 	marshaler LogsMarshaler
 	logger    *zap.Logger
+	// txnMu serializes the transactions of a transactional producer.
+	txnMu sync.Mutex
 }
 
 func (e *kafkaLogsProducer) logsDataPusher(ctx context.Context, ld plog.Logs) error {
@@ -162,7 +170,7 @@ func (e *kafkaLogsProducer) logsDataPusher(ctx context.Context, ld plog.Logs) er
 	if err != nil {
 		return consumererror.NewPermanent(err)
 	}
-	err = e.producer.SendMessages(messages)
+	err = sendMessages(e.producer, &e.txnMu, messages)
 	if err != nil {
 		var prodErr sarama.ProducerErrors
 		if errors.As(err, &prodErr) {
@@ -176,6 +184,9 @@ func (e *kafkaLogsProducer) logsDataPusher(ctx context.Context, ld plog.Logs) er
 }
 
 func (e *kafkaLogsProducer) Close(context.Context) error {
+	if m, ok := e.marshaler.(*schemaRegistryLogsMarshaler); ok {
+		m.client.Close()
+	}
 	if e.producer == nil {
 		return nil
 	}
@@ -193,13 +204,20 @@ func (e *kafkaLogsProducer) start(ctx context.Context, host component.Host) erro
 			encoding:  e.cfg.Encoding,
 		}
 	}
+	if e.marshaler == nil && e.cfg.Encoding == schemaRegistryEncoding {
+		marshaler, err := newSchemaRegistryLogsMarshaler(ctx, *e.cfg.SchemaRegistry)
+		if err != nil {
+			return err
+		}
+		e.marshaler = marshaler
+	}
 	if marshaler, errInt := createLogMarshaler(e.cfg); e.marshaler == nil && errInt == nil {
 		e.marshaler = marshaler
 	}
 	if e.marshaler == nil {
 		return errUnrecognizedEncoding
 	}
-	producer, err := newSaramaProducer(ctx, e.cfg)
+	producer, err := newSaramaProducer(ctx, e.cfg, pipeline.SignalLogs)
 	if err != nil {
 		return err
 	}
@@ -207,7 +225,45 @@ func (e *kafkaLogsProducer) start(ctx context.Context, host component.Host) erro
 	return nil
 }
 
-func newSaramaProducer(ctx context.Context, config Config) (sarama.SyncProducer, error) {
+// sendMessages sends the messages. With a transactional producer, they are sent in a
+// transaction, which is aborted if any of them can't be sent.
+func sendMessages(producer sarama.SyncProducer, txnMu *sync.Mutex, messages []*sarama.ProducerMessage) error {
+	if !producer.IsTransactional() {
+		return producer.SendMessages(messages)
+	}
+
+	// A producer has a single transaction at a time
+	txnMu.Lock()
+	defer txnMu.Unlock()
+	if err := producer.BeginTxn(); err != nil {
+		return err
+	}
+	if err := producer.SendMessages(messages); err != nil {
+		return errors.Join(err, abortTxn(producer))
+	}
+	if err := producer.CommitTxn(); err != nil {
+		return errors.Join(err, abortTxn(producer))
+	}
+	return nil
+}
+
+func abortTxn(producer sarama.SyncProducer) error {
+	if err := producer.AbortTxn(); err != nil {
+		return fmt.Errorf("failed to abort transaction: %w", err)
+	}
+	return nil
+}
+
+// transactionalID returns the transactional ID of the producer of the signal. The producers
+// of the signals get distinct IDs, since producers sharing an ID fence each other off.
+func transactionalID(config Config, signal pipeline.Signal) string {
+	if config.Producer.TransactionalID == "" {
+		return ""
+	}
+	return config.Producer.TransactionalID + "-" + signal.String()
+}
+
+func newSaramaProducer(ctx context.Context, config Config, signal pipeline.Signal) (sarama.SyncProducer, error) {
 	c := sarama.NewConfig()
 
 	c.ClientID = config.ClientID
@@ -223,6 +279,12 @@ func newSaramaProducer(ctx context.Context, config Config) (sarama.SyncProducer,
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
 	c.Producer.MaxMessageBytes = config.Producer.MaxMessageBytes
 	c.Producer.Flush.MaxMessages = config.Producer.FlushMaxMessages
+	if config.Producer.Idempotent {
+		c.Producer.Idempotent = true
+		// Required by sarama to keep the messages in order across retries
+		c.Net.MaxOpenRequests = 1
+		c.Producer.Transaction.ID = transactionalID(config, signal)
+	}
 
 	if config.ResolveCanonicalBootstrapServersOnly {
 		c.Net.ResolveCanonicalBootstrapServers = true
@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+)
+
+const schemaRegistryEncoding = "schema_registry"
+
+// schemaRegistryLogsMarshaler serializes the map body of each log record in the
+// Confluent wire format, with a schema registered in the schema registry.
+type schemaRegistryLogsMarshaler struct {
+	client     *schemaregistry.Client
+	serializer *schemaregistry.Serializer
+	subject    string
+}
+
+func newSchemaRegistryLogsMarshaler(ctx context.Context, cfg SchemaRegistry) (*schemaRegistryLogsMarshaler, error) {
+	client, err := schemaregistry.NewClient(ctx, cfg.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	schemaType := cfg.SchemaType
+	if schemaType == "" {
+		schemaType = schemaregistry.SchemaTypeAvro
+	}
+	serializer, err := schemaregistry.NewSerializer(client, schemaType, cfg.Schema, cfg.MessageName)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &schemaRegistryLogsMarshaler{client: client, serializer: serializer, subject: cfg.Subject}, nil
+}
+
+func (m *schemaRegistryLogsMarshaler) Marshal(logs plog.Logs, topic string) ([]*sarama.ProducerMessage, error) {
+	subject := m.subject
+	if subject == "" {
+		// The default subject name strategy of Kafka serializers
+		subject = topic + "-value"
+	}
+	var messages []*sarama.ProducerMessage
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		rl := logs.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				body := sl.LogRecords().At(k).Body()
+				if body.Type() != pcommon.ValueTypeMap {
+					return nil, fmt.Errorf("log body of type %s cannot be serialized with a schema, a map is expected", body.Type())
+				}
+				data, err := m.serializer.Serialize(context.Background(), subject, body.Map().AsRaw())
+				if err != nil {
+					return nil, err
+				}
+				messages = append(messages, &sarama.ProducerMessage{
+					Topic: topic,
+					Value: sarama.ByteEncoder(data),
+				})
+			}
+		}
+	}
+	return messages, nil
+}
+
+func (m *schemaRegistryLogsMarshaler) Encoding() string {
+	return schemaRegistryEncoding
+}
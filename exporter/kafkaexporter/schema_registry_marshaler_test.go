@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
+)
+
+const testAvroSchema = `{
+	"type": "record",
+	"name": "Log",
+	"fields": [
+		{"name": "message", "type": "string"},
+		{"name": "level", "type": "long"}
+	]
+}`
+
+func newTestSchemaRegistryMarshaler(t *testing.T, cfg SchemaRegistry) *schemaRegistryLogsMarshaler {
+	m, err := newSchemaRegistryLogsMarshaler(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(m.client.Close)
+	return m
+}
+
+func TestSchemaRegistryLogsMarshaler(t *testing.T) {
+	server := schemaregistrytest.NewServer(t)
+	m := newTestSchemaRegistryMarshaler(t, SchemaRegistry{
+		ClientConfig: schemaregistry.ClientConfig{URL: server.URL},
+		Schema:       testAvroSchema,
+	})
+	assert.Equal(t, "schema_registry", m.Encoding())
+
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	require.NoError(t, records.AppendEmpty().Body().SetEmptyMap().FromRaw(map[string]any{"message": "first", "level": 9}))
+	require.NoError(t, records.AppendEmpty().Body().SetEmptyMap().FromRaw(map[string]any{"message": "second", "level": 17}))
+
+	messages, err := m.Marshal(logs, "logs")
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, map[string]int{"logs-value": 1}, server.Subjects())
+
+	client, err := schemaregistry.NewClient(context.Background(), schemaregistry.ClientConfig{URL: server.URL})
+	require.NoError(t, err)
+	defer client.Close()
+	deserializer := schemaregistry.NewDeserializer(client)
+	for i, expected := range []map[string]any{
+		{"message": "first", "level": int64(9)},
+		{"message": "second", "level": int64(17)},
+	} {
+		assert.Equal(t, "logs", messages[i].Topic)
+		value, err := messages[i].Value.Encode()
+		require.NoError(t, err)
+		record, err := deserializer.Deserialize(context.Background(), value)
+		require.NoError(t, err)
+		assert.Equal(t, "Log", record.TypeName)
+		assert.Equal(t, expected, record.Value)
+	}
+}
+
+func TestSchemaRegistryLogsMarshaler_subject(t *testing.T) {
+	server := schemaregistrytest.NewServer(t)
+	m := newTestSchemaRegistryMarshaler(t, SchemaRegistry{
+		ClientConfig: schemaregistry.ClientConfig{URL: server.URL},
+		Subject:      "events",
+		SchemaType:   schemaregistry.SchemaTypeProtobuf,
+		Schema:       `syntax = "proto3"; message Metric { string name = 1; } message Event { string message = 1; }`,
+		MessageName:  "Event",
+	})
+
+	logs := plog.NewLogs()
+	body := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body()
+	body.SetEmptyMap().PutStr("message", "hello")
+
+	messages, err := m.Marshal(logs, "logs")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, map[string]int{"events": 1}, server.Subjects())
+}
+
+func TestSchemaRegistryLogsMarshaler_errors(t *testing.T) {
+	server := schemaregistrytest.NewServer(t)
+	m := newTestSchemaRegistryMarshaler(t, SchemaRegistry{
+		ClientConfig: schemaregistry.ClientConfig{URL: server.URL},
+		Schema:       testAvroSchema,
+	})
+
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+	_, err := m.Marshal(logs, "logs")
+	assert.EqualError(t, err, "log body of type Str cannot be serialized with a schema, a map is expected")
+
+	logs = plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetEmptyMap().PutStr("message", "hello")
+	_, err = m.Marshal(logs, "logs")
+	assert.ErrorContains(t, err, "failed to serialize avro record")
+
+	_, err = newSchemaRegistryLogsMarshaler(context.Background(), SchemaRegistry{
+		ClientConfig: schemaregistry.ClientConfig{URL: server.URL},
+		Schema:       "not a schema",
+	})
+	assert.ErrorContains(t, err, "failed to create avro codec")
+}
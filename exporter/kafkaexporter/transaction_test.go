@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/testdata"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+// txnSyncProducer records the transactions ended by a transactional mock producer.
+type txnSyncProducer struct {
+	*mocks.SyncProducer
+	mu        sync.Mutex
+	committed int
+	aborted   int
+	commitErr error
+}
+
+func newTxnSyncProducer(t *testing.T) *txnSyncProducer {
+	c := sarama.NewConfig()
+	c.Producer.Idempotent = true
+	c.Producer.RequiredAcks = sarama.WaitForAll
+	c.Producer.Transaction.ID = "relay"
+	c.Net.MaxOpenRequests = 1
+	return &txnSyncProducer{SyncProducer: mocks.NewSyncProducer(t, c)}
+}
+
+func (p *txnSyncProducer) CommitTxn() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.commitErr != nil {
+		return p.commitErr
+	}
+	p.committed++
+	return p.SyncProducer.CommitTxn()
+}
+
+func (p *txnSyncProducer) AbortTxn() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.aborted++
+	return p.SyncProducer.AbortTxn()
+}
+
+func TestLogsDataPusher_transactional(t *testing.T) {
+	producer := newTxnSyncProducer(t)
+	producer.ExpectSendMessageAndSucceed()
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaLogsProducer{
+		producer:  producer,
+		marshaler: newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding, true),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	// The logs of each resource are a message, both are sent in a single transaction
+	logs := plog.NewLogs()
+	for _, service := range []string{"frontend", "backend"} {
+		rl := logs.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", service)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("hello")
+	}
+	require.NoError(t, p.logsDataPusher(context.Background(), logs))
+	assert.Equal(t, 1, producer.committed)
+	assert.Equal(t, 0, producer.aborted)
+	assert.Equal(t, sarama.ProducerTxnFlagReady, producer.TxnStatus())
+}
+
+func TestLogsDataPusher_transactional_abort(t *testing.T) {
+	producer := newTxnSyncProducer(t)
+	expErr := errors.New("failed to send")
+	producer.ExpectSendMessageAndFail(expErr)
+
+	p := kafkaLogsProducer{
+		producer:  producer,
+		marshaler: newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding, false),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.logsDataPusher(context.Background(), testdata.GenerateLogs(1))
+	assert.EqualError(t, err, expErr.Error())
+	assert.Equal(t, 0, producer.committed)
+	assert.Equal(t, 1, producer.aborted)
+	assert.Equal(t, sarama.ProducerTxnFlagReady, producer.TxnStatus())
+}
+
+func TestLogsDataPusher_transactional_commit_error(t *testing.T) {
+	producer := newTxnSyncProducer(t)
+	producer.commitErr = errors.New("failed to commit")
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaLogsProducer{
+		producer:  producer,
+		marshaler: newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding, false),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	err := p.logsDataPusher(context.Background(), testdata.GenerateLogs(1))
+	assert.ErrorIs(t, err, producer.commitErr)
+	assert.Equal(t, 1, producer.aborted)
+}
+
+func TestLogsDataPusher_transactional_concurrent(t *testing.T) {
+	producer := newTxnSyncProducer(t)
+	const pushes = 10
+	for i := 0; i < pushes; i++ {
+		producer.ExpectSendMessageAndSucceed()
+	}
+
+	p := &kafkaLogsProducer{
+		producer:  producer,
+		marshaler: newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding, false),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	// The sending queue pushes concurrently, the transactions must not overlap
+	var wg sync.WaitGroup
+	for i := 0; i < pushes; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, p.logsDataPusher(context.Background(), testdata.GenerateLogs(1)))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, pushes, producer.committed)
+}
+
+// newTransactionBroker starts a broker accepting transactions of the producer.
+func newTransactionBroker(t *testing.T, topic string) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetController(broker.BrokerID()).
+			SetLeader(topic, 0, broker.BrokerID()),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorTransaction, "relay-logs", broker),
+		"InitProducerIDRequest": sarama.NewMockInitProducerIDResponse(t).
+			SetProducerID(1000).
+			SetProducerEpoch(1),
+		"AddPartitionsToTxnRequest": sarama.NewMockWrapper(&sarama.AddPartitionsToTxnResponse{
+			Version: 2,
+			Errors: map[string][]*sarama.PartitionError{
+				topic: {{Partition: 0, Err: sarama.ErrNoError}},
+			},
+		}),
+		"ProduceRequest": sarama.NewMockProduceResponse(t).SetVersion(7),
+		"EndTxnRequest":  sarama.NewMockWrapper(&sarama.EndTxnResponse{Version: 1}),
+	})
+	return broker
+}
+
+func TestNewSaramaProducer_transactional(t *testing.T) {
+	broker := newTransactionBroker(t, "relayed_logs")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{broker.Addr()}
+	cfg.ProtocolVersion = "2.1.0"
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Idempotent = true
+	cfg.Producer.TransactionalID = "relay"
+	cfg.Metadata.Retry.Backoff = 10 * time.Millisecond
+	require.NoError(t, cfg.Validate())
+
+	producer, err := newSaramaProducer(context.Background(), *cfg, pipeline.SignalLogs)
+	require.NoError(t, err)
+	assert.True(t, producer.IsTransactional())
+	p := kafkaLogsProducer{
+		cfg:       Config{Topic: "relayed_logs"},
+		producer:  producer,
+		marshaler: newPdataLogsMarshaler(&plog.ProtoMarshaler{}, defaultEncoding, false),
+	}
+	require.NoError(t, p.logsDataPusher(context.Background(), testdata.GenerateLogs(1)))
+	require.NoError(t, p.Close(context.Background()))
+
+	var kinds []string
+	for _, r := range broker.History() {
+		switch req := r.Request.(type) {
+		case *sarama.InitProducerIDRequest:
+			require.NotNil(t, req.TransactionalID)
+			assert.Equal(t, "relay-logs", *req.TransactionalID)
+		case *sarama.AddPartitionsToTxnRequest:
+			kinds = append(kinds, "add_partitions")
+		case *sarama.ProduceRequest:
+			kinds = append(kinds, "produce")
+		case *sarama.EndTxnRequest:
+			kinds = append(kinds, "end_txn")
+		}
+	}
+	assert.Equal(t, []string{"add_partitions", "produce", "end_txn"}, kinds)
+}
+
+func TestTransactionalID(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Empty(t, transactionalID(*cfg, pipeline.SignalLogs))
+
+	cfg.Producer.TransactionalID = "relay"
+	assert.Equal(t, "relay-traces", transactionalID(*cfg, pipeline.SignalTraces))
+	assert.Equal(t, "relay-metrics", transactionalID(*cfg, pipeline.SignalMetrics))
+	assert.Equal(t, "relay-logs", transactionalID(*cfg, pipeline.SignalLogs))
+}
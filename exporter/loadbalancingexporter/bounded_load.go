@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// loadWindow is the period over which the load of the endpoints is measured.
+const loadWindow = time.Second
+
+// boundedLoads assigns routing keys to endpoints so that no endpoint receives more than
+// loadFactor times the average load, measured as the number of items routed in the current window.
+type boundedLoads struct {
+	loadFactor float64
+	now        func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	loads       map[string]int64
+	total       int64
+}
+
+func newBoundedLoads(loadFactor float64) *boundedLoads {
+	return &boundedLoads{
+		loadFactor: loadFactor,
+		now:        time.Now,
+		loads:      map[string]int64{},
+	}
+}
+
+// assign returns the endpoint for the identifier carrying the given load, and whether it differs
+// from the endpoint responsible for the identifier on the ring.
+func (b *boundedLoads) assign(ring *hashRing, identifier []byte, load int) (string, bool) {
+	if ring == nil || ring.endpoints == 0 {
+		return "", false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now := b.now(); now.Sub(b.windowStart) >= loadWindow {
+		b.windowStart = now
+		b.loads = map[string]int64{}
+		b.total = 0
+	}
+
+	capacity := int64(math.Ceil(b.loadFactor * float64(b.total+int64(load)) / float64(ring.endpoints)))
+	endpoint := ring.boundedEndpointFor(identifier, func(endpoint string) bool {
+		return b.loads[endpoint]+int64(load) <= capacity
+	})
+	b.loads[endpoint] += int64(load)
+	b.total += int64(load)
+
+	return endpoint, endpoint != ring.endpointFor(identifier)
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundedLoadsAssign(t *testing.T) {
+	// prepare
+	ring := newHashRing([]string{"endpoint-1", "endpoint-2"})
+	loads := newBoundedLoads(1.5)
+	now := time.Now()
+	loads.now = func() time.Time { return now }
+	id := []byte("ad-service-7")
+	owner := ring.endpointFor(id)
+
+	// test
+	received := map[string]int{}
+	overflows := 0
+	for i := 0; i < 100; i++ {
+		endpoint, overflow := loads.assign(ring, id, 1)
+		received[endpoint]++
+		if overflow {
+			overflows++
+		}
+	}
+
+	// verify
+	assert.Len(t, received, 2)
+	assert.LessOrEqual(t, received[owner], 75)
+	assert.Equal(t, 100-received[owner], overflows)
+}
+
+func TestBoundedLoadsSpreadsKeys(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3", "endpoint-4"}
+	ring := newHashRing(endpoints)
+	loads := newBoundedLoads(1.25)
+	now := time.Now()
+	loads.now = func() time.Time { return now }
+
+	// test
+	received := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		endpoint, _ := loads.assign(ring, []byte(fmt.Sprintf("key-%d", i%10)), 1)
+		received[endpoint]++
+	}
+
+	// verify
+	for _, endpoint := range endpoints {
+		assert.LessOrEqual(t, received[endpoint], 313, endpoint)
+	}
+}
+
+func TestBoundedLoadsWindow(t *testing.T) {
+	// prepare
+	ring := newHashRing([]string{"endpoint-1", "endpoint-2"})
+	loads := newBoundedLoads(1.5)
+	now := time.Now()
+	loads.now = func() time.Time { return now }
+	id := []byte("ad-service-7")
+	owner := ring.endpointFor(id)
+
+	endpoint, overflow := loads.assign(ring, id, 10)
+	assert.Equal(t, owner, endpoint)
+	assert.False(t, overflow)
+
+	// test
+	// the owner is full within the window...
+	endpoint, overflow = loads.assign(ring, id, 10)
+	assert.NotEqual(t, owner, endpoint)
+	assert.True(t, overflow)
+
+	// ... and accepts the key again in the next window
+	now = now.Add(loadWindow)
+	endpoint, overflow = loads.assign(ring, id, 10)
+
+	// verify
+	assert.Equal(t, owner, endpoint)
+	assert.False(t, overflow)
+}
+
+func TestBoundedLoadsNoEndpoints(t *testing.T) {
+	loads := newBoundedLoads(1.5)
+
+	endpoint, overflow := loads.assign(nil, []byte("ad-service-7"), 1)
+	assert.Equal(t, "", endpoint)
+	assert.False(t, overflow)
+
+	endpoint, overflow = loads.assign(newHashRing(nil), []byte("ad-service-7"), 1)
+	assert.Equal(t, "", endpoint)
+	assert.False(t, overflow)
+}
@@ -4,9 +4,12 @@
 package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/servicediscovery/types"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
@@ -20,6 +23,8 @@ const (
 	metricNameRouting
 	resourceRouting
 	streamIDRouting
+	attrRouting
+	ottlRouting
 )
 
 const (
@@ -28,6 +33,8 @@ const (
 	metricNameRoutingStr = "metric"
 	resourceRoutingStr   = "resource"
 	streamIDRoutingStr   = "streamID"
+	attrRoutingStr       = "attributes"
+	ottlRoutingStr       = "ottl"
 )
 
 // Config defines configuration for the exporter.
@@ -39,6 +46,97 @@ type Config struct {
 	Protocol   Protocol         `mapstructure:"protocol"`
 	Resolver   ResolverSettings `mapstructure:"resolver"`
 	RoutingKey string           `mapstructure:"routing_key"`
+	// RoutingAttributes are the attributes whose values form the routing key, when the routing key is "attributes".
+	RoutingAttributes []string `mapstructure:"routing_attributes"`
+	// RoutingExpression is the OTTL value expression forming the routing key, when the routing key is "ottl".
+	RoutingExpression string `mapstructure:"routing_expression"`
+
+	ConsistentHashing ConsistentHashing `mapstructure:"consistent_hashing"`
+	HealthCheck       HealthCheck       `mapstructure:"health_check"`
+}
+
+// ConsistentHashing defines the configuration of the consistent hash ring
+type ConsistentHashing struct {
+	// LoadFactor enables consistent hashing with bounded loads: no endpoint receives more than LoadFactor
+	// times the average load, keys in excess are sent to the next endpoints on the ring.
+	// Bounded loads are disabled when zero.
+	LoadFactor float64 `mapstructure:"load_factor"`
+}
+
+// HealthCheck defines the health tracking of the backends. Backends failing their exports, or their gRPC health
+// probes when enabled, are temporarily ejected from the ring and their keys are sent to the other backends.
+type HealthCheck struct {
+	Enabled bool `mapstructure:"enabled"`
+	// FailureThreshold is the number of consecutive failures after which a backend is ejected.
+	FailureThreshold int `mapstructure:"failure_threshold"`
+	// EjectionDuration is how long a backend is ejected the first time. The duration doubles each time
+	// the backend is ejected again, so that a flapping backend does not reshuffle the ring constantly.
+	EjectionDuration time.Duration `mapstructure:"ejection_duration"`
+	// MaxEjectionDuration caps the ejection duration. A backend that stays healthy for this long after its
+	// readmission gets back to the initial ejection duration.
+	MaxEjectionDuration time.Duration `mapstructure:"max_ejection_duration"`
+	// MaxEjectionPercent is the maximum percentage of the backends that can be ejected at the same time.
+	MaxEjectionPercent int `mapstructure:"max_ejection_percent"`
+
+	GRPCProbe GRPCHealthProbe `mapstructure:"grpc_probe"`
+}
+
+// GRPCHealthProbe defines the active probing of the backends with the gRPC health checking protocol
+type GRPCHealthProbe struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	// Service is the name of the service whose health is checked, the overall health of the backend is checked when empty.
+	Service string `mapstructure:"service"`
+}
+
+// Validate checks if the exporter configuration is valid
+func (c *Config) Validate() error {
+	switch c.RoutingKey {
+	case attrRoutingStr:
+		if len(c.RoutingAttributes) == 0 {
+			return errors.New("routing_attributes must be set when routing_key is \"attributes\"")
+		}
+	case ottlRoutingStr:
+		if c.RoutingExpression == "" {
+			return errors.New("routing_expression must be set when routing_key is \"ottl\"")
+		}
+	}
+	if c.ConsistentHashing.LoadFactor != 0 && c.ConsistentHashing.LoadFactor <= 1 {
+		return fmt.Errorf("consistent_hashing::load_factor must be greater than 1, got %v", c.ConsistentHashing.LoadFactor)
+	}
+	// Bounded loads move routes between backends as their load changes, which would split traces
+	if c.ConsistentHashing.LoadFactor != 0 && (c.RoutingKey == "" || c.RoutingKey == traceIDRoutingStr) {
+		return errors.New("consistent_hashing::load_factor cannot be used with the traceID routing key, as the spans of a trace could be sent to different backends")
+	}
+	if c.HealthCheck.Enabled {
+		return c.HealthCheck.validate()
+	}
+	return nil
+}
+
+func (h *HealthCheck) validate() error {
+	if h.FailureThreshold <= 0 {
+		return errors.New("health_check::failure_threshold must be greater than 0")
+	}
+	if h.EjectionDuration <= 0 {
+		return errors.New("health_check::ejection_duration must be greater than 0")
+	}
+	if h.MaxEjectionDuration < h.EjectionDuration {
+		return errors.New("health_check::max_ejection_duration must not be less than health_check::ejection_duration")
+	}
+	if h.MaxEjectionPercent < 0 || h.MaxEjectionPercent > 100 {
+		return fmt.Errorf("health_check::max_ejection_percent must be between 0 and 100, got %d", h.MaxEjectionPercent)
+	}
+	if h.GRPCProbe.Enabled {
+		if h.GRPCProbe.Interval <= 0 {
+			return errors.New("health_check::grpc_probe::interval must be greater than 0")
+		}
+		if h.GRPCProbe.Timeout <= 0 {
+			return errors.New("health_check::grpc_probe::timeout must be greater than 0")
+		}
+	}
+	return nil
 }
 
 // Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
@@ -52,6 +150,8 @@ type ResolverSettings struct {
 	DNS         *DNSResolver         `mapstructure:"dns"`
 	K8sSvc      *K8sSvcResolver      `mapstructure:"k8s"`
 	AWSCloudMap *AWSCloudMapResolver `mapstructure:"aws_cloud_map"`
+	File        *FileResolver        `mapstructure:"file"`
+	Consul      *ConsulResolver      `mapstructure:"consul"`
 }
 
 // StaticResolver defines the configuration for the resolver providing a fixed list of backends
@@ -83,3 +183,23 @@ type AWSCloudMapResolver struct {
 	Timeout       time.Duration            `mapstructure:"timeout"`
 	Port          *uint16                  `mapstructure:"port"`
 }
+
+// FileResolver defines the configuration for the resolver reading the backends from a JSON or YAML file
+type FileResolver struct {
+	// Path is the path of the file, holding a list of endpoints or an object with an "endpoints" list.
+	Path string `mapstructure:"path"`
+}
+
+// ConsulResolver defines the configuration for the resolver watching the instances of a service in a Consul compatible catalog
+type ConsulResolver struct {
+	// Address is the URL of the catalog API, "http://127.0.0.1:8500" by default.
+	Address     string              `mapstructure:"address"`
+	ServiceName string              `mapstructure:"service_name"`
+	Datacenter  string              `mapstructure:"datacenter"`
+	Tag         string              `mapstructure:"tag"`
+	Token       configopaque.String `mapstructure:"token"`
+	// Port overrides the port of the instances registered in the catalog.
+	Port *uint16 `mapstructure:"port"`
+	// WaitTime is the maximum duration of the blocking queries.
+	WaitTime time.Duration `mapstructure:"wait_time"`
+}
@@ -6,7 +6,9 @@ package loadbalancingexporter
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
@@ -25,3 +27,120 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, sub.Unmarshal(cfg))
 	require.NotNil(t, cfg)
 }
+
+func TestLoadConfigRouting(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "6").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	assert.Equal(t, attrRoutingStr, cfg.RoutingKey)
+	assert.Equal(t, []string{"service.name", "tenant.id"}, cfg.RoutingAttributes)
+	assert.Equal(t, 1.25, cfg.ConsistentHashing.LoadFactor)
+	assert.NoError(t, component.ValidateConfig(cfg))
+}
+
+func TestConfigValidate(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		cfg  *Config
+		err  string
+	}{
+		{
+			desc: "default",
+			cfg:  &Config{},
+		},
+		{
+			desc: "attributes without routing_attributes",
+			cfg:  &Config{RoutingKey: attrRoutingStr},
+			err:  `routing_attributes must be set when routing_key is "attributes"`,
+		},
+		{
+			desc: "ottl without routing_expression",
+			cfg:  &Config{RoutingKey: ottlRoutingStr},
+			err:  `routing_expression must be set when routing_key is "ottl"`,
+		},
+		{
+			desc: "ottl",
+			cfg:  &Config{RoutingKey: ottlRoutingStr, RoutingExpression: `attributes["tenant.id"]`},
+		},
+		{
+			desc: "load_factor too low",
+			cfg:  &Config{RoutingKey: svcRoutingStr, ConsistentHashing: ConsistentHashing{LoadFactor: 1}},
+			err:  "consistent_hashing::load_factor must be greater than 1, got 1",
+		},
+		{
+			desc: "load_factor with default routing key",
+			cfg:  &Config{ConsistentHashing: ConsistentHashing{LoadFactor: 1.25}},
+			err:  "consistent_hashing::load_factor cannot be used with the traceID routing key, as the spans of a trace could be sent to different backends",
+		},
+		{
+			desc: "load_factor with traceID routing key",
+			cfg:  &Config{RoutingKey: traceIDRoutingStr, ConsistentHashing: ConsistentHashing{LoadFactor: 1.25}},
+			err:  "consistent_hashing::load_factor cannot be used with the traceID routing key, as the spans of a trace could be sent to different backends",
+		},
+		{
+			desc: "load_factor with service routing key",
+			cfg:  &Config{RoutingKey: svcRoutingStr, ConsistentHashing: ConsistentHashing{LoadFactor: 1.25}},
+		},
+		{
+			desc: "health check",
+			cfg:  &Config{HealthCheck: healthCheckConfig(func(*HealthCheck) {})},
+		},
+		{
+			desc: "health check disabled",
+			cfg:  &Config{HealthCheck: HealthCheck{FailureThreshold: -1}},
+		},
+		{
+			desc: "health check without failure_threshold",
+			cfg:  &Config{HealthCheck: healthCheckConfig(func(h *HealthCheck) { h.FailureThreshold = 0 })},
+			err:  "health_check::failure_threshold must be greater than 0",
+		},
+		{
+			desc: "health check without ejection_duration",
+			cfg:  &Config{HealthCheck: healthCheckConfig(func(h *HealthCheck) { h.EjectionDuration = 0 })},
+			err:  "health_check::ejection_duration must be greater than 0",
+		},
+		{
+			desc: "health check with max_ejection_duration too low",
+			cfg:  &Config{HealthCheck: healthCheckConfig(func(h *HealthCheck) { h.MaxEjectionDuration = time.Second })},
+			err:  "health_check::max_ejection_duration must not be less than health_check::ejection_duration",
+		},
+		{
+			desc: "health check with invalid max_ejection_percent",
+			cfg:  &Config{HealthCheck: healthCheckConfig(func(h *HealthCheck) { h.MaxEjectionPercent = 101 })},
+			err:  "health_check::max_ejection_percent must be between 0 and 100, got 101",
+		},
+		{
+			desc: "grpc probe without interval",
+			cfg:  &Config{HealthCheck: healthCheckConfig(func(h *HealthCheck) { h.GRPCProbe.Interval = 0 })},
+			err:  "health_check::grpc_probe::interval must be greater than 0",
+		},
+		{
+			desc: "grpc probe without timeout",
+			cfg:  &Config{HealthCheck: healthCheckConfig(func(h *HealthCheck) { h.GRPCProbe.Timeout = 0 })},
+			err:  "health_check::grpc_probe::timeout must be greater than 0",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func healthCheckConfig(modify func(*HealthCheck)) HealthCheck {
+	cfg := createDefaultConfig().(*Config).HealthCheck
+	cfg.Enabled = true
+	cfg.GRPCProbe.Enabled = true
+	modify(&cfg)
+	return cfg
+}
@@ -27,13 +27,20 @@ type ringItem struct {
 type hashRing struct {
 	// ringItems holds all the positions, used for the lookup the position for the closest next ring item
 	items []ringItem
+	// endpoints is the number of distinct endpoints in the ring
+	endpoints int
 }
 
 // newHashRing builds a new immutable consistent hash ring based on the given endpoints.
 func newHashRing(endpoints []string) *hashRing {
 	items := positionsForEndpoints(endpoints, defaultWeight)
+	distinct := map[string]struct{}{}
+	for _, item := range items {
+		distinct[item.endpoint] = struct{}{}
+	}
 	return &hashRing{
-		items: items,
+		items:     items,
+		endpoints: len(distinct),
 	}
 }
 
@@ -43,12 +50,40 @@ func (h *hashRing) endpointFor(identifier []byte) string {
 		// perhaps the ring itself couldn't get initialized yet?
 		return ""
 	}
+	return h.findEndpoint(positionForIdentifier(identifier))
+}
+
+// boundedEndpointFor returns the first endpoint accepting the given identifier, walking the ring from the
+// position of the identifier as described in "Consistent Hashing with Bounded Loads" by Mirrokni et al.
+// The endpoint responsible for the identifier is returned when no endpoint accepts it.
+func (h *hashRing) boundedEndpointFor(identifier []byte, accept func(endpoint string) bool) string {
+	if h == nil || len(h.items) == 0 {
+		return ""
+	}
+	pos := positionForIdentifier(identifier)
+	start := sort.Search(len(h.items), func(i int) bool {
+		return h.items[i].pos >= pos
+	})
+
+	tried := make(map[string]struct{}, h.endpoints)
+	for i := 0; i < len(h.items) && len(tried) < h.endpoints; i++ {
+		endpoint := h.items[(start+i)%len(h.items)].endpoint
+		if _, ok := tried[endpoint]; ok {
+			continue
+		}
+		if accept(endpoint) {
+			return endpoint
+		}
+		tried[endpoint] = struct{}{}
+	}
+	return h.findEndpoint(pos)
+}
+
+func positionForIdentifier(identifier []byte) position {
 	hasher := crc32.NewIEEE()
 	hasher.Write(identifier)
 	hash := hasher.Sum32()
-	pos := hash % maxPositions
-
-	return h.findEndpoint(position(pos))
+	return position(hash % maxPositions)
 }
 
 // findEndpoint returns the "next" endpoint starting from the given position, or an empty string in case no endpoints are available
@@ -46,6 +46,36 @@ func TestEndpointFor(t *testing.T) {
 	}
 }
 
+func TestBoundedEndpointFor(t *testing.T) {
+	// prepare
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+	ring := newHashRing(endpoints)
+	id := []byte{128, 128, 0, 0}
+	owner := ring.endpointFor(id)
+
+	// test and verify
+	assert.Equal(t, owner, ring.boundedEndpointFor(id, func(string) bool { return true }))
+
+	var tried []string
+	endpoint := ring.boundedEndpointFor(id, func(endpoint string) bool {
+		tried = append(tried, endpoint)
+		return endpoint != owner
+	})
+	assert.NotEqual(t, owner, endpoint)
+	assert.Equal(t, []string{owner, endpoint}, tried)
+
+	tried = nil
+	endpoint = ring.boundedEndpointFor(id, func(endpoint string) bool {
+		tried = append(tried, endpoint)
+		return false
+	})
+	assert.Equal(t, owner, endpoint)
+	assert.ElementsMatch(t, endpoints, tried)
+
+	var nilRing *hashRing
+	assert.Equal(t, "", nilRing.boundedEndpointFor(id, func(string) bool { return true }))
+}
+
 func TestPositionsFor(t *testing.T) {
 	// prepare
 	endpoint := "host1"
@@ -155,7 +185,7 @@ func TestPositionsForEndpoints(t *testing.T) {
 
 func TestEqual(t *testing.T) {
 	original := &hashRing{
-		[]ringItem{
+		items: []ringItem{
 			{pos: position(123), endpoint: "endpoint-1"},
 		},
 	}
@@ -167,7 +197,7 @@ func TestEqual(t *testing.T) {
 	}{
 		{
 			"empty",
-			&hashRing{[]ringItem{}},
+			&hashRing{items: []ringItem{}},
 			false,
 		},
 		{
@@ -178,7 +208,7 @@ func TestEqual(t *testing.T) {
 		{
 			"equal",
 			&hashRing{
-				[]ringItem{
+				items: []ringItem{
 					{pos: position(123), endpoint: "endpoint-1"},
 				},
 			},
@@ -187,7 +217,7 @@ func TestEqual(t *testing.T) {
 		{
 			"different length",
 			&hashRing{
-				[]ringItem{
+				items: []ringItem{
 					{pos: position(123), endpoint: "endpoint-1"},
 					{pos: position(124), endpoint: "endpoint-2"},
 				},
@@ -197,7 +227,7 @@ func TestEqual(t *testing.T) {
 		{
 			"different position",
 			&hashRing{
-				[]ringItem{
+				items: []ringItem{
 					{pos: position(124), endpoint: "endpoint-1"},
 				},
 			},
@@ -206,7 +236,7 @@ func TestEqual(t *testing.T) {
 		{
 			"different endpoint",
 			&hashRing{
-				[]ringItem{
+				items: []ringItem{
 					{pos: position(123), endpoint: "endpoint-2"},
 				},
 			},
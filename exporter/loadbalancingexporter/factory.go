@@ -8,6 +8,7 @@ package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter"
@@ -44,6 +45,16 @@ func createDefaultConfig() component.Config {
 		Protocol: Protocol{
 			OTLP: *otlpDefaultCfg,
 		},
+		HealthCheck: HealthCheck{
+			FailureThreshold:    5,
+			EjectionDuration:    30 * time.Second,
+			MaxEjectionDuration: 5 * time.Minute,
+			MaxEjectionPercent:  50,
+			GRPCProbe: GRPCHealthProbe{
+				Interval: 10 * time.Second,
+				Timeout:  time.Second,
+			},
+		},
 	}
 }
 
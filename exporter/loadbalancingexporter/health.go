@@ -0,0 +1,250 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+)
+
+// endpointHealth is the health state of a single endpoint.
+type endpointHealth struct {
+	consecutiveFailures int
+	ejected             bool
+	ejectedUntil        time.Time
+	// ejections is the number of times the endpoint was ejected without staying healthy
+	// for the max ejection duration in between, it drives the ejection duration.
+	ejections    int
+	readmittedAt time.Time
+	// probeHealthy is the outcome of the last health probe, readmissions wait for a successful probe when probing.
+	probeHealthy bool
+	timer        *time.Timer
+}
+
+// healthTracker tracks the health of the endpoints from their export outcomes and health probes,
+// and ejects the endpoints failing consecutively.
+type healthTracker struct {
+	cfg       HealthCheck
+	logger    *zap.Logger
+	telemetry *metadata.TelemetryBuilder
+	now       func() time.Time
+	// onChange is called when endpoints are ejected or can be readmitted.
+	onChange func()
+
+	mu        sync.Mutex
+	endpoints map[string]*endpointHealth
+	stopped   bool
+}
+
+func newHealthTracker(cfg HealthCheck, logger *zap.Logger, telemetry *metadata.TelemetryBuilder, onChange func()) *healthTracker {
+	return &healthTracker{
+		cfg:       cfg,
+		logger:    logger,
+		telemetry: telemetry,
+		now:       time.Now,
+		onChange:  onChange,
+		endpoints: map[string]*endpointHealth{},
+	}
+}
+
+// setEndpoints sets the endpoints currently returned by the resolver, the state of the other endpoints is dropped.
+func (h *healthTracker) setEndpoints(endpoints []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	current := make(map[string]struct{}, len(endpoints))
+	for _, endpoint := range endpoints {
+		current[endpoint] = struct{}{}
+		if _, ok := h.endpoints[endpoint]; !ok {
+			h.endpoints[endpoint] = &endpointHealth{probeHealthy: true}
+		}
+	}
+	for endpoint, state := range h.endpoints {
+		if _, ok := current[endpoint]; !ok {
+			if state.timer != nil {
+				state.timer.Stop()
+			}
+			delete(h.endpoints, endpoint)
+		}
+	}
+	h.recordEjected()
+}
+
+// list returns the tracked endpoints.
+func (h *healthTracker) list() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	endpoints := make([]string, 0, len(h.endpoints))
+	for endpoint := range h.endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// recordSuccess records a successful export to the endpoint.
+func (h *healthTracker) recordSuccess(endpoint string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.endpoints[endpoint]
+	if !ok || state.ejected {
+		return
+	}
+	h.recordHealthy(state)
+}
+
+// recordFailure records a failed export to the endpoint, ejecting it once it reaches the failure threshold.
+func (h *healthTracker) recordFailure(ctx context.Context, endpoint string) {
+	if h.fail(ctx, endpoint) {
+		h.onChange()
+	}
+}
+
+// recordProbe records the outcome of a health probe of the endpoint.
+func (h *healthTracker) recordProbe(ctx context.Context, endpoint string, healthy bool) {
+	if !healthy {
+		h.mu.Lock()
+		if state, ok := h.endpoints[endpoint]; ok {
+			state.probeHealthy = false
+		}
+		h.mu.Unlock()
+		h.recordFailure(ctx, endpoint)
+		return
+	}
+
+	h.mu.Lock()
+	state, ok := h.endpoints[endpoint]
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+	state.probeHealthy = true
+	readmittable := state.ejected && !h.now().Before(state.ejectedUntil)
+	if !state.ejected {
+		h.recordHealthy(state)
+	}
+	h.mu.Unlock()
+
+	if readmittable {
+		h.onChange()
+	}
+}
+
+func (h *healthTracker) recordHealthy(state *endpointHealth) {
+	state.consecutiveFailures = 0
+	if state.ejections > 0 && h.now().Sub(state.readmittedAt) >= h.cfg.MaxEjectionDuration {
+		state.ejections = 0
+	}
+}
+
+// fail records a failure of the endpoint and reports whether it got ejected.
+func (h *healthTracker) fail(ctx context.Context, endpoint string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	state, ok := h.endpoints[endpoint]
+	if !ok || state.ejected || h.stopped {
+		return false
+	}
+
+	state.consecutiveFailures++
+	if state.consecutiveFailures < h.cfg.FailureThreshold {
+		return false
+	}
+
+	if !h.canEject() {
+		h.logger.Debug("endpoint is unhealthy but the maximum number of ejected endpoints is reached", zap.String("endpoint", endpoint))
+		return false
+	}
+
+	duration := h.cfg.EjectionDuration << state.ejections
+	if duration > h.cfg.MaxEjectionDuration || duration <= 0 {
+		duration = h.cfg.MaxEjectionDuration
+	}
+	state.ejected = true
+	state.ejections++
+	state.ejectedUntil = h.now().Add(duration)
+	state.timer = time.AfterFunc(duration, h.onChange)
+
+	h.logger.Warn("ejecting unhealthy endpoint from the ring",
+		zap.String("endpoint", endpoint),
+		zap.Int("consecutive_failures", state.consecutiveFailures),
+		zap.Duration("duration", duration),
+	)
+	h.telemetry.LoadbalancerBackendEjections.Add(ctx, 1, metric.WithAttributes(attribute.String("endpoint", endpoint)))
+	h.recordEjected()
+	return true
+}
+
+// canEject reports whether one more endpoint can be ejected, at least one endpoint always stays in the ring.
+func (h *healthTracker) canEject() bool {
+	ejected := 0
+	for _, state := range h.endpoints {
+		if state.ejected {
+			ejected++
+		}
+	}
+	if ejected+1 >= len(h.endpoints) {
+		return false
+	}
+	return (ejected+1)*100 <= len(h.endpoints)*h.cfg.MaxEjectionPercent
+}
+
+// healthy returns the given endpoints that are not ejected, readmitting the endpoints whose ejection is over.
+func (h *healthTracker) healthy(endpoints []string) []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	readmitted := false
+	healthy := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		state, ok := h.endpoints[endpoint]
+		if ok && state.ejected {
+			if now.Before(state.ejectedUntil) || !state.probeHealthy {
+				continue
+			}
+			state.ejected = false
+			state.consecutiveFailures = 0
+			state.readmittedAt = now
+			readmitted = true
+			h.logger.Info("readmitting endpoint to the ring", zap.String("endpoint", endpoint))
+		}
+		healthy = append(healthy, endpoint)
+	}
+	if readmitted {
+		h.recordEjected()
+	}
+	return healthy
+}
+
+func (h *healthTracker) recordEjected() {
+	ejected := 0
+	for _, state := range h.endpoints {
+		if state.ejected {
+			ejected++
+		}
+	}
+	h.telemetry.LoadbalancerNumEjectedBackends.Record(context.Background(), int64(ejected))
+}
+
+func (h *healthTracker) shutdown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stopped = true
+	for _, state := range h.endpoints {
+		if state.timer != nil {
+			state.timer.Stop()
+		}
+	}
+}
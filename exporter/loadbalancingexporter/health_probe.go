@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthProber periodically checks the health of the endpoints with the gRPC health checking protocol.
+type healthProber struct {
+	cfg     GRPCHealthProbe
+	creds   credentials.TransportCredentials
+	tracker *healthTracker
+	logger  *zap.Logger
+
+	conns    map[string]*grpc.ClientConn
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newHealthProber(cfg GRPCHealthProbe, creds credentials.TransportCredentials, tracker *healthTracker, logger *zap.Logger) *healthProber {
+	return &healthProber{
+		cfg:      cfg,
+		creds:    creds,
+		tracker:  tracker,
+		logger:   logger,
+		conns:    map[string]*grpc.ClientConn{},
+		stopChan: make(chan struct{}),
+	}
+}
+
+func (p *healthProber) start() {
+	p.wg.Add(1)
+	go p.periodicallyProbe()
+}
+
+func (p *healthProber) periodicallyProbe() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.probeAll(context.Background())
+		case <-p.stopChan:
+			return
+		}
+	}
+}
+
+// probeAll probes all the tracked endpoints concurrently.
+func (p *healthProber) probeAll(ctx context.Context) {
+	endpoints := p.tracker.list()
+	p.closeStaleConns(endpoints)
+
+	var wg sync.WaitGroup
+	for _, endpoint := range endpoints {
+		conn, err := p.conn(endpoint)
+		if err != nil {
+			p.logger.Debug("failed to create the health probe connection", zap.String("endpoint", endpoint), zap.Error(err))
+			p.tracker.recordProbe(ctx, endpoint, false)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.tracker.recordProbe(ctx, endpoint, p.probe(ctx, endpoint, conn))
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *healthProber) probe(ctx context.Context, endpoint string, conn *grpc.ClientConn) bool {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: p.cfg.Service})
+	if err != nil {
+		p.logger.Debug("health probe failed", zap.String("endpoint", endpoint), zap.Error(err))
+		return false
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		p.logger.Debug("endpoint is not serving", zap.String("endpoint", endpoint), zap.Stringer("status", resp.GetStatus()))
+		return false
+	}
+	return true
+}
+
+func (p *healthProber) conn(endpoint string) (*grpc.ClientConn, error) {
+	if conn, ok := p.conns[endpoint]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.NewClient(endpointWithPort(endpoint), grpc.WithTransportCredentials(p.creds))
+	if err != nil {
+		return nil, err
+	}
+	p.conns[endpoint] = conn
+	return conn, nil
+}
+
+func (p *healthProber) closeStaleConns(endpoints []string) {
+	for endpoint, conn := range p.conns {
+		if !endpointFound(endpoint, endpoints) {
+			_ = conn.Close()
+			delete(p.conns, endpoint)
+		}
+	}
+}
+
+func (p *healthProber) shutdown() {
+	close(p.stopChan)
+	p.wg.Wait()
+	p.closeStaleConns(nil)
+}
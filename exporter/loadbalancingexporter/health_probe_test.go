@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func startHealthServer(t *testing.T) (string, *health.Server) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	srv := grpc.NewServer()
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+
+	return ln.Addr().String(), healthSrv
+}
+
+func TestHealthProber(t *testing.T) {
+	// prepare
+	endpoint, healthSrv := startHealthServer(t)
+	other, _ := startHealthServer(t)
+	// nothing listens on this endpoint
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	unreachable := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	cfg := testHealthCheckConfig()
+	cfg.FailureThreshold = 1
+	cfg.MaxEjectionPercent = 100
+	h, now, _ := newTestHealthTracker(t, cfg)
+	endpoints := []string{endpoint, unreachable, other}
+	h.setEndpoints(endpoints)
+
+	p := newHealthProber(GRPCHealthProbe{Interval: time.Hour, Timeout: time.Second}, insecure.NewCredentials(), h, zap.NewNop())
+	defer p.shutdown()
+
+	// test and verify
+	p.probeAll(context.Background())
+	assert.Equal(t, []string{endpoint, other}, h.healthy(endpoints))
+
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	p.probeAll(context.Background())
+	assert.Equal(t, []string{other}, h.healthy(endpoints))
+
+	// the endpoint is readmitted once it serves again after the ejection, unlike the unreachable endpoint
+	*now = now.Add(cfg.EjectionDuration)
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	p.probeAll(context.Background())
+	assert.Equal(t, []string{endpoint, other}, h.healthy(endpoints))
+	assert.Len(t, p.conns, 3)
+
+	// the connections of the removed endpoints are closed
+	h.setEndpoints([]string{endpoint})
+	p.probeAll(context.Background())
+	assert.Len(t, p.conns, 1)
+}
+
+func TestHealthProberService(t *testing.T) {
+	// prepare
+	endpoint, healthSrv := startHealthServer(t)
+	healthSrv.SetServingStatus("opentelemetry.proto.collector.trace.v1.TraceService", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	cfg := testHealthCheckConfig()
+	cfg.FailureThreshold = 1
+	cfg.MaxEjectionPercent = 100
+	h, _, _ := newTestHealthTracker(t, cfg)
+	endpoints := []string{endpoint, "endpoint-2"}
+	h.setEndpoints(endpoints)
+
+	p := newHealthProber(GRPCHealthProbe{
+		Interval: time.Hour,
+		Timeout:  time.Second,
+		Service:  "opentelemetry.proto.collector.trace.v1.TraceService",
+	}, insecure.NewCredentials(), h, zap.NewNop())
+	defer p.shutdown()
+
+	// test
+	h.recordProbe(context.Background(), endpoint, p.probe(context.Background(), endpoint, mustConn(t, p, endpoint)))
+
+	// verify
+	assert.Equal(t, []string{"endpoint-2"}, h.healthy(endpoints))
+}
+
+func TestHealthProberStartShutdown(t *testing.T) {
+	h, _, _ := newTestHealthTracker(t, testHealthCheckConfig())
+	p := newHealthProber(GRPCHealthProbe{Interval: time.Millisecond, Timeout: time.Second}, insecure.NewCredentials(), h, zap.NewNop())
+
+	p.start()
+	time.Sleep(5 * time.Millisecond)
+	p.shutdown()
+}
+
+func mustConn(t *testing.T, p *healthProber, endpoint string) *grpc.ClientConn {
+	conn, err := p.conn(endpoint)
+	require.NoError(t, err)
+	return conn
+}
@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestHealthTracker(t *testing.T, cfg HealthCheck) (*healthTracker, *time.Time, *atomic.Int32) {
+	_, tb := getTelemetryAssets(t)
+	changes := &atomic.Int32{}
+	h := newHealthTracker(cfg, zap.NewNop(), tb, func() { changes.Add(1) })
+	now := time.Now()
+	h.now = func() time.Time { return now }
+	t.Cleanup(h.shutdown)
+	return h, &now, changes
+}
+
+func testHealthCheckConfig() HealthCheck {
+	return HealthCheck{
+		Enabled:             true,
+		FailureThreshold:    3,
+		EjectionDuration:    time.Minute,
+		MaxEjectionDuration: 5 * time.Minute,
+		MaxEjectionPercent:  50,
+	}
+}
+
+func TestHealthTrackerEjectsAfterConsecutiveFailures(t *testing.T) {
+	// prepare
+	h, _, changes := newTestHealthTracker(t, testHealthCheckConfig())
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	h.setEndpoints(endpoints)
+
+	// test
+	h.recordFailure(context.Background(), "endpoint-1")
+	h.recordFailure(context.Background(), "endpoint-1")
+	// a success resets the consecutive failures
+	h.recordSuccess("endpoint-1")
+	h.recordFailure(context.Background(), "endpoint-1")
+	h.recordFailure(context.Background(), "endpoint-1")
+	assert.Equal(t, endpoints, h.healthy(endpoints))
+	assert.Equal(t, int32(0), changes.Load())
+
+	h.recordFailure(context.Background(), "endpoint-1")
+
+	// verify
+	assert.Equal(t, []string{"endpoint-2"}, h.healthy(endpoints))
+	assert.Equal(t, int32(1), changes.Load())
+}
+
+func TestHealthTrackerReadmitsAfterEjection(t *testing.T) {
+	// prepare
+	h, now, _ := newTestHealthTracker(t, testHealthCheckConfig())
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	h.setEndpoints(endpoints)
+	ejectEndpoint(h, "endpoint-1")
+
+	// test
+	*now = now.Add(59 * time.Second)
+	assert.Equal(t, []string{"endpoint-2"}, h.healthy(endpoints))
+
+	*now = now.Add(time.Second)
+
+	// verify
+	assert.Equal(t, endpoints, h.healthy(endpoints))
+}
+
+func TestHealthTrackerHysteresis(t *testing.T) {
+	// prepare
+	h, now, _ := newTestHealthTracker(t, testHealthCheckConfig())
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	h.setEndpoints(endpoints)
+
+	// test and verify
+	// each ejection following a short readmission lasts twice as long, up to the max ejection duration
+	for _, expected := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute} {
+		ejectEndpoint(h, "endpoint-1")
+		*now = now.Add(expected - time.Second)
+		assert.Equal(t, []string{"endpoint-2"}, h.healthy(endpoints), expected)
+		*now = now.Add(time.Second)
+		assert.Equal(t, endpoints, h.healthy(endpoints), expected)
+	}
+
+	// staying healthy for the max ejection duration resets the ejection duration
+	*now = now.Add(5 * time.Minute)
+	h.recordSuccess("endpoint-1")
+	ejectEndpoint(h, "endpoint-1")
+	*now = now.Add(time.Minute)
+	assert.Equal(t, endpoints, h.healthy(endpoints))
+}
+
+func TestHealthTrackerMaxEjectionPercent(t *testing.T) {
+	// prepare
+	h, _, _ := newTestHealthTracker(t, testHealthCheckConfig())
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3", "endpoint-4"}
+	h.setEndpoints(endpoints)
+
+	// test
+	for _, endpoint := range endpoints {
+		ejectEndpoint(h, endpoint)
+	}
+
+	// verify
+	assert.Equal(t, []string{"endpoint-3", "endpoint-4"}, h.healthy(endpoints))
+}
+
+func TestHealthTrackerKeepsLastEndpoint(t *testing.T) {
+	// prepare
+	cfg := testHealthCheckConfig()
+	cfg.MaxEjectionPercent = 100
+	h, _, _ := newTestHealthTracker(t, cfg)
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	h.setEndpoints(endpoints)
+
+	// test
+	ejectEndpoint(h, "endpoint-1")
+	ejectEndpoint(h, "endpoint-2")
+
+	// verify
+	assert.Equal(t, []string{"endpoint-2"}, h.healthy(endpoints))
+}
+
+func TestHealthTrackerProbes(t *testing.T) {
+	// prepare
+	h, now, changes := newTestHealthTracker(t, testHealthCheckConfig())
+	endpoints := []string{"endpoint-1", "endpoint-2"}
+	h.setEndpoints(endpoints)
+
+	// test
+	for i := 0; i < 3; i++ {
+		h.recordProbe(context.Background(), "endpoint-1", false)
+	}
+	require.Equal(t, []string{"endpoint-2"}, h.healthy(endpoints))
+
+	// verify
+	// the endpoint stays ejected after the ejection duration until a probe succeeds
+	*now = now.Add(time.Minute)
+	assert.Equal(t, []string{"endpoint-2"}, h.healthy(endpoints))
+
+	h.recordProbe(context.Background(), "endpoint-1", true)
+	assert.Equal(t, int32(2), changes.Load())
+	assert.Equal(t, endpoints, h.healthy(endpoints))
+}
+
+func TestHealthTrackerForgetsRemovedEndpoints(t *testing.T) {
+	// prepare
+	h, _, _ := newTestHealthTracker(t, testHealthCheckConfig())
+	h.setEndpoints([]string{"endpoint-1", "endpoint-2", "endpoint-3"})
+	ejectEndpoint(h, "endpoint-1")
+
+	// test
+	h.setEndpoints([]string{"endpoint-2", "endpoint-3"})
+	h.setEndpoints([]string{"endpoint-1", "endpoint-2", "endpoint-3"})
+
+	// verify
+	assert.ElementsMatch(t, []string{"endpoint-1", "endpoint-2", "endpoint-3"}, h.list())
+	assert.Equal(t, []string{"endpoint-1", "endpoint-2", "endpoint-3"}, h.healthy([]string{"endpoint-1", "endpoint-2", "endpoint-3"}))
+}
+
+func ejectEndpoint(h *healthTracker, endpoint string) {
+	for i := 0; i < h.cfg.FailureThreshold; i++ {
+		h.recordFailure(context.Background(), endpoint)
+	}
+}
@@ -24,12 +24,16 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 // TelemetryBuilder provides an interface for components to report telemetry
 // as defined in metadata and user config.
 type TelemetryBuilder struct {
-	meter                         metric.Meter
-	LoadbalancerBackendLatency    metric.Int64Histogram
-	LoadbalancerBackendOutcome    metric.Int64Counter
-	LoadbalancerNumBackendUpdates metric.Int64Counter
-	LoadbalancerNumBackends       metric.Int64Gauge
-	LoadbalancerNumResolutions    metric.Int64Counter
+	meter                            metric.Meter
+	LoadbalancerBackendEjections     metric.Int64Counter
+	LoadbalancerBackendLatency       metric.Int64Histogram
+	LoadbalancerBackendLoad          metric.Int64Counter
+	LoadbalancerBackendOutcome       metric.Int64Counter
+	LoadbalancerBoundedLoadOverflows metric.Int64Counter
+	LoadbalancerNumBackendUpdates    metric.Int64Counter
+	LoadbalancerNumBackends          metric.Int64Gauge
+	LoadbalancerNumEjectedBackends   metric.Int64Gauge
+	LoadbalancerNumResolutions       metric.Int64Counter
 }
 
 // TelemetryBuilderOption applies changes to default builder.
@@ -52,6 +56,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 	}
 	builder.meter = Meter(settings)
 	var err, errs error
+	builder.LoadbalancerBackendEjections, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_loadbalancer_backend_ejections",
+		metric.WithDescription("Number of times each endpoint was ejected from the ring after failing its health checks."),
+		metric.WithUnit("{ejections}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.LoadbalancerBackendLatency, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Histogram(
 		"otelcol_loadbalancer_backend_latency",
 		metric.WithDescription("Response latency in ms for the backends."),
@@ -59,12 +69,24 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithExplicitBucketBoundaries([]float64{5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000}...),
 	)
 	errs = errors.Join(errs, err)
+	builder.LoadbalancerBackendLoad, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_loadbalancer_backend_load",
+		metric.WithDescription("Number of spans, data points or log records routed to each endpoint."),
+		metric.WithUnit("{items}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.LoadbalancerBackendOutcome, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
 		"otelcol_loadbalancer_backend_outcome",
 		metric.WithDescription("Number of successes and failures for each endpoint."),
 		metric.WithUnit("{outcomes}"),
 	)
 	errs = errors.Join(errs, err)
+	builder.LoadbalancerBoundedLoadOverflows, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
+		"otelcol_loadbalancer_bounded_load_overflows",
+		metric.WithDescription("Number of routing keys sent to another endpoint than their owner on the ring, as the owner reached its load bound."),
+		metric.WithUnit("{keys}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.LoadbalancerNumBackendUpdates, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
 		"otelcol_loadbalancer_num_backend_updates",
 		metric.WithDescription("Number of times the list of backends was updated."),
@@ -77,6 +99,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...Teleme
 		metric.WithUnit("{backends}"),
 	)
 	errs = errors.Join(errs, err)
+	builder.LoadbalancerNumEjectedBackends, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Gauge(
+		"otelcol_loadbalancer_num_ejected_backends",
+		metric.WithDescription("Current number of backends ejected from the ring."),
+		metric.WithUnit("{backends}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.LoadbalancerNumResolutions, err = getLeveledMeter(builder.meter, configtelemetry.LevelBasic, settings.MetricsLevel).Int64Counter(
 		"otelcol_loadbalancer_num_resolutions",
 		metric.WithDescription("Number of times the resolver has triggered new resolutions."),
@@ -11,7 +11,10 @@ import (
 	"sync"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 )
@@ -31,8 +34,13 @@ type loadBalancer struct {
 	logger *zap.Logger
 	host   component.Host
 
-	res  resolver
-	ring *hashRing
+	res          resolver
+	resolved     []string
+	ring         *hashRing
+	boundedLoads *boundedLoads
+	health       *healthTracker
+	prober       *healthProber
+	telemetry    *metadata.TelemetryBuilder
 
 	componentFactory componentFactory
 	exporters        map[string]*wrappedExporter
@@ -58,6 +66,12 @@ func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory component
 	if oCfg.Resolver.K8sSvc != nil {
 		count++
 	}
+	if oCfg.Resolver.File != nil {
+		count++
+	}
+	if oCfg.Resolver.Consul != nil {
+		count++
+	}
 	if count > 1 {
 		return nil, errMultipleResolversProvided
 	}
@@ -128,42 +142,132 @@ func newLoadBalancer(logger *zap.Logger, cfg component.Config, factory component
 		}
 	}
 
+	if oCfg.Resolver.File != nil {
+		fileLogger := logger.With(zap.String("resolver", "file"))
+
+		var err error
+		res, err = newFileResolver(fileLogger, oCfg.Resolver.File.Path, telemetry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if oCfg.Resolver.Consul != nil {
+		consulLogger := logger.With(zap.String("resolver", "consul"))
+
+		var err error
+		res, err = newConsulResolver(consulLogger, oCfg.Resolver.Consul, telemetry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if res == nil {
 		return nil, errNoResolver
 	}
 
-	return &loadBalancer{
+	lb := &loadBalancer{
 		logger:           logger,
 		res:              res,
+		telemetry:        telemetry,
 		componentFactory: factory,
 		exporters:        map[string]*wrappedExporter{},
-	}, nil
+	}
+	if oCfg.ConsistentHashing.LoadFactor > 0 {
+		lb.boundedLoads = newBoundedLoads(oCfg.ConsistentHashing.LoadFactor)
+	}
+	if oCfg.HealthCheck.Enabled {
+		healthLogger := logger.With(zap.String("component", "health_check"))
+		lb.health = newHealthTracker(oCfg.HealthCheck, healthLogger, telemetry, lb.onHealthChanges)
+		if oCfg.HealthCheck.GRPCProbe.Enabled {
+			tlsCfg, err := oCfg.Protocol.OTLP.TLSSetting.LoadTLSConfig(context.Background())
+			if err != nil {
+				return nil, fmt.Errorf("failed to load the TLS configuration of the health probe: %w", err)
+			}
+			creds := insecure.NewCredentials()
+			if tlsCfg != nil {
+				creds = credentials.NewTLS(tlsCfg)
+			}
+			lb.prober = newHealthProber(oCfg.HealthCheck.GRPCProbe, creds, lb.health, healthLogger)
+		}
+	}
+	return lb, nil
 }
 
 func (lb *loadBalancer) Start(ctx context.Context, host component.Host) error {
 	lb.res.onChange(lb.onBackendChanges)
 	lb.host = host
-	return lb.res.start(ctx)
+	if err := lb.res.start(ctx); err != nil {
+		return err
+	}
+	if lb.prober != nil {
+		lb.prober.start()
+	}
+	return nil
 }
 
 func (lb *loadBalancer) onBackendChanges(resolved []string) {
-	newRing := newHashRing(resolved)
-
-	if !newRing.equal(lb.ring) {
-		lb.updateLock.Lock()
-		defer lb.updateLock.Unlock()
+	if lb.health != nil {
+		lb.health.setEndpoints(resolved)
+	}
 
-		lb.ring = newRing
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
 
+	lb.resolved = resolved
+	if lb.updateRing() || lb.health != nil {
 		// TODO: set a timeout?
 		ctx := context.Background()
 
 		// add the missing exporters first
+		// the exporters of the ejected endpoints are kept, so that they can be readmitted
 		lb.addMissingExporters(ctx, resolved)
 		lb.removeExtraExporters(ctx, resolved)
 	}
 }
 
+// onHealthChanges rebuilds the ring when endpoints are ejected or can be readmitted.
+func (lb *loadBalancer) onHealthChanges() {
+	lb.updateLock.Lock()
+	defer lb.updateLock.Unlock()
+
+	if lb.stopped {
+		return
+	}
+	lb.updateRing()
+}
+
+// updateRing rebuilds the ring from the resolved endpoints that are not ejected and reports whether it changed.
+// It must be called with the update lock held.
+func (lb *loadBalancer) updateRing() bool {
+	endpoints := lb.resolved
+	if lb.health != nil {
+		endpoints = lb.health.healthy(endpoints)
+	}
+
+	newRing := newHashRing(endpoints)
+	if newRing.equal(lb.ring) {
+		return false
+	}
+	lb.ring = newRing
+	return true
+}
+
+// reportOutcome records the outcome of an export to the endpoint in its health.
+func (lb *loadBalancer) reportOutcome(ctx context.Context, endpoint string, err error) {
+	if lb.health == nil {
+		return
+	}
+	switch {
+	case err == nil:
+		lb.health.recordSuccess(endpoint)
+	case consumererror.IsPermanent(err):
+		// the data was rejected by the endpoint, which is not a sign of its health
+	default:
+		lb.health.recordFailure(ctx, endpoint)
+	}
+}
+
 func (lb *loadBalancer) addMissingExporters(ctx context.Context, endpoints []string) {
 	for _, endpoint := range endpoints {
 		endpoint = endpointWithPort(endpoint)
@@ -220,7 +324,15 @@ func endpointFound(endpoint string, endpoints []string) bool {
 
 func (lb *loadBalancer) Shutdown(ctx context.Context) error {
 	err := lb.res.shutdown(ctx)
+	if lb.prober != nil {
+		lb.prober.shutdown()
+	}
+	if lb.health != nil {
+		lb.health.shutdown()
+	}
+	lb.updateLock.Lock()
 	lb.stopped = true
+	lb.updateLock.Unlock()
 
 	for _, e := range lb.exporters {
 		err = errors.Join(err, e.Shutdown(ctx))
@@ -229,13 +341,23 @@ func (lb *loadBalancer) Shutdown(ctx context.Context) error {
 }
 
 // exporterAndEndpoint returns the exporter and the endpoint for the given identifier.
-func (lb *loadBalancer) exporterAndEndpoint(identifier []byte) (*wrappedExporter, string, error) {
+// The load is the number of items routed with the identifier, used when the loads of the endpoints are bounded.
+func (lb *loadBalancer) exporterAndEndpoint(ctx context.Context, identifier []byte, load int) (*wrappedExporter, string, error) {
 	// NOTE: make rolling updates of next tier of collectors work. currently, this may cause
 	// data loss because the latest batches sent to outdated backend will never find their way out.
 	// for details: https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/1690
 	lb.updateLock.RLock()
 	defer lb.updateLock.RUnlock()
-	endpoint := lb.ring.endpointFor(identifier)
+	var endpoint string
+	if lb.boundedLoads != nil {
+		var overflow bool
+		endpoint, overflow = lb.boundedLoads.assign(lb.ring, identifier, load)
+		if overflow {
+			lb.telemetry.LoadbalancerBoundedLoadOverflows.Add(ctx, 1)
+		}
+	} else {
+		endpoint = lb.ring.endpointFor(identifier)
+	}
 	exp, found := lb.exporters[endpointWithPort(endpoint)]
 	if !found {
 		// something is really wrong... how come we couldn't find the exporter??
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
@@ -147,12 +148,86 @@ func TestWithDNSResolverNoEndpoints(t *testing.T) {
 	defer func() { assert.NoError(t, p.Shutdown(context.Background())) }()
 
 	// test
-	_, e, _ := p.exporterAndEndpoint([]byte{128, 128, 0, 0})
+	_, e, _ := p.exporterAndEndpoint(context.Background(), []byte{128, 128, 0, 0}, 1)
 
 	// verify
 	assert.Equal(t, "", e)
 }
 
+func TestNewLoadBalancerInvalidFileResolver(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			File: &FileResolver{},
+		},
+	}
+
+	// test
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+	// verify
+	require.Nil(t, p)
+	require.Equal(t, errNoFilePath, err)
+}
+
+func TestWithFileResolver(t *testing.T) {
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			File: &FileResolver{Path: "endpoints.yaml"},
+		},
+	}
+
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	// test
+	res, ok := p.res.(*fileResolver)
+
+	// verify
+	assert.NotNil(t, res)
+	assert.True(t, ok)
+}
+
+func TestNewLoadBalancerInvalidConsulResolver(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Consul: &ConsulResolver{},
+		},
+	}
+
+	// test
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+
+	// verify
+	require.Nil(t, p)
+	require.Equal(t, errNoConsulService, err)
+}
+
+func TestWithConsulResolver(t *testing.T) {
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Consul: &ConsulResolver{ServiceName: "service-1"},
+		},
+	}
+
+	p, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	// test
+	res, ok := p.res.(*consulResolver)
+
+	// verify
+	assert.NotNil(t, res)
+	assert.True(t, ok)
+}
+
 func TestMultipleResolvers(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
 	cfg := &Config{
@@ -235,6 +310,66 @@ func TestOnBackendChanges(t *testing.T) {
 	assert.Len(t, p.ring.items, 2*defaultWeight)
 }
 
+func TestHealthAwareFailover(t *testing.T) {
+	// prepare
+	ts, tb := getTelemetryAssets(t)
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		HealthCheck: testHealthCheckConfig(),
+	}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return newNopMockExporter(), nil
+	}
+
+	p, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, p.Shutdown(context.Background())) }()
+
+	// this trace ID will reach the endpoint-1 -- see the consistent hashing tests for more info
+	id := []byte{1, 2, 0, 0}
+	_, endpoint, err := p.exporterAndEndpoint(context.Background(), id, 1)
+	require.NoError(t, err)
+	require.Equal(t, "endpoint-1", endpoint)
+
+	// test
+	// permanent errors are not a sign of the health of the endpoint
+	for i := 0; i < cfg.HealthCheck.FailureThreshold; i++ {
+		p.reportOutcome(context.Background(), endpoint, consumererror.NewPermanent(errors.New("invalid data")))
+	}
+	_, endpoint, err = p.exporterAndEndpoint(context.Background(), id, 1)
+	require.NoError(t, err)
+	require.Equal(t, "endpoint-1", endpoint)
+
+	for i := 0; i < cfg.HealthCheck.FailureThreshold; i++ {
+		p.reportOutcome(context.Background(), endpoint, errors.New("connection refused"))
+	}
+
+	// verify
+	_, endpoint, err = p.exporterAndEndpoint(context.Background(), id, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "endpoint-2", endpoint)
+	// the exporter of the ejected endpoint is kept for its readmission
+	assert.Contains(t, p.exporters, "endpoint-1:4317")
+}
+
+func TestNewLoadBalancerInvalidHealthProbeTLS(t *testing.T) {
+	ts, tb := getTelemetryAssets(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Resolver.Static = &StaticResolver{Hostnames: []string{"endpoint-1"}}
+	cfg.HealthCheck.Enabled = true
+	cfg.HealthCheck.GRPCProbe.Enabled = true
+	cfg.Protocol.OTLP.TLSSetting.CAFile = "/non/existent/ca.pem"
+
+	_, err := newLoadBalancer(ts.Logger, cfg, nil, tb)
+	assert.ErrorContains(t, err, "failed to load the TLS configuration of the health probe")
+}
+
 func TestRemoveExtraExporters(t *testing.T) {
 	// prepare
 	ts, tb := getTelemetryAssets(t)
@@ -397,14 +532,14 @@ func TestFailedExporterInRing(t *testing.T) {
 
 	// test
 	// this trace ID will reach the endpoint-2 -- see the consistent hashing tests for more info
-	_, _, err = p.exporterAndEndpoint([]byte{128, 128, 0, 0})
+	_, _, err = p.exporterAndEndpoint(context.Background(), []byte{128, 128, 0, 0}, 1)
 
 	// verify
 	assert.Error(t, err)
 
 	// test
 	// this service name will reach the endpoint-2 -- see the consistent hashing tests for more info
-	_, _, err = p.exporterAndEndpoint([]byte("get-recommendations-1"))
+	_, _, err = p.exporterAndEndpoint(context.Background(), []byte("get-recommendations-1"), 1)
 
 	// verify
 	assert.Error(t, err)
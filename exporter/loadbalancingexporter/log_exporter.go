@@ -5,6 +5,7 @@ package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"sync"
 	"time"
@@ -21,12 +22,16 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 )
 
 var _ exporter.Logs = (*logExporterImp)(nil)
 
 type logExporterImp struct {
-	loadBalancer *loadBalancer
+	loadBalancer      *loadBalancer
+	routingKey        routingKey
+	routingAttributes []string
+	routingExpression *routingExpression[ottllog.TransformContext]
 
 	logger     *zap.Logger
 	started    bool
@@ -53,11 +58,30 @@ func newLogsExporter(params exporter.Settings, cfg component.Config) (*logExport
 		return nil, err
 	}
 
-	return &logExporterImp{
+	logExporter := logExporterImp{
 		loadBalancer: lb,
+		routingKey:   traceIDRouting,
 		telemetry:    telemetry,
 		logger:       params.Logger,
-	}, nil
+	}
+
+	// other routing keys are ignored, logs are routed by trace ID
+	switch cfg.(*Config).RoutingKey {
+	case attrRoutingStr:
+		logExporter.routingKey = attrRouting
+		logExporter.routingAttributes = cfg.(*Config).RoutingAttributes
+	case ottlRoutingStr:
+		logExporter.routingKey = ottlRouting
+		parser, err := ottllog.NewParser(routingFunctions[ottllog.TransformContext](), params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+		logExporter.routingExpression, err = newRoutingExpression(parser, cfg.(*Config).RoutingExpression)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse routing_expression: %w", err)
+		}
+	}
+	return &logExporter, nil
 }
 
 func (e *logExporterImp) Capabilities() consumer.Capabilities {
@@ -90,16 +114,12 @@ func (e *logExporterImp) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
 }
 
 func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
-	traceID := traceIDFromLogs(ld)
-	balancingKey := traceID
-	if traceID == pcommon.NewTraceIDEmpty() {
-		// every log may not contain a traceID
-		// generate a random traceID as balancingKey
-		// so the log can be routed to a random backend
-		balancingKey = random()
+	balancingKey, err := e.balancingKey(ctx, ld)
+	if err != nil {
+		return err
 	}
 
-	le, _, err := e.loadBalancer.exporterAndEndpoint(balancingKey[:])
+	le, endpoint, err := e.loadBalancer.exporterAndEndpoint(ctx, balancingKey, ld.LogRecordCount())
 	if err != nil {
 		return err
 	}
@@ -107,9 +127,11 @@ func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 	le.consumeWG.Add(1)
 	defer le.consumeWG.Done()
 
+	e.telemetry.LoadbalancerBackendLoad.Add(ctx, int64(ld.LogRecordCount()), metric.WithAttributeSet(le.endpointAttr))
 	start := time.Now()
 	err = le.ConsumeLogs(ctx, ld)
 	duration := time.Since(start)
+	e.loadBalancer.reportOutcome(ctx, endpoint, err)
 	e.telemetry.LoadbalancerBackendLatency.Record(ctx, duration.Milliseconds(), metric.WithAttributeSet(le.endpointAttr))
 	if err == nil {
 		e.telemetry.LoadbalancerBackendOutcome.Add(ctx, 1, metric.WithAttributeSet(le.successAttr))
@@ -121,6 +143,60 @@ func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 	return err
 }
 
+// balancingKey returns the routing key of the batch. With the "attributes" and "ottl" routing keys, the batch
+// is routed by the attributes or the expression evaluated on its first log record, and by the trace ID when
+// they yield no value.
+func (e *logExporterImp) balancingKey(ctx context.Context, ld plog.Logs) ([]byte, error) {
+	if e.routingKey == attrRouting || e.routingKey == ottlRouting {
+		key, found, err := e.routingKeyFromLogs(ctx, ld)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			return []byte(key), nil
+		}
+	}
+
+	traceID := traceIDFromLogs(ld)
+	balancingKey := traceID
+	if traceID == pcommon.NewTraceIDEmpty() {
+		// every log may not contain a traceID
+		// generate a random traceID as balancingKey
+		// so the log can be routed to a random backend
+		balancingKey = random()
+	}
+	return balancingKey[:], nil
+}
+
+func (e *logExporterImp) routingKeyFromLogs(ctx context.Context, ld plog.Logs) (string, bool, error) {
+	rl := ld.ResourceLogs()
+	if rl.Len() == 0 {
+		return "", false, nil
+	}
+
+	sl := rl.At(0).ScopeLogs()
+	if sl.Len() == 0 {
+		return "", false, nil
+	}
+
+	logs := sl.At(0).LogRecords()
+	if logs.Len() == 0 {
+		return "", false, nil
+	}
+
+	if e.routingKey == attrRouting {
+		key, found := routingKeyFromAttributes(e.routingAttributes, rl.At(0).Resource().Attributes(), logs.At(0).Attributes())
+		return key, found, nil
+	}
+
+	value, err := e.routingExpression.Eval(ctx, ottllog.NewTransformContext(logs.At(0), sl.At(0).Scope(), rl.At(0).Resource(), sl.At(0), rl.At(0)))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to evaluate routing_expression: %w", err)
+	}
+	key, found := routingKeyFromValue(value)
+	return key, found, nil
+}
+
 func traceIDFromLogs(ld plog.Logs) pcommon.TraceID {
 	rl := ld.ResourceLogs()
 	if rl.Len() == 0 {
@@ -23,6 +23,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
+	conventions "go.opentelemetry.io/collector/semconv/v1.27.0"
 	"go.uber.org/zap"
 )
 
@@ -296,6 +297,61 @@ func TestLogsWithoutTraceID(t *testing.T) {
 	assert.Len(t, sink.AllLogs(), 1)
 }
 
+func TestAttributeAndExpressionRoutingForLogs(t *testing.T) {
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	for _, tt := range []struct {
+		desc  string
+		cfg   *Config
+		batch plog.Logs
+		key   []byte
+	}{
+		{
+			"resource attribute",
+			attributeBasedRoutingConfig(conventions.AttributeServiceName),
+			logsWithAttributes(),
+			[]byte("service-1"),
+		},
+		{
+			"record attribute",
+			attributeBasedRoutingConfig("tenant"),
+			logsWithAttributes(),
+			[]byte("tenant-1"),
+		},
+		{
+			"missing attribute falls back to trace id",
+			attributeBasedRoutingConfig("namespace"),
+			logsWithAttributes(),
+			traceID[:],
+		},
+		{
+			"expression",
+			expressionBasedRoutingConfig(`Concat([resource.attributes["service.name"], attributes["tenant"]], "/")`),
+			logsWithAttributes(),
+			[]byte("service-1/tenant-1"),
+		},
+		{
+			"nil expression falls back to trace id",
+			expressionBasedRoutingConfig(`attributes["namespace"]`),
+			logsWithAttributes(),
+			traceID[:],
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			p, err := newLogsExporter(exportertest.NewNopSettings(), tt.cfg)
+			require.NoError(t, err)
+
+			key, err := p.balancingKey(context.Background(), tt.batch)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.key, key)
+		})
+	}
+}
+
+func TestInvalidRoutingExpressionForLogs(t *testing.T) {
+	_, err := newLogsExporter(exportertest.NewNopSettings(), expressionBasedRoutingConfig(`attributes["tenant"`))
+	assert.ErrorContains(t, err, "failed to parse routing_expression")
+}
+
 // this test validates that exporter is can concurrently change the endpoints while consuming logs.
 func TestConsumeLogs_ConcurrentResolverChange(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
@@ -518,6 +574,15 @@ func simpleLogWithoutID() plog.Logs {
 	return logs
 }
 
+func logsWithAttributes() plog.Logs {
+	logs := simpleLogs()
+	rl := logs.ResourceLogs().At(0)
+	rl.Resource().Attributes().PutStr(conventions.AttributeServiceName, "service-1")
+	rl.ScopeLogs().At(0).LogRecords().At(0).Attributes().PutStr("tenant", "tenant-1")
+
+	return logs
+}
+
 type mockLogsExporter struct {
 	component.Component
 	consumelogsfn func(ctx context.Context, ld plog.Logs) error
@@ -23,13 +23,16 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
 )
 
 var _ exporter.Metrics = (*metricExporterImp)(nil)
 
 type metricExporterImp struct {
-	loadBalancer *loadBalancer
-	routingKey   routingKey
+	loadBalancer      *loadBalancer
+	routingKey        routingKey
+	routingAttributes []string
+	routingExpression *routingExpression[ottlresource.TransformContext]
 
 	logger     *zap.Logger
 	stopped    bool
@@ -72,6 +75,19 @@ func newMetricsExporter(params exporter.Settings, cfg component.Config) (*metric
 		metricExporter.routingKey = metricNameRouting
 	case streamIDRoutingStr:
 		metricExporter.routingKey = streamIDRouting
+	case attrRoutingStr:
+		metricExporter.routingKey = attrRouting
+		metricExporter.routingAttributes = cfg.(*Config).RoutingAttributes
+	case ottlRoutingStr:
+		metricExporter.routingKey = ottlRouting
+		parser, err := ottlresource.NewParser(routingFunctions[ottlresource.TransformContext](), params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+		metricExporter.routingExpression, err = newRoutingExpression(parser, cfg.(*Config).RoutingExpression)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse routing_expression: %w", err)
+		}
 	default:
 		return nil, fmt.Errorf("unsupported routing_key: %q", cfg.(*Config).RoutingKey)
 	}
@@ -109,6 +125,14 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 		batches = splitMetricsByMetricName(md)
 	case streamIDRouting:
 		batches = splitMetricsByStreamID(md)
+	case attrRouting, ottlRouting:
+		var err error
+		batches, err = splitMetricsByResourceKey(md, func(rm pmetric.ResourceMetrics) (string, bool, error) {
+			return e.resourceRoutingKey(ctx, rm)
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	// Now assign each batch to an exporter, and merge as we go
@@ -116,7 +140,7 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 	exporterEndpoints := map[*wrappedExporter]string{}
 
 	for routingID, mds := range batches {
-		exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(routingID))
+		exp, endpoint, err := e.loadBalancer.exporterAndEndpoint(ctx, []byte(routingID), mds.DataPointCount())
 		if err != nil {
 			return err
 		}
@@ -134,12 +158,14 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 
 	var errs error
 	for exp, mds := range metricsByExporter {
+		e.telemetry.LoadbalancerBackendLoad.Add(ctx, int64(mds.DataPointCount()), metric.WithAttributeSet(exp.endpointAttr))
 		start := time.Now()
 		err := exp.ConsumeMetrics(ctx, mds)
 		duration := time.Since(start)
 
 		exp.consumeWG.Done()
 		errs = multierr.Append(errs, err)
+		e.loadBalancer.reportOutcome(ctx, exporterEndpoints[exp], err)
 		e.telemetry.LoadbalancerBackendLatency.Record(ctx, duration.Milliseconds(), metric.WithAttributeSet(exp.endpointAttr))
 		if err == nil {
 			e.telemetry.LoadbalancerBackendOutcome.Add(ctx, 1, metric.WithAttributeSet(exp.successAttr))
@@ -152,6 +178,52 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 	return errs
 }
 
+// resourceRoutingKey returns the routing key of the resource from the routing attributes or the routing expression.
+func (e *metricExporterImp) resourceRoutingKey(ctx context.Context, rm pmetric.ResourceMetrics) (string, bool, error) {
+	if e.routingKey == attrRouting {
+		key, found := routingKeyFromAttributes(e.routingAttributes, rm.Resource().Attributes())
+		return key, found, nil
+	}
+
+	value, err := e.routingExpression.Eval(ctx, ottlresource.NewTransformContext(rm.Resource(), rm))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to evaluate routing_expression: %w", err)
+	}
+	key, found := routingKeyFromValue(value)
+	return key, found, nil
+}
+
+// splitMetricsByResourceKey splits the metrics by the key of their resource, resources without a key
+// are split by their identity.
+func splitMetricsByResourceKey(md pmetric.Metrics, resourceKey func(pmetric.ResourceMetrics) (string, bool, error)) (map[string]pmetric.Metrics, error) {
+	results := map[string]pmetric.Metrics{}
+
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+
+		key, found, err := resourceKey(rm)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			key = identity.OfResource(rm.Resource()).String()
+		}
+
+		newMD := pmetric.NewMetrics()
+		rmClone := newMD.ResourceMetrics().AppendEmpty()
+		rm.CopyTo(rmClone)
+
+		existing, ok := results[key]
+		if ok {
+			metrics.Merge(existing, newMD)
+		} else {
+			results[key] = newMD
+		}
+	}
+
+	return results, nil
+}
+
 func splitMetricsByResourceServiceName(md pmetric.Metrics) (map[string]pmetric.Metrics, error) {
 	results := map[string]pmetric.Metrics{}
 
@@ -24,11 +24,13 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	conventions "go.opentelemetry.io/collector/semconv/v1.27.0"
 	"gopkg.in/yaml.v2"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
 )
@@ -307,6 +309,51 @@ func TestSplitMetrics(t *testing.T) {
 	}
 }
 
+func TestAttributeAndExpressionRoutingForMetrics(t *testing.T) {
+	for _, tt := range []struct {
+		desc string
+		cfg  *Config
+		keys []string
+	}{
+		{
+			"attributes",
+			attributeBasedRoutingConfig(conventions.AttributeServiceName, keyAttr2),
+			[]string{serviceName1 + "\x00", serviceName2 + "\x0010"},
+		},
+		{
+			"expression",
+			expressionBasedRoutingConfig(`Concat([attributes["service.name"], "metrics"], "/")`),
+			[]string{serviceName1 + "/metrics", serviceName2 + "/metrics"},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			p, err := newMetricsExporter(exportertest.NewNopSettings(), tt.cfg)
+			require.NoError(t, err)
+
+			md := twoServicesWithSameMetricName()
+			md.ResourceMetrics().At(1).Resource().Attributes().PutInt(keyAttr2, valueAttr2)
+
+			batches, err := splitMetricsByResourceKey(md, func(rm pmetric.ResourceMetrics) (string, bool, error) {
+				return p.resourceRoutingKey(context.Background(), rm)
+			})
+			require.NoError(t, err)
+			assert.Len(t, batches, len(tt.keys))
+			for _, key := range tt.keys {
+				assert.Contains(t, batches, key)
+			}
+		})
+	}
+}
+
+func TestSplitMetricsByResourceKeyFallsBackToResourceID(t *testing.T) {
+	md := simpleMetricsWithResource()
+	batches, err := splitMetricsByResourceKey(md, func(pmetric.ResourceMetrics) (string, bool, error) {
+		return "", false, nil
+	})
+	require.NoError(t, err)
+	assert.Contains(t, batches, identity.OfResource(md.ResourceMetrics().At(0).Resource()).String())
+}
+
 func TestConsumeMetrics_SingleEndpoint(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
 	t.Parallel()
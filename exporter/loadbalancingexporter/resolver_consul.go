@@ -0,0 +1,280 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+)
+
+var _ resolver = (*consulResolver)(nil)
+
+const (
+	defaultConsulAddress   = "http://127.0.0.1:8500"
+	defaultConsulWaitTime  = 5 * time.Minute
+	defaultConsulRetryWait = 5 * time.Second
+
+	consulIndexHeader = "X-Consul-Index"
+	consulTokenHeader = "X-Consul-Token"
+)
+
+var (
+	errNoConsulService = errors.New("no service name specified for the consul resolver")
+
+	consulResolverAttr           = attribute.String("resolver", "consul")
+	consulResolverAttrSet        = attribute.NewSet(consulResolverAttr)
+	consulResolverSuccessAttrSet = attribute.NewSet(consulResolverAttr, attribute.Bool("success", true))
+	consulResolverFailureAttrSet = attribute.NewSet(consulResolverAttr, attribute.Bool("success", false))
+)
+
+// consulCatalogService is the part of an entry of the Consul catalog service API used by the resolver.
+type consulCatalogService struct {
+	Address        string `json:"Address"`
+	ServiceAddress string `json:"ServiceAddress"`
+	ServicePort    int    `json:"ServicePort"`
+}
+
+// consulResolver resolves the backends from the instances of a service in a Consul compatible catalog,
+// using blocking queries to be notified of the changes as soon as they happen.
+type consulResolver struct {
+	logger *zap.Logger
+
+	client    *http.Client
+	url       *url.URL
+	token     string
+	port      *uint16
+	waitTime  time.Duration
+	retryWait time.Duration
+
+	endpoints         []string
+	index             uint64
+	onChangeCallbacks []func([]string)
+
+	cancel             context.CancelFunc
+	updateLock         sync.Mutex
+	shutdownWg         sync.WaitGroup
+	changeCallbackLock sync.RWMutex
+	telemetry          *metadata.TelemetryBuilder
+}
+
+func newConsulResolver(logger *zap.Logger, cfg *ConsulResolver, tb *metadata.TelemetryBuilder) (*consulResolver, error) {
+	if cfg.ServiceName == "" {
+		return nil, errNoConsulService
+	}
+
+	address := cfg.Address
+	if address == "" {
+		address = defaultConsulAddress
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address for the consul resolver: %w", err)
+	}
+	u = u.JoinPath("v1", "catalog", "service", cfg.ServiceName)
+	query := u.Query()
+	if cfg.Datacenter != "" {
+		query.Set("dc", cfg.Datacenter)
+	}
+	if cfg.Tag != "" {
+		query.Set("tag", cfg.Tag)
+	}
+	u.RawQuery = query.Encode()
+
+	waitTime := cfg.WaitTime
+	if waitTime == 0 {
+		waitTime = defaultConsulWaitTime
+	}
+
+	return &consulResolver{
+		logger:    logger,
+		client:    &http.Client{},
+		url:       u,
+		token:     string(cfg.Token),
+		port:      cfg.Port,
+		waitTime:  waitTime,
+		retryWait: defaultConsulRetryWait,
+		telemetry: tb,
+	}, nil
+}
+
+func (r *consulResolver) start(ctx context.Context) error {
+	if _, err := r.resolve(ctx); err != nil {
+		r.logger.Warn("failed to resolve", zap.Error(err))
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.shutdownWg.Add(1)
+	go r.watch(watchCtx)
+
+	r.logger.Debug("consul resolver started", zap.Stringer("url", r.url), zap.Duration("wait_time", r.waitTime))
+	return nil
+}
+
+func (r *consulResolver) shutdown(_ context.Context) error {
+	r.changeCallbackLock.Lock()
+	r.onChangeCallbacks = nil
+	r.changeCallbackLock.Unlock()
+
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.shutdownWg.Wait()
+	return nil
+}
+
+// watch resolves the backends in a loop of blocking queries, each returning when the catalog changed or the wait time is over.
+func (r *consulResolver) watch(ctx context.Context) {
+	defer r.shutdownWg.Done()
+
+	for {
+		_, err := r.resolve(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			r.logger.Warn("failed to resolve", zap.Error(err))
+		}
+
+		r.updateLock.Lock()
+		index := r.index
+		r.updateLock.Unlock()
+
+		// the query did not block, wait before the next one
+		if err != nil || index == 0 {
+			select {
+			case <-time.After(r.retryWait):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (r *consulResolver) resolve(ctx context.Context) ([]string, error) {
+	services, index, err := r.query(ctx)
+	if err != nil {
+		r.telemetry.LoadbalancerNumResolutions.Add(ctx, 1, metric.WithAttributeSet(consulResolverFailureAttrSet))
+		return nil, err
+	}
+
+	r.telemetry.LoadbalancerNumResolutions.Add(ctx, 1, metric.WithAttributeSet(consulResolverSuccessAttrSet))
+
+	backends := make([]string, 0, len(services))
+	for _, service := range services {
+		address := service.ServiceAddress
+		if address == "" {
+			// the service uses the address of its node
+			address = service.Address
+		}
+		port := service.ServicePort
+		if r.port != nil {
+			port = int(*r.port)
+		}
+		if port == 0 {
+			backends = append(backends, address)
+			continue
+		}
+		backends = append(backends, net.JoinHostPort(address, strconv.Itoa(port)))
+	}
+
+	// keep it always in the same order
+	sort.Strings(backends)
+
+	r.updateLock.Lock()
+	// the index must be reset when it goes backwards, see https://developer.hashicorp.com/consul/api-docs/features/blocking
+	if index < r.index {
+		index = 0
+	}
+	r.index = index
+	if equalStringSlice(r.endpoints, backends) {
+		r.updateLock.Unlock()
+		return backends, nil
+	}
+
+	// the list has changed!
+	r.endpoints = backends
+	r.updateLock.Unlock()
+	r.telemetry.LoadbalancerNumBackends.Record(ctx, int64(len(backends)), metric.WithAttributeSet(consulResolverAttrSet))
+	r.telemetry.LoadbalancerNumBackendUpdates.Add(ctx, 1, metric.WithAttributeSet(consulResolverAttrSet))
+
+	// propagate the change
+	r.changeCallbackLock.RLock()
+	for _, callback := range r.onChangeCallbacks {
+		callback(backends)
+	}
+	r.changeCallbackLock.RUnlock()
+
+	return backends, nil
+}
+
+// query runs a blocking query of the service instances, returning immediately on the first query.
+func (r *consulResolver) query(ctx context.Context) ([]consulCatalogService, uint64, error) {
+	r.updateLock.Lock()
+	index := r.index
+	r.updateLock.Unlock()
+
+	u := *r.url
+	if index > 0 {
+		query := u.Query()
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", r.waitTime.String())
+		u.RawQuery = query.Encode()
+	}
+
+	// consul adds up to wait/16 of jitter to the wait time
+	ctx, cancel := context.WithTimeout(ctx, r.waitTime+r.waitTime/16+defaultResTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if r.token != "" {
+		req.Header.Set(consulTokenHeader, r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query the catalog: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("failed to query the catalog: unexpected status %q", resp.Status)
+	}
+
+	var services []consulCatalogService
+	if err = json.NewDecoder(resp.Body).Decode(&services); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode the catalog response: %w", err)
+	}
+
+	newIndex, err := strconv.ParseUint(resp.Header.Get(consulIndexHeader), 10, 64)
+	if err != nil {
+		// without an index, the next queries can't block
+		newIndex = 0
+	}
+	return services, newIndex, nil
+}
+
+func (r *consulResolver) onChange(f func([]string)) {
+	r.changeCallbackLock.Lock()
+	defer r.changeCallbackLock.Unlock()
+	r.onChangeCallbacks = append(r.onChangeCallbacks, f)
+}
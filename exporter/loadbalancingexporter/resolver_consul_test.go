@@ -0,0 +1,273 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeCatalog stands in for the catalog service API of Consul, supporting blocking queries.
+type fakeCatalog struct {
+	mu       sync.Mutex
+	index    uint64
+	services []consulCatalogService
+	changed  chan struct{}
+	requests []*http.Request
+	status   int
+}
+
+func newFakeCatalog(t *testing.T, services ...consulCatalogService) (*fakeCatalog, *httptest.Server) {
+	c := &fakeCatalog{index: 1, services: services, changed: make(chan struct{}), status: http.StatusOK}
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+	return c, srv
+}
+
+func (c *fakeCatalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	c.requests = append(c.requests, r)
+	index, changed, status := c.index, c.changed, c.status
+	c.mu.Unlock()
+
+	if status != http.StatusOK {
+		w.WriteHeader(status)
+		return
+	}
+
+	if requested, err := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64); err == nil && requested >= index {
+		wait, err := time.ParseDuration(r.URL.Query().Get("wait"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		select {
+		case <-changed:
+		case <-time.After(wait):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w.Header().Set(consulIndexHeader, strconv.FormatUint(c.index, 10))
+	_ = json.NewEncoder(w).Encode(c.services)
+}
+
+func (c *fakeCatalog) update(services ...consulCatalogService) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.services = services
+	c.index++
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *fakeCatalog) lastRequest() *http.Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests[len(c.requests)-1]
+}
+
+func TestConsulResolverNoServiceName(t *testing.T) {
+	_, tb := getTelemetryAssets(t)
+	res, err := newConsulResolver(zap.NewNop(), &ConsulResolver{}, tb)
+	require.Nil(t, res)
+	require.ErrorIs(t, err, errNoConsulService)
+}
+
+func TestConsulResolverInitialResolution(t *testing.T) {
+	// prepare
+	_, tb := getTelemetryAssets(t)
+	catalog, srv := newFakeCatalog(t,
+		consulCatalogService{Address: "10.0.0.2", ServicePort: 4317},
+		consulCatalogService{Address: "10.0.0.1", ServiceAddress: "10.0.1.1", ServicePort: 4317},
+		consulCatalogService{Address: "10.0.0.3"},
+	)
+	res, err := newConsulResolver(zap.NewNop(), &ConsulResolver{
+		Address:     srv.URL,
+		ServiceName: "otelcol",
+		Datacenter:  "dc1",
+		Tag:         "otlp",
+		Token:       "secret",
+	}, tb)
+	require.NoError(t, err)
+
+	// test
+	resolved, err := res.resolve(context.Background())
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.2:4317", "10.0.0.3", "10.0.1.1:4317"}, resolved)
+
+	req := catalog.lastRequest()
+	assert.Equal(t, "/v1/catalog/service/otelcol", req.URL.Path)
+	assert.Equal(t, "dc1", req.URL.Query().Get("dc"))
+	assert.Equal(t, "otlp", req.URL.Query().Get("tag"))
+	assert.False(t, req.URL.Query().Has("index"))
+	assert.Equal(t, "secret", req.Header.Get(consulTokenHeader))
+	assert.Equal(t, uint64(1), res.index)
+}
+
+func TestConsulResolverPortOverride(t *testing.T) {
+	// prepare
+	_, tb := getTelemetryAssets(t)
+	_, srv := newFakeCatalog(t,
+		consulCatalogService{Address: "10.0.0.1", ServicePort: 8080},
+		consulCatalogService{Address: "10.0.0.2"},
+	)
+	port := uint16(4317)
+	res, err := newConsulResolver(zap.NewNop(), &ConsulResolver{
+		Address:     srv.URL,
+		ServiceName: "otelcol",
+		Port:        &port,
+	}, tb)
+	require.NoError(t, err)
+
+	// test
+	resolved, err := res.resolve(context.Background())
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1:4317", "10.0.0.2:4317"}, resolved)
+}
+
+func TestConsulResolverBlockingQueries(t *testing.T) {
+	// prepare
+	_, tb := getTelemetryAssets(t)
+	catalog, srv := newFakeCatalog(t, consulCatalogService{Address: "10.0.0.1", ServicePort: 4317})
+	res, err := newConsulResolver(zap.NewNop(), &ConsulResolver{
+		Address:     srv.URL,
+		ServiceName: "otelcol",
+		WaitTime:    time.Minute,
+	}, tb)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var resolved []string
+	res.onChange(func(endpoints []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		resolved = endpoints
+	})
+	lastResolved := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return resolved
+	}
+
+	// test
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		require.NoError(t, res.shutdown(context.Background()))
+	}()
+	assert.Equal(t, []string{"10.0.0.1:4317"}, lastResolved())
+
+	// wait for the blocking query to be in flight
+	require.Eventually(t, func() bool {
+		return catalog.lastRequest().URL.Query().Get("index") == "1"
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "1m0s", catalog.lastRequest().URL.Query().Get("wait"))
+
+	catalog.update(
+		consulCatalogService{Address: "10.0.0.1", ServicePort: 4317},
+		consulCatalogService{Address: "10.0.0.2", ServicePort: 4317},
+	)
+
+	// verify
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"10.0.0.1:4317", "10.0.0.2:4317"}, lastResolved())
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestConsulResolverIndexGoesBackwards(t *testing.T) {
+	// prepare
+	_, tb := getTelemetryAssets(t)
+	catalog, srv := newFakeCatalog(t, consulCatalogService{Address: "10.0.0.1", ServicePort: 4317})
+	res, err := newConsulResolver(zap.NewNop(), &ConsulResolver{Address: srv.URL, ServiceName: "otelcol"}, tb)
+	require.NoError(t, err)
+	res.index = 10
+	catalog.index = 11
+
+	// test
+	_, err = res.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11), res.index)
+
+	catalog.mu.Lock()
+	catalog.index = 5
+	catalog.mu.Unlock()
+	// the query returns after the wait time, as the index did not move forward
+	res.waitTime = 10 * time.Millisecond
+	_, err = res.resolve(context.Background())
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), res.index)
+}
+
+func TestConsulResolverFailure(t *testing.T) {
+	// prepare
+	_, tb := getTelemetryAssets(t)
+	catalog, srv := newFakeCatalog(t, consulCatalogService{Address: "10.0.0.1", ServicePort: 4317})
+	res, err := newConsulResolver(zap.NewNop(), &ConsulResolver{Address: srv.URL, ServiceName: "otelcol"}, tb)
+	require.NoError(t, err)
+
+	_, err = res.resolve(context.Background())
+	require.NoError(t, err)
+
+	catalog.mu.Lock()
+	catalog.status = http.StatusForbidden
+	catalog.mu.Unlock()
+
+	// test
+	resolved, err := res.resolve(context.Background())
+
+	// verify
+	require.ErrorContains(t, err, "unexpected status")
+	assert.Nil(t, resolved)
+	// the last known endpoints are kept
+	assert.Equal(t, []string{"10.0.0.1:4317"}, res.endpoints)
+}
+
+func TestConsulResolverShutdownInterruptsQuery(t *testing.T) {
+	// prepare
+	_, tb := getTelemetryAssets(t)
+	catalog, srv := newFakeCatalog(t, consulCatalogService{Address: "10.0.0.1", ServicePort: 4317})
+	res, err := newConsulResolver(zap.NewNop(), &ConsulResolver{
+		Address:     srv.URL,
+		ServiceName: "otelcol",
+		WaitTime:    time.Hour,
+	}, tb)
+	require.NoError(t, err)
+	require.NoError(t, res.start(context.Background()))
+	require.Eventually(t, func() bool {
+		return catalog.lastRequest().URL.Query().Get("index") == "1"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// test
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, res.shutdown(context.Background()))
+	}()
+
+	// verify
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the shutdown did not interrupt the blocking query")
+	}
+}
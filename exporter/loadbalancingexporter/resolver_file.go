@@ -0,0 +1,202 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
+)
+
+var _ resolver = (*fileResolver)(nil)
+
+var (
+	errNoFilePath         = errors.New("no path specified for the file resolver")
+	errEmptyEndpointsFile = errors.New("the endpoints file is empty")
+
+	fileResolverAttr           = attribute.String("resolver", "file")
+	fileResolverAttrSet        = attribute.NewSet(fileResolverAttr)
+	fileResolverSuccessAttrSet = attribute.NewSet(fileResolverAttr, attribute.Bool("success", true))
+	fileResolverFailureAttrSet = attribute.NewSet(fileResolverAttr, attribute.Bool("success", false))
+)
+
+// fileResolver reads the endpoints from a JSON or YAML file, and resolves them again whenever the file changes.
+type fileResolver struct {
+	logger *zap.Logger
+
+	path string
+
+	endpoints         []string
+	onChangeCallbacks []func([]string)
+
+	watcher            *fsnotify.Watcher
+	stopCh             chan (struct{})
+	updateLock         sync.Mutex
+	shutdownWg         sync.WaitGroup
+	changeCallbackLock sync.RWMutex
+	telemetry          *metadata.TelemetryBuilder
+}
+
+func newFileResolver(logger *zap.Logger, path string, tb *metadata.TelemetryBuilder) (*fileResolver, error) {
+	if path == "" {
+		return nil, errNoFilePath
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path for the file resolver: %w", err)
+	}
+
+	return &fileResolver{
+		logger:    logger,
+		path:      absPath,
+		stopCh:    make(chan struct{}),
+		telemetry: tb,
+	}, nil
+}
+
+func (r *fileResolver) start(ctx context.Context) error {
+	if _, err := r.resolve(ctx); err != nil {
+		r.logger.Warn("failed to resolve", zap.Error(err))
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create the file watcher: %w", err)
+	}
+	// the directory is watched, so that the changes are noticed when the file is replaced,
+	// as config management tools and Kubernetes config maps do
+	if err = watcher.Add(filepath.Dir(r.path)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("failed to watch the directory of %q: %w", r.path, err)
+	}
+	r.watcher = watcher
+
+	r.shutdownWg.Add(1)
+	go r.watch()
+
+	r.logger.Debug("file resolver started", zap.String("path", r.path))
+	return nil
+}
+
+func (r *fileResolver) shutdown(_ context.Context) error {
+	r.changeCallbackLock.Lock()
+	r.onChangeCallbacks = nil
+	r.changeCallbackLock.Unlock()
+
+	close(r.stopCh)
+	r.shutdownWg.Wait()
+	if r.watcher != nil {
+		return r.watcher.Close()
+	}
+	return nil
+}
+
+func (r *fileResolver) watch() {
+	defer r.shutdownWg.Done()
+
+	for {
+		select {
+		case _, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			// any change in the directory might be a change of the file, a symlink to it or one of its parents
+			if _, err := r.resolve(context.Background()); err != nil {
+				r.logger.Warn("failed to resolve", zap.Error(err))
+			}
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Warn("file watcher error", zap.Error(err))
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+func (r *fileResolver) resolve(ctx context.Context) ([]string, error) {
+	backends, err := readEndpointsFile(r.path)
+	if err != nil {
+		r.telemetry.LoadbalancerNumResolutions.Add(ctx, 1, metric.WithAttributeSet(fileResolverFailureAttrSet))
+		return nil, err
+	}
+
+	r.telemetry.LoadbalancerNumResolutions.Add(ctx, 1, metric.WithAttributeSet(fileResolverSuccessAttrSet))
+
+	// keep it always in the same order
+	sort.Strings(backends)
+
+	r.updateLock.Lock()
+	if equalStringSlice(r.endpoints, backends) {
+		r.updateLock.Unlock()
+		return backends, nil
+	}
+
+	// the list has changed!
+	r.endpoints = backends
+	r.updateLock.Unlock()
+	r.telemetry.LoadbalancerNumBackends.Record(ctx, int64(len(backends)), metric.WithAttributeSet(fileResolverAttrSet))
+	r.telemetry.LoadbalancerNumBackendUpdates.Add(ctx, 1, metric.WithAttributeSet(fileResolverAttrSet))
+
+	// propagate the change
+	r.changeCallbackLock.RLock()
+	for _, callback := range r.onChangeCallbacks {
+		callback(backends)
+	}
+	r.changeCallbackLock.RUnlock()
+
+	return backends, nil
+}
+
+func (r *fileResolver) onChange(f func([]string)) {
+	r.changeCallbackLock.Lock()
+	defer r.changeCallbackLock.Unlock()
+	r.onChangeCallbacks = append(r.onChangeCallbacks, f)
+}
+
+// readEndpointsFile reads the endpoints from the file, holding either a list of endpoints or an object
+// with an "endpoints" list. JSON files are parsed as YAML, of which JSON is a subset.
+func readEndpointsFile(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the endpoints file: %w", err)
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		// most likely a file being written, the current endpoints are kept
+		return nil, errEmptyEndpointsFile
+	}
+
+	var endpoints []string
+	if err = yaml.Unmarshal(content, &endpoints); err != nil {
+		var file struct {
+			Endpoints []string `yaml:"endpoints"`
+		}
+		if err = yaml.Unmarshal(content, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse the endpoints file: %w", err)
+		}
+		endpoints = file.Endpoints
+	}
+
+	backends := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if endpoint != "" {
+			backends = append(backends, endpoint)
+		}
+	}
+	return backends, nil
+}
@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFileResolverNoPath(t *testing.T) {
+	_, tb := getTelemetryAssets(t)
+	res, err := newFileResolver(zap.NewNop(), "", tb)
+	require.Nil(t, res)
+	require.ErrorIs(t, err, errNoFilePath)
+}
+
+func TestReadEndpointsFile(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		content  string
+		expected []string
+		err      error
+	}{
+		{
+			name:     "json list",
+			content:  `["endpoint-2:4317", "endpoint-1:4317"]`,
+			expected: []string{"endpoint-2:4317", "endpoint-1:4317"},
+		},
+		{
+			name:     "json object",
+			content:  `{"endpoints": ["endpoint-1:4317", "endpoint-2:4317"]}`,
+			expected: []string{"endpoint-1:4317", "endpoint-2:4317"},
+		},
+		{
+			name:     "yaml list",
+			content:  "- endpoint-1:4317\n- endpoint-2:4317\n",
+			expected: []string{"endpoint-1:4317", "endpoint-2:4317"},
+		},
+		{
+			name:     "yaml object",
+			content:  "endpoints:\n  - endpoint-1:4317\n  - \"\"\n  - endpoint-2:4317\n",
+			expected: []string{"endpoint-1:4317", "endpoint-2:4317"},
+		},
+		{
+			name:    "empty",
+			content: " \n",
+			err:     errEmptyEndpointsFile,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "endpoints")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			endpoints, err := readEndpointsFile(path)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, endpoints)
+		})
+	}
+}
+
+func TestReadEndpointsFileInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("endpoints: endpoint-1"), 0o600))
+
+	_, err := readEndpointsFile(path)
+	require.ErrorContains(t, err, "failed to parse the endpoints file")
+
+	_, err = readEndpointsFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorContains(t, err, "failed to read the endpoints file")
+}
+
+func TestFileResolverWatchesChanges(t *testing.T) {
+	// prepare
+	_, tb := getTelemetryAssets(t)
+	path := filepath.Join(t.TempDir(), "endpoints.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("- endpoint-2\n- endpoint-1\n"), 0o600))
+
+	res, err := newFileResolver(zap.NewNop(), path, tb)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var resolved []string
+	res.onChange(func(endpoints []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		resolved = endpoints
+	})
+	lastResolved := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return resolved
+	}
+
+	// test
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		require.NoError(t, res.shutdown(context.Background()))
+	}()
+	assert.Equal(t, []string{"endpoint-1", "endpoint-2"}, lastResolved())
+
+	// the file is replaced, as config management tools do
+	tmp := filepath.Join(filepath.Dir(path), "endpoints.tmp")
+	require.NoError(t, os.WriteFile(tmp, []byte(`{"endpoints": ["endpoint-3"]}`), 0o600))
+	require.NoError(t, os.Rename(tmp, path))
+
+	// verify
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"endpoint-3"}, lastResolved())
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// an empty file, most likely being written, keeps the current endpoints
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	_, err = res.resolve(context.Background())
+	require.ErrorIs(t, err, errEmptyEndpointsFile)
+	assert.Equal(t, []string{"endpoint-3"}, lastResolved())
+}
+
+func TestFileResolverMissingFile(t *testing.T) {
+	// prepare
+	_, tb := getTelemetryAssets(t)
+	path := filepath.Join(t.TempDir(), "endpoints.yaml")
+	res, err := newFileResolver(zap.NewNop(), path, tb)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var resolved []string
+	res.onChange(func(endpoints []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		resolved = endpoints
+	})
+
+	// test
+	// the resolver starts without the file, and picks it up once it gets created
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		require.NoError(t, res.shutdown(context.Background()))
+	}()
+	require.NoError(t, os.WriteFile(path, []byte("- endpoint-1\n"), 0o600))
+
+	// verify
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return assert.ObjectsAreEqual([]string{"endpoint-1"}, resolved)
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+// routingAttributesSeparator separates the values of the routing attributes in the routing key.
+const routingAttributesSeparator = "\x00"
+
+// routingKeyFromAttributes joins the values of the given attributes into a routing key. Each attribute is
+// looked up in the maps in order, the first map holding the attribute wins. Returns false when none
+// of the attributes is found.
+func routingKeyFromAttributes(names []string, maps ...pcommon.Map) (string, bool) {
+	values := make([]string, len(names))
+	found := false
+	for i, name := range names {
+		for _, m := range maps {
+			if v, ok := m.Get(name); ok {
+				values[i] = v.AsString()
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		return "", false
+	}
+	return strings.Join(values, routingAttributesSeparator), true
+}
+
+// routingKeyFromValue converts the result of the routing expression into a routing key.
+// Returns false when the expression evaluated to nil or to an empty value.
+func routingKeyFromValue(value any) (string, bool) {
+	var key string
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		key = v
+	case []byte:
+		key = string(v)
+	case pcommon.Value:
+		key = v.AsString()
+	case pcommon.Map:
+		key = fmt.Sprint(v.AsRaw())
+	case pcommon.Slice:
+		key = fmt.Sprint(v.AsRaw())
+	default:
+		key = fmt.Sprint(v)
+	}
+	return key, key != ""
+}
+
+// routeFunctionName is the name of the editor the routing expression is passed to, so that
+// the expression can be parsed as an OTTL statement returning the value of the expression.
+const routeFunctionName = "route"
+
+type routeArguments[K any] struct {
+	Value ottl.Getter[K]
+}
+
+func newRouteFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory(routeFunctionName, &routeArguments[K]{}, createRouteFunction[K])
+}
+
+func createRouteFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*routeArguments[K])
+	if !ok {
+		return nil, errors.New("RouteFactory args must be of type *routeArguments[K]")
+	}
+	return args.Value.Get, nil
+}
+
+// routingFunctions returns the OTTL functions the routing expression can use.
+func routingFunctions[K any]() map[string]ottl.Factory[K] {
+	functions := ottlfuncs.StandardConverters[K]()
+	functions[routeFunctionName] = newRouteFactory[K]()
+	return functions
+}
+
+// routingExpression evaluates the routing_expression, such as a path, a literal or a
+// converter call, to the value the routing key is made of.
+type routingExpression[K any] struct {
+	statement *ottl.Statement[K]
+}
+
+// newRoutingExpression parses the routing expression with a parser created with routingFunctions.
+func newRoutingExpression[K any](parser ottl.Parser[K], expression string) (*routingExpression[K], error) {
+	statement, err := parser.ParseStatement(fmt.Sprintf("%s(%s)", routeFunctionName, expression))
+	if err != nil {
+		return nil, err
+	}
+	return &routingExpression[K]{statement: statement}, nil
+}
+
+// Eval returns the value of the expression for the given transform context.
+func (e *routingExpression[K]) Eval(ctx context.Context, tCtx K) (any, error) {
+	value, _, err := e.statement.Execute(ctx, tCtx)
+	return value, err
+}
@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
+)
+
+func TestRoutingKeyFromAttributes(t *testing.T) {
+	resource := pcommon.NewMap()
+	resource.PutStr("service.name", "service-1")
+	resource.PutStr("tenant", "resource-tenant")
+	record := pcommon.NewMap()
+	record.PutStr("tenant", "record-tenant")
+	record.PutInt("shard", 3)
+
+	for _, tt := range []struct {
+		desc     string
+		names    []string
+		expected string
+		found    bool
+	}{
+		{"single", []string{"service.name"}, "service-1", true},
+		{"first map wins", []string{"tenant"}, "resource-tenant", true},
+		{"non-string value", []string{"shard"}, "3", true},
+		{"multiple", []string{"service.name", "missing", "shard"}, "service-1\x00\x003", true},
+		{"missing", []string{"missing"}, "", false},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			key, found := routingKeyFromAttributes(tt.names, resource, record)
+			assert.Equal(t, tt.expected, key)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestRoutingKeyFromValue(t *testing.T) {
+	m := pcommon.NewMap()
+	m.PutStr("a", "b")
+	s := pcommon.NewSlice()
+	s.AppendEmpty().SetStr("a")
+
+	for _, tt := range []struct {
+		desc     string
+		value    any
+		expected string
+		found    bool
+	}{
+		{"nil", nil, "", false},
+		{"empty string", "", "", false},
+		{"string", "tenant-1", "tenant-1", true},
+		{"bytes", []byte("tenant-1"), "tenant-1", true},
+		{"int", int64(42), "42", true},
+		{"value", pcommon.NewValueStr("tenant-1"), "tenant-1", true},
+		{"empty value", pcommon.NewValueEmpty(), "", false},
+		{"map", m, "map[a:b]", true},
+		{"slice", s, "[a]", true},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			key, found := routingKeyFromValue(tt.value)
+			assert.Equal(t, tt.expected, key)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestRoutingExpression(t *testing.T) {
+	parser, err := ottlresource.NewParser(routingFunctions[ottlresource.TransformContext](), componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("tenant", "tenant-1")
+	tCtx := ottlresource.NewTransformContext(resource, pmetric.NewResourceMetrics())
+
+	for _, tt := range []struct {
+		expression string
+		expected   any
+	}{
+		{`attributes["tenant"]`, "tenant-1"},
+		{`"literal"`, "literal"},
+		{`Concat([attributes["tenant"], "a"], "-")`, "tenant-1-a"},
+		{`attributes["missing"]`, nil},
+	} {
+		t.Run(tt.expression, func(t *testing.T) {
+			expression, err := newRoutingExpression(parser, tt.expression)
+			require.NoError(t, err)
+
+			value, err := expression.Eval(context.Background(), tCtx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+
+	for _, expression := range []string{`attributes["tenant"] ==`, `set(attributes["tenant"], "a")`, `Unknown()`} {
+		t.Run(expression, func(t *testing.T) {
+			_, err := newRoutingExpression(parser, expression)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/batchpersignal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
 var _ exporter.Traces = (*traceExporterImp)(nil)
@@ -28,8 +29,10 @@ var _ exporter.Traces = (*traceExporterImp)(nil)
 type exporterTraces map[*wrappedExporter]ptrace.Traces
 
 type traceExporterImp struct {
-	loadBalancer *loadBalancer
-	routingKey   routingKey
+	loadBalancer      *loadBalancer
+	routingKey        routingKey
+	routingAttributes []string
+	routingExpression *routingExpression[ottlspan.TransformContext]
 
 	logger     *zap.Logger
 	stopped    bool
@@ -67,6 +70,19 @@ func newTracesExporter(params exporter.Settings, cfg component.Config) (*traceEx
 	switch cfg.(*Config).RoutingKey {
 	case svcRoutingStr:
 		traceExporter.routingKey = svcRouting
+	case attrRoutingStr:
+		traceExporter.routingKey = attrRouting
+		traceExporter.routingAttributes = cfg.(*Config).RoutingAttributes
+	case ottlRoutingStr:
+		traceExporter.routingKey = ottlRouting
+		parser, err := ottlspan.NewParser(routingFunctions[ottlspan.TransformContext](), params.TelemetrySettings)
+		if err != nil {
+			return nil, err
+		}
+		traceExporter.routingExpression, err = newRoutingExpression(parser, cfg.(*Config).RoutingExpression)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse routing_expression: %w", err)
+		}
 	case traceIDRoutingStr, "":
 	default:
 		return nil, fmt.Errorf("unsupported routing_key: %s", cfg.(*Config).RoutingKey)
@@ -95,13 +111,13 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 	exporterSegregatedTraces := make(exporterTraces)
 	endpoints := make(map[*wrappedExporter]string)
 	for _, batch := range batches {
-		routingID, err := routingIdentifiersFromTraces(batch, e.routingKey)
+		routingID, err := e.routingIdentifiers(ctx, batch)
 		if err != nil {
 			return err
 		}
 
 		for rid := range routingID {
-			exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
+			exp, endpoint, err := e.loadBalancer.exporterAndEndpoint(ctx, []byte(rid), batch.SpanCount())
 			if err != nil {
 				return err
 			}
@@ -120,11 +136,13 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 	var errs error
 
 	for exp, td := range exporterSegregatedTraces {
+		e.telemetry.LoadbalancerBackendLoad.Add(ctx, int64(td.SpanCount()), metric.WithAttributeSet(exp.endpointAttr))
 		start := time.Now()
 		err := exp.ConsumeTraces(ctx, td)
 		exp.consumeWG.Done()
 		errs = multierr.Append(errs, err)
 		duration := time.Since(start)
+		e.loadBalancer.reportOutcome(ctx, endpoints[exp], err)
 		e.telemetry.LoadbalancerBackendLatency.Record(ctx, duration.Milliseconds(), metric.WithAttributeSet(exp.endpointAttr))
 		if err == nil {
 			e.telemetry.LoadbalancerBackendOutcome.Add(ctx, 1, metric.WithAttributeSet(exp.successAttr))
@@ -137,6 +155,61 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 	return errs
 }
 
+// routingIdentifiers returns the routing keys of the batch. With the "attributes" and "ottl" routing keys,
+// each resource is routed by the attributes or the expression evaluated on its first span, and by the
+// trace ID when they yield no value.
+func (e *traceExporterImp) routingIdentifiers(ctx context.Context, td ptrace.Traces) (map[string]bool, error) {
+	if e.routingKey != attrRouting && e.routingKey != ottlRouting {
+		return routingIdentifiersFromTraces(td, e.routingKey)
+	}
+
+	// the trace ID routing also validates that the batch holds at least one span
+	fallback, err := routingIdentifiersFromTraces(td, traceIDRouting)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool)
+	rs := td.ResourceSpans()
+	for i := 0; i < rs.Len(); i++ {
+		key, found, err := e.resourceRoutingKey(ctx, rs.At(i))
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			for tid := range fallback {
+				ids[tid] = true
+			}
+			continue
+		}
+		ids[key] = true
+	}
+	return ids, nil
+}
+
+func (e *traceExporterImp) resourceRoutingKey(ctx context.Context, rs ptrace.ResourceSpans) (string, bool, error) {
+	for j := 0; j < rs.ScopeSpans().Len(); j++ {
+		ss := rs.ScopeSpans().At(j)
+		if ss.Spans().Len() == 0 {
+			continue
+		}
+		span := ss.Spans().At(0)
+
+		if e.routingKey == attrRouting {
+			key, found := routingKeyFromAttributes(e.routingAttributes, rs.Resource().Attributes(), span.Attributes())
+			return key, found, nil
+		}
+
+		value, err := e.routingExpression.Eval(ctx, ottlspan.NewTransformContext(span, ss.Scope(), rs.Resource(), ss, rs))
+		if err != nil {
+			return "", false, fmt.Errorf("failed to evaluate routing_expression: %w", err)
+		}
+		key, found := routingKeyFromValue(value)
+		return key, found, nil
+	}
+	return "", false, nil
+}
+
 func routingIdentifiersFromTraces(td ptrace.Traces, key routingKey) (map[string]bool, error) {
 	ids := make(map[string]bool)
 	rs := td.ResourceSpans()
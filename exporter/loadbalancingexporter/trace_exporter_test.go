@@ -272,6 +272,113 @@ func TestServiceBasedRoutingForSameTraceId(t *testing.T) {
 	}
 }
 
+func TestAttributeAndExpressionRoutingForTraces(t *testing.T) {
+	b := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	for _, tt := range []struct {
+		desc  string
+		cfg   *Config
+		batch ptrace.Traces
+		res   map[string]bool
+	}{
+		{
+			"resource attribute",
+			attributeBasedRoutingConfig(conventions.AttributeServiceName),
+			twoServicesWithSameTraceID(),
+			map[string]bool{"ad-service-1": true, "get-recommendations-7": true},
+		},
+		{
+			"span attribute",
+			attributeBasedRoutingConfig("tenant"),
+			tracesWithSpanAttribute("tenant", "tenant-1"),
+			map[string]bool{"tenant-1": true},
+		},
+		{
+			"multiple attributes",
+			attributeBasedRoutingConfig(conventions.AttributeServiceName, "tenant"),
+			tracesWithSpanAttribute("tenant", "tenant-1"),
+			map[string]bool{"service-1\x00tenant-1": true},
+		},
+		{
+			"missing attribute falls back to trace id",
+			attributeBasedRoutingConfig("tenant"),
+			twoServicesWithSameTraceID(),
+			map[string]bool{string(b[:]): true},
+		},
+		{
+			"expression",
+			expressionBasedRoutingConfig(`Concat([resource.attributes["service.name"], attributes["tenant"]], "/")`),
+			tracesWithSpanAttribute("tenant", "tenant-1"),
+			map[string]bool{"service-1/tenant-1": true},
+		},
+		{
+			"nil expression falls back to trace id",
+			expressionBasedRoutingConfig(`attributes["tenant"]`),
+			twoServicesWithSameTraceID(),
+			map[string]bool{string(b[:]): true},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			p, err := newTracesExporter(exportertest.NewNopSettings(), tt.cfg)
+			require.NoError(t, err)
+
+			res, err := p.routingIdentifiers(context.Background(), tt.batch)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.res, res)
+		})
+	}
+}
+
+func TestInvalidRoutingExpressionForTraces(t *testing.T) {
+	_, err := newTracesExporter(exportertest.NewNopSettings(), expressionBasedRoutingConfig(`attributes["tenant"`))
+	assert.ErrorContains(t, err, "failed to parse routing_expression")
+}
+
+func TestConsumeTracesBoundedLoads(t *testing.T) {
+	ts, tb := getTelemetryAssets(t)
+	cfg := serviceBasedRoutingConfig()
+	cfg.ConsistentHashing.LoadFactor = 1.25
+
+	sinks := map[string]*consumertest.TracesSink{}
+	componentFactory := func(_ context.Context, endpoint string) (component.Component, error) {
+		sink := new(consumertest.TracesSink)
+		sinks[endpoint] = sink
+		return newMockTracesExporter(sink.ConsumeTraces), nil
+	}
+	lb, err := newLoadBalancer(ts.Logger, cfg, componentFactory, tb)
+	require.NoError(t, err)
+	// keep all the traces in the same load window
+	now := time.Now()
+	lb.boundedLoads.now = func() time.Time { return now }
+
+	p, err := newTracesExporter(ts, cfg)
+	require.NoError(t, err)
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// test
+	// a single service would be sent to a single endpoint without bounded loads
+	for i := 0; i < 10; i++ {
+		td := ptrace.NewTraces()
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr(conventions.AttributeServiceName, "service-1")
+		appendSimpleTraceWithID(rs, [16]byte{1, 2, 3, byte(i)})
+		require.NoError(t, p.ConsumeTraces(context.Background(), td))
+	}
+
+	// verify
+	// no endpoint receives more than ceil(1.25 * 10 / 2) spans
+	require.Len(t, sinks, 2)
+	for _, sink := range sinks {
+		assert.Positive(t, sink.SpanCount())
+		assert.LessOrEqual(t, sink.SpanCount(), 7)
+	}
+}
+
 func TestConsumeTracesExporterNoEndpoint(t *testing.T) {
 	ts, tb := getTelemetryAssets(t)
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
@@ -713,6 +820,36 @@ func serviceBasedRoutingConfig() *Config {
 	}
 }
 
+func attributeBasedRoutingConfig(attributes ...string) *Config {
+	return &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		RoutingKey:        "attributes",
+		RoutingAttributes: attributes,
+	}
+}
+
+func expressionBasedRoutingConfig(expression string) *Config {
+	return &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		RoutingKey:        "ottl",
+		RoutingExpression: expression,
+	}
+}
+
+func tracesWithSpanAttribute(key string, value string) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr(conventions.AttributeServiceName, "service-1")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID([16]byte{1, 2, 3, 4})
+	span.Attributes().PutStr(key, value)
+	return traces
+}
+
 type mockTracesExporter struct {
 	component.Component
 	ConsumeTracesFn func(ctx context.Context, td ptrace.Traces) error
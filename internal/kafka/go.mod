@@ -6,11 +6,14 @@ require (
 	github.com/IBM/sarama v1.43.3
 	github.com/aws/aws-msk-iam-sasl-signer-go v1.0.0
 	github.com/aws/aws-sdk-go v1.55.5
+	github.com/bufbuild/protocompile v0.14.1
+	github.com/linkedin/goavro/v2 v2.13.0
 	github.com/stretchr/testify v1.10.0
 	github.com/xdg-go/scram v1.1.2
 	go.opentelemetry.io/collector/config/configtls v1.22.0
 	go.uber.org/goleak v1.3.0
 	go.uber.org/multierr v1.11.0
+	google.golang.org/protobuf v1.34.2
 )
 
 require (
@@ -50,6 +53,7 @@ require (
 	go.opentelemetry.io/collector/config/configopaque v1.22.0 // indirect
 	golang.org/x/crypto v0.31.0 // indirect
 	golang.org/x/net v0.28.0 // indirect
+	golang.org/x/sync v0.10.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
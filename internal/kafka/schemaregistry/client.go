@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schemaregistry implements a client of the Confluent Schema Registry HTTP API,
+// and the serialization of records in the Confluent wire format with Avro and Protobuf schemas.
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// Schema types of the schema registry.
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOBUF"
+)
+
+// ClientConfig defines the configuration of the schema registry client.
+type ClientConfig struct {
+	// URL of the schema registry.
+	URL string `mapstructure:"url"`
+	// Username for basic authentication.
+	Username string `mapstructure:"username"`
+	// Password for basic authentication.
+	Password string `mapstructure:"password"`
+	// TLS configuration of the connection to the schema registry.
+	TLS *configtls.ClientConfig `mapstructure:"tls"`
+	// Timeout of the requests to the schema registry (default 10s).
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// Validate checks the schema registry client configuration is valid.
+func (cfg *ClientConfig) Validate() error {
+	if cfg.URL == "" {
+		return errors.New("url must be specified")
+	}
+	if _, err := url.Parse(cfg.URL); err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if cfg.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	return nil
+}
+
+// Schema is a schema of the schema registry.
+type Schema struct {
+	ID int
+	// Type is SchemaTypeAvro or SchemaTypeProtobuf.
+	Type   string
+	Schema string
+}
+
+// Client is a client of the schema registry HTTP API. Schemas are cached, as
+// registered schemas are immutable.
+type Client struct {
+	url        string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu         sync.Mutex
+	schemas    map[int]Schema
+	registered map[string]int
+}
+
+// NewClient creates a schema registry client.
+func NewClient(ctx context.Context, cfg ClientConfig) (*Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLS != nil {
+		tlsConfig, err := cfg.TLS.LoadTLSConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	return &Client{
+		url:        cfg.URL,
+		username:   cfg.Username,
+		password:   cfg.Password,
+		httpClient: &http.Client{Transport: transport, Timeout: timeout},
+		schemas:    map[int]Schema{},
+		registered: map[string]int{},
+	}, nil
+}
+
+type schemaResponse struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+type registerRequest struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+type registerResponse struct {
+	ID int `json:"id"`
+}
+
+// Schema returns the schema with the given ID.
+func (c *Client) Schema(ctx context.Context, id int) (Schema, error) {
+	c.mu.Lock()
+	schema, ok := c.schemas[id]
+	c.mu.Unlock()
+	if ok {
+		return schema, nil
+	}
+
+	var resp schemaResponse
+	if err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.Itoa(id), nil, &resp); err != nil {
+		return Schema{}, fmt.Errorf("get schema %d: %w", id, err)
+	}
+	schema = Schema{ID: id, Type: resp.SchemaType, Schema: resp.Schema}
+	if schema.Type == "" {
+		// The schema type is omitted for Avro schemas
+		schema.Type = SchemaTypeAvro
+	}
+
+	c.mu.Lock()
+	c.schemas[id] = schema
+	c.mu.Unlock()
+	return schema, nil
+}
+
+// Register registers the schema under the subject, or looks it up if it was
+// already registered, and returns its ID.
+func (c *Client) Register(ctx context.Context, subject string, schemaType string, schema string) (int, error) {
+	key := subject + "\x00" + schemaType + "\x00" + schema
+	c.mu.Lock()
+	id, ok := c.registered[key]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	req := registerRequest{Schema: schema}
+	if schemaType != SchemaTypeAvro {
+		req.SchemaType = schemaType
+	}
+	var resp registerResponse
+	if err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", req, &resp); err != nil {
+		return 0, fmt.Errorf("register schema for subject %q: %w", subject, err)
+	}
+
+	c.mu.Lock()
+	c.registered[key] = resp.ID
+	c.schemas[resp.ID] = Schema{ID: resp.ID, Type: schemaType, Schema: schema}
+	c.mu.Unlock()
+	return resp.ID, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, body any, result any) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var registryErr struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&registryErr) == nil && registryErr.Message != "" {
+			return fmt.Errorf("schema registry returned %d: %s", registryErr.ErrorCode, registryErr.Message)
+		}
+		return fmt.Errorf("schema registry returned status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+// Close closes the idle connections to the schema registry.
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
+)
+
+func newTestClient(t *testing.T, url string) *Client {
+	client, err := NewClient(context.Background(), ClientConfig{URL: url})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClientConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ClientConfig
+		err    string
+	}{
+		{name: "valid", config: ClientConfig{URL: "http://localhost:8081"}},
+		{name: "no url", config: ClientConfig{}, err: "url must be specified"},
+		{name: "invalid url", config: ClientConfig{URL: "http://local host:8081"}, err: "invalid url"},
+		{name: "negative timeout", config: ClientConfig{URL: "http://localhost:8081", Timeout: -1}, err: "timeout must not be negative"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.config.Validate()
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestClientSchema(t *testing.T) {
+	server := schemaregistrytest.NewServer(t)
+	avroID := server.Register("logs-value", "", `"string"`)
+	protobufID := server.Register("logs-value", SchemaTypeProtobuf, `syntax = "proto3"; message Log { string body = 1; }`)
+	client := newTestClient(t, server.URL)
+
+	schema, err := client.Schema(context.Background(), avroID)
+	require.NoError(t, err)
+	assert.Equal(t, Schema{ID: avroID, Type: SchemaTypeAvro, Schema: `"string"`}, schema)
+
+	schema, err = client.Schema(context.Background(), protobufID)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaTypeProtobuf, schema.Type)
+
+	_, err = client.Schema(context.Background(), 42)
+	assert.ErrorContains(t, err, "get schema 42: schema registry returned 40403: Schema not found")
+}
+
+func TestClientSchemaCached(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "/schemas/ids/1", r.URL.Path)
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "user", user)
+		assert.Equal(t, "secret", password)
+		_, _ = w.Write([]byte(`{"schema": "\"string\""}`))
+	}))
+	defer server.Close()
+	client, err := NewClient(context.Background(), ClientConfig{URL: server.URL, Username: "user", Password: "secret"})
+	require.NoError(t, err)
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		schema, err := client.Schema(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, `"string"`, schema.Schema)
+	}
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestClientRegister(t *testing.T) {
+	server := schemaregistrytest.NewServer(t)
+	client := newTestClient(t, server.URL)
+
+	id, err := client.Register(context.Background(), "logs-value", SchemaTypeAvro, `"string"`)
+	require.NoError(t, err)
+	again, err := client.Register(context.Background(), "logs-value", SchemaTypeAvro, `"string"`)
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+	assert.Equal(t, map[string]int{"logs-value": 1}, server.Subjects())
+
+	schema, err := client.Schema(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, Schema{ID: id, Type: SchemaTypeAvro, Schema: `"string"`}, schema)
+
+	_, err = client.Register(context.Background(), "logs-value", SchemaTypeAvro, "")
+	assert.ErrorContains(t, err, `register schema for subject "logs-value": schema registry returned 42201: Invalid schema`)
+}
+
+func TestClientUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := newTestClient(t, server.URL)
+
+	_, err := client.Schema(context.Background(), 1)
+	assert.ErrorContains(t, err, "schema registry returned status 503")
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/linkedin/goavro/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// avroCodec converts between Avro binary records and their JSON representation,
+// where unions are not wrapped in an object naming their type.
+type avroCodec struct {
+	codec *goavro.Codec
+	name  string
+}
+
+func newAvroCodec(schema string) (*avroCodec, error) {
+	codec, err := goavro.NewCodecForStandardJSONFull(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro codec: %w", err)
+	}
+	var named struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	}
+	name := ""
+	if json.Unmarshal([]byte(schema), &named) == nil {
+		name = named.Name
+		if named.Namespace != "" {
+			name = named.Namespace + "." + named.Name
+		}
+	}
+	return &avroCodec{codec: codec, name: name}, nil
+}
+
+func (c *avroCodec) decode(payload []byte) (map[string]any, error) {
+	native, _, err := c.codec.NativeFromBinary(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize avro record: %w", err)
+	}
+	textual, err := c.codec.TextualFromNative(nil, native)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize avro record: %w", err)
+	}
+	return unmarshalJSONRecord(textual)
+}
+
+func (c *avroCodec) encode(record map[string]any) ([]byte, error) {
+	textual, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	native, _, err := c.codec.NativeFromTextual(textual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize avro record: %w", err)
+	}
+	return c.codec.BinaryFromNative(nil, native)
+}
+
+const protobufSchemaFile = "schema.proto"
+
+// protobufCodec converts between Protobuf messages of a schema and their JSON representation.
+type protobufCodec struct {
+	file linker.File
+}
+
+func newProtobufCodec(schema string) (*protobufCodec, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{protobufSchemaFile: schema}),
+		}),
+	}
+	files, err := compiler.Compile(context.Background(), protobufSchemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile protobuf schema: %w", err)
+	}
+	return &protobufCodec{file: files[0]}, nil
+}
+
+// message returns the message at the message indexes of the wire format, which
+// are the indexes of the message and of the messages it is nested in.
+func (c *protobufCodec) message(indexes []int) (protoreflect.MessageDescriptor, error) {
+	if len(indexes) == 0 {
+		return nil, errors.New("no message indexes")
+	}
+	messages := c.file.Messages()
+	var md protoreflect.MessageDescriptor
+	for _, i := range indexes {
+		if i < 0 || i >= messages.Len() {
+			return nil, fmt.Errorf("message index %d out of range", i)
+		}
+		md = messages.Get(i)
+		messages = md.Messages()
+	}
+	return md, nil
+}
+
+// messageIndexes returns the message indexes of the message with the given name, or of
+// the first message if name is empty.
+func (c *protobufCodec) messageIndexes(name string) ([]int, protoreflect.MessageDescriptor, error) {
+	if name == "" {
+		if c.file.Messages().Len() == 0 {
+			return nil, nil, errors.New("protobuf schema has no messages")
+		}
+		return []int{0}, c.file.Messages().Get(0), nil
+	}
+	desc := c.file.FindDescriptorByName(protoreflect.FullName(name))
+	if desc == nil && c.file.Package() != "" {
+		desc = c.file.FindDescriptorByName(c.file.Package().Append(protoreflect.Name(name)))
+	}
+	md, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, nil, fmt.Errorf("message %q not found in protobuf schema", name)
+	}
+	var indexes []int
+	for d := protoreflect.Descriptor(md); d != nil && d != protoreflect.Descriptor(c.file); d = d.Parent() {
+		indexes = append([]int{d.Index()}, indexes...)
+	}
+	return indexes, md, nil
+}
+
+func (c *protobufCodec) decode(md protoreflect.MessageDescriptor, payload []byte) (map[string]any, error) {
+	msg := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(payload, msg); err != nil {
+		return nil, fmt.Errorf("failed to deserialize protobuf message: %w", err)
+	}
+	textual, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize protobuf message: %w", err)
+	}
+	return unmarshalJSONRecord(textual)
+}
+
+func (c *protobufCodec) encode(md protoreflect.MessageDescriptor, record map[string]any) ([]byte, error) {
+	textual, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	msg := dynamicpb.NewMessage(md)
+	if err := protojson.Unmarshal(textual, msg); err != nil {
+		return nil, fmt.Errorf("failed to serialize protobuf message: %w", err)
+	}
+	return proto.Marshal(msg)
+}
+
+// unmarshalJSONRecord unmarshals a JSON object, keeping integers as int64.
+func unmarshalJSONRecord(data []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var record map[string]any
+	if err := dec.Decode(&record); err != nil {
+		return nil, err
+	}
+	return convertNumbers(record).(map[string]any), nil
+}
+
+func convertNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, item := range v {
+			v[key] = convertNumbers(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = convertNumbers(item)
+		}
+	}
+	return value
+}
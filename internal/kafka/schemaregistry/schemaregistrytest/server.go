@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schemaregistrytest implements an in-memory schema registry for tests.
+package schemaregistrytest // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type schema struct {
+	Schema     string `json:"schema"`
+	SchemaType string `json:"schemaType,omitempty"`
+}
+
+// Server is an in-memory schema registry serving the parts of the schema registry
+// HTTP API used to look up and register schemas.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	schemas  []schema
+	subjects map[string][]int
+}
+
+// NewServer starts a schema registry, which is closed when the test finishes.
+func NewServer(tb testing.TB) *Server {
+	s := &Server{subjects: map[string][]int{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /schemas/ids/{id}", s.getSchema)
+	mux.HandleFunc("POST /subjects/{subject}/versions", s.registerSchema)
+	s.Server = httptest.NewServer(mux)
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// Register registers the schema under the subject and returns its ID. The schema type
+// is empty for Avro schemas.
+func (s *Server) Register(subject string, schemaType string, schemaText string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc := schema{Schema: schemaText, SchemaType: schemaType}
+	for _, id := range s.subjects[subject] {
+		if s.schemas[id-1] == sc {
+			return id
+		}
+	}
+	s.schemas = append(s.schemas, sc)
+	id := len(s.schemas)
+	s.subjects[subject] = append(s.subjects[subject], id)
+	return id
+}
+
+// Subjects returns the number of schemas registered under each subject.
+func (s *Server) Subjects() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	subjects := map[string]int{}
+	for subject, ids := range s.subjects {
+		subjects[subject] = len(ids)
+	}
+	return subjects
+}
+
+func (s *Server) getSchema(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	s.mu.Lock()
+	found := err == nil && id > 0 && id <= len(s.schemas)
+	var sc schema
+	if found {
+		sc = s.schemas[id-1]
+	}
+	s.mu.Unlock()
+	if !found {
+		writeError(w, http.StatusNotFound, 40403, "Schema not found")
+		return
+	}
+	writeJSON(w, sc)
+}
+
+func (s *Server) registerSchema(w http.ResponseWriter, r *http.Request) {
+	var sc schema
+	if err := json.NewDecoder(r.Body).Decode(&sc); err != nil || strings.TrimSpace(sc.Schema) == "" {
+		writeError(w, http.StatusUnprocessableEntity, 42201, "Invalid schema")
+		return
+	}
+	id := s.Register(r.PathValue("subject"), sc.SchemaType, sc.Schema)
+	writeJSON(w, map[string]int{"id": id})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code int, message string) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"error_code": code, "message": message})
+}
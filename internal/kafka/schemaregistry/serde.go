@@ -0,0 +1,203 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The Confluent wire format starts with a zero magic byte and the 4-byte big-endian
+// schema ID. Protobuf records then hold the indexes of their message in the schema.
+const (
+	magicByte  = 0
+	headerSize = 5
+)
+
+var errNotFramed = errors.New("record is not in the schema registry wire format")
+
+// Record is a record deserialized with its schema.
+type Record struct {
+	Schema Schema
+	// TypeName is the full name of the Avro record or of the Protobuf message.
+	TypeName string
+	Value    map[string]any
+}
+
+// Deserializer deserializes records in the Confluent wire format, with the schemas
+// fetched from the schema registry.
+type Deserializer struct {
+	client *Client
+
+	mu     sync.Mutex
+	codecs map[int]any
+}
+
+// NewDeserializer creates a deserializer fetching schemas with the client.
+func NewDeserializer(client *Client) *Deserializer {
+	return &Deserializer{client: client, codecs: map[int]any{}}
+}
+
+// Deserialize deserializes a record in the Confluent wire format.
+func (d *Deserializer) Deserialize(ctx context.Context, data []byte) (Record, error) {
+	if len(data) < headerSize || data[0] != magicByte {
+		return Record{}, errNotFramed
+	}
+	id := int(binary.BigEndian.Uint32(data[1:headerSize]))
+	payload := data[headerSize:]
+
+	schema, err := d.client.Schema(ctx, id)
+	if err != nil {
+		return Record{}, err
+	}
+	codec, err := d.codec(schema)
+	if err != nil {
+		return Record{}, err
+	}
+
+	record := Record{Schema: schema}
+	switch c := codec.(type) {
+	case *avroCodec:
+		record.TypeName = c.name
+		record.Value, err = c.decode(payload)
+	case *protobufCodec:
+		var indexes []int
+		if indexes, payload, err = readMessageIndexes(payload); err != nil {
+			return Record{}, err
+		}
+		var md protoreflect.MessageDescriptor
+		if md, err = c.message(indexes); err != nil {
+			return Record{}, err
+		}
+		record.TypeName = string(md.FullName())
+		record.Value, err = c.decode(md, payload)
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	return record, nil
+}
+
+func (d *Deserializer) codec(schema Schema) (any, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if codec, ok := d.codecs[schema.ID]; ok {
+		return codec, nil
+	}
+
+	codec, err := newCodec(schema)
+	if err != nil {
+		return nil, fmt.Errorf("schema %d: %w", schema.ID, err)
+	}
+	d.codecs[schema.ID] = codec
+	return codec, nil
+}
+
+func newCodec(schema Schema) (any, error) {
+	switch schema.Type {
+	case SchemaTypeAvro:
+		return newAvroCodec(schema.Schema)
+	case SchemaTypeProtobuf:
+		return newProtobufCodec(schema.Schema)
+	default:
+		return nil, fmt.Errorf("unsupported schema type %q", schema.Type)
+	}
+}
+
+// Serializer serializes records in the Confluent wire format, with a schema registered
+// in the schema registry.
+type Serializer struct {
+	client     *Client
+	schemaType string
+	schema     string
+
+	avro     *avroCodec
+	protobuf *protobufCodec
+	message  protoreflect.MessageDescriptor
+	indexes  []int
+}
+
+// NewSerializer creates a serializer of records with the schema. For Protobuf schemas,
+// messageName is the message of the records, and defaults to the first message of the schema.
+func NewSerializer(client *Client, schemaType string, schema string, messageName string) (*Serializer, error) {
+	s := &Serializer{client: client, schemaType: schemaType, schema: schema}
+	var err error
+	switch schemaType {
+	case SchemaTypeAvro:
+		s.avro, err = newAvroCodec(schema)
+	case SchemaTypeProtobuf:
+		if s.protobuf, err = newProtobufCodec(schema); err == nil {
+			s.indexes, s.message, err = s.protobuf.messageIndexes(messageName)
+		}
+	default:
+		err = fmt.Errorf("unsupported schema type %q", schemaType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Serialize serializes the record. The schema is registered under the subject on first use.
+func (s *Serializer) Serialize(ctx context.Context, subject string, record map[string]any) ([]byte, error) {
+	id, err := s.client.Register(ctx, subject, s.schemaType, s.schema)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, headerSize, 64)
+	data[0] = magicByte
+	binary.BigEndian.PutUint32(data[1:headerSize], uint32(id))
+
+	var payload []byte
+	if s.avro != nil {
+		payload, err = s.avro.encode(record)
+	} else {
+		data = appendMessageIndexes(data, s.indexes)
+		payload, err = s.protobuf.encode(s.message, record)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(data, payload...), nil
+}
+
+// readMessageIndexes reads the message indexes of a Protobuf record. They are written
+// as zigzag varints prefixed by their count, and a single 0 stands for the first message.
+func readMessageIndexes(data []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(data)
+	if n <= 0 || count < 0 || count > int64(len(data)) {
+		return nil, nil, errors.New("invalid protobuf message indexes")
+	}
+	data = data[n:]
+	if count == 0 {
+		return []int{0}, data, nil
+	}
+	indexes := make([]int, 0, count)
+	for i := int64(0); i < count; i++ {
+		index, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, nil, errors.New("invalid protobuf message indexes")
+		}
+		indexes = append(indexes, int(index))
+		data = data[n:]
+	}
+	return indexes, data, nil
+}
+
+func appendMessageIndexes(data []byte, indexes []int) []byte {
+	if len(indexes) == 1 && indexes[0] == 0 {
+		return append(data, 0)
+	}
+	data = binary.AppendVarint(data, int64(len(indexes)))
+	for _, index := range indexes {
+		data = binary.AppendVarint(data, int64(index))
+	}
+	return data
+}
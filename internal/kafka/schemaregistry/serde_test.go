@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
+)
+
+const (
+	testAvroSchema = `{
+	"type": "record",
+	"name": "Log",
+	"namespace": "com.example",
+	"fields": [
+		{"name": "message", "type": "string"},
+		{"name": "level", "type": "int"},
+		{"name": "duration", "type": "double"},
+		{"name": "user", "type": ["null", "string"], "default": null},
+		{"name": "tags", "type": {"type": "array", "items": "string"}}
+	]
+}`
+	testProtobufSchema = `syntax = "proto3";
+package example;
+
+message Metric {
+	string name = 1;
+}
+
+message Event {
+	message Detail {
+		string key = 1;
+		int64 count = 2;
+	}
+	string message = 1;
+	int32 level = 2;
+	repeated Detail details = 3;
+}`
+)
+
+func TestSerdeAvro(t *testing.T) {
+	server := schemaregistrytest.NewServer(t)
+	client := newTestClient(t, server.URL)
+
+	serializer, err := NewSerializer(client, SchemaTypeAvro, testAvroSchema, "")
+	require.NoError(t, err)
+	value := map[string]any{
+		"message":  "user logged in",
+		"level":    int64(9),
+		"duration": 1.5,
+		"user":     "alice",
+		"tags":     []any{"auth", "web"},
+	}
+	data, err := serializer.Serialize(context.Background(), "logs-value", value)
+	require.NoError(t, err)
+	assert.Equal(t, byte(magicByte), data[0])
+	id := int(binary.BigEndian.Uint32(data[1:headerSize]))
+
+	record, err := NewDeserializer(newTestClient(t, server.URL)).Deserialize(context.Background(), data)
+	require.NoError(t, err)
+	assert.Equal(t, Schema{ID: id, Type: SchemaTypeAvro, Schema: testAvroSchema}, record.Schema)
+	assert.Equal(t, "com.example.Log", record.TypeName)
+	assert.Equal(t, value, record.Value)
+}
+
+func TestSerdeProtobuf(t *testing.T) {
+	server := schemaregistrytest.NewServer(t)
+	client := newTestClient(t, server.URL)
+
+	tests := []struct {
+		name        string
+		messageName string
+		typeName    string
+		indexes     []byte
+		value       map[string]any
+	}{
+		{
+			name:     "first message",
+			typeName: "example.Metric",
+			indexes:  []byte{0},
+			value:    map[string]any{"name": "requests"},
+		},
+		{
+			name:        "message",
+			messageName: "Event",
+			typeName:    "example.Event",
+			indexes:     []byte{2, 2},
+			value: map[string]any{
+				"message": "user logged in",
+				"level":   int64(9),
+				// int64 fields are strings in the JSON mapping of Protobuf
+				"details": []any{map[string]any{"key": "attempts", "count": "3"}},
+			},
+		},
+		{
+			name:        "nested message",
+			messageName: "example.Event.Detail",
+			typeName:    "example.Event.Detail",
+			indexes:     []byte{4, 2, 0},
+			value:       map[string]any{"key": "attempts", "count": "3"},
+		},
+	}
+	deserializer := NewDeserializer(newTestClient(t, server.URL))
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			serializer, err := NewSerializer(client, SchemaTypeProtobuf, testProtobufSchema, test.messageName)
+			require.NoError(t, err)
+			data, err := serializer.Serialize(context.Background(), "events-value", test.value)
+			require.NoError(t, err)
+			assert.Equal(t, test.indexes, data[headerSize:headerSize+len(test.indexes)])
+
+			record, err := deserializer.Deserialize(context.Background(), data)
+			require.NoError(t, err)
+			assert.Equal(t, SchemaTypeProtobuf, record.Schema.Type)
+			assert.Equal(t, test.typeName, record.TypeName)
+			assert.Equal(t, test.value, record.Value)
+		})
+	}
+	assert.Equal(t, map[string]int{"events-value": 1}, server.Subjects())
+}
+
+func TestNewSerializerErrors(t *testing.T) {
+	client := newTestClient(t, "http://localhost:8081")
+
+	_, err := NewSerializer(client, SchemaTypeAvro, `{"type": "unknown"}`, "")
+	assert.ErrorContains(t, err, "failed to create avro codec")
+	_, err = NewSerializer(client, SchemaTypeProtobuf, `syntax = "proto3"; message {`, "")
+	assert.ErrorContains(t, err, "failed to compile protobuf schema")
+	_, err = NewSerializer(client, SchemaTypeProtobuf, testProtobufSchema, "Unknown")
+	assert.ErrorContains(t, err, `message "Unknown" not found in protobuf schema`)
+	_, err = NewSerializer(client, "JSON", "{}", "")
+	assert.ErrorContains(t, err, `unsupported schema type "JSON"`)
+}
+
+func TestDeserializeErrors(t *testing.T) {
+	server := schemaregistrytest.NewServer(t)
+	avroID := server.Register("logs-value", "", testAvroSchema)
+	protobufID := server.Register("events-value", SchemaTypeProtobuf, testProtobufSchema)
+	deserializer := NewDeserializer(newTestClient(t, server.URL))
+
+	frame := func(id int, payload ...byte) []byte {
+		data := binary.BigEndian.AppendUint32([]byte{magicByte}, uint32(id))
+		return append(data, payload...)
+	}
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{name: "not framed", data: []byte(`{"message": "hello"}`), err: errNotFramed.Error()},
+		{name: "too short", data: []byte{magicByte, 0, 0}, err: errNotFramed.Error()},
+		{name: "unknown schema", data: frame(42), err: "get schema 42"},
+		{name: "invalid avro", data: frame(avroID, 0xff), err: "failed to deserialize avro record"},
+		{name: "invalid message indexes", data: frame(protobufID, 0x80), err: "invalid protobuf message indexes"},
+		{name: "message index out of range", data: frame(protobufID, 2, 6), err: "message index 3 out of range"},
+		{name: "invalid protobuf", data: frame(protobufID, 0, 0xff), err: "failed to deserialize protobuf message"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := deserializer.Deserialize(context.Background(), test.data)
+			assert.ErrorContains(t, err, test.err)
+		})
+	}
+}
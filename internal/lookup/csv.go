@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// CSVConfig is the configuration of a table loaded from a CSV file.
+// The first line of the file holds the column names.
+type CSVConfig struct {
+	FileConfig `mapstructure:",squash"`
+	// KeyColumn is the name of the column holding the key of each row.
+	KeyColumn string `mapstructure:"key_column"`
+	// Delimiter is the field delimiter. Defaults to a comma.
+	Delimiter string `mapstructure:"delimiter"`
+}
+
+func (c CSVConfig) Validate() error {
+	if err := c.FileConfig.Validate(); err != nil {
+		return err
+	}
+	if c.KeyColumn == "" {
+		return errors.New("'key_column' cannot be empty")
+	}
+	if c.Delimiter != "" && utf8.RuneCountInString(c.Delimiter) != 1 {
+		return errors.New("'delimiter' must be a single character")
+	}
+	return nil
+}
+
+// NewCSV returns a source reading the rows of a CSV file.
+func NewCSV(cfg CSVConfig, logger *zap.Logger) Source {
+	return newFileSource(cfg.FileConfig, cfg.parse, logger)
+}
+
+func (c CSVConfig) parse(r io.Reader) (map[string]map[string]any, error) {
+	reader := csv.NewReader(r)
+	if c.Delimiter != "" {
+		reader.Comma, _ = utf8.DecodeRuneInString(c.Delimiter)
+	}
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing header line")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, len(header))
+	copy(columns, header)
+	keyIndex := -1
+	for i, column := range columns {
+		if column == c.KeyColumn {
+			keyIndex = i
+			break
+		}
+	}
+	if keyIndex < 0 {
+		return nil, fmt.Errorf("key column %q not found in header", c.KeyColumn)
+	}
+
+	rows := map[string]map[string]any{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			row[column] = record[i]
+		}
+		// Later rows override earlier rows with the same key.
+		rows[record[keyIndex]] = row
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FileConfig is the configuration shared by the sources loading a local file.
+type FileConfig struct {
+	// Path is the path of the file to load.
+	Path string `mapstructure:"path"`
+	// ReloadInterval is the interval at which the file is checked for changes and reloaded.
+	// The file is only loaded once when set to 0.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
+
+func (c FileConfig) Validate() error {
+	if c.Path == "" {
+		return errors.New("'path' cannot be empty")
+	}
+	if c.ReloadInterval < 0 {
+		return errors.New("'reload_interval' cannot be negative")
+	}
+	return nil
+}
+
+// parseFunc reads the content of a file into rows indexed by key.
+type parseFunc func(r io.Reader) (map[string]map[string]any, error)
+
+// fileSource holds the content of a file in memory and reloads it when the file changes.
+type fileSource struct {
+	cfg    FileConfig
+	parse  parseFunc
+	logger *zap.Logger
+
+	mu      sync.RWMutex
+	rows    map[string]map[string]any
+	modTime time.Time
+	size    int64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newFileSource(cfg FileConfig, parse parseFunc, logger *zap.Logger) *fileSource {
+	return &fileSource{
+		cfg:    cfg,
+		parse:  parse,
+		logger: logger,
+	}
+}
+
+func (s *fileSource) Start(_ context.Context) error {
+	if err := s.load(); err != nil {
+		return err
+	}
+	if s.cfg.ReloadInterval == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.cfg.ReloadInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.reloadIfChanged(); err != nil {
+					s.logger.Warn("Failed to reload lookup file, keeping the previous content", zap.String("path", s.cfg.Path), zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (s *fileSource) Lookup(_ context.Context, key string) (map[string]any, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	row, ok := s.rows[key]
+	return row, ok, nil
+}
+
+func (s *fileSource) Shutdown(_ context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	return nil
+}
+
+// reloadIfChanged reloads the file if its modification time or size changed since it was last loaded.
+func (s *fileSource) reloadIfChanged() error {
+	info, err := os.Stat(s.cfg.Path)
+	if err != nil {
+		return err
+	}
+	s.mu.RLock()
+	unchanged := info.ModTime().Equal(s.modTime) && info.Size() == s.size
+	s.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+	if err = s.load(); err != nil {
+		return err
+	}
+	s.logger.Debug("Reloaded lookup file", zap.String("path", s.cfg.Path))
+	return nil
+}
+
+func (s *fileSource) load() error {
+	f, err := os.Open(s.cfg.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	rows, err := s.parse(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", s.cfg.Path, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows = rows
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+	return nil
+}
@@ -0,0 +1,243 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCSVSource(t *testing.T) {
+	s := NewCSV(CSVConfig{
+		FileConfig: FileConfig{Path: filepath.Join("testdata", "teams.csv")},
+		KeyColumn:  "service",
+	}, zap.NewNop())
+	require.NoError(t, s.Start(context.Background()))
+	defer func() { require.NoError(t, s.Shutdown(context.Background())) }()
+
+	row, found, err := s.Lookup(context.Background(), "checkout")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, map[string]any{"service": "checkout", "team": "payments", "owner": "alice"}, row)
+
+	_, found, err = s.Lookup(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestCSVParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      CSVConfig
+		content  string
+		expected map[string]map[string]any
+		err      string
+	}{
+		{
+			name:    "custom delimiter",
+			cfg:     CSVConfig{KeyColumn: "id", Delimiter: ";"},
+			content: "id;name\n1;one\n2;two\n",
+			expected: map[string]map[string]any{
+				"1": {"id": "1", "name": "one"},
+				"2": {"id": "2", "name": "two"},
+			},
+		},
+		{
+			name:    "duplicate keys",
+			cfg:     CSVConfig{KeyColumn: "id"},
+			content: "id,name\n1,one\n1,uno\n",
+			expected: map[string]map[string]any{
+				"1": {"id": "1", "name": "uno"},
+			},
+		},
+		{
+			name:     "header only",
+			cfg:      CSVConfig{KeyColumn: "id"},
+			content:  "id,name\n",
+			expected: map[string]map[string]any{},
+		},
+		{
+			name:    "empty file",
+			cfg:     CSVConfig{KeyColumn: "id"},
+			content: "",
+			err:     "missing header line",
+		},
+		{
+			name:    "missing key column",
+			cfg:     CSVConfig{KeyColumn: "key"},
+			content: "id,name\n1,one\n",
+			err:     `key column "key" not found in header`,
+		},
+		{
+			name:    "wrong number of fields",
+			cfg:     CSVConfig{KeyColumn: "id"},
+			content: "id,name\n1,one,extra\n",
+			err:     "wrong number of fields",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := tt.cfg.parse(strings.NewReader(tt.content))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rows)
+		})
+	}
+}
+
+func TestCSVConfigValidate(t *testing.T) {
+	assert.EqualError(t, CSVConfig{KeyColumn: "id"}.Validate(), "'path' cannot be empty")
+	assert.EqualError(t, CSVConfig{FileConfig: FileConfig{Path: "a.csv"}}.Validate(), "'key_column' cannot be empty")
+	assert.EqualError(t, CSVConfig{FileConfig: FileConfig{Path: "a.csv"}, KeyColumn: "id", Delimiter: ";;"}.Validate(), "'delimiter' must be a single character")
+	assert.EqualError(t, CSVConfig{FileConfig: FileConfig{Path: "a.csv", ReloadInterval: -time.Second}, KeyColumn: "id"}.Validate(), "'reload_interval' cannot be negative")
+	assert.NoError(t, CSVConfig{FileConfig: FileConfig{Path: "a.csv"}, KeyColumn: "id", Delimiter: "\t"}.Validate())
+}
+
+func TestJSONSource(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  JSONConfig
+	}{
+		{
+			name: "object",
+			cfg:  JSONConfig{FileConfig: FileConfig{Path: filepath.Join("testdata", "teams.json")}},
+		},
+		{
+			name: "array",
+			cfg:  JSONConfig{FileConfig: FileConfig{Path: filepath.Join("testdata", "teams_array.json")}, KeyField: "service"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewJSON(tt.cfg, zap.NewNop())
+			require.NoError(t, s.Start(context.Background()))
+			defer func() { require.NoError(t, s.Shutdown(context.Background())) }()
+
+			row, found, err := s.Lookup(context.Background(), "checkout")
+			require.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, "payments", row["team"])
+			assert.Equal(t, int64(1), row["tier"])
+
+			_, found, err = s.Lookup(context.Background(), "unknown")
+			require.NoError(t, err)
+			assert.False(t, found)
+		})
+	}
+}
+
+func TestJSONParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      JSONConfig
+		content  string
+		expected map[string]map[string]any
+		err      string
+	}{
+		{
+			name:    "nested values",
+			content: `{"a": {"n": 1.5, "list": [1, "x"], "map": {"b": true}, "null": null}}`,
+			expected: map[string]map[string]any{
+				"a": {"n": 1.5, "list": []any{int64(1), "x"}, "map": map[string]any{"b": true}, "null": nil},
+			},
+		},
+		{
+			name:    "numeric keys in array",
+			cfg:     JSONConfig{KeyField: "id"},
+			content: `[{"id": 1, "name": "one"}]`,
+			expected: map[string]map[string]any{
+				"1": {"id": int64(1), "name": "one"},
+			},
+		},
+		{
+			name:    "row is not an object",
+			content: `{"a": "b"}`,
+			err:     `row "a" is not an object`,
+		},
+		{
+			name:    "array without key field",
+			content: `[{"id": 1}]`,
+			err:     "'key_field' must be set when the file holds an array",
+		},
+		{
+			name:    "array row without key",
+			cfg:     JSONConfig{KeyField: "id"},
+			content: `[{"name": "one"}]`,
+			err:     `row 0 has no "id" field`,
+		},
+		{
+			name:    "scalar",
+			content: `"value"`,
+			err:     "expected an object or an array, got string",
+		},
+		{
+			name:    "invalid",
+			content: `{`,
+			err:     "unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := tt.cfg.parse(strings.NewReader(tt.content))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rows)
+		})
+	}
+}
+
+func TestFileSourceReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "teams.csv")
+	require.NoError(t, os.WriteFile(path, []byte("service,team\ncheckout,payments\n"), 0o600))
+
+	s := NewCSV(CSVConfig{
+		FileConfig: FileConfig{Path: path, ReloadInterval: 10 * time.Millisecond},
+		KeyColumn:  "service",
+	}, zap.NewNop())
+	require.NoError(t, s.Start(context.Background()))
+	defer func() { require.NoError(t, s.Shutdown(context.Background())) }()
+
+	row, found, err := s.Lookup(context.Background(), "checkout")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "payments", row["team"])
+
+	// An invalid file keeps the previous content.
+	require.NoError(t, os.WriteFile(path, []byte("id,team\ncheckout,broken\n"), 0o600))
+	require.Never(t, func() bool {
+		row, _, _ = s.Lookup(context.Background(), "checkout")
+		return row["team"] != "payments"
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte("service,team\ncheckout,billing\ncart,storefront\n"), 0o600))
+	require.Eventually(t, func() bool {
+		row, found, err = s.Lookup(context.Background(), "cart")
+		return err == nil && found
+	}, 5*time.Second, 10*time.Millisecond)
+	row, _, _ = s.Lookup(context.Background(), "checkout")
+	assert.Equal(t, "billing", row["team"])
+}
+
+func TestFileSourceStartError(t *testing.T) {
+	s := NewCSV(CSVConfig{
+		FileConfig: FileConfig{Path: filepath.Join("testdata", "missing.csv"), ReloadInterval: time.Second},
+		KeyColumn:  "service",
+	}, zap.NewNop())
+	assert.Error(t, s.Start(context.Background()))
+	assert.NoError(t, s.Shutdown(context.Background()))
+}
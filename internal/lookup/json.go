@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"go.uber.org/zap"
+)
+
+// JSONConfig is the configuration of a table loaded from a JSON file.
+// The file holds either an object whose keys are the row keys and whose values are the rows,
+// or an array of rows, in which case KeyField must be set.
+type JSONConfig struct {
+	FileConfig `mapstructure:",squash"`
+	// KeyField is the field holding the key of each row when the file holds an array of rows.
+	KeyField string `mapstructure:"key_field"`
+}
+
+func (c JSONConfig) Validate() error {
+	return c.FileConfig.Validate()
+}
+
+// NewJSON returns a source reading the rows of a JSON file.
+func NewJSON(cfg JSONConfig, logger *zap.Logger) Source {
+	return newFileSource(cfg.FileConfig, cfg.parse, logger)
+}
+
+func (c JSONConfig) parse(r io.Reader) (map[string]map[string]any, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var content any
+	if err := decoder.Decode(&content); err != nil {
+		return nil, err
+	}
+
+	rows := map[string]map[string]any{}
+	switch v := normalizeJSON(content).(type) {
+	case map[string]any:
+		for key, value := range v {
+			row, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("row %q is not an object", key)
+			}
+			rows[key] = row
+		}
+	case []any:
+		if c.KeyField == "" {
+			return nil, fmt.Errorf("'key_field' must be set when the file holds an array")
+		}
+		for i, value := range v {
+			row, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("row %d is not an object", i)
+			}
+			key, ok := row[c.KeyField]
+			if !ok || key == nil {
+				return nil, fmt.Errorf("row %d has no %q field", i, c.KeyField)
+			}
+			// Later rows override earlier rows with the same key.
+			rows[fmt.Sprint(key)] = row
+		}
+	default:
+		return nil, fmt.Errorf("expected an object or an array, got %T", v)
+	}
+	return rows, nil
+}
+
+// normalizeJSON converts the numbers decoded as json.Number into int64 or float64 values,
+// so that rows can be converted into pdata values.
+func normalizeJSON(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalizeJSON(item)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeJSON(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
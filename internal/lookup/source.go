@@ -0,0 +1,20 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lookup implements the lookup tables queried by the lookup and transform processors,
+// and the sources they are loaded from.
+package lookup // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+
+import (
+	"context"
+)
+
+// Source provides the rows of a lookup table, indexed by key.
+type Source interface {
+	// Start loads the source. It is called once, before any lookup.
+	Start(ctx context.Context) error
+	// Lookup returns the row associated with the key, and false if there is no such row.
+	Lookup(ctx context.Context, key string) (map[string]any, bool, error)
+	// Shutdown releases the resources held by the source.
+	Shutdown(ctx context.Context) error
+}
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sqlquery"
+)
+
+const (
+	defaultSQLTimeout   = 5 * time.Second
+	defaultCacheMaxSize = 1000
+)
+
+// SQLConfig is the configuration of a table queried from a SQL database.
+type SQLConfig struct {
+	// Driver is the name of the database driver, one of the drivers supported by the sqlquery receiver.
+	Driver string `mapstructure:"driver"`
+	// DataSource is the driver specific connection string.
+	DataSource string `mapstructure:"datasource"`
+	// Query is the query returning the row of a key. The key is passed as its only parameter,
+	// and only the first returned row is used.
+	Query string `mapstructure:"query"`
+	// Timeout is the maximum duration of a query. Defaults to 5s.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Cache configures the LRU cache holding the results of the queries.
+	Cache CacheConfig `mapstructure:"cache"`
+	// Telemetry configures the logs emitted by the sqlquery client.
+	Telemetry sqlquery.TelemetryConfig `mapstructure:"telemetry"`
+}
+
+// CacheConfig configures the LRU cache of a SQL table.
+type CacheConfig struct {
+	// MaxSize is the maximum number of keys kept in the cache. Defaults to 1000.
+	MaxSize int `mapstructure:"max_size"`
+	// TTL is the duration after which a cached key is queried again. Keys do not expire when set to 0.
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+func (c SQLConfig) Validate() error {
+	if c.Driver == "" {
+		return errors.New("'driver' cannot be empty")
+	}
+	if c.DataSource == "" {
+		return errors.New("'datasource' cannot be empty")
+	}
+	if c.Query == "" {
+		return errors.New("'query' cannot be empty")
+	}
+	if c.Timeout < 0 {
+		return errors.New("'timeout' cannot be negative")
+	}
+	if c.Cache.MaxSize < 0 {
+		return errors.New("'cache::max_size' cannot be negative")
+	}
+	if c.Cache.TTL < 0 {
+		return errors.New("'cache::ttl' cannot be negative")
+	}
+	return nil
+}
+
+type sqlRow struct {
+	row       map[string]any
+	found     bool
+	expiresAt time.Time
+}
+
+// sqlSource queries the row of each key from a database, caching the results.
+// Keys without rows are cached as well, to avoid querying them again.
+type sqlSource struct {
+	cfg                SQLConfig
+	logger             *zap.Logger
+	sqlOpenerFunc      sqlquery.SQLOpenerFunc
+	clientProviderFunc sqlquery.ClientProviderFunc
+
+	db     *sql.DB
+	client sqlquery.DbClient
+	cache  *lru.Cache[string, sqlRow]
+}
+
+// NewSQL returns a source querying a SQL database.
+func NewSQL(cfg SQLConfig, logger *zap.Logger) Source {
+	return newSQLSource(cfg, logger, sql.Open, sqlquery.NewDbClient)
+}
+
+func newSQLSource(cfg SQLConfig, logger *zap.Logger, sqlOpenerFunc sqlquery.SQLOpenerFunc, clientProviderFunc sqlquery.ClientProviderFunc) *sqlSource {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = defaultSQLTimeout
+	}
+	if cfg.Cache.MaxSize == 0 {
+		cfg.Cache.MaxSize = defaultCacheMaxSize
+	}
+	return &sqlSource{
+		cfg:                cfg,
+		logger:             logger,
+		sqlOpenerFunc:      sqlOpenerFunc,
+		clientProviderFunc: clientProviderFunc,
+	}
+}
+
+func (s *sqlSource) Start(_ context.Context) error {
+	db, err := s.sqlOpenerFunc(s.cfg.Driver, s.cfg.DataSource)
+	if err != nil {
+		return fmt.Errorf("failed to open database connection: %w", err)
+	}
+	s.db = db
+	s.client = s.clientProviderFunc(sqlquery.DbWrapper{Db: db}, s.cfg.Query, s.logger, s.cfg.Telemetry)
+	s.cache, err = lru.New[string, sqlRow](s.cfg.Cache.MaxSize)
+	return err
+}
+
+func (s *sqlSource) Lookup(ctx context.Context, key string) (map[string]any, bool, error) {
+	if cached, ok := s.cache.Get(key); ok && (cached.expiresAt.IsZero() || time.Now().Before(cached.expiresAt)) {
+		return cached.row, cached.found, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	rows, err := s.client.QueryRows(ctx, key)
+	if err != nil {
+		if !errors.Is(err, sqlquery.ErrNullValueWarning) {
+			return nil, false, err
+		}
+		s.logger.Debug("NULL values returned by lookup query", zap.Error(err))
+	}
+
+	result := sqlRow{}
+	if s.cfg.Cache.TTL > 0 {
+		result.expiresAt = time.Now().Add(s.cfg.Cache.TTL)
+	}
+	if len(rows) > 0 {
+		result.found = true
+		result.row = make(map[string]any, len(rows[0]))
+		for column, value := range rows[0] {
+			result.row[column] = value
+		}
+	}
+	s.cache.Add(key, result)
+	return result.row, result.found, nil
+}
+
+func (s *sqlSource) Shutdown(_ context.Context) error {
+	if s.cache != nil {
+		s.cache.Purge()
+	}
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
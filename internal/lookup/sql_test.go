@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sqlquery"
+)
+
+func newTestSQLSource(t *testing.T, cfg SQLConfig, client *sqlquery.FakeDBClient) *sqlSource {
+	s := newSQLSource(cfg, zap.NewNop(),
+		func(string, string) (*sql.DB, error) {
+			return nil, nil
+		},
+		func(sqlquery.Db, string, *zap.Logger, sqlquery.TelemetryConfig) sqlquery.DbClient {
+			return client
+		},
+	)
+	require.NoError(t, s.Start(context.Background()))
+	t.Cleanup(func() {
+		require.NoError(t, s.Shutdown(context.Background()))
+	})
+	return s
+}
+
+func TestSQLSource(t *testing.T) {
+	client := &sqlquery.FakeDBClient{
+		StringMaps: [][]sqlquery.StringMap{
+			{{"team": "payments", "owner": "alice"}, {"team": "ignored"}},
+			{},
+		},
+	}
+	s := newTestSQLSource(t, SQLConfig{}, client)
+
+	for i := 0; i < 2; i++ {
+		row, found, err := s.Lookup(context.Background(), "checkout")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, map[string]any{"team": "payments", "owner": "alice"}, row)
+	}
+
+	for i := 0; i < 2; i++ {
+		_, found, err := s.Lookup(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.False(t, found)
+	}
+
+	// Both the found and missing keys are served from the cache.
+	assert.Equal(t, 2, client.RequestCounter)
+}
+
+func TestSQLSourceCacheEviction(t *testing.T) {
+	client := &sqlquery.FakeDBClient{
+		StringMaps: [][]sqlquery.StringMap{
+			{{"team": "payments"}},
+			{{"team": "storefront"}},
+			{{"team": "payments"}},
+		},
+	}
+	s := newTestSQLSource(t, SQLConfig{Cache: CacheConfig{MaxSize: 1}}, client)
+
+	for _, key := range []string{"checkout", "cart", "checkout"} {
+		_, found, err := s.Lookup(context.Background(), key)
+		require.NoError(t, err)
+		assert.True(t, found)
+	}
+	assert.Equal(t, 3, client.RequestCounter)
+}
+
+func TestSQLSourceCacheTTL(t *testing.T) {
+	client := &sqlquery.FakeDBClient{
+		StringMaps: [][]sqlquery.StringMap{
+			{{"team": "payments"}},
+			{{"team": "billing"}},
+		},
+	}
+	s := newTestSQLSource(t, SQLConfig{Cache: CacheConfig{TTL: time.Millisecond}}, client)
+
+	row, _, err := s.Lookup(context.Background(), "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "payments", row["team"])
+
+	time.Sleep(5 * time.Millisecond)
+	row, _, err = s.Lookup(context.Background(), "checkout")
+	require.NoError(t, err)
+	assert.Equal(t, "billing", row["team"])
+}
+
+func TestSQLSourceError(t *testing.T) {
+	s := newTestSQLSource(t, SQLConfig{}, &sqlquery.FakeDBClient{Err: errors.New("connection refused")})
+
+	_, _, err := s.Lookup(context.Background(), "checkout")
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestSQLSourceNullValues(t *testing.T) {
+	client := &sqlquery.FakeDBClient{
+		StringMaps: [][]sqlquery.StringMap{
+			{{"team": "payments", "owner": ""}},
+		},
+	}
+	s := newTestSQLSource(t, SQLConfig{}, client)
+	s.client = nullWarningClient{client}
+
+	row, found, err := s.Lookup(context.Background(), "checkout")
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, map[string]any{"team": "payments", "owner": ""}, row)
+}
+
+// nullWarningClient returns the rows of the wrapped client along with a NULL value warning.
+type nullWarningClient struct {
+	client sqlquery.DbClient
+}
+
+func (c nullWarningClient) QueryRows(ctx context.Context, args ...any) ([]sqlquery.StringMap, error) {
+	rows, err := c.client.QueryRows(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return rows, errors.Join(sqlquery.ErrNullValueWarning)
+}
+
+func TestSQLConfigValidate(t *testing.T) {
+	valid := SQLConfig{Driver: "postgres", DataSource: "host=localhost", Query: "SELECT team FROM teams WHERE service = $1"}
+	require.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(*SQLConfig)
+		err    string
+	}{
+		{name: "driver", modify: func(c *SQLConfig) { c.Driver = "" }, err: "'driver' cannot be empty"},
+		{name: "datasource", modify: func(c *SQLConfig) { c.DataSource = "" }, err: "'datasource' cannot be empty"},
+		{name: "query", modify: func(c *SQLConfig) { c.Query = "" }, err: "'query' cannot be empty"},
+		{name: "timeout", modify: func(c *SQLConfig) { c.Timeout = -time.Second }, err: "'timeout' cannot be negative"},
+		{name: "max_size", modify: func(c *SQLConfig) { c.Cache.MaxSize = -1 }, err: "'cache::max_size' cannot be negative"},
+		{name: "ttl", modify: func(c *SQLConfig) { c.Cache.TTL = -time.Second }, err: "'cache::ttl' cannot be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			assert.EqualError(t, cfg.Validate(), tt.err)
+		})
+	}
+}
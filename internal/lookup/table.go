@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// TableConfig configures a lookup table. Exactly one source must be set.
+type TableConfig struct {
+	// Name identifies the table in the OTTL Lookup converter.
+	Name string `mapstructure:"name"`
+
+	CSV  *CSVConfig  `mapstructure:"csv"`
+	JSON *JSONConfig `mapstructure:"json"`
+	SQL  *SQLConfig  `mapstructure:"sql"`
+}
+
+func (cfg TableConfig) Validate() error {
+	var sources int
+	for _, set := range []bool{cfg.CSV != nil, cfg.JSON != nil, cfg.SQL != nil} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return errors.New("exactly one of 'csv', 'json' or 'sql' must be set")
+	}
+	return nil
+}
+
+// ValidateTables validates the tables and checks that their names are set and unique.
+func ValidateTables(tables []TableConfig) error {
+	names := make(map[string]struct{}, len(tables))
+	for i, table := range tables {
+		if table.Name == "" {
+			return fmt.Errorf("table %d: 'name' cannot be empty", i)
+		}
+		if _, ok := names[table.Name]; ok {
+			return fmt.Errorf("table %q is defined more than once", table.Name)
+		}
+		names[table.Name] = struct{}{}
+		if err := table.Validate(); err != nil {
+			return fmt.Errorf("table %q: %w", table.Name, err)
+		}
+	}
+	return nil
+}
+
+// NewSource creates the source of the table.
+func NewSource(cfg TableConfig, logger *zap.Logger) Source {
+	switch {
+	case cfg.CSV != nil:
+		return NewCSV(*cfg.CSV, logger)
+	case cfg.JSON != nil:
+		return NewJSON(*cfg.JSON, logger)
+	default:
+		return NewSQL(*cfg.SQL, logger)
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestValidateTables(t *testing.T) {
+	csv := &CSVConfig{FileConfig: FileConfig{Path: "testdata/teams.csv"}, KeyColumn: "service"}
+	for _, tt := range []struct {
+		desc   string
+		tables []TableConfig
+		err    string
+	}{
+		{"valid", []TableConfig{{Name: "teams", CSV: csv}}, ""},
+		{"missing name", []TableConfig{{CSV: csv}}, "table 0: 'name' cannot be empty"},
+		{"duplicate name", []TableConfig{{Name: "teams", CSV: csv}, {Name: "teams", CSV: csv}}, `table "teams" is defined more than once`},
+		{"no source", []TableConfig{{Name: "teams"}}, `table "teams": exactly one of 'csv', 'json' or 'sql' must be set`},
+		{"two sources", []TableConfig{{Name: "teams", CSV: csv, JSON: &JSONConfig{}}}, `table "teams": exactly one of 'csv', 'json' or 'sql' must be set`},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := ValidateTables(tt.tables)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestNewSource(t *testing.T) {
+	table := TableConfig{
+		Name: "teams",
+		CSV:  &CSVConfig{FileConfig: FileConfig{Path: "testdata/teams.csv"}, KeyColumn: "service"},
+	}
+	source := NewSource(table, zap.NewNop())
+	require.NoError(t, source.Start(context.Background()))
+	defer func() {
+		assert.NoError(t, source.Shutdown(context.Background()))
+	}()
+
+	_, found, err := source.Lookup(context.Background(), "checkout")
+	require.NoError(t, err)
+	assert.True(t, found)
+}
@@ -0,0 +1,253 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// variableScope tracks the variables declared while parsing a statement block.
+// Each variable is assigned a slot in the values stored in the context at execution time.
+type variableScope struct {
+	indexes map[string]int
+}
+
+func newVariableScope() *variableScope {
+	return &variableScope{indexes: map[string]int{}}
+}
+
+func (s *variableScope) declare(name string) int {
+	if idx, ok := s.indexes[name]; ok {
+		return idx
+	}
+	idx := len(s.indexes)
+	s.indexes[name] = idx
+	return idx
+}
+
+type variablesKey struct{}
+
+// variableValues holds the values of the variables of a statement block for a single execution.
+type variableValues []any
+
+var errNoVariableScope = errors.New("variables can only be accessed while executing a statement block")
+
+func getVariable(ctx context.Context, idx int) (any, error) {
+	values, ok := ctx.Value(variablesKey{}).(variableValues)
+	if !ok || idx >= len(values) {
+		return nil, errNoVariableScope
+	}
+	return values[idx], nil
+}
+
+func setVariable(ctx context.Context, idx int, val any) error {
+	values, ok := ctx.Value(variablesKey{}).(variableValues)
+	if !ok || idx >= len(values) {
+		return errNoVariableScope
+	}
+	values[idx] = val
+	return nil
+}
+
+func (p *Parser[K]) variableIndex(name string) (int, error) {
+	if p.variables == nil {
+		return 0, fmt.Errorf("variable %s can only be used in statements starting with a conditional block or a variable assignment", name)
+	}
+	idx, ok := p.variables.indexes[name]
+	if !ok {
+		return 0, fmt.Errorf("variable %s is used before being assigned", name)
+	}
+	return idx, nil
+}
+
+func (p *Parser[K]) newVariableGetter(v *variable) (Getter[K], error) {
+	idx, err := p.variableIndex(v.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &exprGetter[K]{
+		expr: Expr[K]{
+			exprFunc: func(ctx context.Context, _ K) (any, error) {
+				return getVariable(ctx, idx)
+			},
+		},
+		keys: v.Keys,
+	}, nil
+}
+
+// newVariableGetSetter returns a GetSetter for a variable used as the target of an editor,
+// declaring the variable if it was not assigned before.
+func (p *Parser[K]) newVariableGetSetter(v *variable) (GetSetter[K], error) {
+	if p.variables == nil {
+		return nil, fmt.Errorf("variable %s can only be used in statements starting with a conditional block or a variable assignment", v.Name)
+	}
+	if len(v.Keys) > 0 {
+		return nil, fmt.Errorf("variable %s cannot be indexed when used as a target, set the whole variable instead", v.Name)
+	}
+	idx := p.variables.declare(v.Name)
+	return StandardGetSetter[K]{
+		Getter: func(ctx context.Context, _ K) (any, error) {
+			return getVariable(ctx, idx)
+		},
+		Setter: func(ctx context.Context, _ K, val any) error {
+			return setVariable(ctx, idx, val)
+		},
+	}, nil
+}
+
+// blockInstruction is a single executable element of a statement block.
+// execute returns true if at least one editor was invoked.
+type blockInstruction[K any] interface {
+	execute(ctx context.Context, tCtx K) (bool, error)
+}
+
+type blockInstructions[K any] []blockInstruction[K]
+
+func (b blockInstructions[K]) execute(ctx context.Context, tCtx K) (bool, error) {
+	var executed bool
+	for _, instruction := range b {
+		ran, err := instruction.execute(ctx, tCtx)
+		executed = executed || ran
+		if err != nil {
+			return executed, err
+		}
+	}
+	return executed, nil
+}
+
+type assignmentInstruction[K any] struct {
+	index int
+	value Getter[K]
+}
+
+func (a *assignmentInstruction[K]) execute(ctx context.Context, tCtx K) (bool, error) {
+	val, err := a.value.Get(ctx, tCtx)
+	if err != nil {
+		return false, err
+	}
+	return false, setVariable(ctx, a.index, val)
+}
+
+type statementInstruction[K any] struct {
+	function  Expr[K]
+	condition BoolExpr[K]
+}
+
+func (s *statementInstruction[K]) execute(ctx context.Context, tCtx K) (bool, error) {
+	condition, err := s.condition.Eval(ctx, tCtx)
+	if err != nil || !condition {
+		return false, err
+	}
+	_, err = s.function.Eval(ctx, tCtx)
+	return true, err
+}
+
+type ifInstruction[K any] struct {
+	condition BoolExpr[K]
+	body      blockInstructions[K]
+	elseBody  blockInstructions[K]
+}
+
+func (i *ifInstruction[K]) execute(ctx context.Context, tCtx K) (bool, error) {
+	condition, err := i.condition.Eval(ctx, tCtx)
+	if err != nil {
+		return false, err
+	}
+	if condition {
+		return i.body.execute(ctx, tCtx)
+	}
+	return i.elseBody.execute(ctx, tCtx)
+}
+
+func (p *Parser[K]) newBlockStatement(statement string, block *statementBlock) (*Statement[K], error) {
+	// Variables are scoped to the statement, so each block statement is built with its own scope.
+	bp := *p
+	bp.variables = newVariableScope()
+	instructions, err := bp.newBlockInstructions(block.items())
+	if err != nil {
+		return nil, err
+	}
+	return &Statement[K]{
+		block:             instructions,
+		variableCount:     len(bp.variables.indexes),
+		origText:          statement,
+		telemetrySettings: p.telemetrySettings,
+	}, nil
+}
+
+func (p *Parser[K]) newBlockInstructions(items []*blockItem) (blockInstructions[K], error) {
+	instructions := make(blockInstructions[K], 0, len(items))
+	for _, item := range items {
+		switch {
+		case item.If != nil:
+			instruction, err := p.newIfInstruction(item.If)
+			if err != nil {
+				return nil, err
+			}
+			instructions = append(instructions, instruction)
+		case item.Assignment != nil:
+			getter, err := p.newGetter(item.Assignment.Value)
+			if err != nil {
+				return nil, err
+			}
+			// The variable is declared after its value is built, so that an assignment
+			// cannot reference the variable it declares.
+			instructions = append(instructions, &assignmentInstruction[K]{
+				index: p.variables.declare(item.Assignment.Variable),
+				value: getter,
+			})
+		case item.Statement != nil && item.Statement.Block != nil:
+			nested, err := p.newBlockInstructions(item.Statement.Block.items())
+			if err != nil {
+				return nil, err
+			}
+			instructions = append(instructions, nested...)
+		case item.Statement != nil:
+			function, err := p.newFunctionCall(item.Statement.Editor)
+			if err != nil {
+				return nil, err
+			}
+			condition, err := p.newBoolExpr(item.Statement.WhereClause)
+			if err != nil {
+				return nil, err
+			}
+			instructions = append(instructions, &statementInstruction[K]{
+				function:  function,
+				condition: condition,
+			})
+		}
+	}
+	return instructions, nil
+}
+
+func (p *Parser[K]) newIfInstruction(block *ifBlock) (*ifInstruction[K], error) {
+	condition, err := p.newBoolExpr(block.Condition)
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.newBlockInstructions(block.Body)
+	if err != nil {
+		return nil, err
+	}
+	instruction := &ifInstruction[K]{
+		condition: condition,
+		body:      body,
+	}
+	switch {
+	case block.ElseIf != nil:
+		elseIf, err := p.newIfInstruction(block.ElseIf)
+		if err != nil {
+			return nil, err
+		}
+		instruction.elseBody = blockInstructions[K]{elseIf}
+	case block.Else != nil:
+		instruction.elseBody, err = p.newBlockInstructions(block.Else)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return instruction, nil
+}
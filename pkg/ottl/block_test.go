@@ -0,0 +1,293 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/internal/ottlcommon"
+)
+
+type blockSetArguments struct {
+	Target GetSetter[pcommon.Map]
+	Value  Getter[pcommon.Map]
+}
+
+type blockFailArguments struct{}
+
+func newBlockTestParser(t *testing.T) Parser[pcommon.Map] {
+	functions := CreateFactoryMap(
+		NewFactory("set", &blockSetArguments{}, func(_ FunctionContext, oArgs Arguments) (ExprFunc[pcommon.Map], error) {
+			args := oArgs.(*blockSetArguments)
+			return func(ctx context.Context, tCtx pcommon.Map) (any, error) {
+				val, err := args.Value.Get(ctx, tCtx)
+				if err != nil {
+					return nil, err
+				}
+				return nil, args.Target.Set(ctx, tCtx, val)
+			}, nil
+		}),
+		NewFactory("fail", &blockFailArguments{}, func(FunctionContext, Arguments) (ExprFunc[pcommon.Map], error) {
+			return func(context.Context, pcommon.Map) (any, error) {
+				return nil, fmt.Errorf("failed")
+			}, nil
+		}),
+	)
+	p, err := NewParser[pcommon.Map](functions, blockTestParsePath, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	return p
+}
+
+func blockTestParsePath(p Path[pcommon.Map]) (GetSetter[pcommon.Map], error) {
+	if p == nil || p.Name() != "attributes" || len(p.Keys()) != 1 {
+		return nil, fmt.Errorf("bad path %v", p)
+	}
+	key := p.Keys()[0]
+	return &StandardGetSetter[pcommon.Map]{
+		Getter: func(ctx context.Context, tCtx pcommon.Map) (any, error) {
+			k, err := key.String(ctx, tCtx)
+			if err != nil {
+				return nil, err
+			}
+			val, ok := tCtx.Get(*k)
+			if !ok {
+				return nil, nil
+			}
+			return ottlcommon.GetValue(val), nil
+		},
+		Setter: func(ctx context.Context, tCtx pcommon.Map, val any) error {
+			k, err := key.String(ctx, tCtx)
+			if err != nil {
+				return err
+			}
+			if m, ok := val.(pcommon.Map); ok {
+				m.CopyTo(tCtx.PutEmptyMap(*k))
+				return nil
+			}
+			return tCtx.PutEmpty(*k).FromRaw(val)
+		},
+	}, nil
+}
+
+func Test_BlockStatement_Execute(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		input     map[string]any
+		expected  map[string]any
+		executed  bool
+	}{
+		{
+			name:      "if branch",
+			statement: `if attributes["kind"] == "a" { set(attributes["out"], "if") } else { set(attributes["out"], "else") }`,
+			input:     map[string]any{"kind": "a"},
+			expected:  map[string]any{"kind": "a", "out": "if"},
+			executed:  true,
+		},
+		{
+			name:      "else branch",
+			statement: `if attributes["kind"] == "a" { set(attributes["out"], "if") } else { set(attributes["out"], "else") }`,
+			input:     map[string]any{"kind": "b"},
+			expected:  map[string]any{"kind": "b", "out": "else"},
+			executed:  true,
+		},
+		{
+			name:      "else if branch",
+			statement: `if attributes["kind"] == "a" { set(attributes["out"], 1) } else if attributes["kind"] == "b" { set(attributes["out"], 2) } else { set(attributes["out"], 3) }`,
+			input:     map[string]any{"kind": "b"},
+			expected:  map[string]any{"kind": "b", "out": int64(2)},
+			executed:  true,
+		},
+		{
+			name:      "no branch taken",
+			statement: `if attributes["kind"] == "a" { set(attributes["out"], 1) } else if attributes["kind"] == "b" { set(attributes["out"], 2) }`,
+			input:     map[string]any{"kind": "c"},
+			expected:  map[string]any{"kind": "c"},
+			executed:  false,
+		},
+		{
+			name: "multiple lines",
+			statement: `if attributes["kind"] == "a" {
+				set(attributes["first"], true)
+				set(attributes["second"], true) where attributes["kind"] == "b"
+			}`,
+			input:    map[string]any{"kind": "a"},
+			expected: map[string]any{"kind": "a", "first": true},
+			executed: true,
+		},
+		{
+			name:      "nested blocks",
+			statement: `if attributes["kind"] == "a" { if attributes["sub"] == 1 { set(attributes["out"], "nested") } }`,
+			input:     map[string]any{"kind": "a", "sub": int64(1)},
+			expected:  map[string]any{"kind": "a", "sub": int64(1), "out": "nested"},
+			executed:  true,
+		},
+		{
+			name:      "variable assignment",
+			statement: `$kind = attributes["kind"]; set(attributes["copy"], $kind)`,
+			input:     map[string]any{"kind": "a"},
+			expected:  map[string]any{"kind": "a", "copy": "a"},
+			executed:  true,
+		},
+		{
+			name:      "variable in condition",
+			statement: `$kind = attributes["kind"]; if $kind == "a" { set(attributes["out"], $kind) }`,
+			input:     map[string]any{"kind": "a"},
+			expected:  map[string]any{"kind": "a", "out": "a"},
+			executed:  true,
+		},
+		{
+			name:      "variable in where clause",
+			statement: `$kind = attributes["kind"]; set(attributes["out"], true) where $kind == "b"`,
+			input:     map[string]any{"kind": "a"},
+			expected:  map[string]any{"kind": "a"},
+			executed:  false,
+		},
+		{
+			name:      "indexed variable",
+			statement: `$m = attributes["map"]; set(attributes["out"], $m["key"])`,
+			input:     map[string]any{"map": map[string]any{"key": "value"}},
+			expected:  map[string]any{"map": map[string]any{"key": "value"}, "out": "value"},
+			executed:  true,
+		},
+		{
+			name:      "variable set by editor",
+			statement: `$v = "initial"; set($v, attributes["kind"]); set(attributes["out"], $v)`,
+			input:     map[string]any{"kind": "a"},
+			expected:  map[string]any{"kind": "a", "out": "a"},
+			executed:  true,
+		},
+		{
+			name:      "variable reassigned in branch",
+			statement: `$v = "default"; if attributes["kind"] == "a" { $v = "a" }; set(attributes["out"], $v)`,
+			input:     map[string]any{"kind": "a"},
+			expected:  map[string]any{"kind": "a", "out": "a"},
+			executed:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newBlockTestParser(t)
+			statement, err := p.ParseStatement(tt.statement)
+			require.NoError(t, err)
+
+			tCtx := pcommon.NewMap()
+			require.NoError(t, tCtx.FromRaw(tt.input))
+
+			_, executed, err := statement.Execute(context.Background(), tCtx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.executed, executed)
+			assert.Equal(t, tt.expected, tCtx.AsRaw())
+		})
+	}
+}
+
+func Test_BlockStatement_Execute_VariablesAreNotShared(t *testing.T) {
+	p := newBlockTestParser(t)
+	statement, err := p.ParseStatement(`if attributes["kind"] == "a" { $v = "a" } else { set(attributes["out"], $v) }`)
+	require.NoError(t, err)
+
+	first := pcommon.NewMap()
+	first.PutStr("kind", "a")
+	_, _, err = statement.Execute(context.Background(), first)
+	require.NoError(t, err)
+
+	second := pcommon.NewMap()
+	second.PutStr("kind", "b")
+	_, _, err = statement.Execute(context.Background(), second)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"kind": "b", "out": nil}, second.AsRaw())
+}
+
+func Test_BlockStatement_Execute_Error(t *testing.T) {
+	p := newBlockTestParser(t)
+	statement, err := p.ParseStatement(`if attributes["kind"] == "a" { set(attributes["before"], true); fail(); set(attributes["after"], true) }`)
+	require.NoError(t, err)
+
+	tCtx := pcommon.NewMap()
+	tCtx.PutStr("kind", "a")
+	_, executed, err := statement.Execute(context.Background(), tCtx)
+	assert.EqualError(t, err, "failed")
+	assert.True(t, executed)
+	assert.Equal(t, map[string]any{"kind": "a", "before": true}, tCtx.AsRaw())
+}
+
+func Test_BlockStatement_Parse_Error(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		err       string
+	}{
+		{
+			name:      "variable in single line statement",
+			statement: `set(attributes["out"], $v)`,
+			err:       "variable $v can only be used in statements starting with a conditional block or a variable assignment",
+		},
+		{
+			name:      "variable used before assignment",
+			statement: `if attributes["kind"] == "a" { set(attributes["out"], $v) }; $v = "a"`,
+			err:       "variable $v is used before being assigned",
+		},
+		{
+			name:      "variable referencing itself",
+			statement: `$v = $v`,
+			err:       "variable $v is used before being assigned",
+		},
+		{
+			name:      "indexed variable as target",
+			statement: `$v = attributes["map"]; set($v["key"], "value")`,
+			err:       "variable $v cannot be indexed when used as a target",
+		},
+		{
+			name:      "where clause on block",
+			statement: `if attributes["kind"] == "a" { set(attributes["out"], true) } where attributes["kind"] == "a"`,
+		},
+		{
+			name:      "missing closing brace",
+			statement: `if attributes["kind"] == "a" { set(attributes["out"], true)`,
+		},
+		{
+			name:      "else without if",
+			statement: `else { set(attributes["out"], true) }`,
+		},
+		{
+			name:      "unknown function in block",
+			statement: `if attributes["kind"] == "a" { unknown(attributes["out"]) }`,
+			err:       "undefined function",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newBlockTestParser(t)
+			_, err := p.ParseStatement(tt.statement)
+			require.Error(t, err)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func Test_ParseStatement_WithoutBlock_Unchanged(t *testing.T) {
+	p := newBlockTestParser(t)
+	statement, err := p.ParseStatement(`set(attributes["out"], "value") where attributes["kind"] == "a"`)
+	require.NoError(t, err)
+	assert.Nil(t, statement.block)
+
+	tCtx := pcommon.NewMap()
+	tCtx.PutStr("kind", "a")
+	_, executed, err := statement.Execute(context.Background(), tCtx)
+	require.NoError(t, err)
+	assert.True(t, executed)
+	assert.Equal(t, map[string]any{"kind": "a", "out": "value"}, tCtx.AsRaw())
+}
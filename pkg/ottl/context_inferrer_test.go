@@ -32,6 +32,14 @@ func Test_NewPriorityContextInferrer_Infer(t *testing.T) {
 			},
 			expected: "spanevent",
 		},
+		{
+			name:     "with block statement",
+			priority: []string{"spanevent", "span", "resource"},
+			statements: []string{
+				`if span.bar == true { $v = resource.value; set(spanevent.foo, $v) }`,
+			},
+			expected: "spanevent",
+		},
 		{
 			name:       "with no context",
 			priority:   []string{"log", "resource"},
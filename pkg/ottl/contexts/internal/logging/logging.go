@@ -9,6 +9,7 @@ import (
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pprofile"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap/zapcore"
 )
@@ -388,3 +389,53 @@ func (e Exemplar) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
 	}
 	return err
 }
+
+type Profile pprofile.Profile
+
+func (p Profile) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	pp := pprofile.Profile(p)
+	profileID := pp.ProfileID()
+	err := encoder.AddObject("attributes", Map(pp.Attributes()))
+	encoder.AddUint32("dropped_attributes_count", pp.DroppedAttributesCount())
+	encoder.AddInt64("duration_unix_nano", int64(pp.Duration()))
+	encoder.AddString("original_payload_format", pp.OriginalPayloadFormat())
+	encoder.AddInt64("period", pp.Period())
+	encoder.AddString("profile_id", hex.EncodeToString(profileID[:]))
+	encoder.AddInt("samples_count", pp.Sample().Len())
+	encoder.AddUint64("time_unix_nano", uint64(pp.Time()))
+	return err
+}
+
+type ProfileSample pprofile.Sample
+
+func (s ProfileSample) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	ps := pprofile.Sample(s)
+	encoder.AddInt32("locations_length", ps.LocationsLength())
+	encoder.AddInt32("locations_start_index", ps.LocationsStartIndex())
+	err := encoder.AddArray("timestamps_unix_nano", zapcore.ArrayMarshalerFunc(func(ae zapcore.ArrayEncoder) error {
+		for i := 0; i < ps.TimestampsUnixNano().Len(); i++ {
+			ae.AppendUint64(ps.TimestampsUnixNano().At(i))
+		}
+		return nil
+	}))
+	err = errors.Join(err, encoder.AddArray("values", zapcore.ArrayMarshalerFunc(func(ae zapcore.ArrayEncoder) error {
+		for i := 0; i < ps.Value().Len(); i++ {
+			ae.AppendInt64(ps.Value().At(i))
+		}
+		return nil
+	})))
+	return err
+}
+
+type ProfileLocation pprofile.Location
+
+func (l ProfileLocation) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	pl := pprofile.Location(l)
+	encoder.AddUint64("address", pl.Address())
+	encoder.AddBool("is_folded", pl.IsFolded())
+	encoder.AddInt("lines_count", pl.Line().Len())
+	if pl.HasMappingIndex() {
+		encoder.AddInt32("mapping_index", pl.MappingIndex())
+	}
+	return nil
+}
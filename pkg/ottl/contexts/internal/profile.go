@@ -0,0 +1,486 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal"
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+const (
+	ProfileContextName = "Profile"
+)
+
+type ProfileContext interface {
+	GetProfile() pprofile.Profile
+}
+
+func ProfilePathGetSetter[K ProfileContext](path ottl.Path[K]) (ottl.GetSetter[K], error) {
+	if path == nil {
+		return accessProfile[K](), nil
+	}
+	switch path.Name() {
+	case "profile_id":
+		nextPath := path.Next()
+		if nextPath != nil {
+			if nextPath.Name() == "string" {
+				return accessStringProfileID[K](), nil
+			}
+			return nil, FormatDefaultErrorMessage(nextPath.Name(), nextPath.String(), ProfileContextName, ProfileRef)
+		}
+		return accessProfileID[K](), nil
+	case "attributes":
+		mapKeys := path.Keys()
+		if mapKeys == nil {
+			return accessProfileAttributes[K](), nil
+		}
+		return accessProfileAttributesKey[K](mapKeys), nil
+	case "dropped_attributes_count":
+		return accessProfileDroppedAttributesCount[K](), nil
+	case "time_unix_nano":
+		return accessProfileTimeUnixNano[K](), nil
+	case "time":
+		return accessProfileTime[K](), nil
+	case "duration_unix_nano":
+		return accessProfileDurationUnixNano[K](), nil
+	case "duration":
+		return accessProfileDuration[K](), nil
+	case "period":
+		return accessProfilePeriod[K](), nil
+	case "period_type":
+		nextPath := path.Next()
+		if nextPath != nil {
+			switch nextPath.Name() {
+			case "type":
+				return accessProfilePeriodType[K](), nil
+			case "unit":
+				return accessProfilePeriodUnit[K](), nil
+			}
+			return nil, FormatDefaultErrorMessage(nextPath.Name(), nextPath.String(), ProfileContextName, ProfileRef)
+		}
+		return nil, FormatDefaultErrorMessage(path.Name(), path.String(), ProfileContextName, ProfileRef)
+	case "sample_type":
+		nextPath := path.Next()
+		if nextPath != nil {
+			switch nextPath.Name() {
+			case "type":
+				return accessProfileSampleTypeTypes[K](), nil
+			case "unit":
+				return accessProfileSampleTypeUnits[K](), nil
+			}
+			return nil, FormatDefaultErrorMessage(nextPath.Name(), nextPath.String(), ProfileContextName, ProfileRef)
+		}
+		return nil, FormatDefaultErrorMessage(path.Name(), path.String(), ProfileContextName, ProfileRef)
+	case "default_sample_type":
+		return accessProfileDefaultSampleType[K](), nil
+	case "original_payload_format":
+		return accessProfileOriginalPayloadFormat[K](), nil
+	case "original_payload":
+		return accessProfileOriginalPayload[K](), nil
+	default:
+		return nil, FormatDefaultErrorMessage(path.Name(), path.String(), ProfileContextName, ProfileRef)
+	}
+}
+
+// ProfileString returns the entry of the profile string table at the given index,
+// or an empty string if the index is out of range.
+func ProfileString(profile pprofile.Profile, idx int32) string {
+	if idx < 0 || int(idx) >= profile.StringTable().Len() {
+		return ""
+	}
+	return profile.StringTable().At(int(idx))
+}
+
+// PutProfileString returns the index of the given string in the profile string table,
+// appending it to the table if it is not present yet.
+func PutProfileString(profile pprofile.Profile, s string) int32 {
+	table := profile.StringTable()
+	for i := 0; i < table.Len(); i++ {
+		if table.At(i) == s {
+			return int32(i)
+		}
+	}
+	table.Append(s)
+	return int32(table.Len() - 1)
+}
+
+// ProfileAttributes resolves the given attribute table indices into a new map.
+// Indices pointing outside the attribute table are ignored.
+func ProfileAttributes(profile pprofile.Profile, indices pcommon.Int32Slice) pcommon.Map {
+	attrs := pcommon.NewMap()
+	table := profile.AttributeTable()
+	for i := 0; i < indices.Len(); i++ {
+		idx := int(indices.At(i))
+		if idx < 0 || idx >= table.Len() {
+			continue
+		}
+		attr := table.At(idx)
+		attr.Value().CopyTo(attrs.PutEmpty(attr.Key()))
+	}
+	return attrs
+}
+
+// SetProfileAttributes replaces the given attribute table indices with entries
+// matching the content of attrs. Existing attribute table entries are reused when
+// both key and value match; other entries are appended to the attribute table.
+func SetProfileAttributes(profile pprofile.Profile, indices pcommon.Int32Slice, attrs pcommon.Map) {
+	table := profile.AttributeTable()
+	newIndices := make([]int32, 0, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		idx := -1
+		for i := 0; i < table.Len(); i++ {
+			existing := table.At(i)
+			if existing.Key() == k && existing.Value().Type() == v.Type() && existing.Value().AsString() == v.AsString() {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			attr := table.AppendEmpty()
+			attr.SetKey(k)
+			v.CopyTo(attr.Value())
+			idx = table.Len() - 1
+		}
+		newIndices = append(newIndices, int32(idx))
+		return true
+	})
+	indices.FromRaw(newIndices)
+}
+
+// AppendLocationFrames appends one map per line of the given location to dest,
+// resolving function names and file names through the profile tables.
+// Inlined functions are listed before the function they were inlined into.
+func AppendLocationFrames(profile pprofile.Profile, location pprofile.Location, dest pcommon.Slice) {
+	var mappingFilename string
+	if location.HasMappingIndex() {
+		if idx := int(location.MappingIndex()); idx >= 0 && idx < profile.MappingTable().Len() {
+			mappingFilename = ProfileString(profile, profile.MappingTable().At(idx).FilenameStrindex())
+		}
+	}
+	appendFrame := func() pcommon.Map {
+		frame := dest.AppendEmpty().SetEmptyMap()
+		frame.PutInt("address", int64(location.Address()))
+		frame.PutStr("mapping", mappingFilename)
+		return frame
+	}
+	if location.Line().Len() == 0 {
+		appendFrame()
+		return
+	}
+	functions := profile.FunctionTable()
+	for i := 0; i < location.Line().Len(); i++ {
+		line := location.Line().At(i)
+		frame := appendFrame()
+		var name, systemName, filename string
+		if idx := int(line.FunctionIndex()); idx >= 0 && idx < functions.Len() {
+			function := functions.At(idx)
+			name = ProfileString(profile, function.NameStrindex())
+			systemName = ProfileString(profile, function.SystemNameStrindex())
+			filename = ProfileString(profile, function.FilenameStrindex())
+		}
+		frame.PutStr("function", name)
+		frame.PutStr("system_name", systemName)
+		frame.PutStr("filename", filename)
+		frame.PutInt("line", line.Line())
+		frame.PutInt("column", line.Column())
+	}
+}
+
+func accessProfile[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return tCtx.GetProfile(), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if newProfile, ok := val.(pprofile.Profile); ok {
+				newProfile.CopyTo(tCtx.GetProfile())
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfileID[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return tCtx.GetProfile().ProfileID(), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if newProfileID, ok := val.(pprofile.ProfileID); ok {
+				tCtx.GetProfile().SetProfileID(newProfileID)
+			}
+			return nil
+		},
+	}
+}
+
+func accessStringProfileID[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			id := tCtx.GetProfile().ProfileID()
+			return hex.EncodeToString(id[:]), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if str, ok := val.(string); ok {
+				id, err := ParseProfileID(str)
+				if err != nil {
+					return err
+				}
+				tCtx.GetProfile().SetProfileID(id)
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfileAttributes[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return tCtx.GetProfile().Attributes(), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if attrs, ok := val.(pcommon.Map); ok {
+				attrs.CopyTo(tCtx.GetProfile().Attributes())
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfileAttributesKey[K ProfileContext](keys []ottl.Key[K]) ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(ctx context.Context, tCtx K) (any, error) {
+			return GetMapValue[K](ctx, tCtx, tCtx.GetProfile().Attributes(), keys)
+		},
+		Setter: func(ctx context.Context, tCtx K, val any) error {
+			return SetMapValue[K](ctx, tCtx, tCtx.GetProfile().Attributes(), keys, val)
+		},
+	}
+}
+
+func accessProfileDroppedAttributesCount[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return int64(tCtx.GetProfile().DroppedAttributesCount()), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if newCount, ok := val.(int64); ok {
+				tCtx.GetProfile().SetDroppedAttributesCount(uint32(newCount))
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfileTimeUnixNano[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return tCtx.GetProfile().Time().AsTime().UnixNano(), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if t, ok := val.(int64); ok {
+				tCtx.GetProfile().SetTime(pcommon.NewTimestampFromTime(time.Unix(0, t)))
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfileTime[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return tCtx.GetProfile().Time().AsTime(), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if t, ok := val.(time.Time); ok {
+				tCtx.GetProfile().SetTime(pcommon.NewTimestampFromTime(t))
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfileDurationUnixNano[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return int64(tCtx.GetProfile().Duration()), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if d, ok := val.(int64); ok {
+				tCtx.GetProfile().SetDuration(pcommon.Timestamp(d))
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfileDuration[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return time.Duration(tCtx.GetProfile().Duration()), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if d, ok := val.(time.Duration); ok {
+				tCtx.GetProfile().SetDuration(pcommon.Timestamp(d))
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfilePeriod[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return tCtx.GetProfile().Period(), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if p, ok := val.(int64); ok {
+				tCtx.GetProfile().SetPeriod(p)
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfilePeriodType[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			profile := tCtx.GetProfile()
+			return ProfileString(profile, profile.PeriodType().TypeStrindex()), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if str, ok := val.(string); ok {
+				profile := tCtx.GetProfile()
+				profile.PeriodType().SetTypeStrindex(PutProfileString(profile, str))
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfilePeriodUnit[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			profile := tCtx.GetProfile()
+			return ProfileString(profile, profile.PeriodType().UnitStrindex()), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if str, ok := val.(string); ok {
+				profile := tCtx.GetProfile()
+				profile.PeriodType().SetUnitStrindex(PutProfileString(profile, str))
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfileSampleTypeTypes[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			profile := tCtx.GetProfile()
+			types := make([]string, profile.SampleType().Len())
+			for i := range types {
+				types[i] = ProfileString(profile, profile.SampleType().At(i).TypeStrindex())
+			}
+			return types, nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			profile := tCtx.GetProfile()
+			return setProfileSampleTypeStrings(profile, val, pprofile.ValueType.SetTypeStrindex)
+		},
+	}
+}
+
+func accessProfileSampleTypeUnits[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			profile := tCtx.GetProfile()
+			units := make([]string, profile.SampleType().Len())
+			for i := range units {
+				units[i] = ProfileString(profile, profile.SampleType().At(i).UnitStrindex())
+			}
+			return units, nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			profile := tCtx.GetProfile()
+			return setProfileSampleTypeStrings(profile, val, pprofile.ValueType.SetUnitStrindex)
+		},
+	}
+}
+
+// setProfileSampleTypeStrings updates one string field of every sample type. The number
+// of sample types is tied to the number of values of each sample, so the new list must
+// have the same length as the existing one.
+func setProfileSampleTypeStrings(profile pprofile.Profile, val any, set func(pprofile.ValueType, int32)) error {
+	var strs []string
+	switch v := val.(type) {
+	case []string:
+		strs = v
+	case pcommon.Slice:
+		strs = make([]string, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			strs[i] = v.At(i).AsString()
+		}
+	default:
+		return nil
+	}
+	if len(strs) != profile.SampleType().Len() {
+		return fmt.Errorf("expected %d sample types, got %d", profile.SampleType().Len(), len(strs))
+	}
+	for i, str := range strs {
+		set(profile.SampleType().At(i), PutProfileString(profile, str))
+	}
+	return nil
+}
+
+func accessProfileDefaultSampleType[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			profile := tCtx.GetProfile()
+			return ProfileString(profile, profile.DefaultSampleTypeStrindex()), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if str, ok := val.(string); ok {
+				profile := tCtx.GetProfile()
+				profile.SetDefaultSampleTypeStrindex(PutProfileString(profile, str))
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfileOriginalPayloadFormat[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return tCtx.GetProfile().OriginalPayloadFormat(), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if str, ok := val.(string); ok {
+				tCtx.GetProfile().SetOriginalPayloadFormat(str)
+			}
+			return nil
+		},
+	}
+}
+
+func accessProfileOriginalPayload[K ProfileContext]() ottl.StandardGetSetter[K] {
+	return ottl.StandardGetSetter[K]{
+		Getter: func(_ context.Context, tCtx K) (any, error) {
+			return tCtx.GetProfile().OriginalPayload().AsRaw(), nil
+		},
+		Setter: func(_ context.Context, tCtx K, val any) error {
+			if b, ok := val.([]byte); ok {
+				tCtx.GetProfile().OriginalPayload().FromRaw(b)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,433 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottltest"
+)
+
+var (
+	profileID  = [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	profileID2 = [16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+)
+
+func TestProfilePathGetSetter(t *testing.T) {
+	newAttrs := pcommon.NewMap()
+	newAttrs.PutStr("hello", "world")
+
+	tests := []struct {
+		name     string
+		path     ottl.Path[*profileContext]
+		orig     any
+		newVal   any
+		modified func(profile pprofile.Profile)
+	}{
+		{
+			name: "profile_id",
+			path: &TestPath[*profileContext]{
+				N: "profile_id",
+			},
+			orig:   pprofile.ProfileID(profileID),
+			newVal: pprofile.ProfileID(profileID2),
+			modified: func(profile pprofile.Profile) {
+				profile.SetProfileID(profileID2)
+			},
+		},
+		{
+			name: "profile_id string",
+			path: &TestPath[*profileContext]{
+				N: "profile_id",
+				NextPath: &TestPath[*profileContext]{
+					N: "string",
+				},
+			},
+			orig:   hex.EncodeToString(profileID[:]),
+			newVal: hex.EncodeToString(profileID2[:]),
+			modified: func(profile pprofile.Profile) {
+				profile.SetProfileID(profileID2)
+			},
+		},
+		{
+			name: "attributes",
+			path: &TestPath[*profileContext]{
+				N: "attributes",
+			},
+			orig: func() pcommon.Map {
+				m := pcommon.NewMap()
+				m.PutStr("str", "val")
+				return m
+			}(),
+			newVal: newAttrs,
+			modified: func(profile pprofile.Profile) {
+				newAttrs.CopyTo(profile.Attributes())
+			},
+		},
+		{
+			name: "attributes key",
+			path: &TestPath[*profileContext]{
+				N: "attributes",
+				KeySlice: []ottl.Key[*profileContext]{
+					&TestKey[*profileContext]{
+						S: ottltest.Strp("str"),
+					},
+				},
+			},
+			orig:   "val",
+			newVal: "newVal",
+			modified: func(profile pprofile.Profile) {
+				profile.Attributes().PutStr("str", "newVal")
+			},
+		},
+		{
+			name: "dropped_attributes_count",
+			path: &TestPath[*profileContext]{
+				N: "dropped_attributes_count",
+			},
+			orig:   int64(10),
+			newVal: int64(20),
+			modified: func(profile pprofile.Profile) {
+				profile.SetDroppedAttributesCount(20)
+			},
+		},
+		{
+			name: "time_unix_nano",
+			path: &TestPath[*profileContext]{
+				N: "time_unix_nano",
+			},
+			orig:   int64(500_000_000),
+			newVal: int64(200_000_000),
+			modified: func(profile pprofile.Profile) {
+				profile.SetTime(pcommon.NewTimestampFromTime(time.UnixMilli(200)))
+			},
+		},
+		{
+			name: "time",
+			path: &TestPath[*profileContext]{
+				N: "time",
+			},
+			orig:   time.Date(1970, 1, 1, 0, 0, 0, 500000000, time.UTC),
+			newVal: time.Date(1970, 1, 1, 0, 0, 0, 200000000, time.UTC),
+			modified: func(profile pprofile.Profile) {
+				profile.SetTime(pcommon.NewTimestampFromTime(time.UnixMilli(200)))
+			},
+		},
+		{
+			name: "duration_unix_nano",
+			path: &TestPath[*profileContext]{
+				N: "duration_unix_nano",
+			},
+			orig:   int64(time.Second),
+			newVal: int64(2 * time.Second),
+			modified: func(profile pprofile.Profile) {
+				profile.SetDuration(pcommon.Timestamp(2 * time.Second))
+			},
+		},
+		{
+			name: "duration",
+			path: &TestPath[*profileContext]{
+				N: "duration",
+			},
+			orig:   time.Second,
+			newVal: 3 * time.Second,
+			modified: func(profile pprofile.Profile) {
+				profile.SetDuration(pcommon.Timestamp(3 * time.Second))
+			},
+		},
+		{
+			name: "period",
+			path: &TestPath[*profileContext]{
+				N: "period",
+			},
+			orig:   int64(10_000_000),
+			newVal: int64(20_000_000),
+			modified: func(profile pprofile.Profile) {
+				profile.SetPeriod(20_000_000)
+			},
+		},
+		{
+			name: "period_type type",
+			path: &TestPath[*profileContext]{
+				N: "period_type",
+				NextPath: &TestPath[*profileContext]{
+					N: "type",
+				},
+			},
+			orig:   "cpu",
+			newVal: "samples",
+			modified: func(profile pprofile.Profile) {
+				profile.PeriodType().SetTypeStrindex(3)
+			},
+		},
+		{
+			name: "period_type unit",
+			path: &TestPath[*profileContext]{
+				N: "period_type",
+				NextPath: &TestPath[*profileContext]{
+					N: "unit",
+				},
+			},
+			orig:   "nanoseconds",
+			newVal: "microseconds",
+			modified: func(profile pprofile.Profile) {
+				profile.StringTable().Append("microseconds")
+				profile.PeriodType().SetUnitStrindex(12)
+			},
+		},
+		{
+			name: "sample_type type",
+			path: &TestPath[*profileContext]{
+				N: "sample_type",
+				NextPath: &TestPath[*profileContext]{
+					N: "type",
+				},
+			},
+			orig:   []string{"samples", "cpu"},
+			newVal: []string{"events", "cpu"},
+			modified: func(profile pprofile.Profile) {
+				profile.StringTable().Append("events")
+				profile.SampleType().At(0).SetTypeStrindex(12)
+			},
+		},
+		{
+			name: "sample_type unit",
+			path: &TestPath[*profileContext]{
+				N: "sample_type",
+				NextPath: &TestPath[*profileContext]{
+					N: "unit",
+				},
+			},
+			orig:   []string{"count", "nanoseconds"},
+			newVal: []string{"count", "count"},
+			modified: func(profile pprofile.Profile) {
+				profile.SampleType().At(1).SetUnitStrindex(4)
+			},
+		},
+		{
+			name: "default_sample_type",
+			path: &TestPath[*profileContext]{
+				N: "default_sample_type",
+			},
+			orig:   "cpu",
+			newVal: "samples",
+			modified: func(profile pprofile.Profile) {
+				profile.SetDefaultSampleTypeStrindex(3)
+			},
+		},
+		{
+			name: "original_payload_format",
+			path: &TestPath[*profileContext]{
+				N: "original_payload_format",
+			},
+			orig:   "pprofext",
+			newVal: "jfr",
+			modified: func(profile pprofile.Profile) {
+				profile.SetOriginalPayloadFormat("jfr")
+			},
+		},
+		{
+			name: "original_payload",
+			path: &TestPath[*profileContext]{
+				N: "original_payload",
+			},
+			orig:   []byte{1, 2, 3},
+			newVal: []byte{4, 5},
+			modified: func(profile pprofile.Profile) {
+				profile.OriginalPayload().FromRaw([]byte{4, 5})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessor, err := ProfilePathGetSetter[*profileContext](tt.path)
+			require.NoError(t, err)
+
+			profile := createProfile()
+
+			got, err := accessor.Get(context.Background(), newProfileContext(profile))
+			require.NoError(t, err)
+			assert.Equal(t, tt.orig, got)
+
+			err = accessor.Set(context.Background(), newProfileContext(profile), tt.newVal)
+			require.NoError(t, err)
+
+			expectedProfile := createProfile()
+			tt.modified(expectedProfile)
+
+			assert.Equal(t, expectedProfile, profile)
+		})
+	}
+}
+
+func TestProfilePathGetSetter_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		path ottl.Path[*profileContext]
+	}{
+		{
+			name: "unknown path",
+			path: &TestPath[*profileContext]{N: "unknown"},
+		},
+		{
+			name: "period_type without field",
+			path: &TestPath[*profileContext]{N: "period_type"},
+		},
+		{
+			name: "sample_type unknown field",
+			path: &TestPath[*profileContext]{
+				N:        "sample_type",
+				NextPath: &TestPath[*profileContext]{N: "name"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ProfilePathGetSetter[*profileContext](tt.path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProfilePathGetSetter_SampleTypeLengthMismatch(t *testing.T) {
+	accessor, err := ProfilePathGetSetter[*profileContext](&TestPath[*profileContext]{
+		N:        "sample_type",
+		NextPath: &TestPath[*profileContext]{N: "type"},
+	})
+	require.NoError(t, err)
+
+	err = accessor.Set(context.Background(), newProfileContext(createProfile()), []string{"cpu"})
+	assert.ErrorContains(t, err, "expected 2 sample types, got 1")
+}
+
+func TestProfileAttributes(t *testing.T) {
+	profile := createProfile()
+	indices := pcommon.NewInt32Slice()
+	indices.FromRaw([]int32{1, 0, 42})
+
+	attrs := ProfileAttributes(profile, indices)
+	assert.Equal(t, map[string]any{"thread.name": "main", "host.arch": "amd64"}, attrs.AsRaw())
+
+	attrs.PutStr("thread.name", "worker")
+	attrs.Remove("host.arch")
+	SetProfileAttributes(profile, indices, attrs)
+
+	assert.Equal(t, []int32{2}, indices.AsRaw())
+	assert.Equal(t, 3, profile.AttributeTable().Len())
+	assert.Equal(t, map[string]any{"thread.name": "worker"}, ProfileAttributes(profile, indices).AsRaw())
+
+	// Setting the same content again reuses the existing attribute table entries.
+	SetProfileAttributes(profile, indices, attrs)
+	assert.Equal(t, 3, profile.AttributeTable().Len())
+}
+
+func TestAppendLocationFrames(t *testing.T) {
+	profile := createProfile()
+	frames := pcommon.NewSlice()
+	AppendLocationFrames(profile, profile.LocationTable().At(0), frames)
+	AppendLocationFrames(profile, profile.LocationTable().At(1), frames)
+
+	assert.Equal(t, []any{
+		map[string]any{
+			"address":     int64(0x1000),
+			"mapping":     "/usr/bin/app",
+			"function":    "inlined",
+			"system_name": "_inlined",
+			"filename":    "app.go",
+			"line":        int64(12),
+			"column":      int64(3),
+		},
+		map[string]any{
+			"address":     int64(0x1000),
+			"mapping":     "/usr/bin/app",
+			"function":    "main",
+			"system_name": "_main",
+			"filename":    "main.go",
+			"line":        int64(42),
+			"column":      int64(0),
+		},
+		map[string]any{
+			"address": int64(0x2000),
+			"mapping": "",
+		},
+	}, frames.AsRaw())
+}
+
+func createProfile() pprofile.Profile {
+	profile := pprofile.NewProfile()
+	profile.StringTable().FromRaw([]string{"", "cpu", "nanoseconds", "samples", "count"})
+	profile.SetProfileID(profileID)
+	profile.Attributes().PutStr("str", "val")
+	profile.SetDroppedAttributesCount(10)
+	profile.SetTime(pcommon.NewTimestampFromTime(time.UnixMilli(500)))
+	profile.SetDuration(pcommon.Timestamp(time.Second))
+	profile.SetPeriod(10_000_000)
+	profile.PeriodType().SetTypeStrindex(1)
+	profile.PeriodType().SetUnitStrindex(2)
+
+	samples := profile.SampleType().AppendEmpty()
+	samples.SetTypeStrindex(3)
+	samples.SetUnitStrindex(4)
+	cpu := profile.SampleType().AppendEmpty()
+	cpu.SetTypeStrindex(1)
+	cpu.SetUnitStrindex(2)
+	profile.SetDefaultSampleTypeStrindex(1)
+
+	profile.SetOriginalPayloadFormat("pprofext")
+	profile.OriginalPayload().FromRaw([]byte{1, 2, 3})
+
+	attr := profile.AttributeTable().AppendEmpty()
+	attr.SetKey("host.arch")
+	attr.Value().SetStr("amd64")
+	attr = profile.AttributeTable().AppendEmpty()
+	attr.SetKey("thread.name")
+	attr.Value().SetStr("main")
+
+	profile.StringTable().Append("/usr/bin/app", "inlined", "_inlined", "app.go", "main", "_main", "main.go")
+	profile.MappingTable().AppendEmpty().SetFilenameStrindex(5)
+
+	inlined := profile.FunctionTable().AppendEmpty()
+	inlined.SetNameStrindex(6)
+	inlined.SetSystemNameStrindex(7)
+	inlined.SetFilenameStrindex(8)
+	main := profile.FunctionTable().AppendEmpty()
+	main.SetNameStrindex(9)
+	main.SetSystemNameStrindex(10)
+	main.SetFilenameStrindex(11)
+
+	loc := profile.LocationTable().AppendEmpty()
+	loc.SetMappingIndex(0)
+	loc.SetAddress(0x1000)
+	line := loc.Line().AppendEmpty()
+	line.SetFunctionIndex(0)
+	line.SetLine(12)
+	line.SetColumn(3)
+	line = loc.Line().AppendEmpty()
+	line.SetFunctionIndex(1)
+	line.SetLine(42)
+	profile.LocationTable().AppendEmpty().SetAddress(0x2000)
+
+	return profile
+}
+
+type profileContext struct {
+	profile pprofile.Profile
+}
+
+func (r *profileContext) GetProfile() pprofile.Profile {
+	return r.profile
+}
+
+func newProfileContext(profile pprofile.Profile) *profileContext {
+	return &profileContext{profile: profile}
+}
@@ -0,0 +1,250 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlprofile
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottltest"
+)
+
+var (
+	profileID  = [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	profileID2 = [16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+)
+
+func Test_newPathGetSetter(t *testing.T) {
+	newCache := pcommon.NewMap()
+	newCache.PutStr("temp", "value")
+
+	newAttrs := pcommon.NewMap()
+	newAttrs.PutStr("hello", "world")
+
+	tests := []struct {
+		name     string
+		path     ottl.Path[TransformContext]
+		orig     any
+		newVal   any
+		modified func(profile pprofile.Profile, il pcommon.InstrumentationScope, resource pcommon.Resource, cache pcommon.Map)
+	}{
+		{
+			name: "cache",
+			path: &internal.TestPath[TransformContext]{
+				N: "cache",
+			},
+			orig:   pcommon.NewMap(),
+			newVal: newCache,
+			modified: func(_ pprofile.Profile, _ pcommon.InstrumentationScope, _ pcommon.Resource, cache pcommon.Map) {
+				newCache.CopyTo(cache)
+			},
+		},
+		{
+			name: "cache access",
+			path: &internal.TestPath[TransformContext]{
+				N: "cache",
+				KeySlice: []ottl.Key[TransformContext]{
+					&internal.TestKey[TransformContext]{
+						S: ottltest.Strp("temp"),
+					},
+				},
+			},
+			orig:   nil,
+			newVal: "new value",
+			modified: func(_ pprofile.Profile, _ pcommon.InstrumentationScope, _ pcommon.Resource, cache pcommon.Map) {
+				cache.PutStr("temp", "new value")
+			},
+		},
+		{
+			name: "profile_id",
+			path: &internal.TestPath[TransformContext]{
+				N: "profile_id",
+			},
+			orig:   pprofile.ProfileID(profileID),
+			newVal: pprofile.ProfileID(profileID2),
+			modified: func(profile pprofile.Profile, _ pcommon.InstrumentationScope, _ pcommon.Resource, _ pcommon.Map) {
+				profile.SetProfileID(profileID2)
+			},
+		},
+		{
+			name: "profile_id string",
+			path: &internal.TestPath[TransformContext]{
+				N: "profile_id",
+				NextPath: &internal.TestPath[TransformContext]{
+					N: "string",
+				},
+			},
+			orig:   hex.EncodeToString(profileID[:]),
+			newVal: hex.EncodeToString(profileID2[:]),
+			modified: func(profile pprofile.Profile, _ pcommon.InstrumentationScope, _ pcommon.Resource, _ pcommon.Map) {
+				profile.SetProfileID(profileID2)
+			},
+		},
+		{
+			name: "attributes",
+			path: &internal.TestPath[TransformContext]{
+				N: "attributes",
+			},
+			orig: func() pcommon.Map {
+				m := pcommon.NewMap()
+				m.PutStr("service.version", "1.0.0")
+				return m
+			}(),
+			newVal: newAttrs,
+			modified: func(profile pprofile.Profile, _ pcommon.InstrumentationScope, _ pcommon.Resource, _ pcommon.Map) {
+				newAttrs.CopyTo(profile.Attributes())
+			},
+		},
+		{
+			name: "attributes key",
+			path: &internal.TestPath[TransformContext]{
+				N: "attributes",
+				KeySlice: []ottl.Key[TransformContext]{
+					&internal.TestKey[TransformContext]{
+						S: ottltest.Strp("service.version"),
+					},
+				},
+			},
+			orig:   "1.0.0",
+			newVal: "redacted",
+			modified: func(profile pprofile.Profile, _ pcommon.InstrumentationScope, _ pcommon.Resource, _ pcommon.Map) {
+				profile.Attributes().PutStr("service.version", "redacted")
+			},
+		},
+		{
+			name: "time",
+			path: &internal.TestPath[TransformContext]{
+				N: "time",
+			},
+			orig:   time.Date(1970, 1, 1, 0, 0, 0, 100000000, time.UTC),
+			newVal: time.Date(1970, 1, 1, 0, 0, 0, 200000000, time.UTC),
+			modified: func(profile pprofile.Profile, _ pcommon.InstrumentationScope, _ pcommon.Resource, _ pcommon.Map) {
+				profile.SetTime(pcommon.NewTimestampFromTime(time.UnixMilli(200)))
+			},
+		},
+		{
+			name: "duration",
+			path: &internal.TestPath[TransformContext]{
+				N: "duration",
+			},
+			orig:   10 * time.Second,
+			newVal: 5 * time.Second,
+			modified: func(profile pprofile.Profile, _ pcommon.InstrumentationScope, _ pcommon.Resource, _ pcommon.Map) {
+				profile.SetDuration(pcommon.Timestamp(5 * time.Second))
+			},
+		},
+		{
+			name: "sample_type type",
+			path: &internal.TestPath[TransformContext]{
+				N: "sample_type",
+				NextPath: &internal.TestPath[TransformContext]{
+					N: "type",
+				},
+			},
+			orig:   []string{"cpu"},
+			newVal: []string{"cpu"},
+			modified: func(_ pprofile.Profile, _ pcommon.InstrumentationScope, _ pcommon.Resource, _ pcommon.Map) {
+			},
+		},
+		{
+			name: "resource attributes",
+			path: &internal.TestPath[TransformContext]{
+				N: "resource",
+				NextPath: &internal.TestPath[TransformContext]{
+					N: "attributes",
+					KeySlice: []ottl.Key[TransformContext]{
+						&internal.TestKey[TransformContext]{
+							S: ottltest.Strp("service.name"),
+						},
+					},
+				},
+			},
+			orig:   "checkout",
+			newVal: "cart",
+			modified: func(_ pprofile.Profile, _ pcommon.InstrumentationScope, resource pcommon.Resource, _ pcommon.Map) {
+				resource.Attributes().PutStr("service.name", "cart")
+			},
+		},
+		{
+			name: "instrumentation_scope name",
+			path: &internal.TestPath[TransformContext]{
+				N: "instrumentation_scope",
+				NextPath: &internal.TestPath[TransformContext]{
+					N: "name",
+				},
+			},
+			orig:   "profiler",
+			newVal: "ebpf-profiler",
+			modified: func(_ pprofile.Profile, il pcommon.InstrumentationScope, _ pcommon.Resource, _ pcommon.Map) {
+				il.SetName("ebpf-profiler")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pep := pathExpressionParser{}
+			accessor, err := pep.parsePath(tt.path)
+			require.NoError(t, err)
+
+			profile, il, resource := createTelemetry()
+
+			tCtx := NewTransformContext(profile, il, resource, pprofile.NewScopeProfiles(), pprofile.NewResourceProfiles())
+			got, err := accessor.Get(context.Background(), tCtx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.orig, got)
+
+			err = accessor.Set(context.Background(), tCtx, tt.newVal)
+			require.NoError(t, err)
+
+			exProfile, exIl, exRes := createTelemetry()
+			exCache := pcommon.NewMap()
+			tt.modified(exProfile, exIl, exRes, exCache)
+
+			assert.Equal(t, exProfile, profile)
+			assert.Equal(t, exIl, il)
+			assert.Equal(t, exRes, resource)
+			assert.Equal(t, exCache, tCtx.getCache())
+		})
+	}
+}
+
+func Test_newPathGetSetter_Invalid(t *testing.T) {
+	pep := pathExpressionParser{}
+	_, err := pep.parsePath(&internal.TestPath[TransformContext]{N: "sample"})
+	assert.ErrorContains(t, err, `segment "sample" from path "sample" is not a valid path`)
+}
+
+func Test_ParseEnum(t *testing.T) {
+	_, err := parseEnum((*ottl.EnumSymbol)(ottltest.Strp("SPAN_KIND_SERVER")))
+	assert.Error(t, err)
+}
+
+func createTelemetry() (pprofile.Profile, pcommon.InstrumentationScope, pcommon.Resource) {
+	profile := pprofile.NewProfile()
+	profile.StringTable().FromRaw([]string{"", "cpu", "nanoseconds"})
+	profile.SetProfileID(profileID)
+	profile.Attributes().PutStr("service.version", "1.0.0")
+	profile.SetTime(pcommon.NewTimestampFromTime(time.UnixMilli(100)))
+	profile.SetDuration(pcommon.Timestamp(10 * time.Second))
+	sampleType := profile.SampleType().AppendEmpty()
+	sampleType.SetTypeStrindex(1)
+	sampleType.SetUnitStrindex(2)
+
+	il := pcommon.NewInstrumentationScope()
+	il.SetName("profiler")
+
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("service.name", "checkout")
+
+	return profile, il, resource
+}
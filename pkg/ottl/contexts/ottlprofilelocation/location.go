@@ -0,0 +1,422 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlprofilelocation // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlprofilelocation"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/logging"
+)
+
+const (
+	contextName = "Profile Location"
+)
+
+var (
+	_ internal.ResourceContext             = (*TransformContext)(nil)
+	_ internal.InstrumentationScopeContext = (*TransformContext)(nil)
+	_ internal.ProfileContext              = (*TransformContext)(nil)
+	_ zapcore.ObjectMarshaler              = (*TransformContext)(nil)
+)
+
+type TransformContext struct {
+	location             pprofile.Location
+	profile              pprofile.Profile
+	instrumentationScope pcommon.InstrumentationScope
+	resource             pcommon.Resource
+	cache                pcommon.Map
+	scopeProfiles        pprofile.ScopeProfiles
+	resourceProfiles     pprofile.ResourceProfiles
+}
+
+func (tCtx TransformContext) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	err := encoder.AddObject("resource", logging.Resource(tCtx.resource))
+	err = errors.Join(err, encoder.AddObject("scope", logging.InstrumentationScope(tCtx.instrumentationScope)))
+	err = errors.Join(err, encoder.AddObject("profile", logging.Profile(tCtx.profile)))
+	err = errors.Join(err, encoder.AddObject("location", logging.ProfileLocation(tCtx.location)))
+	err = errors.Join(err, encoder.AddObject("cache", logging.Map(tCtx.cache)))
+	return err
+}
+
+type Option func(*ottl.Parser[TransformContext])
+
+func NewTransformContext(location pprofile.Location, profile pprofile.Profile, instrumentationScope pcommon.InstrumentationScope, resource pcommon.Resource, scopeProfiles pprofile.ScopeProfiles, resourceProfiles pprofile.ResourceProfiles) TransformContext {
+	return TransformContext{
+		location:             location,
+		profile:              profile,
+		instrumentationScope: instrumentationScope,
+		resource:             resource,
+		cache:                pcommon.NewMap(),
+		scopeProfiles:        scopeProfiles,
+		resourceProfiles:     resourceProfiles,
+	}
+}
+
+func (tCtx TransformContext) GetLocation() pprofile.Location {
+	return tCtx.location
+}
+
+func (tCtx TransformContext) GetProfile() pprofile.Profile {
+	return tCtx.profile
+}
+
+func (tCtx TransformContext) GetInstrumentationScope() pcommon.InstrumentationScope {
+	return tCtx.instrumentationScope
+}
+
+func (tCtx TransformContext) GetResource() pcommon.Resource {
+	return tCtx.resource
+}
+
+func (tCtx TransformContext) getCache() pcommon.Map {
+	return tCtx.cache
+}
+
+func (tCtx TransformContext) GetScopeSchemaURLItem() internal.SchemaURLItem {
+	return tCtx.scopeProfiles
+}
+
+func (tCtx TransformContext) GetResourceSchemaURLItem() internal.SchemaURLItem {
+	return tCtx.resourceProfiles
+}
+
+func NewParser(functions map[string]ottl.Factory[TransformContext], telemetrySettings component.TelemetrySettings, options ...Option) (ottl.Parser[TransformContext], error) {
+	pep := pathExpressionParser{telemetrySettings}
+	p, err := ottl.NewParser[TransformContext](
+		functions,
+		pep.parsePath,
+		telemetrySettings,
+		ottl.WithEnumParser[TransformContext](parseEnum),
+	)
+	if err != nil {
+		return ottl.Parser[TransformContext]{}, err
+	}
+	for _, opt := range options {
+		opt(&p)
+	}
+	return p, nil
+}
+
+type StatementSequenceOption func(*ottl.StatementSequence[TransformContext])
+
+func WithStatementSequenceErrorMode(errorMode ottl.ErrorMode) StatementSequenceOption {
+	return func(s *ottl.StatementSequence[TransformContext]) {
+		ottl.WithStatementSequenceErrorMode[TransformContext](errorMode)(s)
+	}
+}
+
+func NewStatementSequence(statements []*ottl.Statement[TransformContext], telemetrySettings component.TelemetrySettings, options ...StatementSequenceOption) ottl.StatementSequence[TransformContext] {
+	s := ottl.NewStatementSequence(statements, telemetrySettings)
+	for _, op := range options {
+		op(&s)
+	}
+	return s
+}
+
+type ConditionSequenceOption func(*ottl.ConditionSequence[TransformContext])
+
+func WithConditionSequenceErrorMode(errorMode ottl.ErrorMode) ConditionSequenceOption {
+	return func(c *ottl.ConditionSequence[TransformContext]) {
+		ottl.WithConditionSequenceErrorMode[TransformContext](errorMode)(c)
+	}
+}
+
+func NewConditionSequence(conditions []*ottl.Condition[TransformContext], telemetrySettings component.TelemetrySettings, options ...ConditionSequenceOption) ottl.ConditionSequence[TransformContext] {
+	c := ottl.NewConditionSequence(conditions, telemetrySettings)
+	for _, op := range options {
+		op(&c)
+	}
+	return c
+}
+
+func parseEnum(_ *ottl.EnumSymbol) (*ottl.Enum, error) {
+	return nil, fmt.Errorf("profile location context does not provide Enum support")
+}
+
+type pathExpressionParser struct {
+	telemetrySettings component.TelemetrySettings
+}
+
+func (pep *pathExpressionParser) parsePath(path ottl.Path[TransformContext]) (ottl.GetSetter[TransformContext], error) {
+	if path == nil {
+		return nil, fmt.Errorf("path cannot be nil")
+	}
+	switch path.Name() {
+	case "cache":
+		if path.Keys() == nil {
+			return accessCache(), nil
+		}
+		return accessCacheKey(path.Keys()), nil
+	case "resource":
+		return internal.ResourcePathGetSetter[TransformContext](path.Next())
+	case "instrumentation_scope":
+		return internal.ScopePathGetSetter[TransformContext](path.Next())
+	case "profile":
+		return internal.ProfilePathGetSetter[TransformContext](path.Next())
+	case "address":
+		return accessAddress(), nil
+	case "is_folded":
+		return accessIsFolded(), nil
+	case "mapping":
+		nextPath := path.Next()
+		if nextPath != nil && nextPath.Name() == "filename" {
+			return accessMappingFilename(), nil
+		}
+		return nil, internal.FormatDefaultErrorMessage(path.Name(), path.String(), contextName, internal.ProfileLocationRef)
+	case "function_name":
+		return accessFunctionName(), nil
+	case "filename":
+		return accessFilename(), nil
+	case "line":
+		return accessLine(), nil
+	case "stack_frames":
+		if path.Keys() == nil {
+			return accessStackFrames(), nil
+		}
+		return accessStackFramesKey(path.Keys()), nil
+	case "attributes":
+		if path.Keys() == nil {
+			return accessAttributes(), nil
+		}
+		return accessAttributesKey(path.Keys()), nil
+	default:
+		return nil, internal.FormatDefaultErrorMessage(path.Name(), path.String(), contextName, internal.ProfileLocationRef)
+	}
+}
+
+func accessCache() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			return tCtx.getCache(), nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			if m, ok := val.(pcommon.Map); ok {
+				m.CopyTo(tCtx.getCache())
+			}
+			return nil
+		},
+	}
+}
+
+func accessCacheKey(key []ottl.Key[TransformContext]) ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(ctx context.Context, tCtx TransformContext) (any, error) {
+			return internal.GetMapValue[TransformContext](ctx, tCtx, tCtx.getCache(), key)
+		},
+		Setter: func(ctx context.Context, tCtx TransformContext, val any) error {
+			return internal.SetMapValue[TransformContext](ctx, tCtx, tCtx.getCache(), key, val)
+		},
+	}
+}
+
+func accessAddress() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			return int64(tCtx.GetLocation().Address()), nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			if newAddress, ok := val.(int64); ok {
+				tCtx.GetLocation().SetAddress(uint64(newAddress))
+			}
+			return nil
+		},
+	}
+}
+
+func accessIsFolded() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			return tCtx.GetLocation().IsFolded(), nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			if newIsFolded, ok := val.(bool); ok {
+				tCtx.GetLocation().SetIsFolded(newIsFolded)
+			}
+			return nil
+		},
+	}
+}
+
+func mapping(tCtx TransformContext) (pprofile.Mapping, bool) {
+	location := tCtx.GetLocation()
+	if !location.HasMappingIndex() {
+		return pprofile.Mapping{}, false
+	}
+	idx := int(location.MappingIndex())
+	mappings := tCtx.GetProfile().MappingTable()
+	if idx < 0 || idx >= mappings.Len() {
+		return pprofile.Mapping{}, false
+	}
+	return mappings.At(idx), true
+}
+
+func accessMappingFilename() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			if m, ok := mapping(tCtx); ok {
+				return internal.ProfileString(tCtx.GetProfile(), m.FilenameStrindex()), nil
+			}
+			return nil, nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			str, ok := val.(string)
+			if !ok {
+				return nil
+			}
+			if m, ok := mapping(tCtx); ok {
+				m.SetFilenameStrindex(internal.PutProfileString(tCtx.GetProfile(), str))
+			}
+			return nil
+		},
+	}
+}
+
+// function returns the function of the outermost line of the location, which is the
+// function the location was not inlined into.
+func function(tCtx TransformContext) (pprofile.Line, pprofile.Function, bool) {
+	lines := tCtx.GetLocation().Line()
+	if lines.Len() == 0 {
+		return pprofile.Line{}, pprofile.Function{}, false
+	}
+	line := lines.At(lines.Len() - 1)
+	idx := int(line.FunctionIndex())
+	functions := tCtx.GetProfile().FunctionTable()
+	if idx < 0 || idx >= functions.Len() {
+		return line, pprofile.Function{}, false
+	}
+	return line, functions.At(idx), true
+}
+
+func accessFunctionName() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			if _, f, ok := function(tCtx); ok {
+				return internal.ProfileString(tCtx.GetProfile(), f.NameStrindex()), nil
+			}
+			return nil, nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			str, ok := val.(string)
+			if !ok {
+				return nil
+			}
+			if _, f, ok := function(tCtx); ok {
+				f.SetNameStrindex(internal.PutProfileString(tCtx.GetProfile(), str))
+			}
+			return nil
+		},
+	}
+}
+
+func accessFilename() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			if _, f, ok := function(tCtx); ok {
+				return internal.ProfileString(tCtx.GetProfile(), f.FilenameStrindex()), nil
+			}
+			return nil, nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			str, ok := val.(string)
+			if !ok {
+				return nil
+			}
+			if _, f, ok := function(tCtx); ok {
+				f.SetFilenameStrindex(internal.PutProfileString(tCtx.GetProfile(), str))
+			}
+			return nil
+		},
+	}
+}
+
+func accessLine() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			lines := tCtx.GetLocation().Line()
+			if lines.Len() == 0 {
+				return nil, nil
+			}
+			return lines.At(lines.Len() - 1).Line(), nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			lines := tCtx.GetLocation().Line()
+			if newLine, ok := val.(int64); ok && lines.Len() > 0 {
+				lines.At(lines.Len() - 1).SetLine(newLine)
+			}
+			return nil
+		},
+	}
+}
+
+var errStackFramesReadOnly = errors.New("stack_frames is read-only, use the function_name, filename and line paths to modify frames")
+
+func stackFrames(tCtx TransformContext) pcommon.Slice {
+	frames := pcommon.NewSlice()
+	internal.AppendLocationFrames(tCtx.GetProfile(), tCtx.GetLocation(), frames)
+	return frames
+}
+
+func accessStackFrames() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			return stackFrames(tCtx), nil
+		},
+		Setter: func(_ context.Context, _ TransformContext, _ any) error {
+			return errStackFramesReadOnly
+		},
+	}
+}
+
+func accessStackFramesKey(key []ottl.Key[TransformContext]) ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(ctx context.Context, tCtx TransformContext) (any, error) {
+			return internal.GetSliceValue[TransformContext](ctx, tCtx, stackFrames(tCtx), key)
+		},
+		Setter: func(_ context.Context, _ TransformContext, _ any) error {
+			return errStackFramesReadOnly
+		},
+	}
+}
+
+// Location attributes are stored as indices into the attribute table of the profile, so
+// the getters return a resolved copy and the setters write the result back to the table.
+func accessAttributes() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			return internal.ProfileAttributes(tCtx.GetProfile(), tCtx.GetLocation().AttributeIndices()), nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			if attrs, ok := val.(pcommon.Map); ok {
+				internal.SetProfileAttributes(tCtx.GetProfile(), tCtx.GetLocation().AttributeIndices(), attrs)
+			}
+			return nil
+		},
+	}
+}
+
+func accessAttributesKey(key []ottl.Key[TransformContext]) ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(ctx context.Context, tCtx TransformContext) (any, error) {
+			attrs := internal.ProfileAttributes(tCtx.GetProfile(), tCtx.GetLocation().AttributeIndices())
+			return internal.GetMapValue[TransformContext](ctx, tCtx, attrs, key)
+		},
+		Setter: func(ctx context.Context, tCtx TransformContext, val any) error {
+			attrs := internal.ProfileAttributes(tCtx.GetProfile(), tCtx.GetLocation().AttributeIndices())
+			if err := internal.SetMapValue[TransformContext](ctx, tCtx, attrs, key, val); err != nil {
+				return err
+			}
+			internal.SetProfileAttributes(tCtx.GetProfile(), tCtx.GetLocation().AttributeIndices(), attrs)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlprofilelocation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottltest"
+)
+
+func Test_newPathGetSetter(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     ottl.Path[TransformContext]
+		orig     any
+		newVal   any
+		modified func(location pprofile.Location, profile pprofile.Profile)
+	}{
+		{
+			name: "address",
+			path: &internal.TestPath[TransformContext]{
+				N: "address",
+			},
+			orig:   int64(0x1000),
+			newVal: int64(0),
+			modified: func(location pprofile.Location, _ pprofile.Profile) {
+				location.SetAddress(0)
+			},
+		},
+		{
+			name: "is_folded",
+			path: &internal.TestPath[TransformContext]{
+				N: "is_folded",
+			},
+			orig:   false,
+			newVal: true,
+			modified: func(location pprofile.Location, _ pprofile.Profile) {
+				location.SetIsFolded(true)
+			},
+		},
+		{
+			name: "mapping filename",
+			path: &internal.TestPath[TransformContext]{
+				N: "mapping",
+				NextPath: &internal.TestPath[TransformContext]{
+					N: "filename",
+				},
+			},
+			orig:   "/home/alice/bin/app",
+			newVal: "app",
+			modified: func(_ pprofile.Location, profile pprofile.Profile) {
+				profile.StringTable().Append("app")
+				profile.MappingTable().At(0).SetFilenameStrindex(5)
+			},
+		},
+		{
+			name: "function_name",
+			path: &internal.TestPath[TransformContext]{
+				N: "function_name",
+			},
+			orig:   "main.main",
+			newVal: "runtime.main",
+			modified: func(_ pprofile.Location, profile pprofile.Profile) {
+				profile.StringTable().Append("runtime.main")
+				profile.FunctionTable().At(1).SetNameStrindex(5)
+			},
+		},
+		{
+			name: "filename",
+			path: &internal.TestPath[TransformContext]{
+				N: "filename",
+			},
+			orig:   "/home/alice/src/main.go",
+			newVal: "main.go",
+			modified: func(_ pprofile.Location, profile pprofile.Profile) {
+				profile.StringTable().Append("main.go")
+				profile.FunctionTable().At(1).SetFilenameStrindex(5)
+			},
+		},
+		{
+			name: "line",
+			path: &internal.TestPath[TransformContext]{
+				N: "line",
+			},
+			orig:   int64(42),
+			newVal: int64(0),
+			modified: func(location pprofile.Location, _ pprofile.Profile) {
+				location.Line().At(1).SetLine(0)
+			},
+		},
+		{
+			name: "attributes key",
+			path: &internal.TestPath[TransformContext]{
+				N: "attributes",
+				KeySlice: []ottl.Key[TransformContext]{
+					&internal.TestKey[TransformContext]{
+						S: ottltest.Strp("frame.type"),
+					},
+				},
+			},
+			orig:   "go",
+			newVal: "native",
+			modified: func(location pprofile.Location, profile pprofile.Profile) {
+				attr := profile.AttributeTable().AppendEmpty()
+				attr.SetKey("frame.type")
+				attr.Value().SetStr("native")
+				location.AttributeIndices().FromRaw([]int32{1})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pep := pathExpressionParser{}
+			accessor, err := pep.parsePath(tt.path)
+			require.NoError(t, err)
+
+			location, profile := createTelemetry()
+
+			tCtx := NewTransformContext(location, profile, pcommon.NewInstrumentationScope(), pcommon.NewResource(), pprofile.NewScopeProfiles(), pprofile.NewResourceProfiles())
+			got, err := accessor.Get(context.Background(), tCtx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.orig, got)
+
+			err = accessor.Set(context.Background(), tCtx, tt.newVal)
+			require.NoError(t, err)
+
+			exLocation, exProfile := createTelemetry()
+			tt.modified(exLocation, exProfile)
+
+			assert.Equal(t, exLocation, location)
+			assert.Equal(t, exProfile, profile)
+		})
+	}
+}
+
+func Test_stackFrames(t *testing.T) {
+	location, profile := createTelemetry()
+	tCtx := NewTransformContext(location, profile, pcommon.NewInstrumentationScope(), pcommon.NewResource(), pprofile.NewScopeProfiles(), pprofile.NewResourceProfiles())
+
+	pep := pathExpressionParser{}
+	accessor, err := pep.parsePath(&internal.TestPath[TransformContext]{
+		N: "stack_frames",
+		KeySlice: []ottl.Key[TransformContext]{
+			&internal.TestKey[TransformContext]{I: ottltest.Intp(0)},
+			&internal.TestKey[TransformContext]{S: ottltest.Strp("function")},
+		},
+	})
+	require.NoError(t, err)
+
+	got, err := accessor.Get(context.Background(), tCtx)
+	require.NoError(t, err)
+	assert.Equal(t, "inlined", got)
+	assert.Error(t, accessor.Set(context.Background(), tCtx, "other"))
+}
+
+func Test_newPathGetSetter_Invalid(t *testing.T) {
+	pep := pathExpressionParser{}
+	_, err := pep.parsePath(&internal.TestPath[TransformContext]{N: "mapping"})
+	assert.ErrorContains(t, err, `segment "mapping" from path "mapping" is not a valid path`)
+}
+
+func createTelemetry() (pprofile.Location, pprofile.Profile) {
+	profile := pprofile.NewProfile()
+	profile.StringTable().FromRaw([]string{"", "/home/alice/bin/app", "inlined", "main.main", "/home/alice/src/main.go"})
+
+	attr := profile.AttributeTable().AppendEmpty()
+	attr.SetKey("frame.type")
+	attr.Value().SetStr("go")
+
+	profile.MappingTable().AppendEmpty().SetFilenameStrindex(1)
+
+	inlined := profile.FunctionTable().AppendEmpty()
+	inlined.SetNameStrindex(2)
+	inlined.SetFilenameStrindex(4)
+	mainFn := profile.FunctionTable().AppendEmpty()
+	mainFn.SetNameStrindex(3)
+	mainFn.SetFilenameStrindex(4)
+
+	location := profile.LocationTable().AppendEmpty()
+	location.SetMappingIndex(0)
+	location.SetAddress(0x1000)
+	location.AttributeIndices().FromRaw([]int32{0})
+	location.Line().AppendEmpty().SetFunctionIndex(0)
+	line := location.Line().AppendEmpty()
+	line.SetFunctionIndex(1)
+	line.SetLine(42)
+
+	return location, profile
+}
@@ -0,0 +1,320 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlprofilesample // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlprofilesample"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal/logging"
+)
+
+const (
+	contextName = "Profile Sample"
+)
+
+var (
+	_ internal.ResourceContext             = (*TransformContext)(nil)
+	_ internal.InstrumentationScopeContext = (*TransformContext)(nil)
+	_ internal.ProfileContext              = (*TransformContext)(nil)
+	_ zapcore.ObjectMarshaler              = (*TransformContext)(nil)
+)
+
+type TransformContext struct {
+	sample               pprofile.Sample
+	profile              pprofile.Profile
+	instrumentationScope pcommon.InstrumentationScope
+	resource             pcommon.Resource
+	cache                pcommon.Map
+	scopeProfiles        pprofile.ScopeProfiles
+	resourceProfiles     pprofile.ResourceProfiles
+}
+
+func (tCtx TransformContext) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	err := encoder.AddObject("resource", logging.Resource(tCtx.resource))
+	err = errors.Join(err, encoder.AddObject("scope", logging.InstrumentationScope(tCtx.instrumentationScope)))
+	err = errors.Join(err, encoder.AddObject("profile", logging.Profile(tCtx.profile)))
+	err = errors.Join(err, encoder.AddObject("sample", logging.ProfileSample(tCtx.sample)))
+	err = errors.Join(err, encoder.AddObject("cache", logging.Map(tCtx.cache)))
+	return err
+}
+
+type Option func(*ottl.Parser[TransformContext])
+
+func NewTransformContext(sample pprofile.Sample, profile pprofile.Profile, instrumentationScope pcommon.InstrumentationScope, resource pcommon.Resource, scopeProfiles pprofile.ScopeProfiles, resourceProfiles pprofile.ResourceProfiles) TransformContext {
+	return TransformContext{
+		sample:               sample,
+		profile:              profile,
+		instrumentationScope: instrumentationScope,
+		resource:             resource,
+		cache:                pcommon.NewMap(),
+		scopeProfiles:        scopeProfiles,
+		resourceProfiles:     resourceProfiles,
+	}
+}
+
+func (tCtx TransformContext) GetSample() pprofile.Sample {
+	return tCtx.sample
+}
+
+func (tCtx TransformContext) GetProfile() pprofile.Profile {
+	return tCtx.profile
+}
+
+func (tCtx TransformContext) GetInstrumentationScope() pcommon.InstrumentationScope {
+	return tCtx.instrumentationScope
+}
+
+func (tCtx TransformContext) GetResource() pcommon.Resource {
+	return tCtx.resource
+}
+
+func (tCtx TransformContext) getCache() pcommon.Map {
+	return tCtx.cache
+}
+
+func (tCtx TransformContext) GetScopeSchemaURLItem() internal.SchemaURLItem {
+	return tCtx.scopeProfiles
+}
+
+func (tCtx TransformContext) GetResourceSchemaURLItem() internal.SchemaURLItem {
+	return tCtx.resourceProfiles
+}
+
+func NewParser(functions map[string]ottl.Factory[TransformContext], telemetrySettings component.TelemetrySettings, options ...Option) (ottl.Parser[TransformContext], error) {
+	pep := pathExpressionParser{telemetrySettings}
+	p, err := ottl.NewParser[TransformContext](
+		functions,
+		pep.parsePath,
+		telemetrySettings,
+		ottl.WithEnumParser[TransformContext](parseEnum),
+	)
+	if err != nil {
+		return ottl.Parser[TransformContext]{}, err
+	}
+	for _, opt := range options {
+		opt(&p)
+	}
+	return p, nil
+}
+
+type StatementSequenceOption func(*ottl.StatementSequence[TransformContext])
+
+func WithStatementSequenceErrorMode(errorMode ottl.ErrorMode) StatementSequenceOption {
+	return func(s *ottl.StatementSequence[TransformContext]) {
+		ottl.WithStatementSequenceErrorMode[TransformContext](errorMode)(s)
+	}
+}
+
+func NewStatementSequence(statements []*ottl.Statement[TransformContext], telemetrySettings component.TelemetrySettings, options ...StatementSequenceOption) ottl.StatementSequence[TransformContext] {
+	s := ottl.NewStatementSequence(statements, telemetrySettings)
+	for _, op := range options {
+		op(&s)
+	}
+	return s
+}
+
+type ConditionSequenceOption func(*ottl.ConditionSequence[TransformContext])
+
+func WithConditionSequenceErrorMode(errorMode ottl.ErrorMode) ConditionSequenceOption {
+	return func(c *ottl.ConditionSequence[TransformContext]) {
+		ottl.WithConditionSequenceErrorMode[TransformContext](errorMode)(c)
+	}
+}
+
+func NewConditionSequence(conditions []*ottl.Condition[TransformContext], telemetrySettings component.TelemetrySettings, options ...ConditionSequenceOption) ottl.ConditionSequence[TransformContext] {
+	c := ottl.NewConditionSequence(conditions, telemetrySettings)
+	for _, op := range options {
+		op(&c)
+	}
+	return c
+}
+
+func parseEnum(_ *ottl.EnumSymbol) (*ottl.Enum, error) {
+	return nil, fmt.Errorf("profile sample context does not provide Enum support")
+}
+
+type pathExpressionParser struct {
+	telemetrySettings component.TelemetrySettings
+}
+
+func (pep *pathExpressionParser) parsePath(path ottl.Path[TransformContext]) (ottl.GetSetter[TransformContext], error) {
+	if path == nil {
+		return nil, fmt.Errorf("path cannot be nil")
+	}
+	switch path.Name() {
+	case "cache":
+		if path.Keys() == nil {
+			return accessCache(), nil
+		}
+		return accessCacheKey(path.Keys()), nil
+	case "resource":
+		return internal.ResourcePathGetSetter[TransformContext](path.Next())
+	case "instrumentation_scope":
+		return internal.ScopePathGetSetter[TransformContext](path.Next())
+	case "profile":
+		return internal.ProfilePathGetSetter[TransformContext](path.Next())
+	case "values":
+		return accessValues(), nil
+	case "timestamps_unix_nano":
+		return accessTimestampsUnixNano(), nil
+	case "attributes":
+		if path.Keys() == nil {
+			return accessAttributes(), nil
+		}
+		return accessAttributesKey(path.Keys()), nil
+	case "stack_frames":
+		if path.Keys() == nil {
+			return accessStackFrames(), nil
+		}
+		return accessStackFramesKey(path.Keys()), nil
+	default:
+		return nil, internal.FormatDefaultErrorMessage(path.Name(), path.String(), contextName, internal.ProfileSampleRef)
+	}
+}
+
+func accessCache() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			return tCtx.getCache(), nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			if m, ok := val.(pcommon.Map); ok {
+				m.CopyTo(tCtx.getCache())
+			}
+			return nil
+		},
+	}
+}
+
+func accessCacheKey(key []ottl.Key[TransformContext]) ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(ctx context.Context, tCtx TransformContext) (any, error) {
+			return internal.GetMapValue[TransformContext](ctx, tCtx, tCtx.getCache(), key)
+		},
+		Setter: func(ctx context.Context, tCtx TransformContext, val any) error {
+			return internal.SetMapValue[TransformContext](ctx, tCtx, tCtx.getCache(), key, val)
+		},
+	}
+}
+
+func accessValues() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			return tCtx.GetSample().Value().AsRaw(), nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			if newValues, ok := val.([]int64); ok {
+				tCtx.GetSample().Value().FromRaw(newValues)
+			}
+			return nil
+		},
+	}
+}
+
+func accessTimestampsUnixNano() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			timestamps := tCtx.GetSample().TimestampsUnixNano()
+			result := make([]int64, timestamps.Len())
+			for i := range result {
+				result[i] = int64(timestamps.At(i))
+			}
+			return result, nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			if newTimestamps, ok := val.([]int64); ok {
+				timestamps := make([]uint64, len(newTimestamps))
+				for i, ts := range newTimestamps {
+					timestamps[i] = uint64(ts)
+				}
+				tCtx.GetSample().TimestampsUnixNano().FromRaw(timestamps)
+			}
+			return nil
+		},
+	}
+}
+
+// Sample attributes are stored as indices into the attribute table of the profile, so
+// the getters return a resolved copy and the setters write the result back to the table.
+func accessAttributes() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			return internal.ProfileAttributes(tCtx.GetProfile(), tCtx.GetSample().AttributeIndices()), nil
+		},
+		Setter: func(_ context.Context, tCtx TransformContext, val any) error {
+			if attrs, ok := val.(pcommon.Map); ok {
+				internal.SetProfileAttributes(tCtx.GetProfile(), tCtx.GetSample().AttributeIndices(), attrs)
+			}
+			return nil
+		},
+	}
+}
+
+func accessAttributesKey(key []ottl.Key[TransformContext]) ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(ctx context.Context, tCtx TransformContext) (any, error) {
+			attrs := internal.ProfileAttributes(tCtx.GetProfile(), tCtx.GetSample().AttributeIndices())
+			return internal.GetMapValue[TransformContext](ctx, tCtx, attrs, key)
+		},
+		Setter: func(ctx context.Context, tCtx TransformContext, val any) error {
+			attrs := internal.ProfileAttributes(tCtx.GetProfile(), tCtx.GetSample().AttributeIndices())
+			if err := internal.SetMapValue[TransformContext](ctx, tCtx, attrs, key, val); err != nil {
+				return err
+			}
+			internal.SetProfileAttributes(tCtx.GetProfile(), tCtx.GetSample().AttributeIndices(), attrs)
+			return nil
+		},
+	}
+}
+
+// stackFrames resolves the locations of the sample into one frame per line, starting
+// with the leaf frame.
+func stackFrames(tCtx TransformContext) pcommon.Slice {
+	frames := pcommon.NewSlice()
+	profile := tCtx.GetProfile()
+	sample := tCtx.GetSample()
+	indices := profile.LocationIndices()
+	start := int(sample.LocationsStartIndex())
+	for i := start; i < start+int(sample.LocationsLength()) && i < indices.Len(); i++ {
+		idx := int(indices.At(i))
+		if idx < 0 || idx >= profile.LocationTable().Len() {
+			continue
+		}
+		internal.AppendLocationFrames(profile, profile.LocationTable().At(idx), frames)
+	}
+	return frames
+}
+
+var errStackFramesReadOnly = errors.New("stack_frames is read-only, use the profile location context to modify locations")
+
+func accessStackFrames() ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(_ context.Context, tCtx TransformContext) (any, error) {
+			return stackFrames(tCtx), nil
+		},
+		Setter: func(_ context.Context, _ TransformContext, _ any) error {
+			return errStackFramesReadOnly
+		},
+	}
+}
+
+func accessStackFramesKey(key []ottl.Key[TransformContext]) ottl.StandardGetSetter[TransformContext] {
+	return ottl.StandardGetSetter[TransformContext]{
+		Getter: func(ctx context.Context, tCtx TransformContext) (any, error) {
+			return internal.GetSliceValue[TransformContext](ctx, tCtx, stackFrames(tCtx), key)
+		},
+		Setter: func(_ context.Context, _ TransformContext, _ any) error {
+			return errStackFramesReadOnly
+		},
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlprofilesample
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pprofile"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/internal"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottltest"
+)
+
+func Test_newPathGetSetter(t *testing.T) {
+	newCache := pcommon.NewMap()
+	newCache.PutStr("temp", "value")
+
+	newAttrs := pcommon.NewMap()
+	newAttrs.PutStr("thread.name", "worker")
+
+	tests := []struct {
+		name     string
+		path     ottl.Path[TransformContext]
+		orig     any
+		newVal   any
+		modified func(sample pprofile.Sample, profile pprofile.Profile, cache pcommon.Map)
+	}{
+		{
+			name: "cache",
+			path: &internal.TestPath[TransformContext]{
+				N: "cache",
+			},
+			orig:   pcommon.NewMap(),
+			newVal: newCache,
+			modified: func(_ pprofile.Sample, _ pprofile.Profile, cache pcommon.Map) {
+				newCache.CopyTo(cache)
+			},
+		},
+		{
+			name: "values",
+			path: &internal.TestPath[TransformContext]{
+				N: "values",
+			},
+			orig:   []int64{10, 20},
+			newVal: []int64{0, 0},
+			modified: func(sample pprofile.Sample, _ pprofile.Profile, _ pcommon.Map) {
+				sample.Value().FromRaw([]int64{0, 0})
+			},
+		},
+		{
+			name: "timestamps_unix_nano",
+			path: &internal.TestPath[TransformContext]{
+				N: "timestamps_unix_nano",
+			},
+			orig:   []int64{100},
+			newVal: []int64{200, 300},
+			modified: func(sample pprofile.Sample, _ pprofile.Profile, _ pcommon.Map) {
+				sample.TimestampsUnixNano().FromRaw([]uint64{200, 300})
+			},
+		},
+		{
+			name: "attributes",
+			path: &internal.TestPath[TransformContext]{
+				N: "attributes",
+			},
+			orig: func() pcommon.Map {
+				m := pcommon.NewMap()
+				m.PutStr("thread.name", "main")
+				return m
+			}(),
+			newVal: newAttrs,
+			modified: func(sample pprofile.Sample, profile pprofile.Profile, _ pcommon.Map) {
+				attr := profile.AttributeTable().AppendEmpty()
+				attr.SetKey("thread.name")
+				attr.Value().SetStr("worker")
+				sample.AttributeIndices().FromRaw([]int32{1})
+			},
+		},
+		{
+			name: "attributes key",
+			path: &internal.TestPath[TransformContext]{
+				N: "attributes",
+				KeySlice: []ottl.Key[TransformContext]{
+					&internal.TestKey[TransformContext]{
+						S: ottltest.Strp("thread.id"),
+					},
+				},
+			},
+			orig:   nil,
+			newVal: int64(42),
+			modified: func(sample pprofile.Sample, profile pprofile.Profile, _ pcommon.Map) {
+				attr := profile.AttributeTable().AppendEmpty()
+				attr.SetKey("thread.id")
+				attr.Value().SetInt(42)
+				sample.AttributeIndices().FromRaw([]int32{0, 1})
+			},
+		},
+		{
+			name: "profile duration",
+			path: &internal.TestPath[TransformContext]{
+				N: "profile",
+				NextPath: &internal.TestPath[TransformContext]{
+					N: "duration",
+				},
+			},
+			orig:   10 * time.Second,
+			newVal: 5 * time.Second,
+			modified: func(_ pprofile.Sample, profile pprofile.Profile, _ pcommon.Map) {
+				profile.SetDuration(pcommon.Timestamp(5 * time.Second))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pep := pathExpressionParser{}
+			accessor, err := pep.parsePath(tt.path)
+			require.NoError(t, err)
+
+			sample, profile := createTelemetry()
+
+			tCtx := NewTransformContext(sample, profile, pcommon.NewInstrumentationScope(), pcommon.NewResource(), pprofile.NewScopeProfiles(), pprofile.NewResourceProfiles())
+			got, err := accessor.Get(context.Background(), tCtx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.orig, got)
+
+			err = accessor.Set(context.Background(), tCtx, tt.newVal)
+			require.NoError(t, err)
+
+			exSample, exProfile := createTelemetry()
+			exCache := pcommon.NewMap()
+			tt.modified(exSample, exProfile, exCache)
+
+			assert.Equal(t, exSample, sample)
+			assert.Equal(t, exProfile, profile)
+			assert.Equal(t, exCache, tCtx.getCache())
+		})
+	}
+}
+
+func Test_stackFrames(t *testing.T) {
+	sample, profile := createTelemetry()
+	tCtx := NewTransformContext(sample, profile, pcommon.NewInstrumentationScope(), pcommon.NewResource(), pprofile.NewScopeProfiles(), pprofile.NewResourceProfiles())
+
+	pep := pathExpressionParser{}
+	accessor, err := pep.parsePath(&internal.TestPath[TransformContext]{N: "stack_frames"})
+	require.NoError(t, err)
+
+	got, err := accessor.Get(context.Background(), tCtx)
+	require.NoError(t, err)
+	frames, ok := got.(pcommon.Slice)
+	require.True(t, ok)
+	require.Equal(t, 2, frames.Len())
+	function, _ := frames.At(0).Map().Get("function")
+	assert.Equal(t, "runtime.mallocgc", function.Str())
+	function, _ = frames.At(1).Map().Get("function")
+	assert.Equal(t, "main.main", function.Str())
+
+	assert.Error(t, accessor.Set(context.Background(), tCtx, pcommon.NewSlice()))
+
+	accessor, err = pep.parsePath(&internal.TestPath[TransformContext]{
+		N: "stack_frames",
+		KeySlice: []ottl.Key[TransformContext]{
+			&internal.TestKey[TransformContext]{I: ottltest.Intp(1)},
+			&internal.TestKey[TransformContext]{S: ottltest.Strp("filename")},
+		},
+	})
+	require.NoError(t, err)
+	got, err = accessor.Get(context.Background(), tCtx)
+	require.NoError(t, err)
+	assert.Equal(t, "main.go", got)
+}
+
+func Test_newPathGetSetter_Invalid(t *testing.T) {
+	pep := pathExpressionParser{}
+	_, err := pep.parsePath(&internal.TestPath[TransformContext]{N: "locations"})
+	assert.ErrorContains(t, err, `segment "locations" from path "locations" is not a valid path`)
+}
+
+func createTelemetry() (pprofile.Sample, pprofile.Profile) {
+	profile := pprofile.NewProfile()
+	profile.StringTable().FromRaw([]string{"", "runtime.mallocgc", "malloc.go", "main.main", "main.go"})
+	profile.SetDuration(pcommon.Timestamp(10 * time.Second))
+
+	attr := profile.AttributeTable().AppendEmpty()
+	attr.SetKey("thread.name")
+	attr.Value().SetStr("main")
+
+	mallocgc := profile.FunctionTable().AppendEmpty()
+	mallocgc.SetNameStrindex(1)
+	mallocgc.SetFilenameStrindex(2)
+	mainFn := profile.FunctionTable().AppendEmpty()
+	mainFn.SetNameStrindex(3)
+	mainFn.SetFilenameStrindex(4)
+
+	profile.LocationTable().AppendEmpty().Line().AppendEmpty().SetFunctionIndex(0)
+	line := profile.LocationTable().AppendEmpty().Line().AppendEmpty()
+	line.SetFunctionIndex(1)
+	line.SetLine(12)
+	profile.LocationIndices().FromRaw([]int32{0, 1})
+
+	sample := profile.Sample().AppendEmpty()
+	sample.SetLocationsStartIndex(0)
+	sample.SetLocationsLength(2)
+	sample.Value().FromRaw([]int64{10, 20})
+	sample.TimestampsUnixNano().FromRaw([]uint64{100})
+	sample.AttributeIndices().FromRaw([]int32{0})
+
+	return sample, profile
+}
@@ -774,6 +774,9 @@ func (p *Parser[K]) newGetter(val value) (Getter[K], error) {
 		if i := eL.Int; i != nil {
 			return &literal[K]{value: *i}, nil
 		}
+		if eL.Variable != nil {
+			return p.newVariableGetter(eL.Variable)
+		}
 		if eL.Path != nil {
 			np, err := p.newPath(eL.Path)
 			if err != nil {
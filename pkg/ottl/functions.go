@@ -481,6 +481,9 @@ func (p *Parser[K]) buildArg(argVal value, argType reflect.Type) (any, error) {
 	case strings.HasPrefix(name, "Setter"):
 		fallthrough
 	case strings.HasPrefix(name, "GetSetter"):
+		if argVal.Literal != nil && argVal.Literal.Variable != nil {
+			return p.newVariableGetSetter(argVal.Literal.Variable)
+		}
 		if argVal.Literal == nil || argVal.Literal.Path == nil {
 			return nil, fmt.Errorf("must be a path")
 		}
@@ -18,6 +18,7 @@ require (
 	go.opentelemetry.io/collector/component v0.116.0
 	go.opentelemetry.io/collector/component/componenttest v0.116.0
 	go.opentelemetry.io/collector/pdata v1.22.0
+	go.opentelemetry.io/collector/pdata/pprofile v0.116.0
 	go.opentelemetry.io/collector/semconv v0.116.0
 	go.opentelemetry.io/otel/trace v1.32.0
 	go.uber.org/goleak v1.3.0
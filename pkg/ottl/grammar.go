@@ -12,25 +12,144 @@ import (
 )
 
 // parsedStatement represents a parsed statement. It is the entry point into the statement DSL.
+// A statement is either a single editor invocation with an optional where clause, or a block
+// made of conditional blocks, variable assignments and nested statements.
 type parsedStatement struct {
-	Editor editor `parser:"(@@"`
+	Block  *statementBlock `parser:"( @@ |"`
+	Editor editor          `parser:"( @@"`
 	// If converter is matched then return error
-	Converter   *converter         `parser:"|@@)"`
-	WhereClause *booleanExpression `parser:"( 'where' @@ )?"`
+	Converter   *converter         `parser:"| @@ )"`
+	WhereClause *booleanExpression `parser:"( 'where' @@ )? )"`
 }
 
 func (p *parsedStatement) checkForCustomError() error {
 	validator := &grammarCustomErrorsVisitor{}
+	p.validate(validator)
+	return validator.join()
+}
+
+func (p *parsedStatement) validate(validator *grammarCustomErrorsVisitor) {
+	if p.Block != nil {
+		p.Block.validate(validator)
+		return
+	}
 	if p.Converter != nil {
 		validator.add(fmt.Errorf("editor names must start with a lowercase letter but got '%v'", p.Converter.Function))
 	}
+	p.accept(validator)
+}
 
-	p.Editor.accept(validator)
+func (p *parsedStatement) accept(v grammarVisitor) {
+	if p.Block != nil {
+		p.Block.accept(v)
+		return
+	}
+	p.Editor.accept(v)
 	if p.WhereClause != nil {
-		p.WhereClause.accept(validator)
+		p.WhereClause.accept(v)
 	}
+}
 
-	return validator.join()
+// statementBlock represents a sequence of conditional blocks, variable assignments and statements,
+// optionally separated by semicolons. It must start with a conditional block or a variable
+// assignment, so that single-line statements are always parsed as an editor and a where clause.
+type statementBlock struct {
+	If         *ifBlock     `parser:"( @@"`
+	Assignment *assignment  `parser:"| @@ )"`
+	Rest       []*blockItem `parser:"( ';'? @@ )* ';'?"`
+}
+
+// items returns all the items of the block, in order.
+func (b *statementBlock) items() []*blockItem {
+	items := make([]*blockItem, 0, len(b.Rest)+1)
+	items = append(items, &blockItem{If: b.If, Assignment: b.Assignment})
+	return append(items, b.Rest...)
+}
+
+func (b *statementBlock) validate(validator *grammarCustomErrorsVisitor) {
+	for _, item := range b.items() {
+		item.validate(validator)
+	}
+}
+
+func (b *statementBlock) accept(v grammarVisitor) {
+	for _, item := range b.items() {
+		item.accept(v)
+	}
+}
+
+// blockItem is a single element of a statementBlock or of the body of a conditional block.
+type blockItem struct {
+	If         *ifBlock         `parser:"( @@"`
+	Assignment *assignment      `parser:"| @@"`
+	Statement  *parsedStatement `parser:"| @@ )"`
+}
+
+func (b *blockItem) validate(validator *grammarCustomErrorsVisitor) {
+	switch {
+	case b.If != nil:
+		b.If.validate(validator)
+	case b.Assignment != nil:
+		b.Assignment.accept(validator)
+	case b.Statement != nil:
+		b.Statement.validate(validator)
+	}
+}
+
+func (b *blockItem) accept(v grammarVisitor) {
+	switch {
+	case b.If != nil:
+		b.If.accept(v)
+	case b.Assignment != nil:
+		b.Assignment.accept(v)
+	case b.Statement != nil:
+		b.Statement.accept(v)
+	}
+}
+
+// ifBlock represents a conditional block, with an optional else branch. An else branch
+// is either another conditional block (else if) or a list of items.
+type ifBlock struct {
+	Condition *booleanExpression `parser:"'if' @@"`
+	Body      []*blockItem       `parser:"'{' ( @@ ';'? )* '}'"`
+	ElseIf    *ifBlock           `parser:"( 'else' ( @@"`
+	Else      []*blockItem       `parser:"| '{' ( @@ ';'? )* '}' ) )?"`
+}
+
+func (b *ifBlock) validate(validator *grammarCustomErrorsVisitor) {
+	b.Condition.accept(validator)
+	for _, item := range b.Body {
+		item.validate(validator)
+	}
+	if b.ElseIf != nil {
+		b.ElseIf.validate(validator)
+	}
+	for _, item := range b.Else {
+		item.validate(validator)
+	}
+}
+
+func (b *ifBlock) accept(v grammarVisitor) {
+	b.Condition.accept(v)
+	for _, item := range b.Body {
+		item.accept(v)
+	}
+	if b.ElseIf != nil {
+		b.ElseIf.accept(v)
+	}
+	for _, item := range b.Else {
+		item.accept(v)
+	}
+}
+
+// assignment stores the result of a value into a statement-scoped variable.
+type assignment struct {
+	Variable string `parser:"@Variable Equal"`
+	Value    value  `parser:"@@"`
+}
+
+func (a *assignment) accept(v grammarVisitor) {
+	a.Value.accept(v)
 }
 
 type constExpr struct {
@@ -275,6 +394,12 @@ type field struct {
 	Keys []key  `parser:"( @@ )*"`
 }
 
+// variable represents a reference to a statement-scoped variable, optionally indexed.
+type variable struct {
+	Name string `parser:"@Variable"`
+	Keys []key  `parser:"( @@ )*"`
+}
+
 type key struct {
 	String *string `parser:"'[' (@String "`
 	Int    *int64  `parser:"| @Int) ']'"`
@@ -336,6 +461,7 @@ type mathExprLiteral struct {
 	Converter *converter `parser:"| @@"`
 	Float     *float64   `parser:"| @Float"`
 	Int       *int64     `parser:"| @Int"`
+	Variable  *variable  `parser:"| @@"`
 	Path      *path      `parser:"| @@ )"`
 }
 
@@ -486,7 +612,8 @@ func buildLexer() *lexer.StatefulDefinition {
 		{Name: `LBrace`, Pattern: `\{`},
 		{Name: `RBrace`, Pattern: `\}`},
 		{Name: `Colon`, Pattern: `\:`},
-		{Name: `Punct`, Pattern: `[,.\[\]]`},
+		{Name: `Punct`, Pattern: `[,.\[\];]`},
+		{Name: `Variable`, Pattern: `\$[a-zA-Z_][a-zA-Z0-9_]*`},
 		{Name: `Uppercase`, Pattern: `[A-Z][A-Z0-9_]*`},
 		{Name: `Lowercase`, Pattern: `[a-z][a-z0-9_]*`},
 		{Name: "whitespace", Pattern: `\s+`},
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+// LookupTable is a keyed table of rows that can be queried with the Lookup converter.
+type LookupTable interface {
+	// Lookup returns the row associated with the key, and false if the table has no such row.
+	Lookup(ctx context.Context, key string) (map[string]any, bool, error)
+}
+
+type LookupArguments[K any] struct {
+	Table string
+	Key   ottl.StringLikeGetter[K]
+}
+
+// NewLookupFactory returns the factory of the Lookup converter, which queries the given tables by name.
+// Lookup is not part of the standard functions, components owning lookup tables must add it explicitly.
+func NewLookupFactory[K any](tables map[string]LookupTable) ottl.Factory[K] {
+	return ottl.NewFactory("Lookup", &LookupArguments[K]{}, createLookupFunction[K](tables))
+}
+
+func createLookupFunction[K any](tables map[string]LookupTable) ottl.CreateFunctionFunc[K] {
+	return func(_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+		args, ok := oArgs.(*LookupArguments[K])
+
+		if !ok {
+			return nil, fmt.Errorf("LookupFactory args must be of type *LookupArguments[K]")
+		}
+
+		table, ok := tables[args.Table]
+		if !ok {
+			return nil, fmt.Errorf("unknown lookup table %q", args.Table)
+		}
+
+		return lookup(table, args.Key), nil
+	}
+}
+
+// lookup returns the row matching the key in the table as a pcommon.Map, or nil if the key is not found.
+func lookup[K any](table LookupTable, key ottl.StringLikeGetter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		keyVal, err := key.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		if keyVal == nil {
+			return nil, nil
+		}
+		row, found, err := table.Lookup(ctx, *keyVal)
+		if err != nil || !found {
+			return nil, err
+		}
+		result := pcommon.NewMap()
+		err = result.FromRaw(row)
+		return result, err
+	}
+}
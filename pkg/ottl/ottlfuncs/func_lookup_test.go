@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type testLookupTable map[string]map[string]any
+
+func (t testLookupTable) Lookup(_ context.Context, key string) (map[string]any, bool, error) {
+	if key == "error" {
+		return nil, false, errors.New("lookup failed")
+	}
+	row, ok := t[key]
+	return row, ok, nil
+}
+
+func Test_Lookup(t *testing.T) {
+	teams := testLookupTable{
+		"checkout": {"team": "payments", "tier": int64(1)},
+	}
+
+	tests := []struct {
+		name     string
+		key      any
+		expected any
+		err      string
+	}{
+		{
+			name: "found",
+			key:  "checkout",
+			expected: func() pcommon.Map {
+				m := pcommon.NewMap()
+				m.PutStr("team", "payments")
+				m.PutInt("tier", 1)
+				return m
+			}(),
+		},
+		{
+			name:     "not found",
+			key:      "cart",
+			expected: nil,
+		},
+		{
+			name:     "nil key",
+			key:      nil,
+			expected: nil,
+		},
+		{
+			name: "table error",
+			key:  "error",
+			err:  "lookup failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &ottl.StandardStringLikeGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.key, nil
+				},
+			}
+			result, err := lookup[any](teams, key)(context.Background(), nil)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_NewLookupFactory(t *testing.T) {
+	factory := NewLookupFactory[any](map[string]LookupTable{"teams": testLookupTable{}})
+	assert.Equal(t, "Lookup", factory.Name())
+
+	key := &ottl.StandardStringLikeGetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return "checkout", nil
+		},
+	}
+	_, err := factory.CreateFunction(ottl.FunctionContext{}, &LookupArguments[any]{Table: "teams", Key: key})
+	require.NoError(t, err)
+
+	_, err = factory.CreateFunction(ottl.FunctionContext{}, &LookupArguments[any]{Table: "owners", Key: key})
+	assert.EqualError(t, err, `unknown lookup table "owners"`)
+}
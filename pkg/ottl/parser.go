@@ -16,10 +16,13 @@ import (
 )
 
 // Statement holds a top level Statement for processing telemetry data. A Statement is a combination of a function
-// invocation and the boolean expression to match telemetry for invoking the function.
+// invocation and the boolean expression to match telemetry for invoking the function, or a block of conditional
+// blocks, variable assignments and nested statements.
 type Statement[K any] struct {
 	function          Expr[K]
 	condition         BoolExpr[K]
+	block             blockInstructions[K]
+	variableCount     int
 	origText          string
 	telemetrySettings component.TelemetrySettings
 }
@@ -28,7 +31,11 @@ type Statement[K any] struct {
 // Returns true if the function was run, returns false otherwise.
 // If the statement contains no condition, the function will run and true will be returned.
 // In addition, the functions return value is always returned.
+// For block statements, true is returned if at least one nested function was run, and the returned value is always nil.
 func (s *Statement[K]) Execute(ctx context.Context, tCtx K) (any, bool, error) {
+	if s.block != nil {
+		return s.executeBlock(ctx, tCtx)
+	}
 	condition, err := s.condition.Eval(ctx, tCtx)
 	defer func() {
 		if s.telemetrySettings.Logger != nil {
@@ -48,6 +55,15 @@ func (s *Statement[K]) Execute(ctx context.Context, tCtx K) (any, bool, error) {
 	return result, condition, nil
 }
 
+func (s *Statement[K]) executeBlock(ctx context.Context, tCtx K) (any, bool, error) {
+	ctx = context.WithValue(ctx, variablesKey{}, make(variableValues, s.variableCount))
+	executed, err := s.block.execute(ctx, tCtx)
+	if s.telemetrySettings.Logger != nil {
+		s.telemetrySettings.Logger.Debug("TransformContext after statement execution", zap.String("statement", s.origText), zap.Bool("function executed", executed), zap.Any("TransformContext", tCtx))
+	}
+	return nil, executed, err
+}
+
 // Condition holds a top level Condition. A Condition is a boolean expression to match telemetry.
 type Condition[K any] struct {
 	condition BoolExpr[K]
@@ -67,6 +83,8 @@ type Parser[K any] struct {
 	enumParser        EnumParser
 	telemetrySettings component.TelemetrySettings
 	pathContextNames  map[string]struct{}
+	// variables holds the variables of the statement block being parsed, if any.
+	variables *variableScope
 }
 
 func NewParser[K any](
@@ -147,6 +165,9 @@ func (p *Parser[K]) ParseStatement(statement string) (*Statement[K], error) {
 	if err != nil {
 		return nil, err
 	}
+	if parsed.Block != nil {
+		return p.newBlockStatement(statement, parsed.Block)
+	}
 	function, err := p.newFunctionCall(parsed.Editor)
 	if err != nil {
 		return nil, err
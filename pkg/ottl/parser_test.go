@@ -2832,6 +2832,13 @@ func Test_prependContextToStatementPaths_Success(t *testing.T) {
 			pathContextNames: []string{"log", "resource"},
 			expected:         `set(log.attributes["test"], "pass") where IsMatch(resource.name, "operation[AC]")`,
 		},
+		{
+			name:             "block statement paths without context",
+			statement:        `if name == "foo" { $v = attributes["foo"]; set(value, $v) } else { set(value, resource.name) }`,
+			context:          "span",
+			pathContextNames: []string{"span", "resource"},
+			expected:         `if span.name == "foo" { $v = span.attributes["foo"]; set(span.value, $v) } else { set(span.value, resource.name) }`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -18,10 +18,7 @@ func (v *grammarPathVisitor) visitPath(value *path) {
 
 func getParsedStatementPaths(ps *parsedStatement) []path {
 	visitor := &grammarPathVisitor{}
-	ps.Editor.accept(visitor)
-	if ps.WhereClause != nil {
-		ps.WhereClause.accept(visitor)
-	}
+	ps.accept(visitor)
 	return visitor.paths
 }
 
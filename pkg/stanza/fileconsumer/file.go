@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
 	"time"
 
@@ -204,6 +205,8 @@ func (m *Manager) makeFingerprint(path string) (*fingerprint.Fingerprint, *os.Fi
 // discarding any that have a duplicate fingerprint to other files that have already
 // been read this polling interval
 func (m *Manager) makeReaders(ctx context.Context, paths []string) {
+	var unmatchedFiles []*os.File
+	var unmatchedFingerprints []*fingerprint.Fingerprint
 	for _, path := range paths {
 		fp, file := m.makeFingerprint(path)
 		if fp == nil {
@@ -227,6 +230,31 @@ func (m *Manager) makeReaders(ctx context.Context, paths []string) {
 			m.set.Logger.Error("Failed to create reader", zap.Error(err))
 			continue
 		}
+		if r == nil {
+			// Not tracked in memory, look it up in the archive below.
+			if slices.ContainsFunc(unmatchedFingerprints, fp.Equal) {
+				m.set.Logger.Debug("Skipping duplicate file", zap.String("path", file.Name()))
+				if err := file.Close(); err != nil {
+					m.set.Logger.Debug("problem closing file", zap.Error(err))
+				}
+				continue
+			}
+			unmatchedFiles = append(unmatchedFiles, file)
+			unmatchedFingerprints = append(unmatchedFingerprints, fp)
+			continue
+		}
+
+		m.tracker.Add(r)
+	}
+
+	// The archive is read once per poll for all the files not tracked in memory.
+	archivedMetadata := m.tracker.FindFiles(unmatchedFingerprints)
+	for i, file := range unmatchedFiles {
+		r, err := m.newReaderFromArchive(ctx, file, unmatchedFingerprints[i], archivedMetadata[i])
+		if err != nil {
+			m.set.Logger.Error("Failed to create reader", zap.Error(err))
+			continue
+		}
 
 		m.tracker.Add(r)
 	}
@@ -261,9 +289,23 @@ func (m *Manager) newReader(ctx context.Context, file *os.File, fp *fingerprint.
 		return r, nil
 	}
 
-	// If we don't match any previously known files, create a new reader from scratch
-	m.set.Logger.Info("Started watching file", zap.String("path", file.Name()))
-	r, err := m.readerFactory.NewReader(file, fp)
+	// The file may still be known from the archive
+	return nil, nil
+}
+
+// newReaderFromArchive creates a reader for a file that is not tracked in memory, resuming
+// from its archived metadata if any.
+func (m *Manager) newReaderFromArchive(ctx context.Context, file *os.File, fp *fingerprint.Fingerprint, archived *reader.Metadata) (*reader.Reader, error) {
+	var r *reader.Reader
+	var err error
+	if archived != nil {
+		m.set.Logger.Debug("Resuming file from the archive", zap.String("path", file.Name()), zap.Int64("offset", archived.Offset))
+		r, err = m.readerFactory.NewReaderFromMetadata(file, archived)
+	} else {
+		// If we don't match any previously known files, create a new reader from scratch
+		m.set.Logger.Info("Started watching file", zap.String("path", file.Name()))
+		r, err = m.readerFactory.NewReader(file, fp)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@
 package fileconsumer
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"fmt"
@@ -15,6 +16,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/featuregate"
@@ -1582,6 +1584,169 @@ func TestReadGzipCompressedLogsFromEnd(t *testing.T) {
 	sink.ExpectToken(t, []byte("testlog4"))
 }
 
+const compressedTestContent = "testlog1\ntestlog2\n"
+
+// compressTestContent returns the content compressed with the given compression.
+func compressTestContent(t *testing.T, compression, content string) []byte {
+	var buf bytes.Buffer
+	switch compression {
+	case "gzip":
+		writer := gzip.NewWriter(&buf)
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+	case "zstd":
+		writer, err := zstd.NewWriter(&buf)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+	case "bzip2":
+		// The standard library has no bzip2 compressor
+		require.Equal(t, compressedTestContent, content, "only the testdata content is compressed with bzip2")
+		compressed, err := os.ReadFile(filepath.Join("testdata", "testlog.bz2"))
+		require.NoError(t, err)
+		buf.Write(compressed)
+	default:
+		buf.WriteString(content)
+	}
+	return buf.Bytes()
+}
+
+// TestReadCompressedLogs tests that compressed files are read with the configured compression,
+// or with the compression detected from the content of each file.
+func TestReadCompressedLogs(t *testing.T) {
+	t.Parallel()
+
+	for _, compression := range []string{"gzip", "zstd", "bzip2"} {
+		for _, configured := range []string{compression, "auto"} {
+			t.Run(compression+"_"+configured, func(t *testing.T) {
+				t.Parallel()
+
+				tempDir := t.TempDir()
+				cfg := NewConfig().includeDir(tempDir)
+				cfg.StartAt = "beginning"
+				cfg.Compression = configured
+				operator, sink := testManager(t, cfg)
+
+				temp := filetest.OpenTemp(t, tempDir)
+				_, err := temp.Write(compressTestContent(t, compression, compressedTestContent))
+				require.NoError(t, err)
+
+				operator.poll(context.Background())
+				sink.ExpectTokens(t, []byte("testlog1"), []byte("testlog2"))
+
+				// nothing is read again from an unchanged file
+				operator.poll(context.Background())
+				sink.ExpectNoCalls(t)
+			})
+		}
+	}
+}
+
+// TestReadAutoCompressedMixedLogs tests that, with auto compression, compressed and
+// uncompressed files in the same directory are all read.
+func TestReadAutoCompressedMixedLogs(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = "auto"
+	operator, sink := testManager(t, cfg)
+
+	files := map[string]string{
+		"":      "plainlog1\nplainlog2\n",
+		"gzip":  "gziplog1\ngziplog2\n",
+		"zstd":  "zstdlog1\nzstdlog2\n",
+		"bzip2": compressedTestContent,
+	}
+	for compression, content := range files {
+		temp := filetest.OpenTemp(t, tempDir)
+		_, err := temp.Write(compressTestContent(t, compression, content))
+		require.NoError(t, err)
+	}
+
+	operator.poll(context.Background())
+	sink.ExpectTokens(t,
+		[]byte("plainlog1"), []byte("plainlog2"),
+		[]byte("gziplog1"), []byte("gziplog2"),
+		[]byte("zstdlog1"), []byte("zstdlog2"),
+		[]byte("testlog1"), []byte("testlog2"),
+	)
+	sink.ExpectNoCalls(t)
+}
+
+// TestReadCompressedLogsAfterRestart tests that the offset of a compressed file is restored
+// after a restart, so that only the content appended in the meantime is read.
+func TestReadCompressedLogsAfterRestart(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = "zstd"
+	persister := testutil.NewUnscopedMockPersister()
+
+	temp := filetest.OpenTemp(t, tempDir)
+	appendToLog := func(t *testing.T, content string) {
+		writer, err := zstd.NewWriter(temp)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+	}
+	appendToLog(t, "testlog1\ntestlog2\n")
+
+	operator, sink := testManager(t, cfg)
+	require.NoError(t, operator.Start(persister))
+	sink.ExpectTokens(t, []byte("testlog1"), []byte("testlog2"))
+	require.NoError(t, operator.Stop())
+
+	appendToLog(t, "testlog3\n")
+
+	operator2, sink2 := testManager(t, cfg)
+	require.NoError(t, operator2.Start(persister))
+	sink2.ExpectToken(t, []byte("testlog3"))
+	sink2.ExpectNoCalls(t)
+	require.NoError(t, operator2.Stop())
+}
+
+// TestReadRotatedAndCompressedLogs tests that a file which is compressed after being rotated
+// is recognized by its decompressed content, so that only the logs not read yet are read from it.
+func TestReadRotatedAndCompressedLogs(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = "auto"
+	operator, sink := testManager(t, cfg)
+
+	temp := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, temp, "testlog1\n")
+
+	operator.poll(context.Background())
+	sink.ExpectToken(t, []byte("testlog1"))
+
+	filetest.WriteString(t, temp, "testlog2\n")
+	require.NoError(t, temp.Close())
+
+	// compress the rotated file and remove the original, as logrotate does
+	content, err := os.ReadFile(temp.Name())
+	require.NoError(t, err)
+	compressed := filetest.OpenFile(t, temp.Name()+".gz")
+	writer := gzip.NewWriter(compressed)
+	_, err = writer.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	require.NoError(t, os.Remove(temp.Name()))
+
+	operator.poll(context.Background())
+	sink.ExpectToken(t, []byte("testlog2"))
+	sink.ExpectNoCalls(t)
+}
+
 func TestIncludeFileRecordNumber(t *testing.T) {
 	t.Parallel()
 
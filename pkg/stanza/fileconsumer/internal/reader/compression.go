@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
+)
+
+// Supported values for the compression setting.
+const (
+	CompressionNone  = ""
+	CompressionGzip  = "gzip"
+	CompressionZstd  = "zstd"
+	CompressionBzip2 = "bzip2"
+	CompressionAuto  = "auto"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	zstdMagic  = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	bzip2Magic = []byte("BZh")
+)
+
+// detectCompression returns the compression of the file. Unless the compression is
+// "auto", this is the configured compression. Otherwise the compression is detected
+// from the magic bytes at the start of the file, and files that don't start with a
+// known magic number are read as is.
+func detectCompression(file *os.File, compression string) string {
+	if compression != CompressionAuto {
+		return compression
+	}
+
+	buf := make([]byte, len(zstdMagic))
+	n, _ := file.ReadAt(buf, 0)
+	buf = buf[:n]
+	switch {
+	case bytes.HasPrefix(buf, gzipMagic):
+		return CompressionGzip
+	case bytes.HasPrefix(buf, zstdMagic):
+		return CompressionZstd
+	case bytes.HasPrefix(buf, bzip2Magic):
+		return CompressionBzip2
+	default:
+		return CompressionNone
+	}
+}
+
+// newDecompressor returns a reader of the decompressed content of r.
+func newDecompressor(r io.Reader, compression string) (io.ReadCloser, error) {
+	switch compression {
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	case CompressionZstd:
+		d, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	case CompressionBzip2:
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
+}
+
+// openDecompressed returns a reader of the decompressed content of the file, positioned
+// offset bytes into the decompressed content.
+func openDecompressed(file *os.File, compression string, offset int64) (io.ReadCloser, error) {
+	dr, err := newDecompressor(io.NewSectionReader(file, 0, 1<<63-1), compression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.CopyN(io.Discard, dr, offset); err != nil {
+		_ = dr.Close()
+		return nil, fmt.Errorf("skip to offset %d: %w", offset, err)
+	}
+	return dr, nil
+}
+
+// decompressedSize returns the length of the decompressed content of the file.
+func decompressedSize(file *os.File, compression string) (int64, error) {
+	dr, err := openDecompressed(file, compression, 0)
+	if err != nil {
+		if isTruncated(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	defer dr.Close()
+
+	n, err := io.Copy(io.Discard, dr)
+	if err != nil && !isTruncated(err) {
+		return 0, err
+	}
+	return n, nil
+}
+
+// newFingerprint creates a fingerprint from the first bytes of the file content. The
+// fingerprint of a compressed file is taken from its decompressed content, so that a
+// file is recognized after it has been rotated and compressed.
+func newFingerprint(file *os.File, compression string, size int) (*fingerprint.Fingerprint, error) {
+	if compression == CompressionNone {
+		return fingerprint.NewFromFile(file, size)
+	}
+
+	dr, err := openDecompressed(file, compression, 0)
+	if err != nil {
+		if isTruncated(err) {
+			// The compressed file is still being written
+			return fingerprint.New([]byte{}), nil
+		}
+		return nil, fmt.Errorf("reading fingerprint bytes: %w", err)
+	}
+	defer dr.Close()
+
+	buf := make([]byte, size)
+	n, err := io.ReadFull(dr, buf)
+	if err != nil && !isTruncated(err) {
+		return nil, fmt.Errorf("reading fingerprint bytes: %w", err)
+	}
+	return fingerprint.New(buf[:n]), nil
+}
+
+// isTruncated returns true if the error indicates that the end of the compressed
+// content was reached, which is expected while a compressed file is being written.
+func isTruncated(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/filetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
+)
+
+func gzipCompress(t *testing.T, content string) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func zstdCompress(t *testing.T, content string) []byte {
+	var buf bytes.Buffer
+	writer, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+	_, err = writer.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestDetectCompression(t *testing.T) {
+	testCases := []struct {
+		name       string
+		configured string
+		content    []byte
+		expected   string
+	}{
+		{"gzip", CompressionAuto, gzipCompress(t, "testlog\n"), CompressionGzip},
+		{"zstd", CompressionAuto, zstdCompress(t, "testlog\n"), CompressionZstd},
+		{"bzip2", CompressionAuto, []byte("BZh91AY&SY"), CompressionBzip2},
+		{"plain", CompressionAuto, []byte("testlog\n"), CompressionNone},
+		{"short", CompressionAuto, []byte{0x1f}, CompressionNone},
+		{"empty", CompressionAuto, []byte{}, CompressionNone},
+		{"configured", CompressionGzip, []byte("testlog\n"), CompressionGzip},
+		{"disabled", CompressionNone, gzipCompress(t, "testlog\n"), CompressionNone},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			temp := filetest.OpenTemp(t, t.TempDir())
+			_, err := temp.Write(tc.content)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, detectCompression(temp, tc.configured))
+		})
+	}
+}
+
+func TestCompressedFingerprint(t *testing.T) {
+	temp := filetest.OpenTemp(t, t.TempDir())
+	compressed := zstdCompress(t, "testlog1\ntestlog2\n")
+
+	// A partially written frame does not have a fingerprint yet
+	_, err := temp.Write(compressed[:4])
+	require.NoError(t, err)
+	fp, err := newFingerprint(temp, CompressionZstd, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, fp.Len())
+
+	_, err = temp.Write(compressed[4:])
+	require.NoError(t, err)
+	fp, err = newFingerprint(temp, CompressionZstd, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 10, fp.Len())
+	assert.True(t, fp.Equal(fingerprint.New([]byte("testlog1\nt"))))
+
+	size, err := decompressedSize(temp, CompressionZstd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(18), size)
+
+	// A file that is not compressed can't be read as compressed
+	plain := filetest.OpenTemp(t, t.TempDir())
+	filetest.WriteString(t, plain, "testlog1\ntestlog2\n")
+	_, err = newFingerprint(plain, CompressionGzip, 10)
+	require.Error(t, err)
+}
@@ -48,7 +48,7 @@ type Factory struct {
 }
 
 func (f *Factory) NewFingerprint(file *os.File) (*fingerprint.Fingerprint, error) {
-	return fingerprint.NewFromFile(file, f.FingerprintSize)
+	return newFingerprint(file, detectCompression(file, f.Compression), f.FingerprintSize)
 }
 
 func (f *Factory) NewReader(file *os.File, fp *fingerprint.Fingerprint) (*Reader, error) {
@@ -75,14 +75,14 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		decoder:              decode.New(f.Encoding),
 		deleteAtEOF:          f.DeleteAtEOF,
 		includeFileRecordNum: f.IncludeFileRecordNumber,
-		compression:          f.Compression,
+		compression:          detectCompression(file, f.Compression),
 		acquireFSLock:        f.AcquireFSLock,
 	}
 	r.set.Logger = r.set.Logger.With(zap.String("path", r.fileName))
 
 	if r.Fingerprint.Len() > r.fingerprintSize {
 		// User has reconfigured fingerprint_size
-		shorter, rereadErr := fingerprint.NewFromFile(file, r.fingerprintSize)
+		shorter, rereadErr := newFingerprint(file, r.compression, r.fingerprintSize)
 		if rereadErr != nil {
 			return nil, fmt.Errorf("reread fingerprint: %w", rereadErr)
 		}
@@ -98,6 +98,12 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 			return nil, fmt.Errorf("stat: %w", err)
 		}
 		r.Offset = info.Size()
+		if r.compression != CompressionNone {
+			if r.Offset, err = decompressedSize(file, r.compression); err != nil {
+				return nil, fmt.Errorf("decompress: %w", err)
+			}
+			r.CompressedSize = info.Size()
+		}
 	}
 
 	flushFunc := m.FlushState.Func(f.SplitFunc, f.FlushTimeout)
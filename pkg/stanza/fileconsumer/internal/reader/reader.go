@@ -5,7 +5,6 @@ package reader // import "github.com/open-telemetry/opentelemetry-collector-cont
 
 import (
 	"bufio"
-	"compress/gzip"
 	"context"
 	"errors"
 	"io"
@@ -30,6 +29,9 @@ type Metadata struct {
 	FileAttributes  map[string]any
 	HeaderFinalized bool
 	FlushState      *flush.State
+	// CompressedSize is the size of a compressed file when it was last read to the end.
+	// The Offset of a compressed file counts decompressed bytes.
+	CompressedSize int64 `json:",omitempty"`
 }
 
 // Reader manages a single file
@@ -39,6 +41,7 @@ type Reader struct {
 	fileName               string
 	file                   *os.File
 	reader                 io.Reader
+	decompressor           io.ReadCloser
 	fingerprintSize        int
 	initialBufferSize      int
 	maxLogSize             int
@@ -63,41 +66,35 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		defer r.unlockFile()
 	}
 
-	switch r.compression {
-	case "gzip":
-		// We need to create a gzip reader each time ReadToEnd is called because the underlying
-		// SectionReader can only read a fixed window (from previous offset to EOF).
+	if r.compression != CompressionNone {
+		// A compressed file can't be read from an offset, so the decompressed content is
+		// read from the start each time ReadToEnd is called, unless nothing was appended.
+		// This is cheap for static archives, which are only read once.
 		info, err := r.file.Stat()
 		if err != nil {
 			r.set.Logger.Error("failed to stat", zap.Error(err))
 			return
 		}
-		currentEOF := info.Size()
-
-		// use a gzip Reader with an underlying SectionReader to pick up at the last
-		// offset of a gzip compressed file
-		gzipReader, err := gzip.NewReader(io.NewSectionReader(r.file, r.Offset, currentEOF))
-		if err != nil {
-			if !errors.Is(err, io.EOF) {
-				r.set.Logger.Error("failed to create gzip reader", zap.Error(err))
-			}
+		if info.Size() == r.CompressedSize {
 			return
-		} else {
-			r.reader = gzipReader
 		}
-		// Offset tracking in an uncompressed file is based on the length of emitted tokens, but in this case
-		// we need to set the offset to the end of the file.
 		defer func() {
-			r.Offset = currentEOF
+			if ctx.Err() == nil {
+				r.CompressedSize = info.Size()
+			}
 		}()
-	default:
-		r.reader = r.file
 	}
 
-	if _, err := r.file.Seek(r.Offset, 0); err != nil {
+	if err := r.seek(); err != nil {
+		if isTruncated(err) {
+			// The compressed file is still being written
+			r.set.Logger.Debug("compressed content ends before offset", zap.Error(err))
+			return
+		}
 		r.set.Logger.Error("failed to seek", zap.Error(err))
 		return
 	}
+	defer r.closeDecompressor()
 
 	defer func() {
 		if r.needsUpdateFingerprint {
@@ -167,7 +164,7 @@ func (r *Reader) readHeader(ctx context.Context) (doneReadingFile bool) {
 	r.initialBufferSize = scanner.DefaultBufferSize
 
 	// Reset position in file to r.Offest after the header scanner might have moved it past a content token.
-	if _, err := r.file.Seek(r.Offset, 0); err != nil {
+	if err := r.seek(); err != nil {
 		r.set.Logger.Error("failed to seek post-header", zap.Error(err))
 		return true
 	}
@@ -218,6 +215,35 @@ func (r *Reader) readContents(ctx context.Context) {
 	}
 }
 
+// seek positions the reader at r.Offset. The decompressed content of a compressed
+// file is skipped up to r.Offset.
+func (r *Reader) seek() error {
+	if r.compression == CompressionNone {
+		r.reader = r.file
+		_, err := r.file.Seek(r.Offset, 0)
+		return err
+	}
+
+	r.closeDecompressor()
+	dr, err := openDecompressed(r.file, r.compression, r.Offset)
+	if err != nil {
+		return err
+	}
+	r.decompressor = dr
+	r.reader = dr
+	return nil
+}
+
+func (r *Reader) closeDecompressor() {
+	if r.decompressor == nil {
+		return
+	}
+	if err := r.decompressor.Close(); err != nil {
+		r.set.Logger.Debug("Problem closing decompressor", zap.Error(err))
+	}
+	r.decompressor = nil
+}
+
 // Delete will close and delete the file
 func (r *Reader) delete() {
 	r.close()
@@ -271,7 +297,7 @@ func (r *Reader) Validate() bool {
 	if r.file == nil {
 		return false
 	}
-	refreshedFingerprint, err := fingerprint.NewFromFile(r.file, r.fingerprintSize)
+	refreshedFingerprint, err := newFingerprint(r.file, r.compression, r.fingerprintSize)
 	if err != nil {
 		return false
 	}
@@ -294,7 +320,7 @@ func (r *Reader) updateFingerprint() {
 	if r.file == nil {
 		return
 	}
-	refreshedFingerprint, err := fingerprint.NewFromFile(r.file, r.fingerprintSize)
+	refreshedFingerprint, err := newFingerprint(r.file, r.compression, r.fingerprintSize)
 	if err != nil {
 		return
 	}
@@ -5,6 +5,7 @@ package tracker // import "github.com/open-telemetry/opentelemetry-collector-con
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"go.opentelemetry.io/collector/component"
@@ -17,6 +18,8 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
 )
 
+const archiveIndexKey = "knownFilesArchiveIndex"
+
 // Interface for tracking files that are being consumed.
 type Tracker interface {
 	Add(reader *reader.Reader)
@@ -58,7 +61,7 @@ func NewFileTracker(set component.TelemetrySettings, maxBatchFiles int, pollsToA
 		knownFiles[i] = fileset.New[*reader.Metadata](maxBatchFiles)
 	}
 	set.Logger = set.Logger.With(zap.String("tracker", "fileTracker"))
-	return &fileTracker{
+	t := &fileTracker{
 		set:               set,
 		maxBatchFiles:     maxBatchFiles,
 		currentPollFiles:  fileset.New[*reader.Reader](maxBatchFiles),
@@ -68,6 +71,10 @@ func NewFileTracker(set component.TelemetrySettings, maxBatchFiles int, pollsToA
 		persister:         persister,
 		archiveIndex:      0,
 	}
+	if t.archiveEnabled() {
+		t.restoreArchiveIndex()
+	}
+	return t
 }
 
 func (t *fileTracker) Add(reader *reader.Reader) {
@@ -162,13 +169,54 @@ func (t *fileTracker) archive(metadata *fileset.Fileset[*reader.Metadata]) {
 	//                   start
 	//                   index
 
-	if t.pollsToArchive <= 0 || t.persister == nil {
+	// Polls that closed no files leave the archive untouched, so that it spans more history.
+	if !t.archiveEnabled() || metadata.Len() == 0 {
 		return
 	}
 	if err := t.writeArchive(t.archiveIndex, metadata); err != nil {
 		t.set.Logger.Error("error faced while saving to the archive", zap.Error(err))
 	}
 	t.archiveIndex = (t.archiveIndex + 1) % t.pollsToArchive // increment the index
+	if err := t.writeArchiveIndex(); err != nil {
+		t.set.Logger.Error("error faced while saving the archive index", zap.Error(err))
+	}
+}
+
+func (t *fileTracker) archiveEnabled() bool {
+	return t.pollsToArchive > 0 && t.persister != nil
+}
+
+// restoreArchiveIndex loads the index of the next archive set to write, so that the archive
+// keeps rolling over from where it was before a restart.
+func (t *fileTracker) restoreArchiveIndex() {
+	encoded, err := t.persister.Get(context.Background(), archiveIndexKey)
+	if err != nil {
+		t.set.Logger.Error("error while reading the archive index", zap.Error(err))
+		return
+	}
+	if encoded == nil {
+		return
+	}
+
+	var index int
+	if err = json.Unmarshal(encoded, &index); err != nil {
+		t.set.Logger.Error("error while decoding the archive index", zap.Error(err))
+		return
+	}
+	if index < 0 || index >= t.pollsToArchive {
+		// polls_to_archive was lowered, start over from the first archive set.
+		t.set.Logger.Warn("archive index out of range, restarting from the first archive set", zap.Int("index", index))
+		return
+	}
+	t.archiveIndex = index
+}
+
+func (t *fileTracker) writeArchiveIndex() error {
+	encoded, err := json.Marshal(t.archiveIndex)
+	if err != nil {
+		return err
+	}
+	return t.persister.Set(context.Background(), archiveIndexKey, encoded)
 }
 
 // readArchive loads data from the archive for a given index and returns a fileset.Filset.
@@ -200,6 +248,9 @@ func (t *fileTracker) FindFiles(fps []*fingerprint.Fingerprint) []*reader.Metada
 	// Determine the index for reading archive, starting from the most recent and moving towards the oldest
 	nextIndex := t.archiveIndex
 	matchedMetadata := make([]*reader.Metadata, len(fps))
+	if !t.archiveEnabled() || len(fps) == 0 {
+		return matchedMetadata
+	}
 
 	// continue executing the loop until either all records are matched or all archive sets have been processed.
 	for i := 0; i < t.pollsToArchive; i++ {
@@ -294,4 +345,6 @@ func (t *noStateTracker) EndPoll() {}
 
 func (t *noStateTracker) TotalReaders() int { return 0 }
 
-func (t *noStateTracker) FindFiles([]*fingerprint.Fingerprint) []*reader.Metadata { return nil }
+func (t *noStateTracker) FindFiles(fps []*fingerprint.Fingerprint) []*reader.Metadata {
+	return make([]*reader.Metadata, len(fps))
+}
@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/checkpoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fileset"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/operator"
@@ -44,6 +45,38 @@ func TestFindFilesOrder(t *testing.T) {
 	}
 }
 
+func TestArchiveIndexRestored(t *testing.T) {
+	persister := testutil.NewUnscopedMockPersister()
+	set := componenttest.NewNopTelemetrySettings()
+
+	tracker := NewFileTracker(set, 0, 3, persister).(*fileTracker)
+	for i := 0; i < 2; i++ {
+		knownFiles := fileset.New[*reader.Metadata](1)
+		knownFiles.Add(&reader.Metadata{Fingerprint: fingerprint.New([]byte(uuid.NewString()))})
+		tracker.archive(knownFiles)
+	}
+	// polls that closed no files are not archived
+	tracker.archive(fileset.New[*reader.Metadata](0))
+	require.Equal(t, 2, tracker.archiveIndex)
+
+	restored := NewFileTracker(set, 0, 3, persister).(*fileTracker)
+	require.Equal(t, 2, restored.archiveIndex)
+
+	// the index is out of range once polls_to_archive is lowered
+	lowered := NewFileTracker(set, 0, 2, persister).(*fileTracker)
+	require.Equal(t, 0, lowered.archiveIndex)
+}
+
+func TestFindFilesArchiveDisabled(t *testing.T) {
+	fps := []*fingerprint.Fingerprint{fingerprint.New([]byte(uuid.NewString()))}
+
+	tracker := NewFileTracker(componenttest.NewNopTelemetrySettings(), 0, 3, nil)
+	require.Equal(t, []*reader.Metadata{nil}, tracker.FindFiles(fps))
+
+	tracker = NewFileTracker(componenttest.NewNopTelemetrySettings(), 0, 0, testutil.NewUnscopedMockPersister())
+	require.Equal(t, []*reader.Metadata{nil}, tracker.FindFiles(fps))
+}
+
 func populatedPersisterData(persister operator.Persister, fps []*fingerprint.Fingerprint) []bool {
 	md := make([]*reader.Metadata, 0)
 
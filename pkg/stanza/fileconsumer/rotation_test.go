@@ -496,3 +496,112 @@ func TestFileMovedWhileOff_BigFiles(t *testing.T) {
 	sink2.ExpectTokens(t, log2, log3)
 	require.NoError(t, operator2.Stop())
 }
+
+// When a file leaves the matched set for longer than the known files are kept
+// in memory and comes back, its offset is found in the archive.
+func TestArchivedFileReappears(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("Moving files while open is unsupported on Windows")
+	}
+
+	testCases := []struct {
+		name           string
+		pollsToArchive int
+		expected       [][]byte
+	}{
+		{
+			name:           "archive_enabled",
+			pollsToArchive: 10,
+			expected:       [][]byte{[]byte("testlog2")},
+		},
+		{
+			name:           "archive_disabled",
+			pollsToArchive: 0,
+			expected:       [][]byte{[]byte("testlog1"), []byte("testlog2")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			cfg := NewConfig()
+			cfg.Include = append(cfg.Include, fmt.Sprintf("%s/*.log", tempDir))
+			cfg.StartAt = "beginning"
+			cfg.PollsToArchive = tc.pollsToArchive
+			operator, sink := testManager(t, cfg)
+			operator.persister = testutil.NewUnscopedMockPersister()
+
+			file := filetest.OpenTempWithPattern(t, tempDir, "*.log")
+			fileName := file.Name()
+			filetest.WriteString(t, file, "testlog1\n")
+			require.NoError(t, file.Close())
+
+			operator.poll(context.Background())
+			sink.ExpectToken(t, []byte("testlog1"))
+			operator.wg.Wait()
+
+			// move the file out of the pattern until it is only known from the archive
+			require.NoError(t, os.Rename(fileName, fileName+".away"))
+			for i := 0; i < 5; i++ {
+				operator.poll(context.Background())
+			}
+			sink.ExpectNoCalls(t)
+
+			// move it back with more content
+			file, err := os.OpenFile(fileName+".away", os.O_APPEND|os.O_WRONLY, 0o600)
+			require.NoError(t, err)
+			filetest.WriteString(t, file, "testlog2\n")
+			require.NoError(t, file.Close())
+			require.NoError(t, os.Rename(fileName+".away", fileName))
+
+			operator.poll(context.Background())
+			sink.ExpectTokens(t, tc.expected...)
+			sink.ExpectNoCalls(t)
+		})
+	}
+}
+
+// The archive is kept in the storage, so a file that comes back after a restart
+// is resumed from its archived offset.
+func TestArchivedFileReappearsAfterRestart(t *testing.T) {
+	if runtime.GOOS == windowsOS {
+		t.Skip("Moving files while open is unsupported on Windows")
+	}
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig()
+	cfg.Include = append(cfg.Include, fmt.Sprintf("%s/*.log", tempDir))
+	cfg.StartAt = "beginning"
+	cfg.PollInterval = 10 * time.Millisecond
+	cfg.PollsToArchive = 10
+	persister := testutil.NewUnscopedMockPersister()
+
+	file := filetest.OpenTempWithPattern(t, tempDir, "*.log")
+	fileName := file.Name()
+	filetest.WriteString(t, file, "testlog1\n")
+	require.NoError(t, file.Close())
+
+	operator, sink := testManager(t, cfg)
+	require.NoError(t, operator.Start(persister))
+	sink.ExpectToken(t, []byte("testlog1"))
+
+	// move the file out of the pattern for more polls than the known files are kept in memory
+	require.NoError(t, os.Rename(fileName, fileName+".away"))
+	time.Sleep(20 * cfg.PollInterval)
+	require.NoError(t, operator.Stop())
+
+	file, err := os.OpenFile(fileName+".away", os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	filetest.WriteString(t, file, "testlog2\n")
+	require.NoError(t, file.Close())
+	require.NoError(t, os.Rename(fileName+".away", fileName))
+
+	operator2, sink2 := testManager(t, cfg)
+	require.NoError(t, operator2.Start(persister))
+	sink2.ExpectToken(t, []byte("testlog2"))
+	sink2.ExpectNoCalls(t)
+	require.NoError(t, operator2.Stop())
+}
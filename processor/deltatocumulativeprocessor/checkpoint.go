@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deltatocumulativeprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/deltatocumulativeprocessor/internal/metrics"
+)
+
+// checkpointKey is the storage key holding the last checkpoint of the state.
+const checkpointKey = "state"
+
+// origin holds what is needed to rebuild the identity of the streams of a metric:
+// the resource, the scope and the metric without its datapoints.
+type origin struct {
+	res    pcommon.Resource
+	scope  pcommon.InstrumentationScope
+	metric pmetric.Metric
+}
+
+func newOrigin(m metrics.Metric) origin {
+	o := origin{
+		res:    pcommon.NewResource(),
+		scope:  pcommon.NewInstrumentationScope(),
+		metric: pmetric.NewMetric(),
+	}
+	m.Resource().CopyTo(o.res)
+	m.Scope().CopyTo(o.scope)
+
+	o.metric.SetName(m.Name())
+	o.metric.SetUnit(m.Unit())
+	o.metric.SetDescription(m.Description())
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		sum := o.metric.SetEmptySum()
+		sum.SetIsMonotonic(m.Sum().IsMonotonic())
+		sum.SetAggregationTemporality(m.Sum().AggregationTemporality())
+	case pmetric.MetricTypeHistogram:
+		o.metric.SetEmptyHistogram().SetAggregationTemporality(m.Histogram().AggregationTemporality())
+	case pmetric.MetricTypeExponentialHistogram:
+		o.metric.SetEmptyExponentialHistogram().SetAggregationTemporality(m.ExponentialHistogram().AggregationTemporality())
+	}
+	return o
+}
+
+// checkpoint writes the state into a pmetric.Metrics holding one metric per tracked metric identity,
+// with the accumulated datapoints of its streams. The identities are recomputed from it on restore.
+func (p *Processor) checkpoint() pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	byMetric := make(map[identity.Metric]pmetric.Metric, len(p.last.origins))
+	into := func(id identity.Stream) pmetric.Metric {
+		if m, ok := byMetric[id.Metric()]; ok {
+			return m
+		}
+		o := p.last.origins[id.Metric()]
+		rm := md.ResourceMetrics().AppendEmpty()
+		o.res.CopyTo(rm.Resource())
+		sm := rm.ScopeMetrics().AppendEmpty()
+		o.scope.CopyTo(sm.Scope())
+		m := sm.Metrics().AppendEmpty()
+		o.metric.CopyTo(m)
+		byMetric[id.Metric()] = m
+		return m
+	}
+
+	for id, dp := range p.last.nums {
+		dp.CopyTo(into(id).Sum().DataPoints().AppendEmpty())
+	}
+	for id, dp := range p.last.hist {
+		dp.CopyTo(into(id).Histogram().DataPoints().AppendEmpty())
+	}
+	for id, dp := range p.last.expo {
+		dp.CopyTo(into(id).ExponentialHistogram().DataPoints().AppendEmpty())
+	}
+
+	// forget the metrics whose streams were all removed since the last checkpoint
+	for id := range p.last.origins {
+		if _, ok := byMetric[id]; !ok {
+			delete(p.last.origins, id)
+		}
+	}
+	return md
+}
+
+// restore loads the streams of a checkpoint into the state. The restored streams are tracked
+// for staleness as if they just received a sample, and the streams exceeding max_streams are dropped.
+func (p *Processor) restore(md pmetric.Metrics, now time.Time) int {
+	restored := 0
+	begin := func(m metrics.Metric, id identity.Stream, dp any) {
+		if p.last.Has(id) || p.last.Len() >= p.cfg.MaxStreams {
+			return
+		}
+		p.last.BeginWith(id, dp)
+		p.last.Track(id, m)
+		p.stale.Refresh(now, id)
+		restored++
+	}
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sm := sms.At(j)
+			ms := sm.Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				m := metrics.From(rm.Resource(), sm.Scope(), ms.At(k))
+				id := m.Ident()
+				switch m.Type() {
+				case pmetric.MetricTypeSum:
+					dps := m.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						begin(m, identity.OfStream(id, dps.At(l)), dps.At(l))
+					}
+				case pmetric.MetricTypeHistogram:
+					dps := m.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						begin(m, identity.OfStream(id, dps.At(l)), dps.At(l))
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					dps := m.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						begin(m, identity.OfStream(id, dps.At(l)), dps.At(l))
+					}
+				}
+			}
+		}
+	}
+	return restored
+}
+
+// saveCheckpoint writes the current state to the storage.
+func (p *Processor) saveCheckpoint(ctx context.Context) error {
+	p.mtx.Lock()
+	md := p.checkpoint()
+	p.mtx.Unlock()
+
+	data, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(md)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the state: %w", err)
+	}
+	return p.storage.Set(ctx, checkpointKey, data)
+}
+
+// loadCheckpoint restores the state from the last checkpoint written to the storage, if any.
+func (p *Processor) loadCheckpoint(ctx context.Context) error {
+	data, err := p.storage.Get(ctx, checkpointKey)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return nil
+	}
+
+	md, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal the state: %w", err)
+	}
+
+	p.mtx.Lock()
+	restored := p.restore(md, time.Now())
+	p.mtx.Unlock()
+
+	p.logger.Debug("restored state from checkpoint", zap.Int("streams", restored))
+	return nil
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID component.ID, componentID component.ID) (storage.Client, error) {
+	ext, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExt.GetClient(ctx, component.KindProcessor, componentID, "")
+}
@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package deltatocumulativeprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+)
+
+var epoch = time.Unix(1700000000, 0)
+
+func ts(sec int) pcommon.Timestamp {
+	return pcommon.NewTimestampFromTime(epoch.Add(time.Duration(sec) * time.Second))
+}
+
+// deltas returns a delta sum and a delta histogram, each with two streams, covering the given time range.
+func deltas(start, end int, value int64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("test")
+
+	sum := sm.Metrics().AppendEmpty()
+	sum.SetName("requests")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	for _, code := range []string{"200", "500"} {
+		dp := sum.Sum().DataPoints().AppendEmpty()
+		dp.Attributes().PutStr("code", code)
+		dp.SetStartTimestamp(ts(start))
+		dp.SetTimestamp(ts(end))
+		dp.SetIntValue(value)
+	}
+
+	hist := sm.Metrics().AppendEmpty()
+	hist.SetName("latency")
+	hist.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dp := hist.Histogram().DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(ts(start))
+	dp.SetTimestamp(ts(end))
+	dp.SetCount(uint64(value))
+	dp.SetSum(float64(value))
+	dp.ExplicitBounds().FromRaw([]float64{1})
+	dp.BucketCounts().FromRaw([]uint64{uint64(value), 0})
+	return md
+}
+
+func newCheckpointTest(t *testing.T, storageID component.ID) (*Processor, *consumertest.MetricsSink) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Storage = &storageID
+	cfg.CheckpointInterval = time.Hour
+
+	sink := new(consumertest.MetricsSink)
+	tt := setupTestTelemetry()
+	proc, err := NewFactory().CreateMetrics(context.Background(), tt.NewSettings(), cfg, sink)
+	require.NoError(t, err)
+	return proc.(*Processor), sink
+}
+
+func TestCheckpointRestore(t *testing.T) {
+	ctx := context.Background()
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("test", t.TempDir())
+	storageID := storagetest.NewStorageID("test")
+
+	proc, _ := newCheckpointTest(t, storageID)
+	require.NoError(t, proc.Start(ctx, host))
+	require.NoError(t, proc.ConsumeMetrics(ctx, deltas(0, 10, 1)))
+	require.NoError(t, proc.ConsumeMetrics(ctx, deltas(10, 20, 2)))
+	require.NoError(t, proc.Shutdown(ctx))
+
+	proc, sink := newCheckpointTest(t, storageID)
+	require.NoError(t, proc.Start(ctx, host))
+	defer func() { require.NoError(t, proc.Shutdown(ctx)) }()
+	assert.Equal(t, 3, proc.last.Len())
+
+	require.NoError(t, proc.ConsumeMetrics(ctx, deltas(20, 30, 4)))
+	require.Len(t, sink.AllMetrics(), 1)
+	metrics := sink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+
+	sum := metrics.At(0).Sum()
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, sum.AggregationTemporality())
+	for i := 0; i < sum.DataPoints().Len(); i++ {
+		dp := sum.DataPoints().At(i)
+		assert.Equal(t, int64(7), dp.IntValue())
+		assert.Equal(t, ts(0), dp.StartTimestamp())
+		assert.Equal(t, ts(30), dp.Timestamp())
+	}
+
+	hist := metrics.At(1).Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(7), hist.Count())
+	assert.Equal(t, []uint64{7, 0}, hist.BucketCounts().AsRaw())
+	assert.Equal(t, ts(0), hist.StartTimestamp())
+}
+
+func TestCheckpointRestoreMaxStreams(t *testing.T) {
+	ctx := context.Background()
+	host := storagetest.NewStorageHost().WithInMemoryStorageExtension("test")
+	storageID := storagetest.NewStorageID("test")
+
+	proc, _ := newCheckpointTest(t, storageID)
+	require.NoError(t, proc.Start(ctx, host))
+	require.NoError(t, proc.ConsumeMetrics(ctx, deltas(0, 10, 1)))
+	md := proc.checkpoint()
+	require.NoError(t, proc.Shutdown(ctx))
+
+	proc, _ = newCheckpointTest(t, storageID)
+	proc.cfg.MaxStreams = 2
+	assert.Equal(t, 2, proc.restore(md, time.Now()))
+	assert.Equal(t, 2, proc.last.Len())
+}
+
+func TestCheckpointForgetsRemovedStreams(t *testing.T) {
+	ctx := context.Background()
+	host := storagetest.NewStorageHost().WithInMemoryStorageExtension("test")
+
+	proc, _ := newCheckpointTest(t, storagetest.NewStorageID("test"))
+	require.NoError(t, proc.Start(ctx, host))
+	defer func() { require.NoError(t, proc.Shutdown(ctx)) }()
+	require.NoError(t, proc.ConsumeMetrics(ctx, deltas(0, 10, 1)))
+	assert.Len(t, proc.last.origins, 2)
+
+	for id := range proc.last.hist {
+		proc.last.Delete(id)
+	}
+	md := proc.checkpoint()
+	assert.Equal(t, 1, md.MetricCount())
+	assert.Len(t, proc.last.origins, 1)
+}
+
+func TestCheckpointStorageErrors(t *testing.T) {
+	ctx := context.Background()
+
+	proc, _ := newCheckpointTest(t, storagetest.NewStorageID("missing"))
+	assert.ErrorContains(t, proc.Start(ctx, storagetest.NewStorageHost()), "storage extension 'test_storage/missing' not found")
+
+	proc, _ = newCheckpointTest(t, storagetest.NewNonStorageID("test"))
+	host := storagetest.NewStorageHost().WithNonStorageExtension("test")
+	assert.ErrorContains(t, proc.Start(ctx, host), "non-storage extension 'non_storage/test' found")
+}
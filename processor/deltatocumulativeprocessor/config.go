@@ -19,6 +19,13 @@ var _ component.ConfigValidator = (*Config)(nil)
 type Config struct {
 	MaxStale   time.Duration `mapstructure:"max_stale"`
 	MaxStreams int           `mapstructure:"max_streams"`
+
+	// Storage is the ID of a storage extension the accumulated state is checkpointed to,
+	// so that streams resume from their last value after a restart instead of starting over.
+	Storage *component.ID `mapstructure:"storage"`
+	// CheckpointInterval is how often the state is checkpointed to the storage extension.
+	// The state is also checkpointed on shutdown.
+	CheckpointInterval time.Duration `mapstructure:"checkpoint_interval"`
 }
 
 func (c *Config) Validate() error {
@@ -28,6 +35,9 @@ func (c *Config) Validate() error {
 	if c.MaxStreams < 0 {
 		return fmt.Errorf("max_streams must be a positive number (got %d)", c.MaxStreams)
 	}
+	if c.Storage != nil && c.CheckpointInterval <= 0 {
+		return fmt.Errorf("checkpoint_interval must be a positive duration (got %s)", c.CheckpointInterval)
+	}
 	return nil
 }
 
@@ -38,6 +48,8 @@ func createDefaultConfig() component.Config {
 		// TODO: find good default
 		// https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/31603
 		MaxStreams: math.MaxInt,
+
+		CheckpointInterval: 30 * time.Second,
 	}
 }
 
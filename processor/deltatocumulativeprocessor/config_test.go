@@ -23,6 +23,7 @@ func TestLoadConfig(t *testing.T) {
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
 	require.NoError(t, err)
 
+	storageID := component.MustNewIDWithName("file_storage", "dtc")
 	tests := []struct {
 		id       component.ID
 		expected component.Config
@@ -32,6 +33,8 @@ func TestLoadConfig(t *testing.T) {
 			expected: &Config{
 				MaxStale:   1 * time.Minute,
 				MaxStreams: 10,
+
+				CheckpointInterval: 30 * time.Second,
 			},
 		},
 		{
@@ -39,6 +42,8 @@ func TestLoadConfig(t *testing.T) {
 			expected: &Config{
 				MaxStale:   2 * time.Minute,
 				MaxStreams: math.MaxInt,
+
+				CheckpointInterval: 30 * time.Second,
 			},
 		},
 		{
@@ -46,6 +51,18 @@ func TestLoadConfig(t *testing.T) {
 			expected: &Config{
 				MaxStale:   5 * time.Minute,
 				MaxStreams: 20,
+
+				CheckpointInterval: 30 * time.Second,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "storage"),
+			expected: &Config{
+				MaxStale:   5 * time.Minute,
+				MaxStreams: math.MaxInt,
+
+				Storage:            &storageID,
+				CheckpointInterval: 10 * time.Second,
 			},
 		},
 	}
@@ -64,3 +81,11 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	storageID := component.MustNewID("file_storage")
+	cfg := createDefaultConfig().(*Config)
+	cfg.Storage = &storageID
+	cfg.CheckpointInterval = 0
+	assert.EqualError(t, cfg.Validate(), "checkpoint_interval must be a positive duration (got 0s)")
+}
@@ -5,13 +5,17 @@ package deltatocumulativeprocessor // import "github.com/open-telemetry/opentele
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/processor"
+	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/identity"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/exp/metrics/staleness"
@@ -23,8 +27,10 @@ import (
 var _ processor.Metrics = (*Processor)(nil)
 
 type Processor struct {
-	next consumer.Metrics
-	cfg  Config
+	id     component.ID
+	logger *zap.Logger
+	next   consumer.Metrics
+	cfg    Config
 
 	last state
 	mtx  sync.Mutex
@@ -34,14 +40,19 @@ type Processor struct {
 
 	stale staleness.Tracker
 	tel   telemetry.Metrics
+
+	// storage is the client of the storage extension the state is checkpointed to, if configured.
+	storage storage.Client
 }
 
-func newProcessor(cfg *Config, tel telemetry.Metrics, next consumer.Metrics) *Processor {
+func newProcessor(cfg *Config, set processor.Settings, tel telemetry.Metrics, next consumer.Metrics) *Processor {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	proc := Processor{
-		next: next,
-		cfg:  *cfg,
+		id:     set.ID,
+		logger: set.Logger,
+		next:   next,
+		cfg:    *cfg,
 		last: state{
 			nums: make(map[identity.Stream]pmetric.NumberDataPoint),
 			hist: make(map[identity.Stream]pmetric.HistogramDataPoint),
@@ -54,6 +65,10 @@ func newProcessor(cfg *Config, tel telemetry.Metrics, next consumer.Metrics) *Pr
 		tel:   tel,
 	}
 
+	if cfg.Storage != nil {
+		proc.last.origins = make(map[identity.Metric]origin)
+	}
+
 	tel.WithTracked(proc.last.Len)
 	cfg.Metrics(tel)
 
@@ -99,6 +114,9 @@ func (p *Processor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) erro
 			// aggregate with, so clone this value into the state and done
 			if !exist {
 				p.last.BeginWith(id, dp)
+				if p.cfg.Storage != nil {
+					p.last.Track(id, m)
+				}
 				return keep
 			}
 
@@ -141,7 +159,35 @@ func (p *Processor) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) erro
 	return p.next.ConsumeMetrics(ctx, md)
 }
 
-func (p *Processor) Start(_ context.Context, _ component.Host) error {
+func (p *Processor) Start(ctx context.Context, host component.Host) error {
+	if p.cfg.Storage != nil {
+		client, err := getStorageClient(ctx, host, *p.cfg.Storage, p.id)
+		if err != nil {
+			return fmt.Errorf("failed to get storage client: %w", err)
+		}
+		p.storage = client
+
+		if err := p.loadCheckpoint(ctx); err != nil {
+			return fmt.Errorf("failed to restore state: %w", err)
+		}
+
+		// checkpoint the state periodically, so that it survives a crash
+		go func() {
+			tick := time.NewTicker(p.cfg.CheckpointInterval)
+			defer tick.Stop()
+			for {
+				select {
+				case <-p.ctx.Done():
+					return
+				case <-tick.C:
+					if err := p.saveCheckpoint(p.ctx); err != nil {
+						p.logger.Warn("failed to checkpoint state", zap.Error(err))
+					}
+				}
+			}
+		}()
+	}
+
 	if p.cfg.MaxStale != 0 {
 		// delete stale streams once per minute
 		go func() {
@@ -166,9 +212,17 @@ func (p *Processor) Start(_ context.Context, _ component.Host) error {
 	return nil
 }
 
-func (p *Processor) Shutdown(_ context.Context) error {
+func (p *Processor) Shutdown(ctx context.Context) error {
 	p.cancel()
-	return nil
+	if p.storage == nil {
+		return nil
+	}
+
+	err := p.saveCheckpoint(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to checkpoint state: %w", err)
+	}
+	return errors.Join(err, p.storage.Close(ctx))
 }
 
 func (p *Processor) Capabilities() consumer.Capabilities {
@@ -180,6 +234,10 @@ type state struct {
 	nums map[identity.Stream]pmetric.NumberDataPoint
 	hist map[identity.Stream]pmetric.HistogramDataPoint
 	expo map[identity.Stream]pmetric.ExponentialHistogramDataPoint
+
+	// origins keeps the metric of the streams, required to checkpoint the state.
+	// it is only populated if a storage is configured.
+	origins map[identity.Metric]origin
 }
 
 func (m state) Len() int {
@@ -212,3 +270,10 @@ func (m state) BeginWith(id identity.Stream, dp any) {
 		dp.CopyTo(m.expo[id])
 	}
 }
+
+// Track records the metric of the stream, if not already known.
+func (m state) Track(id identity.Stream, metric metrics.Metric) {
+	if _, ok := m.origins[id.Metric()]; !ok {
+		m.origins[id.Metric()] = newOrigin(metric)
+	}
+}
@@ -6,6 +6,8 @@ package geoipprocessor // import "github.com/open-telemetry/opentelemetry-collec
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	"go.opentelemetry.io/collector/component"
@@ -25,6 +27,26 @@ const (
 	record   ContextID = "record"
 )
 
+type Strategy string
+
+const (
+	// merge queries every provider and merges the found attributes.
+	merge Strategy = "merge"
+	// fallback queries the providers in order until one of them finds the IP.
+	fallback Strategy = "fallback"
+)
+
+func (s *Strategy) UnmarshalText(text []byte) error {
+	str := Strategy(strings.ToLower(string(text)))
+	switch str {
+	case merge, fallback:
+		*s = str
+		return nil
+	default:
+		return fmt.Errorf("unknown strategy %s, available values: %s, %s", str, merge, fallback)
+	}
+}
+
 func (c *ContextID) UnmarshalText(text []byte) error {
 	str := ContextID(strings.ToLower(string(text)))
 	switch str {
@@ -39,8 +61,19 @@ func (c *ContextID) UnmarshalText(text []byte) error {
 // Config holds the configuration for the GeoIP processor.
 type Config struct {
 	// Providers specifies the sources to extract geographical information about a given IP.
+	// The keys are made of the provider type optionally followed by a slash and a name, e.g. `maxmind` or `cidr/internal`,
+	// to allow configuring several providers of the same type.
 	Providers map[string]provider.Config `mapstructure:"-"`
 
+	// ProviderOrder specifies the order in which the providers are queried. The providers that are not listed
+	// are queried afterwards in the lexical order of their keys.
+	ProviderOrder []string `mapstructure:"provider_order"`
+
+	// Strategy defines how the results of the providers are combined. Available options: merge or fallback.
+	// With merge, every provider is queried and the attributes found by earlier providers take precedence.
+	// With fallback, the first provider finding the IP wins and an erroring provider falls back to the next one.
+	Strategy Strategy `mapstructure:"strategy"`
+
 	// Context section allows specifying the source type to look for the IP. Available options: resource or record.
 	Context ContextID `mapstructure:"context"`
 }
@@ -62,9 +95,42 @@ func (cfg *Config) Validate() error {
 		}
 	}
 
+	seen := make(map[string]bool, len(cfg.ProviderOrder))
+	for _, providerID := range cfg.ProviderOrder {
+		if _, ok := cfg.Providers[providerID]; !ok {
+			return fmt.Errorf("provider_order references an unknown provider: %s", providerID)
+		}
+		if seen[providerID] {
+			return fmt.Errorf("provider_order references provider %s more than once", providerID)
+		}
+		seen[providerID] = true
+	}
+
 	return nil
 }
 
+// orderedProviders returns the keys of the providers in the order they must be queried.
+func (cfg *Config) orderedProviders() []string {
+	ordered := make([]string, 0, len(cfg.Providers))
+	ordered = append(ordered, cfg.ProviderOrder...)
+
+	remaining := make([]string, 0, len(cfg.Providers))
+	for providerID := range cfg.Providers {
+		if !slices.Contains(cfg.ProviderOrder, providerID) {
+			remaining = append(remaining, providerID)
+		}
+	}
+	sort.Strings(remaining)
+
+	return append(ordered, remaining...)
+}
+
+// providerType returns the type of a provider key, e.g. `cidr` for `cidr/internal`.
+func providerType(key string) string {
+	providerType, _, _ := strings.Cut(key, "/")
+	return providerType
+}
+
 // Unmarshal a config.Parser into the config struct.
 func (cfg *Config) Unmarshal(componentParser *confmap.Conf) error {
 	if componentParser == nil {
@@ -88,7 +154,7 @@ func (cfg *Config) Unmarshal(componentParser *confmap.Conf) error {
 
 	// loop through all defined providers and load their configuration
 	for key := range providersSection.ToStringMap() {
-		factory, ok := getProviderFactory(key)
+		factory, ok := getProviderFactory(providerType(key))
 		if !ok {
 			return fmt.Errorf("invalid provider key: %s", key)
 		}
@@ -16,6 +16,8 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider"
+	cidr "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/cidrprovider"
+	ip2location "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/ip2locationprovider"
 	maxmind "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/maxmindprovider"
 )
 
@@ -35,7 +37,8 @@ func TestLoadConfig(t *testing.T) {
 		{
 			id: component.NewIDWithName(metadata.Type, "maxmind"),
 			expected: &Config{
-				Context: resource,
+				Context:  resource,
+				Strategy: merge,
 				Providers: map[string]provider.Config{
 					"maxmind": &maxmind.Config{DatabasePath: "/tmp/db"},
 				},
@@ -44,7 +47,8 @@ func TestLoadConfig(t *testing.T) {
 		{
 			id: component.NewIDWithName(metadata.Type, "maxmind_record_context"),
 			expected: &Config{
-				Context: record,
+				Context:  record,
+				Strategy: merge,
 				Providers: map[string]provider.Config{
 					"maxmind": &maxmind.Config{DatabasePath: "/tmp/db"},
 				},
@@ -58,6 +62,35 @@ func TestLoadConfig(t *testing.T) {
 			id:                    component.NewIDWithName(metadata.Type, "invalid_source"),
 			unmarshalErrorMessage: "unknown context not.an.otlp.context, available values: resource, record",
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "fallback"),
+			expected: &Config{
+				Context:       resource,
+				Strategy:      fallback,
+				ProviderOrder: []string{"cidr/internal", "ip2location"},
+				Providers: map[string]provider.Config{
+					"cidr/internal": &cidr.Config{Path: "/tmp/networks.csv", NetworkColumn: "network", Delimiter: ","},
+					"ip2location":   &ip2location.Config{DatabasePath: "/tmp/IP2LOCATION-LITE-DB5.BIN"},
+					"maxmind":       &maxmind.Config{DatabasePath: "/tmp/db"},
+				},
+			},
+		},
+		{
+			id:                    component.NewIDWithName(metadata.Type, "invalid_strategy"),
+			unmarshalErrorMessage: "unknown strategy first, available values: merge, fallback",
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "unknown_provider_order"),
+			validateErrorMessage: "provider_order references an unknown provider: maxmind/secondary",
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "duplicated_provider_order"),
+			validateErrorMessage: "provider_order references provider maxmind more than once",
+		},
+		{
+			id:                    component.NewIDWithName(metadata.Type, "invalid_provider_type"),
+			unmarshalErrorMessage: "invalid provider key: geoip2/secondary",
+		},
 	}
 
 	for _, tt := range tests {
@@ -16,6 +16,8 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider"
+	cidr "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/cidrprovider"
+	ip2location "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/ip2locationprovider"
 	maxmind "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/maxmindprovider"
 )
 
@@ -30,7 +32,9 @@ var (
 
 // providerFactories is a map that stores GeoIPProviderFactory instances, keyed by the provider type.
 var providerFactories = map[string]provider.GeoIPProviderFactory{
-	maxmind.TypeStr: &maxmind.Factory{},
+	maxmind.TypeStr:     &maxmind.Factory{},
+	ip2location.TypeStr: &ip2location.Factory{},
+	cidr.TypeStr:        &cidr.Factory{},
 }
 
 // NewFactory creates a new processor factory with default configuration,
@@ -52,11 +56,13 @@ func getProviderFactory(key string) (provider.GeoIPProviderFactory, bool) {
 // createDefaultConfig returns a default configuration for the processor.
 func createDefaultConfig() component.Config {
 	return &Config{
-		Context: resource,
+		Context:  resource,
+		Strategy: merge,
 	}
 }
 
 // createGeoIPProviders creates a list of GeoIPProvider instances based on the provided configuration and providers factories.
+// The providers are returned in the order they must be queried.
 func createGeoIPProviders(
 	ctx context.Context,
 	set processor.Settings,
@@ -65,13 +71,13 @@ func createGeoIPProviders(
 ) ([]provider.GeoIPProvider, error) {
 	providers := make([]provider.GeoIPProvider, 0, len(config.Providers))
 
-	for key, cfg := range config.Providers {
-		factory := factories[key]
+	for _, key := range config.orderedProviders() {
+		factory := factories[providerType(key)]
 		if factory == nil {
 			return nil, fmt.Errorf("geoIP provider factory not found for key: %q", key)
 		}
 
-		provider, err := factory.CreateGeoIPProvider(ctx, set, cfg)
+		provider, err := factory.CreateGeoIPProvider(ctx, set, config.Providers[key])
 		if err != nil {
 			return nil, fmt.Errorf("failed to create provider for key %q: %w", key, err)
 		}
@@ -86,3 +86,33 @@ func TestCreateProcessor_FailedProvider(t *testing.T) {
 	_, err := factory.CreateMetrics(context.Background(), processortest.NewNopSettings(), cfg, consumertest.NewNop())
 	assert.EqualError(t, err, fmt.Errorf("failed to create provider for key %q: %w", providerKey, errors.New("error creating provider")).Error())
 }
+
+func TestCreateProcessor_ProvidersOrder(t *testing.T) {
+	cfg := &Config{
+		Providers: map[string]provider.Config{
+			"mock":         &providerConfigMock{},
+			"mock/b":       &providerConfigMock{},
+			"mock/a":       &providerConfigMock{},
+			"mock/primary": &providerConfigMock{},
+		},
+		ProviderOrder: []string{"mock/primary", "mock/b"},
+	}
+	keys := make(map[provider.Config]string, len(cfg.Providers))
+	for key, providerCfg := range cfg.Providers {
+		keys[providerCfg] = key
+	}
+
+	var created []string
+	factory := providerFactoryMock{
+		CreateDefaultConfigF: baseMockFactory.CreateDefaultConfigF,
+		CreateGeoIPProviderF: func(_ context.Context, _ processor.Settings, providerCfg provider.Config) (provider.GeoIPProvider, error) {
+			created = append(created, keys[providerCfg])
+			return &baseProviderMock, nil
+		},
+	}
+
+	providers, err := createGeoIPProviders(context.Background(), processortest.NewNopSettings(), cfg, map[string]provider.GeoIPProviderFactory{"mock": &factory})
+	assert.NoError(t, err)
+	assert.Len(t, providers, 4)
+	assert.Equal(t, []string{"mock/primary", "mock/b", "mock", "mock/a"}, created)
+}
@@ -73,6 +73,10 @@ func ipFromAttributes(attributes []attribute.Key, resource pcommon.Map) (net.IP,
 // geoLocation fetches geolocation information for the given IP address using the configured providers.
 // It returns a set of attributes containing the geolocation data, or an error if the location could not be determined.
 func (g *geoIPProcessor) geoLocation(ctx context.Context, ip net.IP) (attribute.Set, error) {
+	if g.cfg.Strategy == fallback {
+		return g.firstGeoLocation(ctx, ip)
+	}
+
 	allAttributes := &attribute.Set{}
 	for _, geoProvider := range g.providers {
 		geoAttributes, err := geoProvider.Location(ctx, ip)
@@ -84,12 +88,33 @@ func (g *geoIPProcessor) geoLocation(ctx context.Context, ip net.IP) (attribute.
 			}
 			return attribute.Set{}, err
 		}
-		*allAttributes = attribute.NewSet(append(allAttributes.ToSlice(), geoAttributes.ToSlice()...)...)
+		// the attributes found by the previous providers take precedence, attribute.NewSet keeps the last duplicated key
+		*allAttributes = attribute.NewSet(append(geoAttributes.ToSlice(), allAttributes.ToSlice()...)...)
 	}
 
 	return *allAttributes, nil
 }
 
+// firstGeoLocation returns the geolocation information of the first provider finding the given IP address.
+// The failing providers are skipped, an error is only returned if none of the remaining providers found the IP.
+func (g *geoIPProcessor) firstGeoLocation(ctx context.Context, ip net.IP) (attribute.Set, error) {
+	var errs error
+	for _, geoProvider := range g.providers {
+		geoAttributes, err := geoProvider.Location(ctx, ip)
+		if err == nil {
+			return geoAttributes, nil
+		}
+		if errors.Is(err, provider.ErrNoMetadataFound) {
+			g.logger.Debug(err.Error(), zap.String("IP", ip.String()))
+			continue
+		}
+		g.logger.Debug("geoIP provider failed, falling back to the next one", zap.String("IP", ip.String()), zap.Error(err))
+		errs = errors.Join(errs, err)
+	}
+
+	return attribute.Set{}, errs
+}
+
 // processAttributes processes a pcommon.Map by adding geolocation attributes based on the found IP address.
 func (g *geoIPProcessor) processAttributes(ctx context.Context, metadata pcommon.Map) error {
 	ipAddr, err := ipFromAttributes(g.resourceAttributes, metadata)
@@ -5,6 +5,7 @@ package geoipprocessor
 
 import (
 	"context"
+	"errors"
 	"net"
 	"path/filepath"
 	"testing"
@@ -231,3 +232,83 @@ func TestProcessor(t *testing.T) {
 		})
 	}
 }
+
+func TestGeoLocationStrategies(t *testing.T) {
+	ip := net.IPv4(10, 1, 2, 3)
+	staticProvider := func(attrs ...attribute.KeyValue) provider.GeoIPProvider {
+		return &providerMock{LocationF: func(context.Context, net.IP) (attribute.Set, error) {
+			return attribute.NewSet(attrs...), nil
+		}}
+	}
+	errorProvider := func(err error) provider.GeoIPProvider {
+		return &providerMock{LocationF: func(context.Context, net.IP) (attribute.Set, error) {
+			return attribute.Set{}, err
+		}}
+	}
+	internal := staticProvider(attribute.String("zone", "zone-a"), attribute.String(conventions.AttributeGeoCountryIsoCode, "DE"))
+	public := staticProvider(attribute.String(conventions.AttributeGeoCountryIsoCode, "US"), attribute.String(conventions.AttributeGeoCityName, "Boston"))
+	notFound := errorProvider(provider.ErrNoMetadataFound)
+	failing := errorProvider(errors.New("database is corrupted"))
+
+	tests := []struct {
+		name               string
+		strategy           Strategy
+		providers          []provider.GeoIPProvider
+		expectedAttributes attribute.Set
+		expectedErrMsg     string
+	}{
+		{
+			name:      "merge gives precedence to the first providers",
+			strategy:  merge,
+			providers: []provider.GeoIPProvider{notFound, internal, public},
+			expectedAttributes: attribute.NewSet(
+				attribute.String("zone", "zone-a"),
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "DE"),
+				attribute.String(conventions.AttributeGeoCityName, "Boston"),
+			),
+		},
+		{
+			name:           "merge fails on provider error",
+			strategy:       merge,
+			providers:      []provider.GeoIPProvider{internal, failing},
+			expectedErrMsg: "database is corrupted",
+		},
+		{
+			name:               "fallback stops at the first provider finding the IP",
+			strategy:           fallback,
+			providers:          []provider.GeoIPProvider{notFound, public, internal},
+			expectedAttributes: attribute.NewSet(attribute.String(conventions.AttributeGeoCountryIsoCode, "US"), attribute.String(conventions.AttributeGeoCityName, "Boston")),
+		},
+		{
+			name:               "fallback skips failing providers",
+			strategy:           fallback,
+			providers:          []provider.GeoIPProvider{failing, internal},
+			expectedAttributes: attribute.NewSet(attribute.String("zone", "zone-a"), attribute.String(conventions.AttributeGeoCountryIsoCode, "DE")),
+		},
+		{
+			name:               "fallback without any provider finding the IP",
+			strategy:           fallback,
+			providers:          []provider.GeoIPProvider{notFound, notFound},
+			expectedAttributes: attribute.Set{},
+		},
+		{
+			name:           "fallback with failing providers only",
+			strategy:       fallback,
+			providers:      []provider.GeoIPProvider{notFound, failing},
+			expectedErrMsg: "database is corrupted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := newGeoIPProcessor(&Config{Strategy: tt.strategy}, defaultResourceAttributes, tt.providers, processortest.NewNopSettings())
+			actualAttributes, err := processor.geoLocation(context.Background(), ip)
+			if tt.expectedErrMsg != "" {
+				require.EqualError(t, err, tt.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, tt.expectedAttributes.Equals(&actualAttributes), "expected %v, got %v", tt.expectedAttributes.ToSlice(), actualAttributes.ToSlice())
+		})
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cidr // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/cidrprovider"
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider"
+)
+
+const defaultNetworkColumn = "network"
+
+type Config struct {
+	// Path section allows specifying a local CSV file mapping network ranges
+	// to the attributes to add.
+	Path string `mapstructure:"path"`
+
+	// NetworkColumn is the name of the column holding the network range in CIDR notation.
+	// The remaining columns are added as attributes named after the column header.
+	NetworkColumn string `mapstructure:"network_column"`
+
+	// Delimiter is the field delimiter of the CSV file.
+	Delimiter string `mapstructure:"delimiter"`
+}
+
+var _ provider.Config = (*Config)(nil)
+
+func (c *Config) Validate() error {
+	if c.Path == "" {
+		return errors.New("a local CSV file path must be provided")
+	}
+	if c.NetworkColumn == "" {
+		return errors.New("the network column cannot be empty")
+	}
+	if utf8.RuneCountInString(c.Delimiter) != 1 {
+		return errors.New("the delimiter must be a single character")
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cidr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/processor/processortest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &Config{NetworkColumn: "network", Delimiter: ","}, cfg)
+}
+
+func TestCreateProvider(t *testing.T) {
+	factory := &Factory{}
+	cfg := &Config{
+		Path:          "",
+		NetworkColumn: "network",
+		Delimiter:     ",",
+	}
+
+	provider, err := factory.CreateGeoIPProvider(context.Background(), processortest.NewNopSettings(), cfg)
+
+	assert.ErrorContains(t, err, "could not open CIDR file")
+	assert.Nil(t, provider)
+}
+
+func TestConfigValidate(t *testing.T) {
+	assert.EqualError(t, (&Config{NetworkColumn: "network", Delimiter: ","}).Validate(), "a local CSV file path must be provided")
+	assert.EqualError(t, (&Config{Path: "a.csv", Delimiter: ","}).Validate(), "the network column cannot be empty")
+	assert.EqualError(t, (&Config{Path: "a.csv", NetworkColumn: "network", Delimiter: ";;"}).Validate(), "the delimiter must be a single character")
+	assert.NoError(t, (&Config{Path: "a.csv", NetworkColumn: "network", Delimiter: "\t"}).Validate())
+}
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cidr // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/cidrprovider"
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	conventions "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/convention"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider"
+)
+
+var errNilIP = errors.New("IP address cannot be nil")
+
+// network holds the attributes associated to a network range.
+type network struct {
+	prefix     netip.Prefix
+	attributes attribute.Set
+}
+
+// cidrProvider resolves the attributes of an IP from the most specific network range containing it.
+type cidrProvider struct {
+	// networks are sorted from the most to the least specific range.
+	networks []network
+}
+
+var _ provider.GeoIPProvider = (*cidrProvider)(nil)
+
+func newCIDRProvider(cfg *Config) (*cidrProvider, error) {
+	file, err := os.Open(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open CIDR file: %w", err)
+	}
+	defer file.Close()
+
+	networks, err := parseNetworks(file, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse CIDR file %q: %w", cfg.Path, err)
+	}
+	return &cidrProvider{networks: networks}, nil
+}
+
+// parseNetworks reads the network ranges of the CSV content. The first line is a header naming the columns.
+func parseNetworks(r io.Reader, cfg *Config) ([]network, error) {
+	reader := csv.NewReader(r)
+	reader.Comma, _ = utf8.DecodeRuneInString(cfg.Delimiter)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing header line")
+	}
+	if err != nil {
+		return nil, err
+	}
+	networkIndex := slices.Index(header, cfg.NetworkColumn)
+	if networkIndex < 0 {
+		return nil, fmt.Errorf("network column %q not found in header", cfg.NetworkColumn)
+	}
+
+	var networks []network
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		prefix, err := netip.ParsePrefix(record[networkIndex])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		attributes := make([]attribute.KeyValue, 0, len(record)-1)
+		for i, value := range record {
+			if i == networkIndex || value == "" {
+				continue
+			}
+			attr, err := newAttribute(header[i], value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			attributes = append(attributes, attr)
+		}
+		networks = append(networks, network{prefix: prefix.Masked(), attributes: attribute.NewSet(attributes...)})
+	}
+
+	// a stable sort keeps the file order for identical ranges, the first one wins
+	slices.SortStableFunc(networks, func(a, b network) int {
+		return b.prefix.Bits() - a.prefix.Bits()
+	})
+	return networks, nil
+}
+
+// newAttribute creates the attribute of a column. The location columns are converted to numbers
+// to follow the geo conventions, any other column is kept as a string.
+func newAttribute(key, value string) (attribute.KeyValue, error) {
+	switch key {
+	case conventions.AttributeGeoLocationLat, conventions.AttributeGeoLocationLon:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return attribute.KeyValue{}, fmt.Errorf("invalid %s value %q: %w", key, value, err)
+		}
+		return attribute.Float64(key, number), nil
+	default:
+		return attribute.String(key, value), nil
+	}
+}
+
+func (c *cidrProvider) Location(_ context.Context, ipAddress net.IP) (attribute.Set, error) {
+	if ipAddress == nil {
+		return attribute.Set{}, errNilIP
+	}
+	addr, ok := netip.AddrFromSlice(ipAddress)
+	if !ok {
+		return attribute.Set{}, fmt.Errorf("invalid IP address: %s", ipAddress)
+	}
+	addr = addr.Unmap()
+
+	for _, n := range c.networks {
+		if !n.prefix.Contains(addr) {
+			continue
+		}
+		if n.attributes.Len() == 0 {
+			return attribute.Set{}, provider.ErrNoMetadataFound
+		}
+		return n.attributes, nil
+	}
+	return attribute.Set{}, provider.ErrNoMetadataFound
+}
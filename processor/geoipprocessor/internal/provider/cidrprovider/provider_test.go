@@ -0,0 +1,176 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cidr
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	conventions "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/convention"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider"
+)
+
+func TestInvalidNewProvider(t *testing.T) {
+	_, err := newCIDRProvider(&Config{Path: filepath.Join("testdata", "missing.csv"), NetworkColumn: "network", Delimiter: ","})
+	require.ErrorContains(t, err, "could not open CIDR file")
+
+	_, err = newCIDRProvider(&Config{Path: filepath.Join("testdata", "networks.csv"), NetworkColumn: "cidr", Delimiter: ","})
+	require.ErrorContains(t, err, `network column "cidr" not found in header`)
+}
+
+func TestParseNetworks(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		content  string
+		expected []network
+		err      string
+	}{
+		{
+			name:    "most specific network first",
+			cfg:     Config{NetworkColumn: "network", Delimiter: ","},
+			content: "network,zone\n10.0.0.0/8,a\n10.1.2.3/16,b\n",
+			expected: []network{
+				{prefix: netipPrefix(t, "10.1.0.0/16"), attributes: attribute.NewSet(attribute.String("zone", "b"))},
+				{prefix: netipPrefix(t, "10.0.0.0/8"), attributes: attribute.NewSet(attribute.String("zone", "a"))},
+			},
+		},
+		{
+			name:    "custom delimiter and column",
+			cfg:     Config{NetworkColumn: "cidr", Delimiter: ";"},
+			content: "zone;cidr\na;10.0.0.0/8\n",
+			expected: []network{
+				{prefix: netipPrefix(t, "10.0.0.0/8"), attributes: attribute.NewSet(attribute.String("zone", "a"))},
+			},
+		},
+		{
+			name:    "empty file",
+			cfg:     Config{NetworkColumn: "network", Delimiter: ","},
+			content: "",
+			err:     "missing header line",
+		},
+		{
+			name:    "invalid network",
+			cfg:     Config{NetworkColumn: "network", Delimiter: ","},
+			content: "network,zone\n10.0.0.1,a\n",
+			err:     `line 2: netip.ParsePrefix("10.0.0.1"): no '/'`,
+		},
+		{
+			name:    "invalid coordinate",
+			cfg:     Config{NetworkColumn: "network", Delimiter: ","},
+			content: "network,geo.location.lat\n10.0.0.0/8,north\n",
+			err:     `line 2: invalid geo.location.lat value "north"`,
+		},
+		{
+			name:    "wrong number of fields",
+			cfg:     Config{NetworkColumn: "network", Delimiter: ","},
+			content: "network,zone\n10.0.0.0/8,a,b\n",
+			err:     "wrong number of fields",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			networks, err := parseNetworks(strings.NewReader(tt.content), &tt.cfg)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, networks)
+		})
+	}
+}
+
+func TestProviderLocation(t *testing.T) {
+	factory := &Factory{}
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Path = filepath.Join("testdata", "networks.csv")
+	cidrProvider, err := newCIDRProvider(cfg)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		sourceIP           net.IP
+		expectedAttributes attribute.Set
+		expectedErr        error
+		expectedErrMsg     string
+	}{
+		{
+			name:           "nil IP address",
+			expectedErrMsg: "IP address cannot be nil",
+		},
+		{
+			name:        "IP not in any network",
+			sourceIP:    net.IPv4(8, 8, 8, 8),
+			expectedErr: provider.ErrNoMetadataFound,
+		},
+		{
+			name:        "network without attributes",
+			sourceIP:    net.IPv4(192, 168, 1, 1),
+			expectedErr: provider.ErrNoMetadataFound,
+		},
+		{
+			name:     "least specific network",
+			sourceIP: net.IPv4(10, 3, 0, 1),
+			expectedAttributes: attribute.NewSet(
+				attribute.String("datacenter", "dc1"),
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "DE"),
+				attribute.Float64(conventions.AttributeGeoLocationLat, 50.1109),
+				attribute.Float64(conventions.AttributeGeoLocationLon, 8.6821),
+			),
+		},
+		{
+			name:     "most specific network",
+			sourceIP: net.IPv4(10, 2, 0, 1),
+			expectedAttributes: attribute.NewSet(
+				attribute.String("datacenter", "dc1"),
+				attribute.String("zone", "zone-b"),
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "DE"),
+				attribute.Float64(conventions.AttributeGeoLocationLat, 50.1109),
+				attribute.Float64(conventions.AttributeGeoLocationLon, 8.6821),
+			),
+		},
+		{
+			name:     "IPv6 network",
+			sourceIP: net.ParseIP("fd00:1::10"),
+			expectedAttributes: attribute.NewSet(
+				attribute.String("datacenter", "dc2"),
+				attribute.String("zone", "zone-c"),
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "NL"),
+				attribute.Float64(conventions.AttributeGeoLocationLat, 52.3676),
+				attribute.Float64(conventions.AttributeGeoLocationLon, 4.9041),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualAttributes, err := cidrProvider.Location(context.Background(), tt.sourceIP)
+			switch {
+			case tt.expectedErrMsg != "":
+				assert.EqualError(t, err, tt.expectedErrMsg)
+				return
+			case tt.expectedErr != nil:
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.expectedAttributes.Equals(&actualAttributes), "expected %v, got %v", tt.expectedAttributes.ToSlice(), actualAttributes.ToSlice())
+		})
+	}
+}
+
+func netipPrefix(t *testing.T, s string) netip.Prefix {
+	prefix, err := netip.ParsePrefix(s)
+	require.NoError(t, err)
+	return prefix.Masked()
+}
@@ -0,0 +1,25 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ip2location // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/ip2locationprovider"
+
+import (
+	"errors"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider"
+)
+
+type Config struct {
+	// DatabasePath section allows specifying a local IP2Location BIN database
+	// file to retrieve the geographical metadata from.
+	DatabasePath string `mapstructure:"database_path"`
+}
+
+var _ provider.Config = (*Config)(nil)
+
+func (c *Config) Validate() error {
+	if c.DatabasePath == "" {
+		return errors.New("a local IP2Location database path must be provided")
+	}
+	return nil
+}
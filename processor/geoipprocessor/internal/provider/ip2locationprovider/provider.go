@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ip2location // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider/ip2locationprovider"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/ip2location/ip2location-go/v9"
+	"go.opentelemetry.io/otel/attribute"
+
+	conventions "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/convention"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider"
+)
+
+var errNilIP = errors.New("IP address cannot be nil")
+
+// unknownValue is the value used by IP2Location databases for fields without information, e.g. for private networks.
+const unknownValue = "-"
+
+// libraryMessages holds the messages the ip2location library returns in the record fields instead of an error,
+// e.g. when the IP is not found or the field is not available in the database type.
+var libraryMessages = map[string]bool{
+	"Invalid IP address.":               true,
+	"Invalid database file.":            true,
+	"IPv6 address missing in IPv4 BIN.": true,
+	"This parameter is unavailable for selected data file. Please upgrade the data file.": true,
+}
+
+// noCoordinatesDBTypes holds the database types (DB1 to DB26) that do not include the latitude and longitude fields.
+var noCoordinatesDBTypes = map[string]bool{
+	"1": true,
+	"2": true,
+	"3": true,
+	"4": true,
+	"7": true,
+}
+
+type ip2LocationProvider struct {
+	db *ip2location.DB
+	// hasCoordinates is true if the database type includes the latitude and longitude fields.
+	hasCoordinates bool
+}
+
+var _ provider.GeoIPProvider = (*ip2LocationProvider)(nil)
+
+func newIP2LocationProvider(cfg *Config) (*ip2LocationProvider, error) {
+	db, err := ip2location.OpenDB(cfg.DatabasePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open IP2Location database: %w", err)
+	}
+
+	return &ip2LocationProvider{db: db, hasCoordinates: !noCoordinatesDBTypes[db.PackageVersion()]}, nil
+}
+
+func (g *ip2LocationProvider) Location(_ context.Context, ipAddress net.IP) (attribute.Set, error) {
+	if ipAddress == nil {
+		return attribute.Set{}, errNilIP
+	}
+
+	record, err := g.db.Get_all(ipAddress.String())
+	if err != nil {
+		return attribute.Set{}, err
+	}
+
+	// The country is available in every database type, a missing country means the IP is not part of the database.
+	if !isKnown(record.Country_short) {
+		return attribute.Set{}, provider.ErrNoMetadataFound
+	}
+
+	attributes := make([]attribute.KeyValue, 0, 7)
+	appendIfKnown := func(keyName, value string) {
+		if isKnown(value) {
+			attributes = append(attributes, attribute.String(keyName, value))
+		}
+	}
+
+	appendIfKnown(conventions.AttributeGeoCountryIsoCode, record.Country_short)
+	appendIfKnown(conventions.AttributeGeoCountryName, record.Country_long)
+	appendIfKnown(conventions.AttributeGeoRegionName, record.Region)
+	appendIfKnown(conventions.AttributeGeoCityName, record.City)
+	appendIfKnown(conventions.AttributeGeoPostalCode, record.Zipcode)
+
+	if g.hasCoordinates && (record.Latitude != 0 || record.Longitude != 0) {
+		attributes = append(attributes,
+			attribute.Float64(conventions.AttributeGeoLocationLat, roundCoordinate(record.Latitude)),
+			attribute.Float64(conventions.AttributeGeoLocationLon, roundCoordinate(record.Longitude)),
+		)
+	}
+
+	return attribute.NewSet(attributes...), nil
+}
+
+// isKnown returns true if the value holds actual metadata.
+func isKnown(value string) bool {
+	return value != "" && value != unknownValue && !libraryMessages[value]
+}
+
+// roundCoordinate converts the single precision coordinate stored in the database without
+// introducing spurious digits, e.g. 37.386 instead of 37.38600158691406.
+func roundCoordinate(value float32) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(float64(value), 'f', -1, 32), 64)
+	return rounded
+}
@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ip2location
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+
+	conventions "github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/convention"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor/internal/provider"
+)
+
+type testRange struct {
+	from                                    net.IP
+	countryShort, countryLong, region, city string
+	lat, lon                                float32
+}
+
+// writeTestDB writes an IPv4 only IP2Location BIN database of the given type with the given ranges.
+// Only the DB3 (country, region, city) and DB5 (DB3 with coordinates) layouts are supported.
+func writeTestDB(t *testing.T, dbType uint8, ranges []testRange) string {
+	columns := uint32(4)
+	if dbType == 5 {
+		columns = 6
+	}
+	const headerSize = 64
+	rowSize := columns * 4
+	// the last row only holds the end of the last range
+	rows := uint32(len(ranges)) + 1
+	stringsOffset := headerSize + rows*rowSize + rowSize
+
+	header := make([]byte, headerSize)
+	header[0] = dbType
+	header[1] = byte(columns)
+	header[2], header[3], header[4] = 24, 1, 1
+	binary.LittleEndian.PutUint32(header[5:], uint32(len(ranges)))
+	binary.LittleEndian.PutUint32(header[9:], headerSize+1)
+	header[29] = 1
+
+	var data, stringsData []byte
+	addString := func(value string) uint32 {
+		pos := stringsOffset + uint32(len(stringsData))
+		stringsData = append(stringsData, byte(len(value)))
+		stringsData = append(stringsData, value...)
+		return pos
+	}
+	for _, r := range ranges {
+		row := make([]byte, rowSize)
+		binary.LittleEndian.PutUint32(row, binary.BigEndian.Uint32(r.from.To4()))
+		country := addString(r.countryShort)
+		addString(r.countryLong)
+		binary.LittleEndian.PutUint32(row[4:], country)
+		binary.LittleEndian.PutUint32(row[8:], addString(r.region))
+		binary.LittleEndian.PutUint32(row[12:], addString(r.city))
+		if dbType == 5 {
+			binary.LittleEndian.PutUint32(row[16:], math.Float32bits(r.lat))
+			binary.LittleEndian.PutUint32(row[20:], math.Float32bits(r.lon))
+		}
+		data = append(data, row...)
+	}
+	last := make([]byte, 2*rowSize)
+	binary.LittleEndian.PutUint32(last, math.MaxUint32)
+	data = append(data, last...)
+
+	path := filepath.Join(t.TempDir(), "IP2LOCATION-TEST.BIN")
+	content := append(append(header, data...), stringsData...)
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+	return path
+}
+
+var testRanges = []testRange{
+	{from: net.IPv4(0, 0, 0, 0), countryShort: "-", countryLong: "-", region: "-", city: "-"},
+	{from: net.IPv4(8, 8, 8, 0), countryShort: "US", countryLong: "United States of America", region: "California", city: "Mountain View", lat: 37.386, lon: -122.0838},
+	{from: net.IPv4(8, 8, 9, 0), countryShort: "-", countryLong: "-", region: "-", city: "-"},
+	{from: net.IPv4(81, 2, 69, 0), countryShort: "GB", countryLong: "United Kingdom of Great Britain and Northern Ireland", region: "-", city: "-"},
+	{from: net.IPv4(81, 2, 70, 0), countryShort: "-", countryLong: "-", region: "-", city: "-"},
+}
+
+func TestInvalidNewProvider(t *testing.T) {
+	_, err := newIP2LocationProvider(&Config{DatabasePath: filepath.Join(t.TempDir(), "missing.BIN")})
+	require.ErrorContains(t, err, "could not open IP2Location database")
+
+	path := filepath.Join(t.TempDir(), "invalid.BIN")
+	require.NoError(t, os.WriteFile(path, []byte("not a database"), 0o600))
+	_, err = newIP2LocationProvider(&Config{DatabasePath: path})
+	require.ErrorContains(t, err, "could not open IP2Location database")
+}
+
+func TestProviderLocation(t *testing.T) {
+	tests := []struct {
+		name               string
+		dbType             uint8
+		sourceIP           net.IP
+		expectedAttributes attribute.Set
+		expectedErr        error
+		expectedErrMsg     string
+	}{
+		{
+			name:           "nil IP address",
+			dbType:         5,
+			expectedErrMsg: "IP address cannot be nil",
+		},
+		{
+			name:        "IP not in database",
+			dbType:      5,
+			sourceIP:    net.IPv4(10, 0, 0, 1),
+			expectedErr: provider.ErrNoMetadataFound,
+		},
+		{
+			name:        "IPv6 address in IPv4 database",
+			dbType:      5,
+			sourceIP:    net.ParseIP("2001:db8::1"),
+			expectedErr: provider.ErrNoMetadataFound,
+		},
+		{
+			name:     "city database with coordinates",
+			dbType:   5,
+			sourceIP: net.IPv4(8, 8, 8, 8),
+			expectedAttributes: attribute.NewSet(
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "US"),
+				attribute.String(conventions.AttributeGeoCountryName, "United States of America"),
+				attribute.String(conventions.AttributeGeoRegionName, "California"),
+				attribute.String(conventions.AttributeGeoCityName, "Mountain View"),
+				attribute.Float64(conventions.AttributeGeoLocationLat, 37.386),
+				attribute.Float64(conventions.AttributeGeoLocationLon, -122.0838),
+			),
+		},
+		{
+			name:     "city database without coordinates",
+			dbType:   3,
+			sourceIP: net.IPv4(8, 8, 8, 200),
+			expectedAttributes: attribute.NewSet(
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "US"),
+				attribute.String(conventions.AttributeGeoCountryName, "United States of America"),
+				attribute.String(conventions.AttributeGeoRegionName, "California"),
+				attribute.String(conventions.AttributeGeoCityName, "Mountain View"),
+			),
+		},
+		{
+			name:     "unknown fields are skipped",
+			dbType:   5,
+			sourceIP: net.IPv4(81, 2, 69, 160),
+			expectedAttributes: attribute.NewSet(
+				attribute.String(conventions.AttributeGeoCountryIsoCode, "GB"),
+				attribute.String(conventions.AttributeGeoCountryName, "United Kingdom of Great Britain and Northern Ireland"),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := newIP2LocationProvider(&Config{DatabasePath: writeTestDB(t, tt.dbType, testRanges)})
+			require.NoError(t, err)
+			t.Cleanup(provider.db.Close)
+
+			actualAttributes, err := provider.Location(context.Background(), tt.sourceIP)
+			switch {
+			case tt.expectedErrMsg != "":
+				assert.EqualError(t, err, tt.expectedErrMsg)
+				return
+			case tt.expectedErr != nil:
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.expectedAttributes.Equals(&actualAttributes), "expected %v, got %v", tt.expectedAttributes.ToSlice(), actualAttributes.ToSlice())
+		})
+	}
+}
@@ -5,6 +5,8 @@ package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry
 
 import (
 	"time"
+
+	"go.opentelemetry.io/collector/component"
 )
 
 // Config is the configuration for the processor.
@@ -30,6 +32,11 @@ type Config struct {
 	// StoreOnDisk tells the processor to keep only the trace ID in memory, serializing the trace spans to disk.
 	// Useful when the duration to wait for traces to complete is high.
 	// Default: false.
-	// Not yet implemented, and an error will be returned when this option is used.
+	// Not yet implemented, and an error will be returned when this option is used. Use StorageID instead.
 	StoreOnDisk bool `mapstructure:"store_on_disk"`
+
+	// StorageID is the ID of a storage extension used to persist the in-flight traces, so that they
+	// are released after a restart of the collector instead of being lost.
+	// Default: none, traces are kept in memory.
+	StorageID *component.ID `mapstructure:"storage"`
 }
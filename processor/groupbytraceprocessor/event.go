@@ -32,6 +32,9 @@ const (
 
 	// traceID to be removed
 	traceRemoved
+
+	// in-flight traces restored from a persistent storage
+	traceRestored
 )
 
 var (
@@ -78,6 +81,7 @@ type eventMachine struct {
 	onTraceExpired  func(traceID pcommon.TraceID, worker *eventMachineWorker) error
 	onTraceReleased func(rss []ptrace.ResourceSpans) error
 	onTraceRemoved  func(traceID pcommon.TraceID) error
+	onTraceRestored func(trace pendingTrace, worker *eventMachineWorker) error
 
 	onError func(event)
 
@@ -208,6 +212,22 @@ func (em *eventMachine) handleEvent(e event, w *eventMachineWorker) {
 		em.handleEventWithObservability("onTraceRemoved", func() error {
 			return em.onTraceRemoved(payload)
 		})
+	case traceRestored:
+		if em.onTraceRestored == nil {
+			em.logger.Debug("onTraceRestored not set, skipping event")
+			em.callOnError(e)
+			return
+		}
+		payload, ok := e.payload.(pendingTrace)
+		if !ok {
+			// the payload had an unexpected type!
+			em.callOnError(e)
+			return
+		}
+
+		em.handleEventWithObservability("onTraceRestored", func() error {
+			return em.onTraceRestored(payload, w)
+		})
 	default:
 		em.logger.Info("unknown event type", zap.Any("event", e.typ))
 		em.callOnError(e)
@@ -236,6 +256,19 @@ func (em *eventMachine) consume(td ptrace.Traces) error {
 	return nil
 }
 
+// restore routes a trace restored from a persistent storage to the worker owning its trace ID.
+func (em *eventMachine) restore(trace pendingTrace) {
+	var bucket uint64
+	if len(em.workers) != 1 {
+		bucket = workerIndexForTraceID(trace.id, len(em.workers))
+	}
+
+	em.workers[bucket].fire(event{
+		typ:     traceRestored,
+		payload: trace,
+	})
+}
+
 func workerIndexForTraceID(traceID pcommon.TraceID, numWorkers int) uint64 {
 	hash := hashPool.Get().(*maphash.Hash)
 	defer func() {
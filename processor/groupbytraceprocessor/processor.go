@@ -33,6 +33,7 @@ import (
 // Each worker in the eventMachine also uses a ring buffer to hold the in-flight trace IDs, so that we don't hold more than the given maximum number
 // of traces in memory/storage. Items that are evicted from the buffer are discarded without warning.
 type groupByTraceProcessor struct {
+	id               component.ID
 	nextConsumer     consumer.Traces
 	config           Config
 	logger           *zap.Logger
@@ -59,6 +60,7 @@ func newGroupByTraceProcessor(set processor.Settings, nextConsumer consumer.Trac
 	eventMachine := newEventMachine(set.Logger, 10000, config.NumWorkers, config.NumTraces, telemetryBuilder)
 
 	sp := &groupByTraceProcessor{
+		id:               set.ID,
 		logger:           set.Logger,
 		nextConsumer:     nextConsumer,
 		config:           config,
@@ -71,6 +73,7 @@ func newGroupByTraceProcessor(set processor.Settings, nextConsumer consumer.Trac
 	eventMachine.onTraceExpired = sp.onTraceExpired
 	eventMachine.onTraceReleased = sp.onTraceReleased
 	eventMachine.onTraceRemoved = sp.onTraceRemoved
+	eventMachine.onTraceRestored = sp.onTraceRestored
 
 	return sp
 }
@@ -88,13 +91,39 @@ func (sp *groupByTraceProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Start is invoked during service startup.
-func (sp *groupByTraceProcessor) Start(context.Context, component.Host) error {
+func (sp *groupByTraceProcessor) Start(ctx context.Context, host component.Host) error {
+	var pst *persistentStorage
+	if sp.config.StorageID != nil {
+		client, err := getStorageClient(ctx, host, *sp.config.StorageID, sp.id)
+		if err != nil {
+			return fmt.Errorf("failed to get storage client: %w", err)
+		}
+		pst = newPersistentStorage(client, sp.telemetryBuilder)
+		sp.st = pst
+	}
+
 	// start these metrics, as it might take a while for them to receive their first event
 	sp.telemetryBuilder.ProcessorGroupbytraceTracesEvicted.Add(context.Background(), 0)
 	sp.telemetryBuilder.ProcessorGroupbytraceIncompleteReleases.Add(context.Background(), 0)
 	sp.telemetryBuilder.ProcessorGroupbytraceConfNumTraces.Record(context.Background(), (int64(sp.config.NumTraces)))
 	sp.eventMachine.startInBackground()
-	return sp.st.start()
+	if err := sp.st.start(); err != nil {
+		return err
+	}
+	if pst == nil {
+		return nil
+	}
+
+	// resume the release of the traces that were in-flight when the processor was last stopped
+	pending, err := pst.restore(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to restore in-flight traces: %w", err)
+	}
+	sp.logger.Debug("restoring in-flight traces", zap.Int("num-traces", len(pending)))
+	for _, trace := range pending {
+		sp.eventMachine.restore(trace)
+	}
+	return nil
 }
 
 // Shutdown is invoked during service shutdown.
@@ -119,7 +148,33 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 
 	// at this point, we determined that we haven't seen the trace yet, so, record the
 	// traceID in the map and the spans to the storage
+	sp.putInBuffer(traceID, worker)
+
+	// we have the traceID in the memory, place the spans in the storage too
+	if err := sp.addSpans(traceID, trace.td); err != nil {
+		return fmt.Errorf("couldn't add spans to existing trace: %w", err)
+	}
 
+	sp.scheduleRelease(traceID, sp.config.WaitDuration, worker)
+	return nil
+}
+
+func (sp *groupByTraceProcessor) onTraceRestored(trace pendingTrace, worker *eventMachineWorker) error {
+	if worker.buffer.contains(trace.id) {
+		// spans for this trace were received since the processor started, its release is already scheduled
+		return nil
+	}
+
+	sp.putInBuffer(trace.id, worker)
+
+	// the trace keeps its original release time, traces that should have been released while the
+	// processor was stopped are released right away
+	sp.scheduleRelease(trace.id, max(0, time.Until(trace.receivedAt.Add(sp.config.WaitDuration))), worker)
+	return nil
+}
+
+// putInBuffer places the trace ID in the worker's ring buffer, removing the evicted trace from the storage if any.
+func (sp *groupByTraceProcessor) putInBuffer(traceID pcommon.TraceID, worker *eventMachineWorker) {
 	// place the trace ID in the buffer, and check if an item had to be evicted
 	evicted := worker.buffer.put(traceID)
 	if !evicted.IsEmpty() {
@@ -133,22 +188,18 @@ func (sp *groupByTraceProcessor) onTraceReceived(trace tracesWithID, worker *eve
 		sp.logger.Info("trace evicted: in order to avoid this in the future, adjust the wait duration and/or number of traces to keep in memory",
 			zap.Stringer("traceID", evicted))
 	}
+}
 
-	// we have the traceID in the memory, place the spans in the storage too
-	if err := sp.addSpans(traceID, trace.td); err != nil {
-		return fmt.Errorf("couldn't add spans to existing trace: %w", err)
-	}
-
-	sp.logger.Debug("scheduled to release trace", zap.Duration("duration", sp.config.WaitDuration))
+func (sp *groupByTraceProcessor) scheduleRelease(traceID pcommon.TraceID, after time.Duration, worker *eventMachineWorker) {
+	sp.logger.Debug("scheduled to release trace", zap.Duration("duration", after))
 
-	time.AfterFunc(sp.config.WaitDuration, func() {
+	time.AfterFunc(after, func() {
 		// if the event machine has stopped, it will just discard the event
 		worker.fire(event{
 			typ:     traceExpired,
 			payload: traceID,
 		})
 	})
-	return nil
 }
 
 func (sp *groupByTraceProcessor) onTraceExpired(traceID pcommon.TraceID, worker *eventMachineWorker) error {
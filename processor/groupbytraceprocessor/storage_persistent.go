@@ -0,0 +1,285 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	extstorage "go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor/internal/metadata"
+)
+
+const (
+	traceKeyPrefix = "trace_"
+	indexKeyPrefix = "index_"
+
+	// numIndexBuckets is the number of keys the index of in-flight traces is split into, so that
+	// registering or removing a trace only rewrites a fraction of the index.
+	numIndexBuckets = 256
+	// indexEntrySize is the size of an encoded index entry: the trace ID followed by its arrival time.
+	indexEntrySize = 16 + 8
+)
+
+var errCorruptedIndex = errors.New("corrupted index of in-flight traces")
+
+// pendingTrace is a trace that was in-flight when the processor was stopped.
+type pendingTrace struct {
+	id         pcommon.TraceID
+	receivedAt time.Time
+}
+
+// persistentStorage keeps the in-flight traces in a storage extension, so that they survive a
+// restart of the collector. Next to the spans of each trace, it keeps an index of the in-flight
+// trace IDs and their arrival time, used to resume their release once the processor starts again.
+type persistentStorage struct {
+	sync.Mutex
+	client    extstorage.Client
+	telemetry *metadata.TelemetryBuilder
+	marshaler ptrace.ProtoMarshaler
+	unmarshal ptrace.ProtoUnmarshaler
+
+	// index mirrors the persisted index, split into the same buckets
+	index [numIndexBuckets]map[pcommon.TraceID]time.Time
+
+	stopped                   bool
+	stoppedLock               sync.RWMutex
+	metricsCollectionInterval time.Duration
+}
+
+var _ storage = (*persistentStorage)(nil)
+
+func newPersistentStorage(client extstorage.Client, telemetry *metadata.TelemetryBuilder) *persistentStorage {
+	st := &persistentStorage{
+		client:                    client,
+		telemetry:                 telemetry,
+		metricsCollectionInterval: time.Second,
+	}
+	for i := range st.index {
+		st.index[i] = make(map[pcommon.TraceID]time.Time)
+	}
+	return st
+}
+
+func (st *persistentStorage) createOrAppend(traceID pcommon.TraceID, td ptrace.Traces) error {
+	st.Lock()
+	defer st.Unlock()
+
+	ctx := context.Background()
+	stored, err := st.load(ctx, traceID)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		td.ResourceSpans().At(i).CopyTo(stored.ResourceSpans().AppendEmpty())
+	}
+
+	value, err := st.marshaler.MarshalTraces(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trace %q: %w", traceID, err)
+	}
+	ops := []extstorage.Operation{extstorage.SetOperation(traceKey(traceID), value)}
+
+	bucket := indexBucket(traceID)
+	_, known := st.index[bucket][traceID]
+	if !known {
+		st.index[bucket][traceID] = time.Now()
+		ops = append(ops, extstorage.SetOperation(indexKey(bucket), encodeIndexBucket(st.index[bucket])))
+	}
+
+	if err := st.client.Batch(ctx, ops...); err != nil {
+		if !known {
+			delete(st.index[bucket], traceID)
+		}
+		return fmt.Errorf("failed to persist trace %q: %w", traceID, err)
+	}
+	return nil
+}
+
+func (st *persistentStorage) get(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	if _, ok := st.index[indexBucket(traceID)][traceID]; !ok {
+		return nil, nil
+	}
+
+	stored, err := st.load(context.Background(), traceID)
+	if err != nil {
+		return nil, err
+	}
+	return toResourceSpans(stored), nil
+}
+
+func (st *persistentStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	bucket := indexBucket(traceID)
+	receivedAt, ok := st.index[bucket][traceID]
+	if !ok {
+		return nil, nil
+	}
+
+	ctx := context.Background()
+	stored, err := st.load(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(st.index[bucket], traceID)
+	err = st.client.Batch(ctx,
+		extstorage.DeleteOperation(traceKey(traceID)),
+		extstorage.SetOperation(indexKey(bucket), encodeIndexBucket(st.index[bucket])),
+	)
+	if err != nil {
+		st.index[bucket][traceID] = receivedAt
+		return nil, fmt.Errorf("failed to delete trace %q: %w", traceID, err)
+	}
+	return toResourceSpans(stored), nil
+}
+
+func (st *persistentStorage) start() error {
+	go st.periodicMetrics()
+	return nil
+}
+
+func (st *persistentStorage) shutdown() error {
+	st.stoppedLock.Lock()
+	st.stopped = true
+	st.stoppedLock.Unlock()
+	return st.client.Close(context.Background())
+}
+
+// restore loads the index of in-flight traces persisted by a previous run, returning the traces
+// that are still waiting to be released.
+func (st *persistentStorage) restore(ctx context.Context) ([]pendingTrace, error) {
+	st.Lock()
+	defer st.Unlock()
+
+	var pending []pendingTrace
+	for bucket := range st.index {
+		value, err := st.client.Get(ctx, indexKey(bucket))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the index of in-flight traces: %w", err)
+		}
+		entries, err := decodeIndexBucket(value)
+		if err != nil {
+			return nil, err
+		}
+		for traceID, receivedAt := range entries {
+			st.index[bucket][traceID] = receivedAt
+			pending = append(pending, pendingTrace{id: traceID, receivedAt: receivedAt})
+		}
+	}
+	return pending, nil
+}
+
+// load returns the persisted spans for the given trace, or empty traces if there are none.
+func (st *persistentStorage) load(ctx context.Context, traceID pcommon.TraceID) (ptrace.Traces, error) {
+	value, err := st.client.Get(ctx, traceKey(traceID))
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("failed to read trace %q: %w", traceID, err)
+	}
+	if value == nil {
+		return ptrace.NewTraces(), nil
+	}
+	td, err := st.unmarshal.UnmarshalTraces(value)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("failed to unmarshal trace %q: %w", traceID, err)
+	}
+	return td, nil
+}
+
+func (st *persistentStorage) periodicMetrics() {
+	st.telemetry.ProcessorGroupbytraceNumTracesInMemory.Record(context.Background(), int64(st.count()))
+
+	st.stoppedLock.RLock()
+	stopped := st.stopped
+	st.stoppedLock.RUnlock()
+	if stopped {
+		return
+	}
+
+	time.AfterFunc(st.metricsCollectionInterval, func() {
+		st.periodicMetrics()
+	})
+}
+
+func (st *persistentStorage) count() int {
+	st.Lock()
+	defer st.Unlock()
+
+	var count int
+	for _, bucket := range st.index {
+		count += len(bucket)
+	}
+	return count
+}
+
+func toResourceSpans(td ptrace.Traces) []ptrace.ResourceSpans {
+	rss := make([]ptrace.ResourceSpans, td.ResourceSpans().Len())
+	for i := range rss {
+		rss[i] = td.ResourceSpans().At(i)
+	}
+	return rss
+}
+
+func traceKey(traceID pcommon.TraceID) string {
+	return traceKeyPrefix + traceID.String()
+}
+
+func indexKey(bucket int) string {
+	return fmt.Sprintf("%s%02x", indexKeyPrefix, bucket)
+}
+
+func indexBucket(traceID pcommon.TraceID) int {
+	return int(traceID[15]) % numIndexBuckets
+}
+
+func encodeIndexBucket(entries map[pcommon.TraceID]time.Time) []byte {
+	buf := make([]byte, 0, len(entries)*indexEntrySize)
+	for traceID, receivedAt := range entries {
+		buf = append(buf, traceID[:]...)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(receivedAt.UnixNano()))
+	}
+	return buf
+}
+
+func decodeIndexBucket(buf []byte) (map[pcommon.TraceID]time.Time, error) {
+	if len(buf)%indexEntrySize != 0 {
+		return nil, errCorruptedIndex
+	}
+	entries := make(map[pcommon.TraceID]time.Time, len(buf)/indexEntrySize)
+	for len(buf) > 0 {
+		var traceID pcommon.TraceID
+		copy(traceID[:], buf[:16])
+		entries[traceID] = time.Unix(0, int64(binary.BigEndian.Uint64(buf[16:indexEntrySize])))
+		buf = buf[indexEntrySize:]
+	}
+	return entries, nil
+}
+
+// getStorageClient returns a client of the storage extension identified by storageID.
+func getStorageClient(ctx context.Context, host component.Host, storageID component.ID, componentID component.ID) (extstorage.Client, error) {
+	ext, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExt, ok := ext.(extstorage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExt.GetClient(ctx, component.KindProcessor, componentID, "")
+}
@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/processor/processortest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor/internal/metadata"
+)
+
+func newTestPersistentStorage(t *testing.T) *persistentStorage {
+	set := processortest.NewNopSettings()
+	tel, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	require.NoError(t, err)
+	client := storagetest.NewInMemoryClient(component.KindProcessor, component.MustNewID("groupbytrace"), "")
+	return newPersistentStorage(client, tel)
+}
+
+func TestPersistentCreateGetAndDeleteTrace(t *testing.T) {
+	st := newTestPersistentStorage(t)
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	first := simpleTracesWithID(traceID)
+	first.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetName("first")
+	second := simpleTracesWithID(traceID)
+	second.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetName("second")
+
+	require.NoError(t, st.createOrAppend(traceID, first))
+	require.NoError(t, st.createOrAppend(traceID, second))
+	assert.Equal(t, 1, st.count())
+
+	// changes to the original traces are not applied to the storage
+	first.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).SetName("changed")
+
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	require.Len(t, retrieved, 2)
+	assert.Equal(t, "first", retrieved[0].ScopeSpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, "second", retrieved[1].ScopeSpans().At(0).Spans().At(0).Name())
+
+	deleted, err := st.delete(traceID)
+	require.NoError(t, err)
+	assert.Len(t, deleted, 2)
+	assert.Equal(t, 0, st.count())
+
+	retrieved, err = st.get(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+
+	deleted, err = st.delete(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+}
+
+func TestPersistentRestore(t *testing.T) {
+	set := processortest.NewNopSettings()
+	tel, err := metadata.NewTelemetryBuilder(set.TelemetrySettings)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	id := component.MustNewID("groupbytrace")
+
+	st := newPersistentStorage(storagetest.NewFileBackedClient(component.KindProcessor, id, "", dir), tel)
+	before := time.Now()
+	kept := pcommon.TraceID([16]byte{1})
+	removed := pcommon.TraceID([16]byte{2})
+	require.NoError(t, st.createOrAppend(kept, simpleTracesWithID(kept)))
+	require.NoError(t, st.createOrAppend(removed, simpleTracesWithID(removed)))
+	_, err = st.delete(removed)
+	require.NoError(t, err)
+	require.NoError(t, st.shutdown())
+
+	restarted := newPersistentStorage(storagetest.NewFileBackedClient(component.KindProcessor, id, "", dir), tel)
+	pending, err := restarted.restore(context.Background())
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, kept, pending[0].id)
+	assert.False(t, pending[0].receivedAt.Before(before))
+
+	retrieved, err := restarted.get(kept)
+	require.NoError(t, err)
+	require.Len(t, retrieved, 1)
+	assert.Equal(t, kept, retrieved[0].ScopeSpans().At(0).Spans().At(0).TraceID())
+}
+
+func TestDecodeCorruptedIndex(t *testing.T) {
+	_, err := decodeIndexBucket([]byte{1, 2, 3})
+	assert.ErrorIs(t, err, errCorruptedIndex)
+}
+
+func TestPendingTracesReleasedAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	storageID := storagetest.NewStorageID("file")
+	config := Config{
+		WaitDuration: time.Hour,
+		NumTraces:    10,
+		NumWorkers:   2,
+		StorageID:    &storageID,
+	}
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	ctx := context.Background()
+
+	// the processor ID is part of the storage keys, so both runs use the same settings
+	set := processortest.NewNopSettings()
+
+	// the first run receives the trace but stops before it is released
+	sink := new(consumertest.TracesSink)
+	p := newGroupByTraceProcessor(set, sink, config)
+	p.st = newMemoryStorage(p.telemetryBuilder)
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("file", dir)
+	require.NoError(t, p.Start(ctx, host))
+	require.NoError(t, p.ConsumeTraces(ctx, simpleTracesWithID(traceID)))
+	require.Eventually(t, func() bool {
+		return p.st.(*persistentStorage).count() == 1
+	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, p.Shutdown(ctx))
+	assert.Equal(t, 0, sink.SpanCount())
+
+	// the trace should have been released while the processor was down, so it's released right away
+	config.WaitDuration = time.Nanosecond
+	sink = new(consumertest.TracesSink)
+	p = newGroupByTraceProcessor(set, sink, config)
+	p.st = newMemoryStorage(p.telemetryBuilder)
+	host = storagetest.NewStorageHost().WithFileBackedStorageExtension("file", dir)
+	require.NoError(t, p.Start(ctx, host))
+	defer func() {
+		assert.NoError(t, p.Shutdown(ctx))
+	}()
+
+	require.Eventually(t, func() bool {
+		return sink.SpanCount() == 1
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, traceID, sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID())
+	require.Eventually(t, func() bool {
+		return p.st.(*persistentStorage).count() == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStartWithMissingStorageExtension(t *testing.T) {
+	storageID := storagetest.NewStorageID("missing")
+	config := Config{
+		WaitDuration: time.Second,
+		NumTraces:    10,
+		NumWorkers:   1,
+		StorageID:    &storageID,
+	}
+	p := newGroupByTraceProcessor(processortest.NewNopSettings(), consumertest.NewNop(), config)
+	p.st = newMemoryStorage(p.telemetryBuilder)
+	assert.ErrorContains(t, p.Start(context.Background(), storagetest.NewStorageHost()), "storage extension 'test_storage/missing' not found")
+}
+
+func TestRestoredTraceAppendsToPersistedSpans(t *testing.T) {
+	st := newTestPersistentStorage(t)
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	require.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+
+	pending, err := st.restore(context.Background())
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	// spans received after a restart are added to the persisted ones
+	require.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	assert.Len(t, retrieved, 2)
+	assert.Equal(t, 1, st.count())
+}
@@ -45,6 +45,14 @@ type Config struct {
 	Timezone          string        `mapstructure:"timezone"`
 	ExcludeFields     []string      `mapstructure:"exclude_fields"`
 	Conditions        []string      `mapstructure:"conditions"`
+
+	// FirstOccurrencePassthrough emits the first occurrence of a log immediately,
+	// only later duplicates within the interval are aggregated.
+	FirstOccurrencePassthrough bool `mapstructure:"first_occurrence_passthrough"`
+
+	// StorageID is the ID of the storage extension used to persist the aggregation window after each batch and on shutdown.
+	// The persisted window is restored when the processor restarts, and keeps running until the end of its interval.
+	StorageID *component.ID `mapstructure:"storage"`
 }
 
 // createDefaultConfig returns the default config for the processor.
@@ -29,6 +29,9 @@ type logAggregator struct {
 	logCountAttribute string
 	timezone          *time.Location
 	telemetryBuilder  *metadata.TelemetryBuilder
+
+	// firstOccurrencePassthrough is true if the first occurrence of a log is not counted.
+	firstOccurrencePassthrough bool
 }
 
 // newLogAggregator creates a new LogCounter.
@@ -46,14 +49,22 @@ func (l *logAggregator) Export(ctx context.Context) plog.Logs {
 	logs := plog.NewLogs()
 
 	for _, resourceAggregator := range l.resources {
-		rl := logs.ResourceLogs().AppendEmpty()
-		resourceAggregator.resource.CopyTo(rl.Resource())
-
+		var rl plog.ResourceLogs
 		for _, scopeAggregator := range resourceAggregator.scopeCounters {
-			sl := rl.ScopeLogs().AppendEmpty()
-			scopeAggregator.scope.CopyTo(sl.Scope())
-
+			var sl plog.ScopeLogs
 			for _, logAggregator := range scopeAggregator.logCounters {
+				// the first occurrence of a log was already emitted in passthrough mode
+				if logAggregator.count == 0 {
+					continue
+				}
+				if sl == (plog.ScopeLogs{}) {
+					if rl == (plog.ResourceLogs{}) {
+						rl = logs.ResourceLogs().AppendEmpty()
+						resourceAggregator.resource.CopyTo(rl.Resource())
+					}
+					sl = rl.ScopeLogs().AppendEmpty()
+					scopeAggregator.scope.CopyTo(sl.Scope())
+				}
 				// Record aggregated logs records
 				l.telemetryBuilder.DedupProcessorAggregatedLogs.Record(ctx, logAggregator.count)
 
@@ -78,15 +89,69 @@ func (l *logAggregator) Export(ctx context.Context) plog.Logs {
 	return logs
 }
 
-// Add adds the logRecord to the resource aggregator that is identified by the resource attributes
-func (l *logAggregator) Add(resource pcommon.Resource, scope pcommon.InstrumentationScope, logRecord plog.LogRecord) {
+// Snapshot returns the counters as a Logs, to persist them and restore them later.
+// Unlike Export, it includes the logs that are not counted yet in passthrough mode. The count of each log record
+// is kept in the log count attribute, and its first and last observed timestamps in its observed timestamp and timestamp.
+func (l *logAggregator) Snapshot() plog.Logs {
+	logs := plog.NewLogs()
+
+	for _, resourceAggregator := range l.resources {
+		rl := logs.ResourceLogs().AppendEmpty()
+		resourceAggregator.resource.CopyTo(rl.Resource())
+		for _, scopeAggregator := range resourceAggregator.scopeCounters {
+			sl := rl.ScopeLogs().AppendEmpty()
+			scopeAggregator.scope.CopyTo(sl.Scope())
+			for _, logAggregator := range scopeAggregator.logCounters {
+				lr := sl.LogRecords().AppendEmpty()
+				logAggregator.logRecord.CopyTo(lr)
+				lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(logAggregator.firstObservedTimestamp))
+				lr.SetTimestamp(pcommon.NewTimestampFromTime(logAggregator.lastObservedTimestamp))
+				lr.Attributes().PutInt(l.logCountAttribute, logAggregator.count)
+			}
+		}
+	}
+
+	return logs
+}
+
+// Restore adds the counters of a snapshot to the aggregator. Log records without a count are ignored.
+func (l *logAggregator) Restore(logs plog.Logs) {
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		rl := logs.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				lr := sl.LogRecords().At(k)
+				countValue, ok := lr.Attributes().Get(l.logCountAttribute)
+				if !ok || countValue.Type() != pcommon.ValueTypeInt {
+					continue
+				}
+				count := countValue.Int()
+				lr.Attributes().Remove(l.logCountAttribute)
+
+				key := getResourceKey(rl.Resource())
+				resourceAggregator, ok := l.resources[key]
+				if !ok {
+					resourceAggregator = newResourceAggregator(rl.Resource())
+					l.resources[key] = resourceAggregator
+				}
+				resourceAggregator.restore(sl.Scope(), lr, count)
+			}
+		}
+	}
+}
+
+// Add adds the logRecord to the resource aggregator that is identified by the resource attributes.
+// It returns true if the logRecord is the first occurrence of the log since the last reset.
+// In passthrough mode, the first occurrence is not counted as it is emitted right away.
+func (l *logAggregator) Add(resource pcommon.Resource, scope pcommon.InstrumentationScope, logRecord plog.LogRecord) bool {
 	key := getResourceKey(resource)
 	resourceAggregator, ok := l.resources[key]
 	if !ok {
 		resourceAggregator = newResourceAggregator(resource)
 		l.resources[key] = resourceAggregator
 	}
-	resourceAggregator.Add(scope, logRecord)
+	return resourceAggregator.Add(scope, logRecord, l.firstOccurrencePassthrough)
 }
 
 // Reset resets the counter.
@@ -101,22 +166,36 @@ type resourceAggregator struct {
 }
 
 // newResourceAggregator creates a new ResourceCounter.
+// The resource is copied, as the original may still be sent down the pipeline.
 func newResourceAggregator(resource pcommon.Resource) *resourceAggregator {
+	res := pcommon.NewResource()
+	resource.CopyTo(res)
 	return &resourceAggregator{
-		resource:      resource,
+		resource:      res,
 		scopeCounters: make(map[uint64]*scopeAggregator),
 	}
 }
 
-// Add increments the counter that the logRecord matches.
-func (r *resourceAggregator) Add(scope pcommon.InstrumentationScope, logRecord plog.LogRecord) {
+// Add increments the counter that the logRecord matches, see logAggregator.Add.
+func (r *resourceAggregator) Add(scope pcommon.InstrumentationScope, logRecord plog.LogRecord, skipFirst bool) bool {
+	key := getScopeKey(scope)
+	scopeAggregator, ok := r.scopeCounters[key]
+	if !ok {
+		scopeAggregator = newScopeAggregator(scope)
+		r.scopeCounters[key] = scopeAggregator
+	}
+	return scopeAggregator.Add(logRecord, skipFirst)
+}
+
+// restore adds a counter of a snapshot, see logAggregator.Restore.
+func (r *resourceAggregator) restore(scope pcommon.InstrumentationScope, logRecord plog.LogRecord, count int64) {
 	key := getScopeKey(scope)
 	scopeAggregator, ok := r.scopeCounters[key]
 	if !ok {
 		scopeAggregator = newScopeAggregator(scope)
 		r.scopeCounters[key] = scopeAggregator
 	}
-	scopeAggregator.Add(logRecord)
+	scopeAggregator.restore(logRecord, count)
 }
 
 // scopeAggregator dimensions the counter by scope.
@@ -126,22 +205,55 @@ type scopeAggregator struct {
 }
 
 // newScopeAggregator creates a new ScopeCounter.
+// The scope is copied, as the original may still be sent down the pipeline.
 func newScopeAggregator(scope pcommon.InstrumentationScope) *scopeAggregator {
+	sc := pcommon.NewInstrumentationScope()
+	scope.CopyTo(sc)
 	return &scopeAggregator{
-		scope:       scope,
+		scope:       sc,
 		logCounters: make(map[uint64]*logCounter),
 	}
 }
 
-// Add increments the counter that the logRecord matches.
-func (s *scopeAggregator) Add(logRecord plog.LogRecord) {
+// Add increments the counter that the logRecord matches, see logAggregator.Add.
+func (s *scopeAggregator) Add(logRecord plog.LogRecord, skipFirst bool) bool {
 	key := getLogKey(logRecord)
 	lc, ok := s.logCounters[key]
 	if !ok {
 		lc = newLogCounter(logRecord)
 		s.logCounters[key] = lc
+		if skipFirst {
+			return true
+		}
 	}
 	lc.Increment()
+	return !ok
+}
+
+// restore adds a counter of a snapshot, merging it with the counter of the same log if there is one.
+func (s *scopeAggregator) restore(logRecord plog.LogRecord, count int64) {
+	firstObservedTimestamp := logRecord.ObservedTimestamp().AsTime().UTC()
+	lastObservedTimestamp := logRecord.Timestamp().AsTime().UTC()
+
+	key := getLogKey(logRecord)
+	lc, ok := s.logCounters[key]
+	if !ok {
+		s.logCounters[key] = &logCounter{
+			logRecord:              logRecord,
+			firstObservedTimestamp: firstObservedTimestamp,
+			lastObservedTimestamp:  lastObservedTimestamp,
+			count:                  count,
+		}
+		return
+	}
+
+	lc.count += count
+	if firstObservedTimestamp.Before(lc.firstObservedTimestamp) {
+		lc.firstObservedTimestamp = firstObservedTimestamp
+	}
+	if lastObservedTimestamp.After(lc.lastObservedTimestamp) {
+		lc.lastObservedTimestamp = lastObservedTimestamp
+	}
 }
 
 // logCounter is a counter for a log record.
@@ -90,6 +90,92 @@ func Test_logAggregatorAdd(t *testing.T) {
 	require.Equal(t, secondExpectedTimestamp, lc.lastObservedTimestamp)
 }
 
+func Test_logAggregatorAddFirstOccurrencePassthrough(t *testing.T) {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	aggregator := newLogAggregator("log_count", time.UTC, telemetryBuilder)
+	aggregator.firstOccurrencePassthrough = true
+
+	resource := pcommon.NewResource()
+	scope := pcommon.NewInstrumentationScope()
+	first := plog.NewLogRecord()
+	first.Body().SetStr("first")
+	second := plog.NewLogRecord()
+	second.Body().SetStr("second")
+
+	// The first occurrences are not counted
+	require.True(t, aggregator.Add(resource, scope, first))
+	require.True(t, aggregator.Add(resource, scope, second))
+	require.Equal(t, 0, aggregator.Export(context.Background()).LogRecordCount())
+
+	// Duplicates are counted
+	require.False(t, aggregator.Add(resource, scope, first))
+	require.False(t, aggregator.Add(resource, scope, first))
+
+	logs := aggregator.Export(context.Background())
+	require.Equal(t, 1, logs.LogRecordCount())
+	lr := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, "first", lr.Body().Str())
+	count, ok := lr.Attributes().Get("log_count")
+	require.True(t, ok)
+	require.Equal(t, int64(2), count.Int())
+}
+
+func Test_logAggregatorSnapshotRestore(t *testing.T) {
+	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	firstExpectedTimestamp := time.Now().UTC()
+	secondExpectedTimestamp := firstExpectedTimestamp.Add(time.Minute)
+	thirdExpectedTimestamp := secondExpectedTimestamp.Add(time.Minute)
+	t.Cleanup(func() {
+		timeNow = time.Now
+	})
+
+	resource := pcommon.NewResource()
+	resource.Attributes().PutStr("one", "two")
+	scope := pcommon.NewInstrumentationScope()
+	logRecord := plog.NewLogRecord()
+	logRecord.Body().SetStr("body")
+	passedThrough := plog.NewLogRecord()
+	passedThrough.Body().SetStr("passed through")
+
+	aggregator := newLogAggregator("log_count", time.UTC, telemetryBuilder)
+	aggregator.firstOccurrencePassthrough = true
+	timeNow = func() time.Time { return firstExpectedTimestamp }
+	aggregator.Add(resource, scope, logRecord)
+	aggregator.Add(resource, scope, passedThrough)
+	timeNow = func() time.Time { return secondExpectedTimestamp }
+	aggregator.Add(resource, scope, logRecord)
+
+	// The snapshot is restored in an aggregator that already counted the log
+	restored := newLogAggregator("log_count", time.UTC, telemetryBuilder)
+	restored.firstOccurrencePassthrough = true
+	timeNow = func() time.Time { return thirdExpectedTimestamp }
+	restored.Add(resource, scope, logRecord)
+	restored.Add(resource, scope, logRecord)
+	restored.Restore(aggregator.Snapshot())
+
+	resourceAggregator := restored.resources[getResourceKey(resource)]
+	require.NotNil(t, resourceAggregator)
+	scopeAggregator := resourceAggregator.scopeCounters[getScopeKey(scope)]
+	require.NotNil(t, scopeAggregator)
+	require.Len(t, scopeAggregator.logCounters, 2)
+
+	lc := scopeAggregator.logCounters[getLogKey(logRecord)]
+	require.NotNil(t, lc)
+	require.Equal(t, int64(2), lc.count)
+	require.Equal(t, firstExpectedTimestamp, lc.firstObservedTimestamp)
+	require.Equal(t, thirdExpectedTimestamp, lc.lastObservedTimestamp)
+
+	// The first occurrence of the passed through log is not emitted again
+	lc = scopeAggregator.logCounters[getLogKey(passedThrough)]
+	require.NotNil(t, lc)
+	require.Equal(t, int64(0), lc.count)
+	require.False(t, restored.Add(resource, scope, passedThrough))
+}
+
 func Test_logAggregatorReset(t *testing.T) {
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(componenttest.NewNopTelemetrySettings())
 	require.NoError(t, err)
@@ -11,6 +11,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/processor"
@@ -29,6 +30,11 @@ type logDedupProcessor struct {
 	remover      *fieldRemover
 	nextConsumer consumer.Logs
 	logger       *zap.Logger
+	id           component.ID
+	storageID    *component.ID
+	storage      storage.Client
+	passthrough  bool
+	windowStart  time.Time
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
 	mux          sync.Mutex
@@ -46,22 +52,46 @@ func newProcessor(cfg *Config, nextConsumer consumer.Logs, settings processor.Se
 		return nil, fmt.Errorf("invalid timezone: %w", err)
 	}
 
+	aggregator := newLogAggregator(cfg.LogCountAttribute, timezone, telemetryBuilder)
+	aggregator.firstOccurrencePassthrough = cfg.FirstOccurrencePassthrough
+
 	return &logDedupProcessor{
 		emitInterval: cfg.Interval,
-		aggregator:   newLogAggregator(cfg.LogCountAttribute, timezone, telemetryBuilder),
+		aggregator:   aggregator,
 		remover:      newFieldRemover(cfg.ExcludeFields),
 		nextConsumer: nextConsumer,
 		logger:       settings.Logger,
+		id:           settings.ID,
+		storageID:    cfg.StorageID,
+		storage:      storage.NewNopClient(),
+		passthrough:  cfg.FirstOccurrencePassthrough,
 	}, nil
 }
 
 // Start starts the processor.
-func (p *logDedupProcessor) Start(ctx context.Context, _ component.Host) error {
+func (p *logDedupProcessor) Start(ctx context.Context, host component.Host) error {
+	p.windowStart = timeNow()
+	if p.storageID != nil {
+		client, err := getStorageClient(ctx, host, *p.storageID, p.id)
+		if err != nil {
+			return err
+		}
+		p.storage = client
+
+		// Resume the window persisted by the last run
+		if err := p.restoreWindow(ctx); err != nil {
+			p.logger.Error("failed to restore persisted aggregation window", zap.Error(err))
+		}
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	p.cancel = cancel
 
+	// The first export happens when the restored window reaches the end of its interval
+	firstExport := max(p.emitInterval-timeNow().Sub(p.windowStart), 0)
+
 	p.wg.Add(1)
-	go p.handleExportInterval(ctx)
+	go p.handleExportInterval(ctx, firstExport)
 
 	return nil
 }
@@ -72,13 +102,13 @@ func (p *logDedupProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Shutdown stops the processor.
-func (p *logDedupProcessor) Shutdown(_ context.Context) error {
+func (p *logDedupProcessor) Shutdown(ctx context.Context) error {
 	if p.cancel != nil {
 		// Call cancel to stop the export interval goroutine and wait for it to finish.
 		p.cancel()
 		p.wg.Wait()
 	}
-	return nil
+	return p.storage.Close(ctx)
 }
 
 // ConsumeLogs processes the logs.
@@ -86,6 +116,7 @@ func (p *logDedupProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) error
 	p.mux.Lock()
 	defer p.mux.Unlock()
 
+	var aggregated bool
 	for i := 0; i < pl.ResourceLogs().Len(); i++ {
 		rl := pl.ResourceLogs().At(i)
 		resource := rl.Resource()
@@ -97,8 +128,8 @@ func (p *logDedupProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) error
 
 			logs.RemoveIf(func(logRecord plog.LogRecord) bool {
 				if p.conditions == nil {
-					p.aggregateLog(logRecord, scope, resource)
-					return true
+					aggregated = true
+					return p.aggregateLog(logRecord, scope, resource)
 				}
 
 				logCtx := ottllog.NewTransformContext(logRecord, scope, resource, sl, rl)
@@ -107,14 +138,20 @@ func (p *logDedupProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) error
 					p.logger.Error("error matching conditions", zap.Error(err))
 					return false
 				}
-				if logMatch {
-					p.aggregateLog(logRecord, scope, resource)
+				if !logMatch {
+					return false
 				}
-				return logMatch
+				aggregated = true
+				return p.aggregateLog(logRecord, scope, resource)
 			})
 		}
 	}
 
+	// Persist the window after each batch that changed it, so its counts survive a crash
+	if p.storageID != nil && aggregated {
+		p.persistWindow(ctx)
+	}
+
 	// immediately consume any logs that didn't match any conditions
 	if pl.LogRecordCount() > 0 {
 		err := p.nextConsumer.ConsumeLogs(ctx, pl)
@@ -126,29 +163,46 @@ func (p *logDedupProcessor) ConsumeLogs(ctx context.Context, pl plog.Logs) error
 	return nil
 }
 
-func (p *logDedupProcessor) aggregateLog(logRecord plog.LogRecord, scope pcommon.InstrumentationScope, resource pcommon.Resource) {
-	p.remover.RemoveFields(logRecord)
-	p.aggregator.Add(resource, scope, logRecord)
+// aggregateLog adds the logRecord to the aggregator and returns true if it must be removed from the batch.
+// In passthrough mode, the first occurrence of a log is kept in the batch untouched.
+func (p *logDedupProcessor) aggregateLog(logRecord plog.LogRecord, scope pcommon.InstrumentationScope, resource pcommon.Resource) bool {
+	if !p.passthrough {
+		p.remover.RemoveFields(logRecord)
+		p.aggregator.Add(resource, scope, logRecord)
+		return true
+	}
+
+	lr := plog.NewLogRecord()
+	logRecord.CopyTo(lr)
+	p.remover.RemoveFields(lr)
+	return !p.aggregator.Add(resource, scope, lr)
 }
 
-// handleExportInterval sends metrics at the configured interval.
-func (p *logDedupProcessor) handleExportInterval(ctx context.Context) {
+// handleExportInterval sends metrics at the configured interval, starting after firstExport.
+func (p *logDedupProcessor) handleExportInterval(ctx context.Context, firstExport time.Duration) {
 	defer p.wg.Done()
 
-	ticker := time.NewTicker(p.emitInterval)
-	defer ticker.Stop()
+	timer := time.NewTimer(firstExport)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			// Export any remaining logs
-			p.exportLogs(ctx)
+			// Persist any remaining logs so they are restored on the next start, or export them
+			if p.storageID != nil {
+				p.mux.Lock()
+				p.persistWindow(context.WithoutCancel(ctx))
+				p.mux.Unlock()
+			} else {
+				p.exportLogs(ctx)
+			}
 			if err := ctx.Err(); err != context.Canceled {
 				p.logger.Error("context error", zap.Error(err))
 			}
 			return
-		case <-ticker.C:
+		case <-timer.C:
 			p.exportLogs(ctx)
+			timer.Reset(p.emitInterval)
 		}
 	}
 }
@@ -167,4 +221,8 @@ func (p *logDedupProcessor) exportLogs(ctx context.Context) {
 		}
 	}
 	p.aggregator.Reset()
+	p.windowStart = timeNow()
+	if p.storageID != nil {
+		p.persistWindow(ctx)
+	}
 }
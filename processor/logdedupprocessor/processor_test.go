@@ -12,12 +12,14 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/processor/processortest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/golden"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/plogtest"
 )
@@ -308,3 +310,189 @@ func TestProcessorConsumeMultipleConditions(t *testing.T) {
 	err = p.Shutdown(context.Background())
 	require.NoError(t, err)
 }
+
+func TestProcessorFirstOccurrencePassthrough(t *testing.T) {
+	logsSink := &consumertest.LogsSink{}
+	cfg := &Config{
+		LogCountAttribute:          defaultLogCountAttribute,
+		Interval:                   1 * time.Second,
+		Timezone:                   defaultTimezone,
+		Conditions:                 []string{},
+		FirstOccurrencePassthrough: true,
+	}
+
+	p, err := createLogsProcessor(context.Background(), processortest.NewNopSettings(), cfg, logsSink)
+	require.NoError(t, err)
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+
+	newLogs := func(bodies ...string) plog.Logs {
+		logs := plog.NewLogs()
+		sl := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+		for _, body := range bodies {
+			sl.LogRecords().AppendEmpty().Body().SetStr(body)
+		}
+		return logs
+	}
+
+	// The first occurrence of each log is emitted immediately
+	err = p.ConsumeLogs(context.Background(), newLogs("a", "b", "a"))
+	require.NoError(t, err)
+	require.Len(t, logsSink.AllLogs(), 1)
+	passedThrough := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, passedThrough.Len())
+	require.Equal(t, "a", passedThrough.At(0).Body().Str())
+	require.Equal(t, "b", passedThrough.At(1).Body().Str())
+	_, ok := passedThrough.At(0).Attributes().Get(defaultLogCountAttribute)
+	require.False(t, ok)
+
+	// Later duplicates are aggregated
+	err = p.ConsumeLogs(context.Background(), newLogs("a"))
+	require.NoError(t, err)
+	require.Len(t, logsSink.AllLogs(), 1)
+
+	err = p.Shutdown(context.Background())
+	require.NoError(t, err)
+
+	// Only the duplicates of "a" are counted, "b" was never duplicated
+	require.Len(t, logsSink.AllLogs(), 2)
+	aggregated := logsSink.AllLogs()[1].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 1, aggregated.Len())
+	require.Equal(t, "a", aggregated.At(0).Body().Str())
+	count, ok := aggregated.At(0).Attributes().Get(defaultLogCountAttribute)
+	require.True(t, ok)
+	require.Equal(t, int64(2), count.Int())
+}
+
+func TestProcessorPersistedWindowIsRestoredOnStart(t *testing.T) {
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("test", t.TempDir())
+	storageID := storagetest.NewStorageID("test")
+	cfg := &Config{
+		LogCountAttribute: defaultLogCountAttribute,
+		Interval:          time.Hour,
+		Timezone:          defaultTimezone,
+		Conditions:        []string{},
+		StorageID:         &storageID,
+	}
+
+	newLogs := func(bodies ...string) plog.Logs {
+		logs := plog.NewLogs()
+		lrs := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+		for _, body := range bodies {
+			lrs.AppendEmpty().Body().SetStr(body)
+		}
+		return logs
+	}
+
+	// Both processors must share the same component ID to share the storage
+	settings := processortest.NewNopSettings()
+
+	// The first processor is stopped in the middle of the interval, its window is persisted instead of exported
+	stoppedSink := &consumertest.LogsSink{}
+	stopped, err := newProcessor(cfg, stoppedSink, settings)
+	require.NoError(t, err)
+	require.NoError(t, stopped.Start(context.Background(), host))
+	require.NoError(t, stopped.ConsumeLogs(context.Background(), newLogs("a", "a")))
+	windowStart := stopped.windowStart
+	require.NoError(t, stopped.Shutdown(context.Background()))
+	require.Empty(t, stoppedSink.AllLogs())
+
+	// The window persisted by the first processor is restored on start, and keeps running
+	logsSink := &consumertest.LogsSink{}
+	p, err := newProcessor(cfg, logsSink, settings)
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), host))
+	require.Empty(t, logsSink.AllLogs())
+	require.True(t, windowStart.Equal(p.windowStart))
+
+	require.NoError(t, p.ConsumeLogs(context.Background(), newLogs("a")))
+	p.exportLogs(context.Background())
+
+	require.Len(t, logsSink.AllLogs(), 1)
+	exported := logsSink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 1, exported.Len())
+	count, ok := exported.At(0).Attributes().Get(defaultLogCountAttribute)
+	require.True(t, ok)
+	require.Equal(t, int64(3), count.Int())
+
+	require.NoError(t, p.Shutdown(context.Background()))
+}
+
+func TestProcessorPersistsWindowAfterEachBatch(t *testing.T) {
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("test", t.TempDir())
+	storageID := storagetest.NewStorageID("test")
+	cfg := &Config{
+		LogCountAttribute: defaultLogCountAttribute,
+		Interval:          time.Hour,
+		Timezone:          defaultTimezone,
+		Conditions:        []string{},
+		StorageID:         &storageID,
+	}
+
+	p, err := newProcessor(cfg, &consumertest.LogsSink{}, processortest.NewNopSettings())
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	persistedCount := func() int64 {
+		data, err := p.storage.Get(context.Background(), windowKey)
+		require.NoError(t, err)
+		require.NotNil(t, data)
+		unmarshaler := plog.ProtoUnmarshaler{}
+		logs, err := unmarshaler.UnmarshalLogs(data)
+		require.NoError(t, err)
+		if logs.LogRecordCount() == 0 {
+			return 0
+		}
+		count, ok := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().Get(defaultLogCountAttribute)
+		require.True(t, ok)
+		return count.Int()
+	}
+
+	// The window is persisted without waiting for a shutdown, so a crash does not lose its counts
+	logs := plog.NewLogs()
+	lrs := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	lrs.AppendEmpty().Body().SetStr("a")
+	lrs.AppendEmpty().Body().SetStr("a")
+	require.NoError(t, p.ConsumeLogs(context.Background(), logs))
+	require.Equal(t, int64(2), persistedCount())
+
+	// The persisted window is emptied once it is exported
+	p.exportLogs(context.Background())
+	require.Equal(t, int64(0), persistedCount())
+}
+
+func TestProcessorStartStorageErrors(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		storageID component.ID
+		err       string
+	}{
+		{
+			desc:      "missing extension",
+			storageID: storagetest.NewStorageID("missing"),
+			err:       "storage extension 'test_storage/missing' not found",
+		},
+		{
+			desc:      "non-storage extension",
+			storageID: storagetest.NewNonStorageID("test"),
+			err:       "non-storage extension 'non_storage/test' found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.StorageID = &tc.storageID
+
+			p, err := newProcessor(cfg, &consumertest.LogsSink{}, processortest.NewNopSettings())
+			require.NoError(t, err)
+
+			host := storagetest.NewStorageHost().WithNonStorageExtension("test")
+			require.EqualError(t, p.Start(context.Background(), host), tc.err)
+			require.NoError(t, p.Shutdown(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package logdedupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/logdedupprocessor"
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+)
+
+// Storage keys of the persisted aggregation window.
+const (
+	windowKey      = "window"
+	windowStartKey = "window_start"
+)
+
+// getStorageClient returns the client of the storage extension identified by storageID.
+func getStorageClient(ctx context.Context, host component.Host, storageID component.ID, componentID component.ID) (storage.Client, error) {
+	extension, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExtension, ok := extension.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExtension.GetClient(ctx, component.KindProcessor, componentID, "")
+}
+
+// persistWindow saves the counters of the current aggregation window and the time it started,
+// so they can be restored after a restart. The caller must hold the lock of the processor.
+func (p *logDedupProcessor) persistWindow(ctx context.Context) {
+	marshaler := plog.ProtoMarshaler{}
+	window, err := marshaler.MarshalLogs(p.aggregator.Snapshot())
+	if err != nil {
+		p.logger.Error("failed to marshal aggregation window", zap.Error(err))
+		return
+	}
+	windowStart, err := p.windowStart.MarshalBinary()
+	if err != nil {
+		p.logger.Error("failed to marshal aggregation window start", zap.Error(err))
+		return
+	}
+
+	err = p.storage.Batch(ctx,
+		storage.SetOperation(windowKey, window),
+		storage.SetOperation(windowStartKey, windowStart),
+	)
+	if err != nil {
+		p.logger.Error("failed to persist aggregation window", zap.Error(err))
+	}
+}
+
+// restoreWindow loads the counters and the start of the aggregation window persisted by a previous run,
+// so the window keeps running from where it stopped.
+func (p *logDedupProcessor) restoreWindow(ctx context.Context) error {
+	window := storage.GetOperation(windowKey)
+	windowStart := storage.GetOperation(windowStartKey)
+	if err := p.storage.Batch(ctx, window, windowStart); err != nil {
+		return fmt.Errorf("failed to read persisted aggregation window: %w", err)
+	}
+
+	if windowStart.Value != nil {
+		var start time.Time
+		if err := start.UnmarshalBinary(windowStart.Value); err != nil {
+			return fmt.Errorf("failed to unmarshal persisted aggregation window start: %w", err)
+		}
+		p.windowStart = start
+	}
+
+	if window.Value != nil {
+		unmarshaler := plog.ProtoUnmarshaler{}
+		logs, err := unmarshaler.UnmarshalLogs(window.Value)
+		if err != nil {
+			return fmt.Errorf("failed to unmarshal persisted aggregation window: %w", err)
+		}
+		p.aggregator.Restore(logs)
+	}
+
+	return nil
+}
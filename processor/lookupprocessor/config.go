@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+type ContextID string
+
+const (
+	resource ContextID = "resource"
+	record   ContextID = "record"
+)
+
+func (c *ContextID) UnmarshalText(text []byte) error {
+	str := ContextID(strings.ToLower(string(text)))
+	switch str {
+	case resource, record:
+		*c = str
+		return nil
+	default:
+		return fmt.Errorf("unknown context %s, available values: %s, %s", str, resource, record)
+	}
+}
+
+type Action string
+
+const (
+	insert Action = "insert"
+	upsert Action = "upsert"
+)
+
+func (a *Action) UnmarshalText(text []byte) error {
+	str := Action(strings.ToLower(string(text)))
+	switch str {
+	case insert, upsert:
+		*a = str
+		return nil
+	default:
+		return fmt.Errorf("unknown action %s, available values: %s, %s", str, insert, upsert)
+	}
+}
+
+// Config holds the configuration for the lookup processor.
+type Config struct {
+	// Tables are the lookup tables loaded by the processor.
+	// They are also made available to the OTTL Lookup converter used in Statements.
+	Tables []lookup.TableConfig `mapstructure:"tables"`
+
+	// Lookups are the enrichments applied to the telemetry passing through the processor.
+	Lookups []LookupConfig `mapstructure:"lookups"`
+
+	// Statements are OTTL statements executed after the lookups. They can query the tables
+	// of this processor with the Lookup converter.
+	Statements StatementsConfig `mapstructure:"statements"`
+
+	// ErrorMode determines how the processor reacts to errors returned by the tables.
+	ErrorMode ottl.ErrorMode `mapstructure:"error_mode"`
+}
+
+// StatementsConfig holds the OTTL statements executed for each signal.
+type StatementsConfig struct {
+	// Logs are executed in the log context.
+	Logs []string `mapstructure:"logs"`
+	// Metrics are executed in the datapoint context.
+	Metrics []string `mapstructure:"metrics"`
+	// Traces are executed in the span context.
+	Traces []string `mapstructure:"traces"`
+}
+
+// LookupConfig configures an enrichment: the value of the key attribute is looked up in the table,
+// and the columns of the matching row are copied to the attributes of the telemetry.
+type LookupConfig struct {
+	// Table is the name of the table to query.
+	Table string `mapstructure:"table"`
+
+	// Context is where the key attribute is read from and the columns are written to: resource or record.
+	// Defaults to resource.
+	Context ContextID `mapstructure:"context"`
+
+	// Key is the attribute holding the key to look up.
+	Key string `mapstructure:"key"`
+
+	// Attributes maps the columns of the table to attributes.
+	Attributes []AttributeConfig `mapstructure:"attributes"`
+
+	// Action is insert to keep existing attributes, or upsert to overwrite them. Defaults to upsert.
+	Action Action `mapstructure:"action"`
+}
+
+// AttributeConfig maps a column of a table to an attribute.
+type AttributeConfig struct {
+	// Column is the name of the column to copy.
+	Column string `mapstructure:"column"`
+	// Target is the name of the attribute to write. Defaults to the column name.
+	Target string `mapstructure:"target"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+func (cfg *Config) Validate() error {
+	if len(cfg.Tables) == 0 {
+		return errors.New("must specify at least one table when using the lookup processor")
+	}
+
+	if err := lookup.ValidateTables(cfg.Tables); err != nil {
+		return err
+	}
+	tables := make(map[string]struct{}, len(cfg.Tables))
+	for _, table := range cfg.Tables {
+		tables[table.Name] = struct{}{}
+	}
+
+	for i, lookupCfg := range cfg.Lookups {
+		if _, ok := tables[lookupCfg.Table]; !ok {
+			return fmt.Errorf("lookup %d: unknown table %q", i, lookupCfg.Table)
+		}
+		if lookupCfg.Key == "" {
+			return fmt.Errorf("lookup %d: 'key' cannot be empty", i)
+		}
+		if len(lookupCfg.Attributes) == 0 {
+			return fmt.Errorf("lookup %d: 'attributes' cannot be empty", i)
+		}
+		for _, attribute := range lookupCfg.Attributes {
+			if attribute.Column == "" {
+				return fmt.Errorf("lookup %d: attribute 'column' cannot be empty", i)
+			}
+		}
+	}
+
+	// Tables are only needed to execute the statements, their names are enough to parse them.
+	lookupTables := make(map[string]ottlfuncs.LookupTable, len(tables))
+	for name := range tables {
+		lookupTables[name] = nil
+	}
+	if _, err := newStatements(cfg.Statements, lookupTables, cfg.ErrorMode, component.TelemetrySettings{Logger: zap.NewNop()}); err != nil {
+		return fmt.Errorf("invalid statements: %w", err)
+	}
+	return nil
+}
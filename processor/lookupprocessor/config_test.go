@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id                    component.ID
+		expected              component.Config
+		validateErrorMessage  string
+		unmarshalErrorMessage string
+	}{
+		{
+			id:                   component.NewID(metadata.Type),
+			validateErrorMessage: "must specify at least one table when using the lookup processor",
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "full"),
+			expected: &Config{
+				Tables: []lookup.TableConfig{
+					{
+						Name: "teams",
+						CSV: &lookup.CSVConfig{
+							FileConfig: lookup.FileConfig{Path: "./testdata/teams.csv", ReloadInterval: 30 * time.Second},
+							KeyColumn:  "service",
+						},
+					},
+					{
+						Name: "cost_centers",
+						JSON: &lookup.JSONConfig{
+							FileConfig: lookup.FileConfig{Path: "./testdata/cost_centers.json"},
+							KeyField:   "namespace",
+						},
+					},
+					{
+						Name: "tiers",
+						SQL: &lookup.SQLConfig{
+							Driver:     "postgres",
+							DataSource: "host=localhost port=5432 user=otel password=otel sslmode=disable",
+							Query:      "SELECT tier FROM customers WHERE tenant_id = $1",
+							Timeout:    2 * time.Second,
+							Cache:      lookup.CacheConfig{MaxSize: 5000, TTL: 5 * time.Minute},
+						},
+					},
+				},
+				Lookups: []LookupConfig{
+					{
+						Table: "teams",
+						Key:   "service.name",
+						Attributes: []AttributeConfig{
+							{Column: "team", Target: "team.name"},
+							{Column: "owner"},
+						},
+					},
+					{
+						Table:      "tiers",
+						Context:    record,
+						Key:        "tenant.id",
+						Action:     insert,
+						Attributes: []AttributeConfig{{Column: "tier"}},
+					},
+				},
+				Statements: StatementsConfig{
+					Logs: []string{`set(attributes["team.slack"], Lookup("teams", resource.attributes["service.name"])["slack"])`},
+				},
+				ErrorMode: ottl.IgnoreError,
+			},
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "no_source"),
+			validateErrorMessage: `table "teams": exactly one of 'csv', 'json' or 'sql' must be set`,
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "two_sources"),
+			validateErrorMessage: `table "teams": exactly one of 'csv', 'json' or 'sql' must be set`,
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "duplicate_table"),
+			validateErrorMessage: `table "teams" is defined more than once`,
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "unknown_table"),
+			validateErrorMessage: `lookup 0: unknown table "owners"`,
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "unknown_statement_table"),
+			validateErrorMessage: `unknown lookup table "owners"`,
+		},
+		{
+			id:                   component.NewIDWithName(metadata.Type, "invalid_source"),
+			validateErrorMessage: "'key_column' cannot be empty",
+		},
+		{
+			id:                    component.NewIDWithName(metadata.Type, "invalid_context"),
+			unmarshalErrorMessage: "unknown context scope, available values: resource, record",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+			require.NoError(t, err)
+
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+
+			if tt.unmarshalErrorMessage != "" {
+				assert.ErrorContains(t, sub.Unmarshal(cfg), tt.unmarshalErrorMessage)
+				return
+			}
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.validateErrorMessage != "" {
+				assert.ErrorContains(t, component.ValidateConfig(cfg), tt.validateErrorMessage)
+				return
+			}
+
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor/internal/metadata"
+)
+
+var processorCapabilities = consumer.Capabilities{MutatesData: true}
+
+// processors shares a single instance between the pipelines using the same configuration,
+// so that its tables are loaded only once.
+var processors = sharedcomponent.NewSharedComponents()
+
+// NewFactory returns a new factory for the lookup processor.
+func NewFactory() processor.Factory {
+	return processor.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		processor.WithLogs(createLogsProcessor, metadata.LogsStability),
+		processor.WithMetrics(createMetricsProcessor, metadata.MetricsStability),
+		processor.WithTraces(createTracesProcessor, metadata.TracesStability),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		ErrorMode: ottl.PropagateError,
+	}
+}
+
+func getOrCreateProcessor(set processor.Settings, cfg component.Config) (*sharedcomponent.SharedComponent, error) {
+	var err error
+	p := processors.GetOrAdd(cfg, func() component.Component {
+		var lp *lookupProcessor
+		lp, err = newLookupProcessor(cfg.(*Config), set, lookup.NewSource)
+		return lp
+	})
+	return p, err
+}
+
+func createLogsProcessor(ctx context.Context, set processor.Settings, cfg component.Config, nextConsumer consumer.Logs) (processor.Logs, error) {
+	p, err := getOrCreateProcessor(set, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewLogs(ctx, set, cfg, nextConsumer,
+		p.Unwrap().(*lookupProcessor).processLogs,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(p.Start),
+		processorhelper.WithShutdown(p.Shutdown))
+}
+
+func createMetricsProcessor(ctx context.Context, set processor.Settings, cfg component.Config, nextConsumer consumer.Metrics) (processor.Metrics, error) {
+	p, err := getOrCreateProcessor(set, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewMetrics(ctx, set, cfg, nextConsumer,
+		p.Unwrap().(*lookupProcessor).processMetrics,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(p.Start),
+		processorhelper.WithShutdown(p.Shutdown))
+}
+
+func createTracesProcessor(ctx context.Context, set processor.Settings, cfg component.Config, nextConsumer consumer.Traces) (processor.Traces, error) {
+	p, err := getOrCreateProcessor(set, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewTraces(ctx, set, cfg, nextConsumer,
+		p.Unwrap().(*lookupProcessor).processTraces,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(p.Start),
+		processorhelper.WithShutdown(p.Shutdown))
+}
@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/processor"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+// newSourceFunc creates the source of a table. It is replaced in tests.
+type newSourceFunc func(cfg lookup.TableConfig, logger *zap.Logger) lookup.Source
+
+type lookupProcessor struct {
+	cfg    *Config
+	logger *zap.Logger
+
+	tables     map[string]lookup.Source
+	statements statements
+}
+
+func newLookupProcessor(cfg *Config, set processor.Settings, newSource newSourceFunc) (*lookupProcessor, error) {
+	tables := make(map[string]lookup.Source, len(cfg.Tables))
+	lookupTables := make(map[string]ottlfuncs.LookupTable, len(cfg.Tables))
+	for _, table := range cfg.Tables {
+		tables[table.Name] = newSource(table, set.Logger.With(zap.String("table", table.Name)))
+		lookupTables[table.Name] = tables[table.Name]
+	}
+	statements, err := newStatements(cfg.Statements, lookupTables, cfg.ErrorMode, set.TelemetrySettings)
+	if err != nil {
+		return nil, err
+	}
+	return &lookupProcessor{
+		cfg:        cfg,
+		logger:     set.Logger,
+		tables:     tables,
+		statements: statements,
+	}, nil
+}
+
+// Start loads the tables.
+func (p *lookupProcessor) Start(ctx context.Context, _ component.Host) error {
+	for _, table := range p.cfg.Tables {
+		if err := p.tables[table.Name].Start(ctx); err != nil {
+			return errors.Join(fmt.Errorf("failed to load table %q: %w", table.Name, err), p.Shutdown(ctx))
+		}
+	}
+	return nil
+}
+
+func (p *lookupProcessor) Shutdown(ctx context.Context) error {
+	var errs []error
+	for name, table := range p.tables {
+		if err := table.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shutdown table %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// enrich applies the lookups of the given context to the attributes.
+func (p *lookupProcessor) enrich(ctx context.Context, contextID ContextID, attributes pcommon.Map) error {
+	for _, lookupCfg := range p.cfg.Lookups {
+		if lookupContext(lookupCfg) != contextID {
+			continue
+		}
+		key, ok := attributes.Get(lookupCfg.Key)
+		if !ok {
+			continue
+		}
+		row, found, err := p.tables[lookupCfg.Table].Lookup(ctx, key.AsString())
+		if err != nil {
+			if p.cfg.ErrorMode == ottl.PropagateError {
+				return fmt.Errorf("failed to lookup %q in table %q: %w", key.AsString(), lookupCfg.Table, err)
+			}
+			if p.cfg.ErrorMode == ottl.IgnoreError {
+				p.logger.Warn("failed to lookup key", zap.String("table", lookupCfg.Table), zap.String("key", key.AsString()), zap.Error(err))
+			}
+			continue
+		}
+		if !found {
+			continue
+		}
+		if err = setAttributes(lookupCfg, row, attributes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setAttributes(lookupCfg LookupConfig, row map[string]any, attributes pcommon.Map) error {
+	for _, attribute := range lookupCfg.Attributes {
+		value, ok := row[attribute.Column]
+		if !ok {
+			continue
+		}
+		target := attribute.Target
+		if target == "" {
+			target = attribute.Column
+		}
+		if _, exists := attributes.Get(target); exists && lookupCfg.Action == insert {
+			continue
+		}
+		if err := attributes.PutEmpty(target).FromRaw(value); err != nil {
+			return fmt.Errorf("failed to set attribute %q: %w", target, err)
+		}
+	}
+	return nil
+}
+
+func lookupContext(lookupCfg LookupConfig) ContextID {
+	if lookupCfg.Context == "" {
+		return resource
+	}
+	return lookupCfg.Context
+}
+
+// hasContext returns true if at least one lookup applies to the given context.
+func (p *lookupProcessor) hasContext(contextID ContextID) bool {
+	for _, lookupCfg := range p.cfg.Lookups {
+		if lookupContext(lookupCfg) == contextID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+)
+
+func (p *lookupProcessor) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	enrichRecords := p.hasContext(record)
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if err := p.enrich(ctx, resource, rl.Resource().Attributes()); err != nil {
+			return ld, err
+		}
+		if !enrichRecords && p.statements.logs == nil {
+			continue
+		}
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			lrs := sl.LogRecords()
+			for k := 0; k < lrs.Len(); k++ {
+				if enrichRecords {
+					if err := p.enrich(ctx, record, lrs.At(k).Attributes()); err != nil {
+						return ld, err
+					}
+				}
+				if p.statements.logs != nil {
+					tCtx := ottllog.NewTransformContext(lrs.At(k), sl.Scope(), rl.Resource(), sl, rl)
+					if err := p.statements.logs.Execute(ctx, tCtx); err != nil {
+						return ld, err
+					}
+				}
+			}
+		}
+	}
+	return ld, nil
+}
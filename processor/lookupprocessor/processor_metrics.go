@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func (p *lookupProcessor) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	enrichRecords := p.hasContext(record)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if err := p.enrich(ctx, resource, rm.Resource().Attributes()); err != nil {
+			return md, err
+		}
+		if !enrichRecords && p.statements.dataPoints == nil {
+			continue
+		}
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sm := sms.At(j)
+			metrics := sm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				if enrichRecords {
+					if err := p.enrichDataPoints(ctx, metrics.At(k)); err != nil {
+						return md, err
+					}
+				}
+				if p.statements.dataPoints != nil {
+					if err := p.statements.executeDataPoints(ctx, metrics.At(k), metrics, sm, rm); err != nil {
+						return md, err
+					}
+				}
+			}
+		}
+	}
+	return md, nil
+}
+
+// enrichDataPoints applies the record lookups to the attributes of every data point of the metric.
+func (p *lookupProcessor) enrichDataPoints(ctx context.Context, metric pmetric.Metric) error {
+	var attributes []pcommon.Map
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attributes = append(attributes, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attributes = append(attributes, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attributes = append(attributes, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attributes = append(attributes, dps.At(i).Attributes())
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			attributes = append(attributes, dps.At(i).Attributes())
+		}
+	}
+	for _, attrs := range attributes {
+		if err := p.enrich(ctx, record, attrs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
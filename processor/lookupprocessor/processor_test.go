@@ -0,0 +1,359 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor/processortest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type fakeSource struct {
+	rows     map[string]map[string]any
+	startErr error
+	started  bool
+	stopped  bool
+}
+
+func (s *fakeSource) Start(context.Context) error {
+	s.started = true
+	return s.startErr
+}
+
+func (s *fakeSource) Lookup(_ context.Context, key string) (map[string]any, bool, error) {
+	if key == "error" {
+		return nil, false, errors.New("lookup failed")
+	}
+	row, ok := s.rows[key]
+	return row, ok, nil
+}
+
+func (s *fakeSource) Shutdown(context.Context) error {
+	s.stopped = true
+	return nil
+}
+
+func newTestProcessor(t *testing.T, cfg *Config, sources map[string]*fakeSource) *lookupProcessor {
+	p, err := newLookupProcessor(cfg, processortest.NewNopSettings(), func(table lookup.TableConfig, _ *zap.Logger) lookup.Source {
+		return sources[table.Name]
+	})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	})
+	return p
+}
+
+func teamsSource() *fakeSource {
+	return &fakeSource{rows: map[string]map[string]any{
+		"checkout": {"team": "payments", "owner": "alice", "tier": int64(1)},
+	}}
+}
+
+func TestEnrich(t *testing.T) {
+	tests := []struct {
+		name      string
+		lookup    LookupConfig
+		errorMode ottl.ErrorMode
+		input     map[string]any
+		expected  map[string]any
+		err       string
+	}{
+		{
+			name: "upsert",
+			lookup: LookupConfig{
+				Table:      "teams",
+				Key:        "service.name",
+				Attributes: []AttributeConfig{{Column: "team", Target: "team.name"}, {Column: "tier"}, {Column: "missing"}},
+			},
+			input:    map[string]any{"service.name": "checkout", "team.name": "unknown"},
+			expected: map[string]any{"service.name": "checkout", "team.name": "payments", "tier": int64(1)},
+		},
+		{
+			name: "insert",
+			lookup: LookupConfig{
+				Table:      "teams",
+				Key:        "service.name",
+				Action:     insert,
+				Attributes: []AttributeConfig{{Column: "team", Target: "team.name"}, {Column: "owner"}},
+			},
+			input:    map[string]any{"service.name": "checkout", "team.name": "unknown"},
+			expected: map[string]any{"service.name": "checkout", "team.name": "unknown", "owner": "alice"},
+		},
+		{
+			name: "key not found",
+			lookup: LookupConfig{
+				Table:      "teams",
+				Key:        "service.name",
+				Attributes: []AttributeConfig{{Column: "team"}},
+			},
+			input:    map[string]any{"service.name": "cart"},
+			expected: map[string]any{"service.name": "cart"},
+		},
+		{
+			name: "missing key attribute",
+			lookup: LookupConfig{
+				Table:      "teams",
+				Key:        "service.name",
+				Attributes: []AttributeConfig{{Column: "team"}},
+			},
+			input:    map[string]any{"host.name": "checkout"},
+			expected: map[string]any{"host.name": "checkout"},
+		},
+		{
+			name: "propagate error",
+			lookup: LookupConfig{
+				Table:      "teams",
+				Key:        "service.name",
+				Attributes: []AttributeConfig{{Column: "team"}},
+			},
+			errorMode: ottl.PropagateError,
+			input:     map[string]any{"service.name": "error"},
+			err:       `failed to lookup "error" in table "teams": lookup failed`,
+		},
+		{
+			name: "ignore error",
+			lookup: LookupConfig{
+				Table:      "teams",
+				Key:        "service.name",
+				Attributes: []AttributeConfig{{Column: "team"}},
+			},
+			errorMode: ottl.IgnoreError,
+			input:     map[string]any{"service.name": "error"},
+			expected:  map[string]any{"service.name": "error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Tables:    []lookup.TableConfig{{Name: "teams"}},
+				Lookups:   []LookupConfig{tt.lookup},
+				ErrorMode: tt.errorMode,
+			}
+			p := newTestProcessor(t, cfg, map[string]*fakeSource{"teams": teamsSource()})
+
+			attributes := pcommon.NewMap()
+			require.NoError(t, attributes.FromRaw(tt.input))
+			err := p.enrich(context.Background(), resource, attributes)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, attributes.AsRaw())
+		})
+	}
+}
+
+func TestProcessSignals(t *testing.T) {
+	cfg := &Config{
+		Tables: []lookup.TableConfig{{Name: "teams"}},
+		Lookups: []LookupConfig{
+			{Table: "teams", Key: "service.name", Attributes: []AttributeConfig{{Column: "team"}}},
+			{Table: "teams", Context: record, Key: "peer.service", Attributes: []AttributeConfig{{Column: "owner", Target: "peer.owner"}}},
+		},
+	}
+	p := newTestProcessor(t, cfg, map[string]*fakeSource{"teams": teamsSource()})
+
+	t.Run("logs", func(t *testing.T) {
+		ld := plog.NewLogs()
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "checkout")
+		lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		lr.Attributes().PutStr("peer.service", "checkout")
+
+		_, err := p.processLogs(context.Background(), ld)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"service.name": "checkout", "team": "payments"}, rl.Resource().Attributes().AsRaw())
+		assert.Equal(t, map[string]any{"peer.service": "checkout", "peer.owner": "alice"}, lr.Attributes().AsRaw())
+	})
+
+	t.Run("traces", func(t *testing.T) {
+		td := ptrace.NewTraces()
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", "checkout")
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.Attributes().PutStr("peer.service", "checkout")
+
+		_, err := p.processTraces(context.Background(), td)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"service.name": "checkout", "team": "payments"}, rs.Resource().Attributes().AsRaw())
+		assert.Equal(t, map[string]any{"peer.service": "checkout", "peer.owner": "alice"}, span.Attributes().AsRaw())
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		md := pmetric.NewMetrics()
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("service.name", "checkout")
+		metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+		metrics.AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes().PutStr("peer.service", "checkout")
+		metrics.AppendEmpty().SetEmptySum().DataPoints().AppendEmpty().Attributes().PutStr("peer.service", "checkout")
+		metrics.AppendEmpty().SetEmptyHistogram().DataPoints().AppendEmpty().Attributes().PutStr("peer.service", "checkout")
+		metrics.AppendEmpty().SetEmptyExponentialHistogram().DataPoints().AppendEmpty().Attributes().PutStr("peer.service", "checkout")
+		metrics.AppendEmpty().SetEmptySummary().DataPoints().AppendEmpty().Attributes().PutStr("peer.service", "checkout")
+
+		_, err := p.processMetrics(context.Background(), md)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"service.name": "checkout", "team": "payments"}, rm.Resource().Attributes().AsRaw())
+		expected := map[string]any{"peer.service": "checkout", "peer.owner": "alice"}
+		assert.Equal(t, expected, metrics.At(0).Gauge().DataPoints().At(0).Attributes().AsRaw())
+		assert.Equal(t, expected, metrics.At(1).Sum().DataPoints().At(0).Attributes().AsRaw())
+		assert.Equal(t, expected, metrics.At(2).Histogram().DataPoints().At(0).Attributes().AsRaw())
+		assert.Equal(t, expected, metrics.At(3).ExponentialHistogram().DataPoints().At(0).Attributes().AsRaw())
+		assert.Equal(t, expected, metrics.At(4).Summary().DataPoints().At(0).Attributes().AsRaw())
+	})
+}
+
+func TestStartLoadsTables(t *testing.T) {
+	teams := teamsSource()
+	p, err := newLookupProcessor(&Config{Tables: []lookup.TableConfig{{Name: "teams"}}}, processortest.NewNopSettings(), func(lookup.TableConfig, *zap.Logger) lookup.Source {
+		return teams
+	})
+	require.NoError(t, err)
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	assert.True(t, teams.started)
+
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.True(t, teams.stopped)
+}
+
+func TestStartError(t *testing.T) {
+	teams := teamsSource()
+	broken := &fakeSource{startErr: errors.New("missing file")}
+	sources := map[string]*fakeSource{"teams": teams, "broken": broken}
+	p, err := newLookupProcessor(&Config{Tables: []lookup.TableConfig{{Name: "teams"}, {Name: "broken"}}}, processortest.NewNopSettings(), func(table lookup.TableConfig, _ *zap.Logger) lookup.Source {
+		return sources[table.Name]
+	})
+	require.NoError(t, err)
+
+	assert.EqualError(t, p.Start(context.Background(), componenttest.NewNopHost()), `failed to load table "broken": missing file`)
+	assert.True(t, teams.stopped)
+}
+
+func TestStatements(t *testing.T) {
+	cfg := &Config{
+		Tables: []lookup.TableConfig{{Name: "teams"}},
+		Statements: StatementsConfig{
+			Logs:    []string{`set(attributes["team"], Lookup("teams", resource.attributes["service.name"])["team"])`},
+			Traces:  []string{`set(attributes["tier"], Lookup("teams", attributes["peer.service"])["tier"])`},
+			Metrics: []string{`set(attributes["owner"], Lookup("teams", attributes["peer.service"])["owner"]) where Lookup("teams", attributes["peer.service"]) != nil`},
+		},
+		ErrorMode: ottl.PropagateError,
+	}
+	p := newTestProcessor(t, cfg, map[string]*fakeSource{"teams": teamsSource()})
+
+	t.Run("logs", func(t *testing.T) {
+		ld := plog.NewLogs()
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", "checkout")
+		lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+
+		_, err := p.processLogs(context.Background(), ld)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"team": "payments"}, lr.Attributes().AsRaw())
+	})
+
+	t.Run("traces", func(t *testing.T) {
+		td := ptrace.NewTraces()
+		span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.Attributes().PutStr("peer.service", "checkout")
+
+		_, err := p.processTraces(context.Background(), td)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"peer.service": "checkout", "tier": int64(1)}, span.Attributes().AsRaw())
+	})
+
+	t.Run("metrics", func(t *testing.T) {
+		md := pmetric.NewMetrics()
+		dps := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptySum().DataPoints()
+		dps.AppendEmpty().Attributes().PutStr("peer.service", "checkout")
+		dps.AppendEmpty().Attributes().PutStr("peer.service", "cart")
+
+		_, err := p.processMetrics(context.Background(), md)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"peer.service": "checkout", "owner": "alice"}, dps.At(0).Attributes().AsRaw())
+		assert.Equal(t, map[string]any{"peer.service": "cart"}, dps.At(1).Attributes().AsRaw())
+	})
+}
+
+func TestStatementsQueryOwnTables(t *testing.T) {
+	// Two processors with tables of the same name each query their own table.
+	newProcessor := func(team string) *lookupProcessor {
+		return newTestProcessor(t, &Config{
+			Tables:     []lookup.TableConfig{{Name: "teams"}},
+			Statements: StatementsConfig{Logs: []string{`set(attributes["team"], Lookup("teams", "checkout")["team"])`}},
+		}, map[string]*fakeSource{"teams": {rows: map[string]map[string]any{"checkout": {"team": team}}}})
+	}
+	first, second := newProcessor("payments"), newProcessor("storefront")
+
+	for p, expected := range map[*lookupProcessor]string{first: "payments", second: "storefront"} {
+		ld := plog.NewLogs()
+		lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		_, err := p.processLogs(context.Background(), ld)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"team": expected}, lr.Attributes().AsRaw())
+	}
+}
+
+func TestStatementsUnknownTable(t *testing.T) {
+	_, err := newLookupProcessor(&Config{
+		Tables:     []lookup.TableConfig{{Name: "teams"}},
+		Statements: StatementsConfig{Logs: []string{`set(attributes["owner"], Lookup("owners", "checkout")["owner"])`}},
+	}, processortest.NewNopSettings(), func(lookup.TableConfig, *zap.Logger) lookup.Source {
+		return teamsSource()
+	})
+	assert.ErrorContains(t, err, `unknown lookup table "owners"`)
+}
+
+func TestFactorySharesTables(t *testing.T) {
+	cfg := &Config{
+		Tables: []lookup.TableConfig{{
+			Name: "teams",
+			CSV: &lookup.CSVConfig{
+				FileConfig: lookup.FileConfig{Path: filepath.Join("testdata", "teams.csv")},
+				KeyColumn:  "service",
+			},
+		}},
+		Lookups: []LookupConfig{
+			{Table: "teams", Key: "service.name", Attributes: []AttributeConfig{{Column: "team"}}},
+		},
+		ErrorMode: ottl.PropagateError,
+	}
+	factory := NewFactory()
+	sink := new(consumertest.LogsSink)
+	logs, err := factory.CreateLogs(context.Background(), processortest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	traces, err := factory.CreateTraces(context.Background(), processortest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, logs.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, traces.Start(context.Background(), componenttest.NewNopHost()))
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().Resource().Attributes().PutStr("service.name", "cart")
+	require.NoError(t, logs.ConsumeLogs(context.Background(), ld))
+	require.Len(t, sink.AllLogs(), 1)
+	team, ok := sink.AllLogs()[0].ResourceLogs().At(0).Resource().Attributes().Get("team")
+	require.True(t, ok)
+	assert.Equal(t, "storefront", team.Str())
+
+	require.NoError(t, logs.Shutdown(context.Background()))
+	require.NoError(t, traces.Shutdown(context.Background()))
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+)
+
+func (p *lookupProcessor) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	enrichRecords := p.hasContext(record)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if err := p.enrich(ctx, resource, rs.Resource().Attributes()); err != nil {
+			return td, err
+		}
+		if !enrichRecords && p.statements.spans == nil {
+			continue
+		}
+		sss := rs.ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			ss := sss.At(j)
+			spans := ss.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if enrichRecords {
+					if err := p.enrich(ctx, record, spans.At(k).Attributes()); err != nil {
+						return td, err
+					}
+				}
+				if p.statements.spans != nil {
+					tCtx := ottlspan.NewTransformContext(spans.At(k), ss.Scope(), rs.Resource(), ss, rs)
+					if err := p.statements.spans.Execute(ctx, tCtx); err != nil {
+						return td, err
+					}
+				}
+			}
+		}
+	}
+	return td, nil
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package lookupprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/lookupprocessor"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+// statements holds the parsed OTTL statements of each signal. The Lookup converter
+// used by the statements queries the tables of the processor they belong to.
+type statements struct {
+	logs       *ottl.StatementSequence[ottllog.TransformContext]
+	spans      *ottl.StatementSequence[ottlspan.TransformContext]
+	dataPoints *ottl.StatementSequence[ottldatapoint.TransformContext]
+}
+
+func newStatements(cfg StatementsConfig, tables map[string]ottlfuncs.LookupTable, errorMode ottl.ErrorMode, set component.TelemetrySettings) (statements, error) {
+	var s statements
+	var errs []error
+	if len(cfg.Logs) > 0 {
+		parser, err := ottllog.NewParser(withLookup[ottllog.TransformContext](tables), set)
+		if err != nil {
+			return s, err
+		}
+		s.logs, err = parseStatements(parser, cfg.Logs, errorMode, set)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("logs: %w", err))
+		}
+	}
+	if len(cfg.Traces) > 0 {
+		parser, err := ottlspan.NewParser(withLookup[ottlspan.TransformContext](tables), set)
+		if err != nil {
+			return s, err
+		}
+		s.spans, err = parseStatements(parser, cfg.Traces, errorMode, set)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("traces: %w", err))
+		}
+	}
+	if len(cfg.Metrics) > 0 {
+		parser, err := ottldatapoint.NewParser(withLookup[ottldatapoint.TransformContext](tables), set)
+		if err != nil {
+			return s, err
+		}
+		s.dataPoints, err = parseStatements(parser, cfg.Metrics, errorMode, set)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("metrics: %w", err))
+		}
+	}
+	return s, errors.Join(errs...)
+}
+
+func parseStatements[K any](parser ottl.Parser[K], statements []string, errorMode ottl.ErrorMode, set component.TelemetrySettings) (*ottl.StatementSequence[K], error) {
+	parsed, err := parser.ParseStatements(statements)
+	if err != nil {
+		return nil, err
+	}
+	sequence := ottl.NewStatementSequence(parsed, set, ottl.WithStatementSequenceErrorMode[K](errorMode))
+	return &sequence, nil
+}
+
+// withLookup returns the standard functions and the Lookup converter querying the given tables.
+func withLookup[K any](tables map[string]ottlfuncs.LookupTable) map[string]ottl.Factory[K] {
+	functions := ottlfuncs.StandardFuncs[K]()
+	lookupFactory := ottlfuncs.NewLookupFactory[K](tables)
+	functions[lookupFactory.Name()] = lookupFactory
+	return functions
+}
+
+// executeDataPoints executes the metric statements on every data point of the metric.
+func (s statements) executeDataPoints(ctx context.Context, metric pmetric.Metric, metrics pmetric.MetricSlice, sm pmetric.ScopeMetrics, rm pmetric.ResourceMetrics) error {
+	var dataPoints []any
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dataPoints = append(dataPoints, dps.At(i))
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dataPoints = append(dataPoints, dps.At(i))
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dataPoints = append(dataPoints, dps.At(i))
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dataPoints = append(dataPoints, dps.At(i))
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dataPoints = append(dataPoints, dps.At(i))
+		}
+	}
+	for _, dp := range dataPoints {
+		tCtx := ottldatapoint.NewTransformContext(dp, metric, metrics, sm.Scope(), rm.Resource(), sm, rm)
+		if err := s.dataPoints.Execute(ctx, tCtx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
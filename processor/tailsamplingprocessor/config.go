@@ -6,6 +6,8 @@ package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry
 import (
 	"time"
 
+	"go.opentelemetry.io/collector/component"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
@@ -237,6 +239,19 @@ type DecisionCacheConfig struct {
 	NonSampledCacheSize int `mapstructure:"non_sampled_cache_size"`
 }
 
+// DecisionStoreConfig holds the configuration for sharing final sampling decisions
+// between collector instances.
+type DecisionStoreConfig struct {
+	// StorageID is the ID of the storage extension used to publish and look up final sampling decisions.
+	// Every instance sharing decisions must be configured with the same storage backend (e.g. redis_storage)
+	// and the same processor ID. If left empty, decisions are only kept in the local decision caches.
+	StorageID *component.ID `mapstructure:"storage"`
+	// TTL is how long a published decision is kept in the storage before this instance deletes it.
+	// Spans reaching another instance after that are treated as belonging to a new trace.
+	// If left as default 0, DecisionWait is used.
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
 // Config holds the configuration for tail-based sampling.
 type Config struct {
 	// DecisionWait is the desired wait time from the arrival of the first span of
@@ -253,4 +268,6 @@ type Config struct {
 	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
 	// DecisionCache holds configuration for the decision cache(s)
 	DecisionCache DecisionCacheConfig `mapstructure:"decision_cache"`
+	// DecisionStore holds configuration for the decision store shared with other collector instances
+	DecisionStore DecisionStoreConfig `mapstructure:"decision_store"`
 }
@@ -186,3 +186,33 @@ func TestLoadConfig(t *testing.T) {
 			},
 		}, cfg)
 }
+
+func TestLoadConfigDecisionStore(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "tail_sampling_decision_store.yaml"))
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	storageID := component.MustNewID("redis_storage")
+	assert.Equal(t,
+		&Config{
+			DecisionWait:  10 * time.Second,
+			NumTraces:     100,
+			DecisionStore: DecisionStoreConfig{StorageID: &storageID, TTL: time.Minute},
+			PolicyCfgs: []PolicyCfg{
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-1",
+						Type: AlwaysSample,
+					},
+				},
+			},
+		}, cfg)
+}
@@ -0,0 +1,310 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+)
+
+const (
+	decisionKeyPrefix = "decision_"
+	// expiryKey holds the expiry times of the published batches of decisions that were not deleted yet.
+	expiryKey = "decision_expiry"
+	// expiryIndexKeyPrefix prefixes the keys holding the decision keys of a published batch.
+	expiryIndexKeyPrefix = "decision_expiry_"
+)
+
+// decisionStore publishes and looks up final sampling decisions in a storage extension, so that
+// every collector instance sharing the storage follows the same decision for a given trace.
+// Decisions already known to this instance are served from an in-memory cache, lookups and
+// publications are batched, and published decisions are deleted once their TTL has elapsed.
+// The keys of every published batch are indexed in the storage along with their expiry time, so that
+// decisions published before a restart, or by another instance, are deleted as well.
+// Errors are logged and otherwise ignored: the processor falls back to its local decisions.
+type decisionStore struct {
+	client storage.Client
+	logger *zap.Logger
+	cache  cache.Cache[sampling.Decision]
+	ttl    time.Duration
+
+	mu sync.Mutex
+	// published holds the keys of the batches published by this instance, by expiry time.
+	published map[int64][]string
+	// nextExpiry is the earliest known expiry time of a published batch, zero when none is known.
+	nextExpiry time.Time
+}
+
+func newDecisionStore(client storage.Client, decisions cache.Cache[sampling.Decision], ttl time.Duration, logger *zap.Logger) *decisionStore {
+	return &decisionStore{
+		client:    client,
+		logger:    logger,
+		cache:     decisions,
+		ttl:       ttl,
+		published: make(map[int64][]string),
+	}
+}
+
+// Load reads the expiry times of the batches of decisions left in the storage, so that they are
+// deleted by DeleteExpired.
+func (s *decisionStore) Load(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiries, err := s.getExpiries(ctx)
+	if err != nil {
+		s.logger.Debug("Failed to read the expiry of published sampling decisions", zap.Error(err))
+		return
+	}
+	s.nextExpiry = earliestExpiry(expiries)
+}
+
+// Get returns the decisions published for the given trace IDs. Trace IDs without a published
+// decision are absent from the returned map. The storage is queried once for all the trace IDs
+// missing from the in-memory cache.
+func (s *decisionStore) Get(ctx context.Context, ids []pcommon.TraceID) map[pcommon.TraceID]sampling.Decision {
+	decisions := make(map[pcommon.TraceID]sampling.Decision)
+	var missing []pcommon.TraceID
+	var ops []storage.Operation
+	for _, id := range ids {
+		if decision, ok := s.cache.Get(id); ok {
+			decisions[id] = decision
+			continue
+		}
+		missing = append(missing, id)
+		ops = append(ops, storage.GetOperation(decisionKey(id)))
+	}
+	if len(ops) == 0 {
+		return decisions
+	}
+
+	if err := s.client.Batch(ctx, ops...); err != nil {
+		s.logger.Debug("Failed to look up sampling decisions", zap.Int("count", len(ops)), zap.Error(err))
+		return decisions
+	}
+	for i, op := range ops {
+		decision, ok := parseDecision(op.Value)
+		if !ok {
+			continue
+		}
+		decisions[missing[i]] = decision
+		s.cache.Put(missing[i], decision)
+	}
+	return decisions
+}
+
+// Put publishes the final decisions of the given trace IDs with a single storage batch.
+func (s *decisionStore) Put(ctx context.Context, decisions map[pcommon.TraceID]sampling.Decision, now time.Time) {
+	if len(decisions) == 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiries, err := s.getExpiries(ctx)
+	if err != nil {
+		s.logger.Debug("Failed to read the expiry of published sampling decisions", zap.Error(err))
+		return
+	}
+	expires := now.Add(s.ttl).UnixNano()
+	expiries = append(expiries, expires)
+
+	ops := make([]storage.Operation, 0, len(decisions)+2)
+	keys := make([]string, 0, len(decisions))
+	for id, decision := range decisions {
+		s.cache.Put(id, decision)
+		key := decisionKey(id)
+		ops = append(ops, storage.SetOperation(key, []byte{byte(decision)}))
+		keys = append(keys, key)
+	}
+	index, err := json.Marshal(keys)
+	if err != nil {
+		s.logger.Debug("Failed to marshal the index of sampling decisions", zap.Error(err))
+		return
+	}
+	expiriesValue, err := json.Marshal(expiries)
+	if err != nil {
+		s.logger.Debug("Failed to marshal the expiry of sampling decisions", zap.Error(err))
+		return
+	}
+	ops = append(ops, storage.SetOperation(expiryIndexKey(expires), index), storage.SetOperation(expiryKey, expiriesValue))
+	if err = s.client.Batch(ctx, ops...); err != nil {
+		s.logger.Debug("Failed to publish sampling decisions", zap.Int("count", len(decisions)), zap.Error(err))
+		return
+	}
+
+	s.published[expires] = keys
+	s.nextExpiry = earliestExpiry(expiries)
+}
+
+// DeleteExpired deletes the published decisions whose TTL has elapsed, including the ones published
+// before a restart or by other instances sharing the storage.
+func (s *decisionStore) DeleteExpired(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.nextExpiry.IsZero() || s.nextExpiry.After(now) {
+		return
+	}
+
+	// The list of expiry times is read again, as other instances may have added or removed entries.
+	expiries, err := s.getExpiries(ctx)
+	if err != nil {
+		s.logger.Debug("Failed to read the expiry of published sampling decisions", zap.Error(err))
+		return
+	}
+	var expired, remaining []int64
+	for _, expires := range expiries {
+		if expires <= now.UnixNano() {
+			expired = append(expired, expires)
+		} else {
+			remaining = append(remaining, expires)
+		}
+	}
+	if len(expired) == 0 {
+		s.nextExpiry = earliestExpiry(remaining)
+		return
+	}
+
+	keys, err := s.getExpiredKeys(ctx, expired)
+	if err != nil {
+		s.logger.Debug("Failed to read the index of expired sampling decisions", zap.Error(err))
+		return
+	}
+	ops := make([]storage.Operation, 0, len(keys)+len(expired)+1)
+	for _, key := range keys {
+		ops = append(ops, storage.DeleteOperation(key))
+	}
+	for _, expires := range expired {
+		ops = append(ops, storage.DeleteOperation(expiryIndexKey(expires)))
+	}
+	if len(remaining) == 0 {
+		ops = append(ops, storage.DeleteOperation(expiryKey))
+	} else {
+		value, err := json.Marshal(remaining)
+		if err != nil {
+			s.logger.Debug("Failed to marshal the expiry of sampling decisions", zap.Error(err))
+			return
+		}
+		ops = append(ops, storage.SetOperation(expiryKey, value))
+	}
+	if err = s.client.Batch(ctx, ops...); err != nil {
+		s.logger.Debug("Failed to delete expired sampling decisions", zap.Int("count", len(keys)), zap.Error(err))
+		return
+	}
+
+	for _, expires := range expired {
+		delete(s.published, expires)
+	}
+	s.nextExpiry = earliestExpiry(remaining)
+}
+
+// getExpiries returns the expiry times of the published batches of decisions, in Unix nanoseconds.
+func (s *decisionStore) getExpiries(ctx context.Context) ([]int64, error) {
+	value, err := s.client.Get(ctx, expiryKey)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	var expiries []int64
+	if err = json.Unmarshal(value, &expiries); err != nil {
+		return nil, err
+	}
+	return expiries, nil
+}
+
+// getExpiredKeys returns the decision keys of the batches expiring at the given times. Only the
+// indexes of the batches not published by this instance are read from the storage.
+func (s *decisionStore) getExpiredKeys(ctx context.Context, expired []int64) ([]string, error) {
+	var keys []string
+	var ops []storage.Operation
+	for _, expires := range expired {
+		if published, ok := s.published[expires]; ok {
+			keys = append(keys, published...)
+			continue
+		}
+		ops = append(ops, storage.GetOperation(expiryIndexKey(expires)))
+	}
+	if len(ops) == 0 {
+		return keys, nil
+	}
+
+	if err := s.client.Batch(ctx, ops...); err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		if op.Value == nil {
+			continue
+		}
+		var index []string
+		if err := json.Unmarshal(op.Value, &index); err != nil {
+			return nil, err
+		}
+		keys = append(keys, index...)
+	}
+	return keys, nil
+}
+
+func (s *decisionStore) Close(ctx context.Context) error {
+	return s.client.Close(ctx)
+}
+
+func parseDecision(value []byte) (sampling.Decision, bool) {
+	if len(value) != 1 {
+		return sampling.Unspecified, false
+	}
+
+	switch decision := sampling.Decision(value[0]); decision {
+	case sampling.Sampled, sampling.NotSampled:
+		return decision, true
+	default:
+		return sampling.Unspecified, false
+	}
+}
+
+func decisionKey(id pcommon.TraceID) string {
+	return decisionKeyPrefix + id.String()
+}
+
+func expiryIndexKey(expires int64) string {
+	return expiryIndexKeyPrefix + strconv.FormatInt(expires, 10)
+}
+
+// earliestExpiry returns the earliest of the given expiry times, or the zero time when there are none.
+func earliestExpiry(expiries []int64) time.Time {
+	if len(expiries) == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, slices.Min(expiries))
+}
+
+// getStorageClient returns a client of the storage extension identified by storageID, or a no-op
+// client when no storage is configured.
+func getStorageClient(ctx context.Context, host component.Host, storageID *component.ID, componentID component.ID) (storage.Client, error) {
+	if storageID == nil {
+		return storage.NewNopClient(), nil
+	}
+
+	ext, ok := host.GetExtensions()[*storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExt.GetClient(ctx, component.KindProcessor, componentID, "")
+}
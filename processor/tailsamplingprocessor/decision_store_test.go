@@ -0,0 +1,305 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+)
+
+// sharedStorageExtension hands out the same client to every component, the way a
+// remote storage such as redis is shared by several collector instances.
+type sharedStorageExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+	client storage.Client
+}
+
+func (s *sharedStorageExtension) GetClient(context.Context, component.Kind, component.ID, string) (storage.Client, error) {
+	return s.client, nil
+}
+
+// countingClient counts the round-trips made to the wrapped storage client.
+type countingClient struct {
+	storage.Client
+	batches atomic.Int64
+}
+
+func (c *countingClient) Batch(ctx context.Context, ops ...storage.Operation) error {
+	c.batches.Add(1)
+	return c.Client.Batch(ctx, ops...)
+}
+
+type decisionStoreTestInstance struct {
+	tsp          *tailSamplingSpanProcessor
+	policy       *mockPolicyEvaluator
+	nextConsumer *consumertest.TracesSink
+}
+
+func newDecisionStoreTestInstance(t *testing.T, host component.Host, storageID component.ID) *decisionStoreTestInstance {
+	cfg := Config{
+		DecisionWait:  defaultTestDecisionWait,
+		NumTraces:     defaultNumTraces,
+		DecisionStore: DecisionStoreConfig{StorageID: &storageID},
+	}
+	nextConsumer := new(consumertest.TracesSink)
+	mpe := &mockPolicyEvaluator{}
+	policies := []*policy{
+		{name: "mock-policy-1", evaluator: mpe, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy-1"))},
+	}
+
+	tel := setupTestTelemetry()
+	set := tel.NewSettings()
+	// Instances share decisions only when they are configured with the same processor ID.
+	set.ID = component.NewIDWithName(metadata.Type, "shared")
+	p, err := newTracesProcessor(context.Background(), set, nextConsumer, cfg, withDecisionBatcher(newSyncIDBatcher()), withPolicies(policies))
+	require.NoError(t, err)
+
+	require.NoError(t, p.Start(context.Background(), host))
+	t.Cleanup(func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+		require.NoError(t, tel.Shutdown(context.Background()))
+	})
+
+	return &decisionStoreTestInstance{
+		tsp:          p.(*tailSamplingSpanProcessor),
+		policy:       mpe,
+		nextConsumer: nextConsumer,
+	}
+}
+
+func newSharedDecisionStoreHost() (component.Host, component.ID) {
+	host, storageID, _ := newCountingDecisionStoreHost()
+	return host, storageID
+}
+
+func newCountingDecisionStoreHost() (component.Host, component.ID, *countingClient) {
+	storageID := storagetest.NewStorageID("shared")
+	client := &countingClient{Client: storagetest.NewInMemoryClient(component.KindProcessor, storageID, "")}
+	ext := &sharedStorageExtension{client: client}
+	return storagetest.NewStorageHost().WithExtension(storageID, ext), storageID, client
+}
+
+func TestDecisionStoreBatchesLookups(t *testing.T) {
+	host, storageID, client := newCountingDecisionStoreHost()
+	instance := newDecisionStoreTestInstance(t, host, storageID)
+	instance.policy.NextDecision = sampling.Sampled
+
+	traces := ptrace.NewTraces()
+	spans := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for i := uint64(1); i <= 3; i++ {
+		span := spans.AppendEmpty()
+		span.SetTraceID(uInt64ToTraceID(i))
+		span.SetSpanID(uInt64ToSpanID(i))
+	}
+
+	// A single lookup covers every unknown trace of the batch.
+	require.NoError(t, instance.tsp.ConsumeTraces(context.Background(), traces))
+	require.EqualValues(t, 1, client.batches.Load())
+
+	// Buffered traces are not looked up again.
+	require.NoError(t, instance.tsp.ConsumeTraces(context.Background(), traces))
+	require.EqualValues(t, 1, client.batches.Load())
+
+	// The evaluation looks up and publishes the decisions of the whole batch at once.
+	instance.tsp.policyTicker.OnTick()
+	instance.tsp.policyTicker.OnTick()
+	require.EqualValues(t, 3, client.batches.Load())
+	require.EqualValues(t, 3, instance.policy.EvaluationCount)
+}
+
+func TestDecisionStoreCacheAndExpiry(t *testing.T) {
+	storageID := storagetest.NewStorageID("shared")
+	client := &countingClient{Client: storagetest.NewInMemoryClient(component.KindProcessor, storageID, "")}
+	decisions, err := cache.NewLRUDecisionCache[sampling.Decision](10)
+	require.NoError(t, err)
+	store := newDecisionStore(client, decisions, time.Minute, zap.NewNop())
+
+	sampled, notSampled, unknown := uInt64ToTraceID(1), uInt64ToTraceID(2), uInt64ToTraceID(3)
+	now := time.Now()
+	store.Put(context.Background(), map[pcommon.TraceID]sampling.Decision{
+		sampled:    sampling.Sampled,
+		notSampled: sampling.NotSampled,
+	}, now)
+	require.EqualValues(t, 1, client.batches.Load())
+
+	// Decisions published by this instance are served from memory.
+	expected := map[pcommon.TraceID]sampling.Decision{sampled: sampling.Sampled, notSampled: sampling.NotSampled}
+	require.Equal(t, expected, store.Get(context.Background(), []pcommon.TraceID{sampled, notSampled}))
+	require.EqualValues(t, 1, client.batches.Load())
+	require.Empty(t, store.Get(context.Background(), []pcommon.TraceID{unknown}))
+	require.EqualValues(t, 2, client.batches.Load())
+
+	// Nothing is deleted before the TTL has elapsed.
+	store.DeleteExpired(context.Background(), now.Add(time.Second))
+	require.EqualValues(t, 2, client.batches.Load())
+	value, err := client.Get(context.Background(), decisionKey(sampled))
+	require.NoError(t, err)
+	require.Equal(t, []byte{byte(sampling.Sampled)}, value)
+
+	store.DeleteExpired(context.Background(), now.Add(time.Minute))
+	require.EqualValues(t, 3, client.batches.Load())
+	for _, key := range []string{decisionKey(sampled), decisionKey(notSampled), expiryKey, expiryIndexKey(now.Add(time.Minute).UnixNano())} {
+		value, err = client.Get(context.Background(), key)
+		require.NoError(t, err)
+		require.Nil(t, value, key)
+	}
+}
+
+func TestDecisionStoreExpiryAfterRestart(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindProcessor, storagetest.NewStorageID("shared"), "")
+	newStore := func() *decisionStore {
+		store := newDecisionStore(client, cache.NewNopDecisionCache[sampling.Decision](), time.Minute, zap.NewNop())
+		store.Load(context.Background())
+		return store
+	}
+
+	// Two instances publish decisions, then both restart before the TTL has elapsed.
+	first, second := uInt64ToTraceID(1), uInt64ToTraceID(2)
+	now := time.Now()
+	newStore().Put(context.Background(), map[pcommon.TraceID]sampling.Decision{first: sampling.Sampled}, now)
+	newStore().Put(context.Background(), map[pcommon.TraceID]sampling.Decision{second: sampling.NotSampled}, now.Add(time.Second))
+
+	restarted := newStore()
+	restarted.DeleteExpired(context.Background(), now.Add(time.Minute))
+	value, err := client.Get(context.Background(), decisionKey(first))
+	require.NoError(t, err)
+	require.Nil(t, value)
+	value, err = client.Get(context.Background(), decisionKey(second))
+	require.NoError(t, err)
+	require.Equal(t, []byte{byte(sampling.NotSampled)}, value)
+
+	restarted.DeleteExpired(context.Background(), now.Add(time.Minute+time.Second))
+	for _, key := range []string{decisionKey(second), expiryKey, expiryIndexKey(now.Add(time.Minute).UnixNano()), expiryIndexKey(now.Add(time.Minute + time.Second).UnixNano())} {
+		value, err = client.Get(context.Background(), key)
+		require.NoError(t, err)
+		require.Nil(t, value, key)
+	}
+}
+
+func TestDecisionStoreLateSpanOnOtherInstance(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		decision      sampling.Decision
+		expectedSpans int
+	}{
+		{name: "sampled", decision: sampling.Sampled, expectedSpans: 1},
+		{name: "not sampled", decision: sampling.NotSampled, expectedSpans: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			host, storageID := newSharedDecisionStoreHost()
+			first := newDecisionStoreTestInstance(t, host, storageID)
+			second := newDecisionStoreTestInstance(t, host, storageID)
+
+			traceID := uInt64ToTraceID(1)
+			spanIndexToTraces := func(spanIndex uint64) ptrace.Traces {
+				traces := ptrace.NewTraces()
+				span := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+				span.SetTraceID(traceID)
+				span.SetSpanID(uInt64ToSpanID(spanIndex))
+				return traces
+			}
+
+			// The first instance receives the beginning of the trace and decides on it.
+			first.policy.NextDecision = tc.decision
+			require.NoError(t, first.tsp.ConsumeTraces(context.Background(), spanIndexToTraces(1)))
+			first.tsp.policyTicker.OnTick()
+			first.tsp.policyTicker.OnTick()
+			require.EqualValues(t, 1, first.policy.EvaluationCount)
+			require.EqualValues(t, tc.expectedSpans, first.nextConsumer.SpanCount())
+
+			// A late span reaching the second instance follows the published decision, without evaluating policies.
+			second.policy.NextDecision = sampling.Sampled
+			require.NoError(t, second.tsp.ConsumeTraces(context.Background(), spanIndexToTraces(2)))
+			require.EqualValues(t, 0, second.policy.EvaluationCount)
+			require.EqualValues(t, tc.expectedSpans, second.nextConsumer.SpanCount())
+			_, ok := second.tsp.idToTrace.Load(traceID)
+			require.False(t, ok, "trace decided by another instance should not be buffered")
+		})
+	}
+}
+
+func TestDecisionStoreFollowedOnEvaluation(t *testing.T) {
+	host, storageID := newSharedDecisionStoreHost()
+	first := newDecisionStoreTestInstance(t, host, storageID)
+	second := newDecisionStoreTestInstance(t, host, storageID)
+
+	traceID := uInt64ToTraceID(1)
+	first.policy.NextDecision = sampling.NotSampled
+	second.policy.NextDecision = sampling.Sampled
+
+	// Both instances buffer spans of the same trace before any decision is made.
+	require.NoError(t, first.tsp.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	require.NoError(t, second.tsp.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+
+	first.tsp.policyTicker.OnTick()
+	first.tsp.policyTicker.OnTick()
+	require.EqualValues(t, 1, first.policy.EvaluationCount)
+
+	// The second instance follows the decision published by the first one instead of its own policies.
+	second.tsp.policyTicker.OnTick()
+	second.tsp.policyTicker.OnTick()
+	require.EqualValues(t, 0, second.policy.EvaluationCount)
+	require.EqualValues(t, 0, second.nextConsumer.SpanCount())
+}
+
+func TestDecisionStoreStartErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		host        component.Host
+		storageID   component.ID
+		expectedErr string
+	}{
+		{
+			name:        "missing extension",
+			host:        storagetest.NewStorageHost(),
+			storageID:   storagetest.NewStorageID("missing"),
+			expectedErr: "storage extension 'test_storage/missing' not found",
+		},
+		{
+			name:        "non-storage extension",
+			host:        storagetest.NewStorageHost().WithNonStorageExtension("non"),
+			storageID:   storagetest.NewNonStorageID("non"),
+			expectedErr: "non-storage extension 'non_storage/non' found",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{
+				DecisionWait:  defaultTestDecisionWait,
+				NumTraces:     defaultNumTraces,
+				DecisionStore: DecisionStoreConfig{StorageID: &tc.storageID},
+			}
+			p, err := newTracesProcessor(context.Background(), processorSettingsForTest(t), consumertest.NewNop(), cfg, withDecisionBatcher(newSyncIDBatcher()))
+			require.NoError(t, err)
+			require.ErrorContains(t, p.Start(context.Background(), tc.host), tc.expectedErr)
+			require.NoError(t, p.Shutdown(context.Background()))
+		})
+	}
+}
+
+func processorSettingsForTest(t *testing.T) processor.Settings {
+	tel := setupTestTelemetry()
+	t.Cleanup(func() {
+		require.NoError(t, tel.Shutdown(context.Background()))
+	})
+	return tel.NewSettings()
+}
@@ -14,6 +14,7 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor"
@@ -59,6 +60,10 @@ type tailSamplingSpanProcessor struct {
 	nonSampledIDCache cache.Cache[bool]
 	deleteChan        chan pcommon.TraceID
 	numTracesOnMap    *atomic.Uint64
+	componentID       component.ID
+	decisionStoreID   *component.ID
+	decisionStoreTTL  time.Duration
+	decisionStore     *decisionStore
 }
 
 // spanAndScope a structure for holding information about span and its instrumentation scope.
@@ -116,6 +121,13 @@ func newTracesProcessor(ctx context.Context, set processor.Settings, nextConsume
 		logger:            telemetrySettings.Logger,
 		numTracesOnMap:    &atomic.Uint64{},
 		deleteChan:        make(chan pcommon.TraceID, cfg.NumTraces),
+		componentID:       set.ID,
+		decisionStoreID:   cfg.DecisionStore.StorageID,
+		decisionStoreTTL:  cfg.DecisionStore.TTL,
+		decisionStore:     newDecisionStore(storage.NewNopClient(), cache.NewNopDecisionCache[sampling.Decision](), 0, telemetrySettings.Logger),
+	}
+	if tsp.decisionStoreTTL <= 0 {
+		tsp.decisionStoreTTL = cfg.DecisionWait
 	}
 	tsp.policyTicker = &timeutils.PolicyTicker{OnTickFunc: tsp.samplingPolicyOnTick}
 
@@ -269,6 +281,11 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 	batch, _ := tsp.decisionBatcher.CloseCurrentAndTakeFirstBatch()
 	batchLen := len(batch)
 	tsp.logger.Debug("Sampling Policy Evaluation ticked")
+
+	// Another instance may have already decided on some of these traces, in which case its decision is followed
+	// so that every span of the trace gets the same decision regardless of where it was received.
+	published := tsp.lookUpPublishedDecisions(batch)
+	newDecisions := make(map[pcommon.TraceID]sampling.Decision)
 	for _, id := range batch {
 		d, ok := tsp.idToTrace.Load(id)
 		if !ok {
@@ -278,7 +295,11 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		trace := d.(*sampling.TraceData)
 		trace.DecisionTime = time.Now()
 
-		decision := tsp.makeDecision(id, trace, &metrics)
+		decision, ok := published[id]
+		if !ok {
+			decision = tsp.makeDecision(id, trace, &metrics)
+			newDecisions[id] = decision
+		}
 		tsp.telemetry.ProcessorTailSamplingSamplingDecisionTimerLatency.Record(tsp.ctx, int64(time.Since(startTime)/time.Microsecond))
 		tsp.telemetry.ProcessorTailSamplingSamplingTraceDroppedTooEarly.Add(tsp.ctx, metrics.idNotFoundOnMapCount)
 		tsp.telemetry.ProcessorTailSamplingSamplingPolicyEvaluationError.Add(tsp.ctx, metrics.evaluateErrorCount)
@@ -297,6 +318,12 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		}
 	}
 
+	if tsp.decisionStoreID != nil {
+		now := time.Now()
+		tsp.decisionStore.Put(tsp.ctx, newDecisions, now)
+		tsp.decisionStore.DeleteExpired(tsp.ctx, now)
+	}
+
 	tsp.logger.Debug("Sampling policy evaluation completed",
 		zap.Int("batch.len", batchLen),
 		zap.Int64("sampled", metrics.decisionSampled),
@@ -381,6 +408,7 @@ func (tsp *tailSamplingSpanProcessor) groupSpansByTraceKey(resourceSpans ptrace.
 func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.ResourceSpans) {
 	// Group spans per their traceId to minimize contention on idToTrace
 	idToSpansAndScope := tsp.groupSpansByTraceKey(resourceSpans)
+	published := tsp.lookUpUnknownTraces(idToSpansAndScope)
 	var newTraceIDs int64
 	for id, spans := range idToSpansAndScope {
 		// If the trace ID is in the sampled cache, short circuit the decision
@@ -400,13 +428,19 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 			continue
 		}
 
+		d, loaded := tsp.idToTrace.Load(id)
+		// If this instance doesn't know about the trace, another instance may have already decided on it
+		if decision, ok := published[id]; ok && !loaded {
+			tsp.releaseFromDecisionStore(id, decision, resourceSpans, spans)
+			continue
+		}
+
 		lenSpans := int64(len(spans))
 		lenPolicies := len(tsp.policies)
 		initialDecisions := make([]sampling.Decision, lenPolicies)
 		for i := 0; i < lenPolicies; i++ {
 			initialDecisions[i] = sampling.Pending
 		}
-		d, loaded := tsp.idToTrace.Load(id)
 		if !loaded {
 			spanCount := &atomic.Int64{}
 			spanCount.Store(lenSpans)
@@ -466,21 +500,85 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 	tsp.telemetry.ProcessorTailSamplingNewTraceIDReceived.Add(tsp.ctx, newTraceIDs)
 }
 
+// lookUpPublishedDecisions returns the decisions published in the decision store for the given traces.
+func (tsp *tailSamplingSpanProcessor) lookUpPublishedDecisions(ids []pcommon.TraceID) map[pcommon.TraceID]sampling.Decision {
+	if tsp.decisionStoreID == nil || len(ids) == 0 {
+		return nil
+	}
+	return tsp.decisionStore.Get(tsp.ctx, ids)
+}
+
+// lookUpUnknownTraces returns the decisions published in the decision store for the traces that
+// this instance neither buffers nor has cached a decision for.
+func (tsp *tailSamplingSpanProcessor) lookUpUnknownTraces(idToSpansAndScope map[pcommon.TraceID][]spanAndScope) map[pcommon.TraceID]sampling.Decision {
+	if tsp.decisionStoreID == nil {
+		return nil
+	}
+
+	var unknown []pcommon.TraceID
+	for id := range idToSpansAndScope {
+		if _, ok := tsp.sampledIDCache.Get(id); ok {
+			continue
+		}
+		if _, ok := tsp.nonSampledIDCache.Get(id); ok {
+			continue
+		}
+		if _, ok := tsp.idToTrace.Load(id); ok {
+			continue
+		}
+		unknown = append(unknown, id)
+	}
+	return tsp.lookUpPublishedDecisions(unknown)
+}
+
+// releaseFromDecisionStore applies the decision published in the decision store for the given trace.
+func (tsp *tailSamplingSpanProcessor) releaseFromDecisionStore(id pcommon.TraceID, decision sampling.Decision, resourceSpans ptrace.ResourceSpans, spans []spanAndScope) {
+	switch decision {
+	case sampling.Sampled:
+		traceTd := ptrace.NewTraces()
+		appendToTraces(traceTd, resourceSpans, spans)
+		tsp.releaseSampledTrace(tsp.ctx, id, traceTd)
+		tsp.telemetry.ProcessorTailSamplingEarlyReleasesFromCacheDecision.
+			Add(tsp.ctx, int64(len(spans)), attrSampledTrue)
+	case sampling.NotSampled:
+		tsp.nonSampledIDCache.Put(id, true)
+		tsp.telemetry.ProcessorTailSamplingEarlyReleasesFromCacheDecision.
+			Add(tsp.ctx, int64(len(spans)), attrSampledFalse)
+	}
+}
+
 func (tsp *tailSamplingSpanProcessor) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
 // Start is invoked during service startup.
-func (tsp *tailSamplingSpanProcessor) Start(context.Context, component.Host) error {
+func (tsp *tailSamplingSpanProcessor) Start(ctx context.Context, host component.Host) error {
+	client, err := getStorageClient(ctx, host, tsp.decisionStoreID, tsp.componentID)
+	if err != nil {
+		return fmt.Errorf("failed to get decision store client: %w", err)
+	}
+	decisions := cache.NewNopDecisionCache[sampling.Decision]()
+	if tsp.decisionStoreID != nil && tsp.maxNumTraces > 0 {
+		// Remember as many decisions as traces kept in memory, so that the storage is only queried
+		// for traces this instance has not seen a decision for.
+		if decisions, err = cache.NewLRUDecisionCache[sampling.Decision](int(tsp.maxNumTraces)); err != nil {
+			return err
+		}
+	}
+	tsp.decisionStore = newDecisionStore(client, decisions, tsp.decisionStoreTTL, tsp.logger)
+	if tsp.decisionStoreID != nil {
+		tsp.decisionStore.Load(ctx)
+	}
+
 	tsp.policyTicker.Start(tsp.tickerFrequency)
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
-func (tsp *tailSamplingSpanProcessor) Shutdown(context.Context) error {
+func (tsp *tailSamplingSpanProcessor) Shutdown(ctx context.Context) error {
 	tsp.decisionBatcher.Stop()
 	tsp.policyTicker.Stop()
-	return nil
+	return tsp.decisionStore.Close(ctx)
 }
 
 func (tsp *tailSamplingSpanProcessor) dropTrace(traceID pcommon.TraceID, deletionTime time.Time) {
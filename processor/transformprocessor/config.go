@@ -5,13 +5,16 @@ package transformprocessor // import "github.com/open-telemetry/opentelemetry-co
 
 import (
 	"errors"
+	"fmt"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/featuregate"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/logs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metrics"
@@ -40,6 +43,9 @@ type Config struct {
 	MetricStatements []common.ContextStatements `mapstructure:"metric_statements"`
 	LogStatements    []common.ContextStatements `mapstructure:"log_statements"`
 
+	// LookupTables are the tables queried by the Lookup converter, available in every context.
+	LookupTables []lookup.TableConfig `mapstructure:"lookup_tables"`
+
 	FlattenData bool `mapstructure:"flatten_data"`
 	logger      *zap.Logger
 }
@@ -49,8 +55,17 @@ var _ component.Config = (*Config)(nil)
 func (c *Config) Validate() error {
 	var errors error
 
+	if err := lookup.ValidateTables(c.LookupTables); err != nil {
+		return fmt.Errorf("invalid 'lookup_tables': %w", err)
+	}
+	// The tables are only loaded when the processor starts, the statements are parsed against their names.
+	tables := make(map[string]ottlfuncs.LookupTable, len(c.LookupTables))
+	for _, table := range c.LookupTables {
+		tables[table.Name] = nil
+	}
+
 	if len(c.TraceStatements) > 0 {
-		pc, err := common.NewTraceParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithSpanParser(traces.SpanFunctions()), common.WithSpanEventParser(traces.SpanEventFunctions()))
+		pc, err := common.NewTraceParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithSpanParser(traces.SpanFunctions(tables)), common.WithSpanEventParser(traces.SpanEventFunctions(tables)), common.WithTraceLookupTables(tables))
 		if err != nil {
 			return err
 		}
@@ -63,7 +78,7 @@ func (c *Config) Validate() error {
 	}
 
 	if len(c.MetricStatements) > 0 {
-		pc, err := common.NewMetricParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithMetricParser(metrics.MetricFunctions()), common.WithDataPointParser(metrics.DataPointFunctions()))
+		pc, err := common.NewMetricParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithMetricParser(metrics.MetricFunctions(tables)), common.WithDataPointParser(metrics.DataPointFunctions(tables)), common.WithMetricLookupTables(tables))
 		if err != nil {
 			return err
 		}
@@ -76,7 +91,7 @@ func (c *Config) Validate() error {
 	}
 
 	if len(c.LogStatements) > 0 {
-		pc, err := common.NewLogParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithLogParser(logs.LogFunctions()))
+		pc, err := common.NewLogParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithLogParser(logs.LogFunctions(tables)), common.WithLogLookupTables(tables))
 		if err != nil {
 			return err
 		}
@@ -12,6 +12,7 @@ import (
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 	"go.uber.org/multierr"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metadata"
@@ -147,6 +148,37 @@ func TestLoadConfig(t *testing.T) {
 			id:       component.NewIDWithName(metadata.Type, "bad_syntax_multi_signal"),
 			errorLen: 3,
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "lookup_tables"),
+			expected: &Config{
+				ErrorMode: ottl.PropagateError,
+				LookupTables: []lookup.TableConfig{
+					{
+						Name: "teams",
+						CSV: &lookup.CSVConfig{
+							FileConfig: lookup.FileConfig{Path: "./testdata/teams.csv"},
+							KeyColumn:  "service",
+						},
+					},
+				},
+				TraceStatements: []common.ContextStatements{
+					{
+						Context: "resource",
+						Statements: []string{
+							`set(attributes["team"], Lookup("teams", attributes["service.name"])["team"])`,
+						},
+					},
+				},
+				MetricStatements: []common.ContextStatements{},
+				LogStatements:    []common.ContextStatements{},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "unknown_lookup_table"),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "duplicate_lookup_table"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.id.String(), func(t *testing.T) {
@@ -49,7 +49,8 @@ func createLogsProcessor(
 ) (processor.Logs, error) {
 	oCfg := cfg.(*Config)
 
-	proc, err := logs.NewProcessor(oCfg.LogStatements, oCfg.ErrorMode, oCfg.FlattenData, set.TelemetrySettings)
+	tables := newLookupTables(oCfg.LookupTables, set.Logger)
+	proc, err := logs.NewProcessor(oCfg.LogStatements, oCfg.ErrorMode, oCfg.FlattenData, tables.ottlTables(), set.TelemetrySettings)
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -59,7 +60,9 @@ func createLogsProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessLogs,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(tables.start),
+		processorhelper.WithShutdown(tables.shutdown))
 }
 
 func createTracesProcessor(
@@ -70,7 +73,8 @@ func createTracesProcessor(
 ) (processor.Traces, error) {
 	oCfg := cfg.(*Config)
 
-	proc, err := traces.NewProcessor(oCfg.TraceStatements, oCfg.ErrorMode, set.TelemetrySettings)
+	tables := newLookupTables(oCfg.LookupTables, set.Logger)
+	proc, err := traces.NewProcessor(oCfg.TraceStatements, oCfg.ErrorMode, tables.ottlTables(), set.TelemetrySettings)
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -80,7 +84,9 @@ func createTracesProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessTraces,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(tables.start),
+		processorhelper.WithShutdown(tables.shutdown))
 }
 
 func createMetricsProcessor(
@@ -92,7 +98,8 @@ func createMetricsProcessor(
 	oCfg := cfg.(*Config)
 	oCfg.logger = set.Logger
 
-	proc, err := metrics.NewProcessor(oCfg.MetricStatements, oCfg.ErrorMode, set.TelemetrySettings)
+	tables := newLookupTables(oCfg.LookupTables, set.Logger)
+	proc, err := metrics.NewProcessor(oCfg.MetricStatements, oCfg.ErrorMode, tables.ottlTables(), set.TelemetrySettings)
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -102,5 +109,7 @@ func createMetricsProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessMetrics,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(tables.start),
+		processorhelper.WithShutdown(tables.shutdown))
 }
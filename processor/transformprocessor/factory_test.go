@@ -5,9 +5,11 @@ package transformprocessor
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
@@ -16,6 +18,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/processor/processortest"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metadata"
@@ -753,3 +756,61 @@ func TestFactoryCreateSpanEventProcessor(t *testing.T) {
 		})
 	}
 }
+
+func TestFactoryCreateProcessorWithLookupTables(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.LookupTables = []lookup.TableConfig{
+		{
+			Name: "teams",
+			CSV: &lookup.CSVConfig{
+				FileConfig: lookup.FileConfig{Path: filepath.Join("testdata", "teams.csv")},
+				KeyColumn:  "service",
+			},
+		},
+	}
+	oCfg.LogStatements = []common.ContextStatements{
+		{
+			Context: "resource",
+			Statements: []string{
+				`set(attributes["team"], Lookup("teams", attributes["service.name"])["team"]) where Lookup("teams", attributes["service.name"]) != nil`,
+			},
+		},
+		{
+			Context: "log",
+			Statements: []string{
+				`set(attributes["owner"], Lookup("teams", resource.attributes["service.name"])["owner"]) where Lookup("teams", resource.attributes["service.name"]) != nil`,
+			},
+		},
+	}
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	lp, err := factory.CreateLogs(context.Background(), processortest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, lp.Shutdown(context.Background()))
+	}()
+
+	ld := plog.NewLogs()
+	for _, service := range []string{"checkout", "unknown"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("service.name", service)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	}
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+
+	checkout := ld.ResourceLogs().At(0)
+	team, ok := checkout.Resource().Attributes().Get("team")
+	require.True(t, ok)
+	assert.Equal(t, "payments", team.Str())
+	owner, ok := checkout.ScopeLogs().At(0).LogRecords().At(0).Attributes().Get("owner")
+	require.True(t, ok)
+	assert.Equal(t, "alice", owner.Str())
+
+	unknown := ld.ResourceLogs().At(1)
+	_, ok = unknown.Resource().Attributes().Get("team")
+	assert.False(t, ok)
+	assert.Equal(t, 0, unknown.ScopeLogs().At(0).LogRecords().At(0).Attributes().Len())
+}
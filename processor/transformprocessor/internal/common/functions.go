@@ -10,10 +10,17 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
-func ResourceFunctions() map[string]ottl.Factory[ottlresource.TransformContext] {
-	return ottlfuncs.StandardFuncs[ottlresource.TransformContext]()
+func ResourceFunctions(tables map[string]ottlfuncs.LookupTable) map[string]ottl.Factory[ottlresource.TransformContext] {
+	return WithLookup(ottlfuncs.StandardFuncs[ottlresource.TransformContext](), tables)
 }
 
-func ScopeFunctions() map[string]ottl.Factory[ottlscope.TransformContext] {
-	return ottlfuncs.StandardFuncs[ottlscope.TransformContext]()
+func ScopeFunctions(tables map[string]ottlfuncs.LookupTable) map[string]ottl.Factory[ottlscope.TransformContext] {
+	return WithLookup(ottlfuncs.StandardFuncs[ottlscope.TransformContext](), tables)
+}
+
+// WithLookup adds the Lookup converter, which queries the lookup tables of the processor, to the functions.
+func WithLookup[K any](functions map[string]ottl.Factory[K], tables map[string]ottlfuncs.LookupTable) map[string]ottl.Factory[K] {
+	lookupFactory := ottlfuncs.NewLookupFactory[K](tables)
+	functions[lookupFactory.Name()] = lookupFactory
+	return functions
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlscope"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
 var _ consumer.Logs = &logStatements{}
@@ -80,20 +81,18 @@ func WithLogErrorMode(errorMode ottl.ErrorMode) LogParserCollectionOption {
 	}
 }
 
-func NewLogParserCollection(settings component.TelemetrySettings, options ...LogParserCollectionOption) (*LogParserCollection, error) {
-	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
-	if err != nil {
-		return nil, err
-	}
-	sp, err := ottlscope.NewParser(ScopeFunctions(), settings)
-	if err != nil {
-		return nil, err
+// WithLogLookupTables sets the tables the Lookup converter queries in the resource and scope contexts.
+func WithLogLookupTables(tables map[string]ottlfuncs.LookupTable) LogParserCollectionOption {
+	return func(lp *LogParserCollection) error {
+		lp.lookupTables = tables
+		return nil
 	}
+}
+
+func NewLogParserCollection(settings component.TelemetrySettings, options ...LogParserCollectionOption) (*LogParserCollection, error) {
 	lpc := &LogParserCollection{
 		parserCollection: parserCollection{
-			settings:       settings,
-			resourceParser: rp,
-			scopeParser:    sp,
+			settings: settings,
 		},
 	}
 
@@ -104,6 +103,17 @@ func NewLogParserCollection(settings component.TelemetrySettings, options ...Log
 		}
 	}
 
+	rp, err := ottlresource.NewParser(ResourceFunctions(lpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := ottlscope.NewParser(ScopeFunctions(lpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	lpc.resourceParser = rp
+	lpc.scopeParser = sp
+
 	return lpc, nil
 }
 
@@ -18,6 +18,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlscope"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
 var _ consumer.Metrics = &metricStatements{}
@@ -206,20 +207,18 @@ func WithMetricErrorMode(errorMode ottl.ErrorMode) MetricParserCollectionOption
 	}
 }
 
-func NewMetricParserCollection(settings component.TelemetrySettings, options ...MetricParserCollectionOption) (*MetricParserCollection, error) {
-	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
-	if err != nil {
-		return nil, err
-	}
-	sp, err := ottlscope.NewParser(ScopeFunctions(), settings)
-	if err != nil {
-		return nil, err
+// WithMetricLookupTables sets the tables the Lookup converter queries in the resource and scope contexts.
+func WithMetricLookupTables(tables map[string]ottlfuncs.LookupTable) MetricParserCollectionOption {
+	return func(mp *MetricParserCollection) error {
+		mp.lookupTables = tables
+		return nil
 	}
+}
+
+func NewMetricParserCollection(settings component.TelemetrySettings, options ...MetricParserCollectionOption) (*MetricParserCollection, error) {
 	mpc := &MetricParserCollection{
 		parserCollection: parserCollection{
-			settings:       settings,
-			resourceParser: rp,
-			scopeParser:    sp,
+			settings: settings,
 		},
 	}
 
@@ -230,6 +229,17 @@ func NewMetricParserCollection(settings component.TelemetrySettings, options ...
 		}
 	}
 
+	rp, err := ottlresource.NewParser(ResourceFunctions(mpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := ottlscope.NewParser(ScopeFunctions(mpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	mpc.resourceParser = rp
+	mpc.scopeParser = sp
+
 	return mpc, nil
 }
 
@@ -17,6 +17,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlscope"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
 var _ consumer.Traces = &traceStatements{}
@@ -134,20 +135,18 @@ func WithTraceErrorMode(errorMode ottl.ErrorMode) TraceParserCollectionOption {
 	}
 }
 
-func NewTraceParserCollection(settings component.TelemetrySettings, options ...TraceParserCollectionOption) (*TraceParserCollection, error) {
-	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
-	if err != nil {
-		return nil, err
-	}
-	sp, err := ottlscope.NewParser(ScopeFunctions(), settings)
-	if err != nil {
-		return nil, err
+// WithTraceLookupTables sets the tables the Lookup converter queries in the resource and scope contexts.
+func WithTraceLookupTables(tables map[string]ottlfuncs.LookupTable) TraceParserCollectionOption {
+	return func(tp *TraceParserCollection) error {
+		tp.lookupTables = tables
+		return nil
 	}
+}
+
+func NewTraceParserCollection(settings component.TelemetrySettings, options ...TraceParserCollectionOption) (*TraceParserCollection, error) {
 	tpc := &TraceParserCollection{
 		parserCollection: parserCollection{
-			settings:       settings,
-			resourceParser: rp,
-			scopeParser:    sp,
+			settings: settings,
 		},
 	}
 
@@ -158,6 +157,17 @@ func NewTraceParserCollection(settings component.TelemetrySettings, options ...T
 		}
 	}
 
+	rp, err := ottlresource.NewParser(ResourceFunctions(tpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := ottlscope.NewParser(ScopeFunctions(tpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	tpc.resourceParser = rp
+	tpc.scopeParser = sp
+
 	return tpc, nil
 }
 
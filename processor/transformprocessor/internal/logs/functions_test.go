@@ -15,7 +15,8 @@ import (
 
 func Test_LogFunctions(t *testing.T) {
 	expected := ottlfuncs.StandardFuncs[ottllog.TransformContext]()
-	actual := LogFunctions()
+	expected["Lookup"] = ottlfuncs.NewLookupFactory[ottllog.TransformContext](nil)
+	actual := LogFunctions(nil)
 	require.Equal(t, len(expected), len(actual))
 	for k := range actual {
 		assert.Contains(t, expected, k)
@@ -10,6 +10,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
 
 var UseConvertBetweenSumAndGaugeMetricContext = featuregate.GlobalRegistry().MustRegister(
@@ -19,8 +20,8 @@ var UseConvertBetweenSumAndGaugeMetricContext = featuregate.GlobalRegistry().Mus
 	featuregate.WithRegisterToVersion("v0.114.0"),
 )
 
-func DataPointFunctions() map[string]ottl.Factory[ottldatapoint.TransformContext] {
-	functions := ottlfuncs.StandardFuncs[ottldatapoint.TransformContext]()
+func DataPointFunctions(tables map[string]ottlfuncs.LookupTable) map[string]ottl.Factory[ottldatapoint.TransformContext] {
+	functions := common.WithLookup(ottlfuncs.StandardFuncs[ottldatapoint.TransformContext](), tables)
 
 	datapointFunctions := ottl.CreateFactoryMap[ottldatapoint.TransformContext](
 		newConvertSummarySumValToSumFactory(),
@@ -34,8 +35,8 @@ func DataPointFunctions() map[string]ottl.Factory[ottldatapoint.TransformContext
 	return functions
 }
 
-func MetricFunctions() map[string]ottl.Factory[ottlmetric.TransformContext] {
-	functions := ottlfuncs.StandardFuncs[ottlmetric.TransformContext]()
+func MetricFunctions(tables map[string]ottlfuncs.LookupTable) map[string]ottl.Factory[ottlmetric.TransformContext] {
+	functions := common.WithLookup(ottlfuncs.StandardFuncs[ottlmetric.TransformContext](), tables)
 
 	metricFunctions := ottl.CreateFactoryMap(
 		newExtractSumMetricFactory(),
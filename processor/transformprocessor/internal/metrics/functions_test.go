@@ -36,8 +36,9 @@ func Test_DataPointFunctions(t *testing.T) {
 			expected := ottlfuncs.StandardFuncs[ottldatapoint.TransformContext]()
 			expected["convert_summary_sum_val_to_sum"] = newConvertSummarySumValToSumFactory()
 			expected["convert_summary_count_val_to_sum"] = newConvertSummaryCountValToSumFactory()
+			expected["Lookup"] = ottlfuncs.NewLookupFactory[ottldatapoint.TransformContext](nil)
 
-			actual := DataPointFunctions()
+			actual := DataPointFunctions(nil)
 
 			require.Equal(t, len(expected), len(actual))
 			for k := range actual {
@@ -59,8 +60,9 @@ func Test_MetricFunctions(t *testing.T) {
 	expected["copy_metric"] = newCopyMetricFactory()
 	expected["scale_metric"] = newScaleMetricFactory()
 	expected["convert_exponential_histogram_to_histogram"] = newconvertExponentialHistToExplicitHistFactory()
+	expected["Lookup"] = ottlfuncs.NewLookupFactory[ottlmetric.TransformContext](nil)
 
-	actual := MetricFunctions()
+	actual := MetricFunctions(nil)
 	require.Equal(t, len(expected), len(actual))
 	for k := range actual {
 		assert.Contains(t, expected, k)
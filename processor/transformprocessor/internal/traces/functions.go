@@ -8,17 +8,18 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 )
 
-func SpanFunctions() map[string]ottl.Factory[ottlspan.TransformContext] {
+func SpanFunctions(tables map[string]ottlfuncs.LookupTable) map[string]ottl.Factory[ottlspan.TransformContext] {
 	// No trace-only functions yet.
-	m := ottlfuncs.StandardFuncs[ottlspan.TransformContext]()
+	m := common.WithLookup(ottlfuncs.StandardFuncs[ottlspan.TransformContext](), tables)
 	isRootSpanFactory := ottlfuncs.NewIsRootSpanFactory()
 	m[isRootSpanFactory.Name()] = isRootSpanFactory
 	return m
 }
 
-func SpanEventFunctions() map[string]ottl.Factory[ottlspanevent.TransformContext] {
+func SpanEventFunctions(tables map[string]ottlfuncs.LookupTable) map[string]ottl.Factory[ottlspanevent.TransformContext] {
 	// No trace-only functions yet.
-	return ottlfuncs.StandardFuncs[ottlspanevent.TransformContext]()
+	return common.WithLookup(ottlfuncs.StandardFuncs[ottlspanevent.TransformContext](), tables)
 }
@@ -18,7 +18,8 @@ func Test_SpanFunctions(t *testing.T) {
 	expected := ottlfuncs.StandardFuncs[ottlspan.TransformContext]()
 	isRootSpanFactory := ottlfuncs.NewIsRootSpanFactory()
 	expected[isRootSpanFactory.Name()] = isRootSpanFactory
-	actual := SpanFunctions()
+	expected["Lookup"] = ottlfuncs.NewLookupFactory[ottlspan.TransformContext](nil)
+	actual := SpanFunctions(nil)
 	require.Equal(t, len(expected), len(actual))
 	for k := range actual {
 		assert.Contains(t, expected, k)
@@ -27,7 +28,8 @@ func Test_SpanFunctions(t *testing.T) {
 
 func Test_SpanEventFunctions(t *testing.T) {
 	expected := ottlfuncs.StandardFuncs[ottlspanevent.TransformContext]()
-	actual := SpanEventFunctions()
+	expected["Lookup"] = ottlfuncs.NewLookupFactory[ottlspanevent.TransformContext](nil)
+	actual := SpanEventFunctions(nil)
 	require.Equal(t, len(expected), len(actual))
 	for k := range actual {
 		assert.Contains(t, expected, k)
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transformprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/lookup"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+)
+
+// lookupTables holds the tables queried by the Lookup converter.
+type lookupTables struct {
+	configs []lookup.TableConfig
+	sources map[string]lookup.Source
+}
+
+func newLookupTables(configs []lookup.TableConfig, logger *zap.Logger) *lookupTables {
+	sources := make(map[string]lookup.Source, len(configs))
+	for _, table := range configs {
+		sources[table.Name] = lookup.NewSource(table, logger.With(zap.String("table", table.Name)))
+	}
+	return &lookupTables{
+		configs: configs,
+		sources: sources,
+	}
+}
+
+// ottlTables returns the tables in the form expected by the Lookup converter.
+func (t *lookupTables) ottlTables() map[string]ottlfuncs.LookupTable {
+	tables := make(map[string]ottlfuncs.LookupTable, len(t.sources))
+	for name, source := range t.sources {
+		tables[name] = source
+	}
+	return tables
+}
+
+// start loads the tables.
+func (t *lookupTables) start(ctx context.Context, _ component.Host) error {
+	for _, table := range t.configs {
+		if err := t.sources[table.Name].Start(ctx); err != nil {
+			return errors.Join(fmt.Errorf("failed to load lookup table %q: %w", table.Name, err), t.shutdown(ctx))
+		}
+	}
+	return nil
+}
+
+func (t *lookupTables) shutdown(ctx context.Context) error {
+	var errs []error
+	for name, source := range t.sources {
+		if err := source.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shutdown lookup table %q: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package awss3receiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awss3receiver"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+)
+
+const checkpointKey = "checkpoint"
+
+// checkpointData is the ingestion progress persisted to the storage extension.
+type checkpointData struct {
+	// Cursor is the time of the first partition that has not been fully ingested.
+	Cursor time.Time `json:"cursor"`
+	// ProcessedKeys are the keys of the objects of the cursor partition that have already been ingested.
+	ProcessedKeys []string `json:"processed_keys,omitempty"`
+}
+
+// checkpointer keeps track of the ingestion progress, so that ingestion can resume after a restart
+// and objects are not ingested twice.
+type checkpointer struct {
+	client    storage.Client
+	cursor    time.Time
+	processed map[string]struct{}
+}
+
+func newCheckpointer(client storage.Client) *checkpointer {
+	return &checkpointer{
+		client:    client,
+		processed: make(map[string]struct{}),
+	}
+}
+
+// load restores the ingestion progress from the storage extension.
+func (c *checkpointer) load(ctx context.Context) error {
+	data, err := c.client.Get(ctx, checkpointKey)
+	if err != nil {
+		return fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if data == nil {
+		return nil
+	}
+
+	var cp checkpointData
+	if err = json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("failed to unmarshal checkpoint: %w", err)
+	}
+
+	c.cursor = cp.Cursor
+	c.processed = make(map[string]struct{}, len(cp.ProcessedKeys))
+	for _, key := range cp.ProcessedKeys {
+		c.processed[key] = struct{}{}
+	}
+	return nil
+}
+
+// resumeTime returns the time from which ingestion starts, given the configured start time.
+func (c *checkpointer) resumeTime(startTime time.Time) time.Time {
+	if c.cursor.After(startTime) {
+		return c.cursor
+	}
+	return startTime
+}
+
+func (c *checkpointer) isProcessed(key string) bool {
+	_, ok := c.processed[key]
+	return ok
+}
+
+// markProcessed records that the object with the given key of the cursor partition has been ingested.
+func (c *checkpointer) markProcessed(ctx context.Context, key string) error {
+	c.processed[key] = struct{}{}
+	return c.save(ctx)
+}
+
+// advance moves the cursor to the given partition time, forgetting the objects of the previous partition.
+func (c *checkpointer) advance(ctx context.Context, cursor time.Time) error {
+	c.cursor = cursor
+	c.processed = make(map[string]struct{})
+	return c.save(ctx)
+}
+
+func (c *checkpointer) save(ctx context.Context) error {
+	cp := checkpointData{
+		Cursor:        c.cursor,
+		ProcessedKeys: make([]string, 0, len(c.processed)),
+	}
+	for key := range c.processed {
+		cp.ProcessedKeys = append(cp.ProcessedKeys, key)
+	}
+
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	if err = c.client.Set(ctx, checkpointKey, data); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID component.ID, componentID component.ID, telemetryType string) (storage.Client, error) {
+	extension, ok := host.GetExtensions()[storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExtension, ok := extension.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	// The receivers of each telemetry type share the same component ID, the telemetry type keeps their checkpoints apart.
+	return storageExtension.GetClient(ctx, component.KindReceiver, componentID, telemetryType)
+}
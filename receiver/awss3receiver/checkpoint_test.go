@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package awss3receiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awss3receiver/internal/metadata"
+)
+
+func Test_checkpointer(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindReceiver, component.NewID(metadata.Type), "traces")
+
+	cp := newCheckpointer(client)
+	require.NoError(t, cp.load(context.Background()))
+	require.Equal(t, testTime, cp.resumeTime(testTime))
+
+	require.NoError(t, cp.advance(context.Background(), testTime.Add(time.Minute)))
+	require.NoError(t, cp.markProcessed(context.Background(), "key1"))
+	require.NoError(t, cp.markProcessed(context.Background(), "key2"))
+
+	restored := newCheckpointer(client)
+	require.NoError(t, restored.load(context.Background()))
+	require.Equal(t, testTime.Add(time.Minute), restored.resumeTime(testTime))
+	require.True(t, restored.isProcessed("key1"))
+	require.True(t, restored.isProcessed("key2"))
+	require.False(t, restored.isProcessed("key3"))
+
+	// The configured start time wins if it is after the checkpoint
+	require.Equal(t, testTime.Add(time.Hour), restored.resumeTime(testTime.Add(time.Hour)))
+
+	// Moving to the next partition forgets the processed keys
+	require.NoError(t, restored.advance(context.Background(), testTime.Add(2*time.Minute)))
+	require.False(t, restored.isProcessed("key1"))
+}
+
+func Test_checkpointer_invalidData(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindReceiver, component.NewID(metadata.Type), "traces")
+	require.NoError(t, client.Set(context.Background(), checkpointKey, []byte("{")))
+
+	require.ErrorContains(t, newCheckpointer(client).load(context.Background()), "failed to unmarshal checkpoint")
+}
+
+func Test_checkpointer_closedClient(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindReceiver, component.NewID(metadata.Type), "traces")
+	require.NoError(t, client.Close(context.Background()))
+
+	cp := newCheckpointer(client)
+	require.ErrorContains(t, cp.load(context.Background()), "failed to read checkpoint")
+	require.ErrorContains(t, cp.markProcessed(context.Background(), "key"), "failed to save checkpoint")
+}
+
+func Test_getStorageClient(t *testing.T) {
+	host := storagetest.NewStorageHost().
+		WithInMemoryStorageExtension("test").
+		WithNonStorageExtension("test")
+	id := component.NewID(metadata.Type)
+
+	client, err := getStorageClient(context.Background(), host, storagetest.NewStorageID("test"), id, "logs")
+	require.NoError(t, err)
+	require.NoError(t, client.Close(context.Background()))
+
+	_, err = getStorageClient(context.Background(), host, storagetest.NewStorageID("missing"), id, "logs")
+	require.EqualError(t, err, "storage extension 'test_storage/missing' not found")
+
+	_, err = getStorageClient(context.Background(), host, storagetest.NewNonStorageID("test"), id, "logs")
+	require.EqualError(t, err, "non-storage extension 'non_storage/test' found")
+}
@@ -35,6 +35,12 @@ type Encoding struct {
 	Suffix    string       `mapstructure:"suffix"`
 }
 
+// Tail configures the continuous ingestion of new objects as they are written to the bucket.
+type Tail struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+}
+
 // Config defines the configuration for the file receiver.
 type Config struct {
 	S3Downloader  S3DownloaderConfig `mapstructure:"s3downloader"`
@@ -42,6 +48,9 @@ type Config struct {
 	EndTime       string             `mapstructure:"endtime"`
 	Encodings     []Encoding         `mapstructure:"encodings"`
 	Notifications Notifications      `mapstructure:"notifications"`
+	Tail          Tail               `mapstructure:"tail"`
+	// StorageID is the ID of the storage extension used to checkpoint the ingestion progress.
+	StorageID *component.ID `mapstructure:"storage"`
 }
 
 const (
@@ -56,6 +65,9 @@ func createDefaultConfig() component.Config {
 			S3Partition:         S3PartitionMinute,
 			EndpointPartitionID: "aws",
 		},
+		Tail: Tail{
+			PollInterval: time.Minute,
+		},
 	}
 }
 
@@ -67,16 +79,25 @@ func (c Config) Validate() error {
 	if c.S3Downloader.S3Partition != S3PartitionHour && c.S3Downloader.S3Partition != S3PartitionMinute {
 		errs = multierr.Append(errs, errors.New("s3_partition must be either 'hour' or 'minute'"))
 	}
-	if c.StartTime == "" {
+	if c.Tail.Enabled && c.Tail.PollInterval <= 0 {
+		errs = multierr.Append(errs, errors.New("tail::poll_interval must be greater than 0"))
+	}
+	switch {
+	case c.StartTime == "" && !c.Tail.Enabled:
 		errs = multierr.Append(errs, errors.New("starttime is required"))
-	} else {
+	case c.StartTime != "":
 		if _, err := parseTime(c.StartTime, "starttime"); err != nil {
 			errs = multierr.Append(errs, err)
 		}
 	}
-	if c.EndTime == "" {
+	switch {
+	case c.Tail.Enabled:
+		if c.EndTime != "" {
+			errs = multierr.Append(errs, errors.New("endtime cannot be set when tail is enabled"))
+		}
+	case c.EndTime == "":
 		errs = multierr.Append(errs, errors.New("endtime is required"))
-	} else {
+	default:
 		if _, err := parseTime(c.EndTime, "endtime"); err != nil {
 			errs = multierr.Append(errs, err)
 		}
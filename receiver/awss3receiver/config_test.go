@@ -6,6 +6,7 @@ package awss3receiver
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,6 +43,7 @@ func TestLoadConfig(t *testing.T) {
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
 	require.NoError(t, err)
 	opampExtension := component.NewIDWithName(component.MustNewType("opamp"), "bar")
+	fileStorage := component.NewID(component.MustNewType("file_storage"))
 	tests := []struct {
 		id           component.ID
 		expected     component.Config
@@ -66,6 +68,7 @@ func TestLoadConfig(t *testing.T) {
 				},
 				StartTime: "2024-01-31 15:00",
 				EndTime:   "2024-02-03",
+				Tail:      Tail{PollInterval: time.Minute},
 			},
 		},
 		{
@@ -92,6 +95,7 @@ func TestLoadConfig(t *testing.T) {
 				Notifications: Notifications{
 					OpAMP: &opampExtension,
 				},
+				Tail: Tail{PollInterval: time.Minute},
 			},
 		},
 		{
@@ -105,8 +109,29 @@ func TestLoadConfig(t *testing.T) {
 				},
 				StartTime: "2024-01-31T15:00:00Z",
 				EndTime:   "2024-02-03T00:00:00Z",
+				Tail:      Tail{PollInterval: time.Minute},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "tail"),
+			expected: &Config{
+				S3Downloader: S3DownloaderConfig{
+					Region:              "us-east-1",
+					S3Bucket:            "abucket",
+					S3Partition:         "minute",
+					EndpointPartitionID: "aws",
+				},
+				Tail: Tail{
+					Enabled:      true,
+					PollInterval: 30 * time.Second,
+				},
+				StorageID: &fileStorage,
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "tail_invalid"),
+			errorMessage: "tail::poll_interval must be greater than 0; endtime cannot be set when tail is enabled",
+		},
 	}
 
 	for _, tt := range tests {
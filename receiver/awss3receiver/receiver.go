@@ -10,9 +10,11 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -42,6 +44,10 @@ type awss3Receiver struct {
 	dataProcessor   receiverProcessor
 	extensions      encodingExtensions
 	notifier        statusNotifier
+	id              component.ID
+	storageID       *component.ID
+	storageClient   storage.Client
+	wg              sync.WaitGroup
 }
 
 func newAWSS3Receiver(ctx context.Context, cfg *Config, telemetryType string, settings receiver.Settings, processor receiverProcessor) (*awss3Receiver, error) {
@@ -68,6 +74,8 @@ func newAWSS3Receiver(ctx context.Context, cfg *Config, telemetryType string, se
 		dataProcessor:   processor,
 		encodingsConfig: cfg.Encodings,
 		notifier:        notifier,
+		id:              settings.ID,
+		storageID:       cfg.StorageID,
 	}, nil
 }
 
@@ -82,10 +90,22 @@ func (r *awss3Receiver) Start(ctx context.Context, host component.Host) error {
 	if err != nil {
 		return err
 	}
+	if r.storageID != nil {
+		r.storageClient, err = getStorageClient(ctx, host, *r.storageID, r.id, r.telemetryType)
+		if err != nil {
+			return err
+		}
+		r.s3Reader.checkpoint = newCheckpointer(r.storageClient)
+		if err = r.s3Reader.checkpoint.load(ctx); err != nil {
+			return err
+		}
+	}
 
 	var cancelCtx context.Context
 	cancelCtx, r.cancel = context.WithCancel(context.Background())
+	r.wg.Add(1)
 	go func() {
+		defer r.wg.Done()
 		_ = r.s3Reader.readAll(cancelCtx, r.telemetryType, r.receiveBytes)
 	}()
 	return nil
@@ -100,6 +120,11 @@ func (r *awss3Receiver) Shutdown(ctx context.Context) error {
 	if r.cancel != nil {
 		r.cancel()
 	}
+	// Wait for the reader to stop before closing the storage client it checkpoints to
+	r.wg.Wait()
+	if r.storageClient != nil {
+		return r.storageClient.Close(ctx)
+	}
 	return nil
 }
 
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.uber.org/zap"
 )
 
@@ -26,8 +27,14 @@ type s3Reader struct {
 	startTime         time.Time
 	endTime           time.Time
 	notifier          statusNotifier
+	tail              bool
+	pollInterval      time.Duration
+	checkpoint        *checkpointer
 }
 
+// timeNow is the current time, replaced in tests.
+var timeNow = time.Now
+
 type s3ReaderDataCallback func(context.Context, string, []byte) error
 
 func newS3Reader(ctx context.Context, notifier statusNotifier, logger *zap.Logger, cfg *Config) (*s3Reader, error) {
@@ -35,17 +42,23 @@ func newS3Reader(ctx context.Context, notifier statusNotifier, logger *zap.Logge
 	if err != nil {
 		return nil, err
 	}
-	startTime, err := parseTime(cfg.StartTime, "starttime")
-	if err != nil {
-		return nil, err
-	}
-	endTime, err := parseTime(cfg.EndTime, "endtime")
-	if err != nil {
-		return nil, err
-	}
 	if cfg.S3Downloader.S3Partition != S3PartitionHour && cfg.S3Downloader.S3Partition != S3PartitionMinute {
 		return nil, errors.New("s3_partition must be either 'hour' or 'minute'")
 	}
+	var startTime, endTime time.Time
+	if cfg.StartTime != "" {
+		if startTime, err = parseTime(cfg.StartTime, "starttime"); err != nil {
+			return nil, err
+		}
+	} else if cfg.Tail.Enabled {
+		// Tail the bucket from the current partition
+		startTime = timeNow().UTC().Truncate(partitionStep(cfg.S3Downloader.S3Partition))
+	}
+	if !cfg.Tail.Enabled {
+		if endTime, err = parseTime(cfg.EndTime, "endtime"); err != nil {
+			return nil, err
+		}
+	}
 
 	return &s3Reader{
 		logger:            logger,
@@ -58,19 +71,32 @@ func newS3Reader(ctx context.Context, notifier statusNotifier, logger *zap.Logge
 		startTime:         startTime,
 		endTime:           endTime,
 		notifier:          notifier,
+		tail:              cfg.Tail.Enabled,
+		pollInterval:      cfg.Tail.PollInterval,
+		checkpoint:        newCheckpointer(storage.NewNopClient()),
 	}, nil
 }
 
+func partitionStep(s3Partition string) time.Duration {
+	if s3Partition == S3PartitionHour {
+		return time.Hour
+	}
+	return time.Minute
+}
+
 //nolint:golint,unparam
 func (s3Reader *s3Reader) readAll(ctx context.Context, telemetryType string, dataCallback s3ReaderDataCallback) error {
-	var timeStep time.Duration
-	if s3Reader.s3Partition == "hour" {
-		timeStep = time.Hour
-	} else {
-		timeStep = time.Minute
+	if s3Reader.tail {
+		return s3Reader.tailBucket(ctx, telemetryType, dataCallback)
+	}
+
+	timeStep := partitionStep(s3Reader.s3Partition)
+	startTime := s3Reader.checkpoint.resumeTime(s3Reader.startTime)
+	if startTime != s3Reader.startTime {
+		s3Reader.logger.Info("Resuming reading telemetry from checkpoint", zap.Time("checkpoint", startTime))
 	}
 	s3Reader.logger.Info("Start reading telemetry", zap.Time("start_time", s3Reader.startTime), zap.Time("end_time", s3Reader.endTime))
-	for currentTime := s3Reader.startTime; currentTime.Before(s3Reader.endTime); currentTime = currentTime.Add(timeStep) {
+	for currentTime := startTime; currentTime.Before(s3Reader.endTime); currentTime = currentTime.Add(timeStep) {
 		s3Reader.sendStatus(ctx, statusNotification{
 			TelemetryType: telemetryType,
 			IngestStatus:  IngestStatusIngesting,
@@ -105,6 +131,7 @@ func (s3Reader *s3Reader) readAll(ctx context.Context, telemetryType string, dat
 				s3Reader.logger.Error("Error reading telemetry", zap.Error(err), zap.Time("time", currentTime))
 				return err
 			}
+			s3Reader.advanceCheckpoint(ctx, currentTime.Add(timeStep))
 		}
 	}
 	s3Reader.sendStatus(ctx, statusNotification{
@@ -137,6 +164,10 @@ func (s3Reader *s3Reader) readTelemetryForTime(ctx context.Context, t time.Time,
 			s3Reader.logger.Info("No telemetry found for time", zap.String("prefix", prefix), zap.Time("time", t))
 		} else {
 			for _, obj := range page.Contents {
+				if s3Reader.checkpoint.isProcessed(*obj.Key) {
+					s3Reader.logger.Debug("Skipping already ingested telemetry", zap.String("key", *obj.Key))
+					continue
+				}
 				data, err := s3Reader.retrieveObject(ctx, *obj.Key)
 				if err != nil {
 					return err
@@ -145,6 +176,10 @@ func (s3Reader *s3Reader) readTelemetryForTime(ctx context.Context, t time.Time,
 				if err := dataCallback(ctx, *obj.Key, data); err != nil {
 					return err
 				}
+				// Checkpoint after each object, so that a crash replays at most the object being ingested
+				if err := s3Reader.checkpoint.markProcessed(ctx, *obj.Key); err != nil {
+					s3Reader.logger.Error("Failed to checkpoint ingested telemetry", zap.Error(err), zap.String("key", *obj.Key))
+				}
 			}
 		}
 		firstPage = false
@@ -152,6 +187,67 @@ func (s3Reader *s3Reader) readTelemetryForTime(ctx context.Context, t time.Time,
 	return nil
 }
 
+// tailBucket continuously reads the telemetry of the current partition, moving to the next partition once
+// no more objects are expected to be written to it.
+func (s3Reader *s3Reader) tailBucket(ctx context.Context, telemetryType string, dataCallback s3ReaderDataCallback) error {
+	timeStep := partitionStep(s3Reader.s3Partition)
+	currentTime := s3Reader.checkpoint.resumeTime(s3Reader.startTime)
+	s3Reader.logger.Info("Start tailing telemetry", zap.Time("start_time", currentTime), zap.Duration("poll_interval", s3Reader.pollInterval))
+
+	ticker := time.NewTicker(s3Reader.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Objects can be written to a partition shortly after its end, so a partition is
+		// only considered complete once it is read a poll interval after its end.
+		complete := !timeNow().Before(currentTime.Add(timeStep + s3Reader.pollInterval))
+
+		s3Reader.logger.Debug("Reading telemetry", zap.Time("time", currentTime))
+		err := s3Reader.readTelemetryForTime(ctx, currentTime, telemetryType, dataCallback)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			s3Reader.sendStatus(ctx, statusNotification{
+				TelemetryType:  telemetryType,
+				IngestStatus:   IngestStatusFailed,
+				StartTime:      s3Reader.startTime,
+				IngestTime:     currentTime,
+				FailureMessage: err.Error(),
+			})
+			// Keep tailing, the partition is read again at the next poll
+			s3Reader.logger.Error("Error reading telemetry", zap.Error(err), zap.Time("time", currentTime))
+		case complete:
+			currentTime = currentTime.Add(timeStep)
+			s3Reader.advanceCheckpoint(ctx, currentTime)
+			s3Reader.sendStatus(ctx, statusNotification{
+				TelemetryType: telemetryType,
+				IngestStatus:  IngestStatusIngesting,
+				StartTime:     s3Reader.startTime,
+				IngestTime:    currentTime,
+			})
+			// Catch up with the following partitions without waiting
+			if ctx.Err() == nil {
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			s3Reader.logger.Info("Context cancelled, stopping tailing telemetry", zap.Time("time", currentTime))
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s3Reader *s3Reader) advanceCheckpoint(ctx context.Context, cursor time.Time) {
+	if err := s3Reader.checkpoint.advance(ctx, cursor); err != nil {
+		s3Reader.logger.Error("Failed to checkpoint ingestion progress", zap.Error(err), zap.Time("cursor", cursor))
+	}
+}
+
 func (s3Reader *s3Reader) getObjectPrefixForTime(t time.Time, telemetryType string) string {
 	var timeKey string
 	switch s3Reader.s3Partition {
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 	"testing"
 	"time"
 
@@ -16,7 +17,11 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awss3receiver/internal/metadata"
 )
 
 var testTime = time.Date(2021, 0o2, 0o1, 17, 32, 0o0, 0o0, time.UTC)
@@ -170,6 +175,7 @@ func Test_readTelemetryForTime(t *testing.T) {
 			}, nil
 		}),
 		logger:      zap.NewNop(),
+		checkpoint:  newCheckpointer(storage.NewNopClient()),
 		s3Bucket:    "bucket",
 		s3Partition: "minute",
 		s3Prefix:    "",
@@ -219,6 +225,7 @@ func Test_readTelemetryForTime_GetObjectError(t *testing.T) {
 			return nil, testError
 		}),
 		logger:      zap.NewNop(),
+		checkpoint:  newCheckpointer(storage.NewNopClient()),
 		s3Bucket:    "bucket",
 		s3Partition: "minute",
 		s3Prefix:    "",
@@ -254,6 +261,7 @@ func Test_readTelemetryForTime_ListObjectsNoResults(t *testing.T) {
 			}, nil
 		}),
 		logger:      zap.NewNop(),
+		checkpoint:  newCheckpointer(storage.NewNopClient()),
 		s3Bucket:    "bucket",
 		s3Partition: "minute",
 		s3Prefix:    "",
@@ -301,6 +309,7 @@ func Test_readTelemetryForTime_NextPageError(t *testing.T) {
 			}, nil
 		}),
 		logger:      zap.NewNop(),
+		checkpoint:  newCheckpointer(storage.NewNopClient()),
 		s3Bucket:    "bucket",
 		s3Partition: "minute",
 		s3Prefix:    "",
@@ -359,6 +368,7 @@ func Test_readAll(t *testing.T) {
 			}, nil
 		}),
 		logger:      zap.NewNop(),
+		checkpoint:  newCheckpointer(storage.NewNopClient()),
 		s3Bucket:    "bucket",
 		s3Prefix:    "",
 		s3Partition: "minute",
@@ -407,6 +417,7 @@ func Test_readAll_StatusMessages(t *testing.T) {
 			}, nil
 		}),
 		logger:      zap.NewNop(),
+		checkpoint:  newCheckpointer(storage.NewNopClient()),
 		s3Bucket:    "bucket",
 		s3Prefix:    "",
 		s3Partition: "minute",
@@ -477,6 +488,7 @@ func Test_readAll_ContextDone(t *testing.T) {
 			}, nil
 		}),
 		logger:      zap.NewNop(),
+		checkpoint:  newCheckpointer(storage.NewNopClient()),
 		s3Bucket:    "bucket",
 		s3Prefix:    "",
 		s3Partition: "minute",
@@ -513,3 +525,206 @@ func Test_readAll_ContextDone(t *testing.T) {
 		},
 	}, notifier.messages)
 }
+
+func Test_readAll_ResumeFromCheckpoint(t *testing.T) {
+	client := storagetest.NewInMemoryClient(component.KindReceiver, component.NewID(metadata.Type), "traces")
+	previous := newCheckpointer(client)
+	require.NoError(t, previous.advance(context.Background(), testTime.Add(time.Minute)))
+	require.NoError(t, previous.markProcessed(context.Background(), "year=2021/month=02/day=01/hour=17/minute=33/traces_1"))
+
+	checkpoint := newCheckpointer(client)
+	require.NoError(t, checkpoint.load(context.Background()))
+
+	reader := s3Reader{
+		listObjectsClient: mockListObjectsAPI(func(params *s3.ListObjectsV2Input) ListObjectsV2Pager {
+			key1 := fmt.Sprintf("%s%s", *params.Prefix, "1")
+			key2 := fmt.Sprintf("%s%s", *params.Prefix, "2")
+			return &mockListObjectsV2Pager{
+				Pages: []*s3.ListObjectsV2Output{
+					{
+						Contents: []types.Object{{Key: &key1}, {Key: &key2}},
+					},
+				},
+			}
+		}),
+		getObjectClient: mockGetObjectAPI(func(_ context.Context, _ *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+			return &s3.GetObjectOutput{
+				Body: io.NopCloser(bytes.NewReader([]byte("this is the body of the object"))),
+			}, nil
+		}),
+		logger:      zap.NewNop(),
+		checkpoint:  checkpoint,
+		s3Bucket:    "bucket",
+		s3Partition: "minute",
+		startTime:   testTime,
+		endTime:     testTime.Add(time.Minute * 3),
+	}
+
+	var dataCallbackKeys []string
+	err := reader.readAll(context.Background(), "traces", func(_ context.Context, key string, _ []byte) error {
+		dataCallbackKeys = append(dataCallbackKeys, key)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"year=2021/month=02/day=01/hour=17/minute=33/traces_2",
+		"year=2021/month=02/day=01/hour=17/minute=34/traces_1",
+		"year=2021/month=02/day=01/hour=17/minute=34/traces_2",
+	}, dataCallbackKeys)
+
+	// A completed ingestion is not replayed
+	restored := newCheckpointer(client)
+	require.NoError(t, restored.load(context.Background()))
+	require.Equal(t, testTime.Add(time.Minute*3), restored.cursor)
+	reader.checkpoint = restored
+	dataCallbackKeys = nil
+	require.NoError(t, reader.readAll(context.Background(), "traces", func(_ context.Context, key string, _ []byte) error {
+		dataCallbackKeys = append(dataCallbackKeys, key)
+		return nil
+	}))
+	require.Empty(t, dataCallbackKeys)
+}
+
+// countingSetClient counts the writes to the wrapped storage client.
+type countingSetClient struct {
+	storage.Client
+	sets int
+}
+
+func (c *countingSetClient) Set(ctx context.Context, key string, value []byte) error {
+	c.sets++
+	return c.Client.Set(ctx, key, value)
+}
+
+func Test_readTelemetryForTime_CheckpointPerObject(t *testing.T) {
+	client := &countingSetClient{Client: storagetest.NewInMemoryClient(component.KindReceiver, component.NewID(metadata.Type), "traces")}
+	reader := s3Reader{
+		listObjectsClient: mockListObjectsAPI(func(params *s3.ListObjectsV2Input) ListObjectsV2Pager {
+			page := func(names ...string) *s3.ListObjectsV2Output {
+				output := &s3.ListObjectsV2Output{}
+				for _, name := range names {
+					key := *params.Prefix + name
+					output.Contents = append(output.Contents, types.Object{Key: &key})
+				}
+				return output
+			}
+			return &mockListObjectsV2Pager{
+				Pages: []*s3.ListObjectsV2Output{page("1", "2", "3"), page("4", "5")},
+			}
+		}),
+		getObjectClient: mockGetObjectAPI(func(_ context.Context, _ *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+			return &s3.GetObjectOutput{
+				Body: io.NopCloser(bytes.NewReader([]byte("this is the body of the object"))),
+			}, nil
+		}),
+		logger:      zap.NewNop(),
+		checkpoint:  newCheckpointer(client),
+		s3Bucket:    "bucket",
+		s3Partition: "minute",
+	}
+
+	var ingested int
+	require.NoError(t, reader.readTelemetryForTime(context.Background(), testTime, "traces", func(context.Context, string, []byte) error {
+		ingested++
+		return nil
+	}))
+	require.Equal(t, 5, ingested)
+	require.Equal(t, 5, client.sets)
+
+	restored := newCheckpointer(client)
+	require.NoError(t, restored.load(context.Background()))
+	require.Len(t, restored.processed, 5)
+
+	// The objects ingested before an error are checkpointed
+	reader.checkpoint = newCheckpointer(client)
+	require.NoError(t, reader.checkpoint.advance(context.Background(), testTime))
+	ingested = 0
+	require.Error(t, reader.readTelemetryForTime(context.Background(), testTime, "traces", func(context.Context, string, []byte) error {
+		ingested++
+		if ingested == 2 {
+			return errors.New("failed to consume")
+		}
+		return nil
+	}))
+	require.NoError(t, restored.load(context.Background()))
+	require.Len(t, restored.processed, 1)
+}
+
+func Test_readAll_Tail(t *testing.T) {
+	oldTimeNow := timeNow
+	defer func() {
+		timeNow = oldTimeNow
+	}()
+	// The partition of minute 32 is complete, the one of minute 33 is still being written.
+	timeNow = func() time.Time {
+		return testTime.Add(2 * time.Minute)
+	}
+
+	var mux sync.Mutex
+	objects := map[string][]string{
+		"year=2021/month=02/day=01/hour=17/minute=32/traces_": {"year=2021/month=02/day=01/hour=17/minute=32/traces_1"},
+		"year=2021/month=02/day=01/hour=17/minute=33/traces_": {"year=2021/month=02/day=01/hour=17/minute=33/traces_1"},
+	}
+	reader := s3Reader{
+		listObjectsClient: mockListObjectsAPI(func(params *s3.ListObjectsV2Input) ListObjectsV2Pager {
+			mux.Lock()
+			defer mux.Unlock()
+			var contents []types.Object
+			for _, key := range objects[*params.Prefix] {
+				contents = append(contents, types.Object{Key: &key})
+			}
+			return &mockListObjectsV2Pager{
+				Pages: []*s3.ListObjectsV2Output{{Contents: contents}},
+			}
+		}),
+		getObjectClient: mockGetObjectAPI(func(_ context.Context, _ *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+			return &s3.GetObjectOutput{
+				Body: io.NopCloser(bytes.NewReader([]byte("this is the body of the object"))),
+			}, nil
+		}),
+		logger:       zap.NewNop(),
+		checkpoint:   newCheckpointer(storage.NewNopClient()),
+		s3Bucket:     "bucket",
+		s3Partition:  "minute",
+		startTime:    testTime,
+		tail:         true,
+		pollInterval: 10 * time.Millisecond,
+	}
+
+	var dataCallbackKeys []string
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- reader.readAll(ctx, "traces", func(_ context.Context, key string, _ []byte) error {
+			mux.Lock()
+			defer mux.Unlock()
+			dataCallbackKeys = append(dataCallbackKeys, key)
+			return nil
+		})
+	}()
+
+	require.Eventually(t, func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+		return len(dataCallbackKeys) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A new object of the current partition is picked up by the next poll, without duplicates
+	mux.Lock()
+	objects["year=2021/month=02/day=01/hour=17/minute=33/traces_"] = append(objects["year=2021/month=02/day=01/hour=17/minute=33/traces_"], "year=2021/month=02/day=01/hour=17/minute=33/traces_2")
+	mux.Unlock()
+	require.Eventually(t, func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+		return len(dataCallbackKeys) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.Equal(t, []string{
+		"year=2021/month=02/day=01/hour=17/minute=32/traces_1",
+		"year=2021/month=02/day=01/hour=17/minute=33/traces_1",
+		"year=2021/month=02/day=01/hour=17/minute=33/traces_2",
+	}, dataCallbackKeys)
+	require.Equal(t, testTime.Add(time.Minute), reader.checkpoint.cursor)
+}
@@ -4,12 +4,17 @@
 package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 )
 
 type AutoCommit struct {
@@ -32,6 +37,13 @@ type MessageMarking struct {
 	OnError bool `mapstructure:"on_error"`
 }
 
+type DeadLetter struct {
+	// The name of the kafka topic that messages which fail to be unmarshaled are
+	// produced to, with headers describing the error. Such messages are not
+	// consumed further. If empty, these messages are handled like any other error.
+	Topic string `mapstructure:"topic"`
+}
+
 type HeaderExtraction struct {
 	ExtractHeaders bool     `mapstructure:"extract_headers"`
 	Headers        []string `mapstructure:"headers"`
@@ -54,6 +66,13 @@ type Config struct {
 	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
 	// The name of the kafka topic to consume from (default "otlp_spans" for traces, "otlp_metrics" for metrics, "otlp_logs" for logs)
 	Topic string `mapstructure:"topic"`
+	// The names of the kafka topics to consume from, instead of Topic.
+	Topics []string `mapstructure:"topics"`
+	// A regular expression matching the names of the kafka topics to consume from,
+	// instead of Topic. Topics created later are consumed from once they match.
+	TopicRegex string `mapstructure:"topic_regex"`
+	// How often the topics matching TopicRegex are listed (default 1m)
+	TopicRefreshInterval time.Duration `mapstructure:"topic_refresh_interval"`
 	// Encoding of the messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
 	// The consumer group that receiver will be consuming messages from (default "otel-collector")
@@ -63,6 +82,9 @@ type Config struct {
 	// The initial offset to use if no offset was previously committed.
 	// Must be `latest` or `earliest` (default "latest").
 	InitialOffset string `mapstructure:"initial_offset"`
+	// Whether the messages of aborted transactions are consumed.
+	// Must be `read_uncommitted` or `read_committed` (default "read_uncommitted").
+	IsolationLevel string `mapstructure:"isolation_level"`
 
 	// Metadata is the namespace for metadata management properties used by the
 	// Client, and shared by the Producer/Consumer.
@@ -79,6 +101,12 @@ type Config struct {
 	// Extract headers from kafka records
 	HeaderExtraction HeaderExtraction `mapstructure:"header_extraction"`
 
+	// Produce the messages that fail to be unmarshaled to a dead letter topic
+	DeadLetter DeadLetter `mapstructure:"dead_letter"`
+
+	// The schema registry the schemas of the "schema_registry" encoding are fetched from
+	SchemaRegistry *schemaregistry.ClientConfig `mapstructure:"schema_registry"`
+
 	// The minimum bytes per fetch from Kafka (default "1")
 	MinFetchSize int32 `mapstructure:"min_fetch_size"`
 	// The default bytes per fetch from Kafka (default "1048576")
@@ -92,9 +120,48 @@ const (
 	offsetEarliest string = "earliest"
 )
 
+const (
+	isolationLevelReadUncommitted string = "read_uncommitted"
+	isolationLevelReadCommitted   string = "read_committed"
+)
+
 var _ component.Config = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	subscriptions := 0
+	if cfg.Topic != "" {
+		subscriptions++
+	}
+	if len(cfg.Topics) > 0 {
+		subscriptions++
+	}
+	if cfg.TopicRegex != "" {
+		subscriptions++
+	}
+	if subscriptions > 1 {
+		return errors.New("only one of topic, topics and topic_regex can be set")
+	}
+
+	if cfg.TopicRegex != "" {
+		regex, err := regexp.Compile(cfg.TopicRegex)
+		if err != nil {
+			return fmt.Errorf("topic_regex: %w", err)
+		}
+		if cfg.TopicRefreshInterval <= 0 {
+			return errors.New("topic_refresh_interval must be positive")
+		}
+		if cfg.DeadLetter.Topic != "" && regex.MatchString(cfg.DeadLetter.Topic) {
+			return errors.New("dead_letter::topic must not match topic_regex")
+		}
+	}
+
+	if cfg.DeadLetter.Topic != "" && (cfg.DeadLetter.Topic == cfg.Topic || slices.Contains(cfg.Topics, cfg.DeadLetter.Topic)) {
+		return errors.New("dead_letter::topic must not be consumed from")
+	}
+
+	if cfg.Encoding == schemaRegistryEncoding && cfg.SchemaRegistry == nil {
+		return errors.New("schema_registry must be configured with the schema_registry encoding")
+	}
 	return nil
 }
@@ -40,8 +40,10 @@ func TestLoadConfig(t *testing.T) {
 				ClientID:                             "otel-collector",
 				GroupID:                              "otel-collector",
 				InitialOffset:                        "latest",
+				IsolationLevel:                       "read_uncommitted",
 				SessionTimeout:                       10 * time.Second,
 				HeartbeatInterval:                    3 * time.Second,
+				TopicRefreshInterval:                 time.Minute,
 				Authentication: kafka.Authentication{
 					TLS: &configtls.ClientConfig{
 						Config: configtls.Config{
@@ -70,14 +72,16 @@ func TestLoadConfig(t *testing.T) {
 		{
 			id: component.NewIDWithName(metadata.Type, "logs"),
 			expected: &Config{
-				Topic:             "logs",
-				Encoding:          "direct",
-				Brokers:           []string{"coffee:123", "foobar:456"},
-				ClientID:          "otel-collector",
-				GroupID:           "otel-collector",
-				InitialOffset:     "earliest",
-				SessionTimeout:    45 * time.Second,
-				HeartbeatInterval: 15 * time.Second,
+				Topic:                "logs",
+				Encoding:             "direct",
+				Brokers:              []string{"coffee:123", "foobar:456"},
+				ClientID:             "otel-collector",
+				GroupID:              "otel-collector",
+				InitialOffset:        "earliest",
+				IsolationLevel:       "read_uncommitted",
+				SessionTimeout:       45 * time.Second,
+				HeartbeatInterval:    15 * time.Second,
+				TopicRefreshInterval: time.Minute,
 				Authentication: kafka.Authentication{
 					TLS: &configtls.ClientConfig{
 						Config: configtls.Config{
@@ -103,6 +107,38 @@ func TestLoadConfig(t *testing.T) {
 				MaxFetchSize:     0,
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "tenants"),
+			expected: &Config{
+				TopicRegex:           "^tenant_.*_logs$",
+				TopicRefreshInterval: 30 * time.Second,
+				Encoding:             "otlp_proto",
+				Brokers:              []string{"localhost:9092"},
+				ClientID:             "otel-collector",
+				GroupID:              "otel-collector",
+				InitialOffset:        "latest",
+				IsolationLevel:       "read_uncommitted",
+				SessionTimeout:       10 * time.Second,
+				HeartbeatInterval:    3 * time.Second,
+				Metadata: kafkaexporter.Metadata{
+					Full: true,
+					Retry: kafkaexporter.MetadataRetry{
+						Max:     3,
+						Backoff: time.Millisecond * 250,
+					},
+				},
+				AutoCommit: AutoCommit{
+					Enable:   true,
+					Interval: 1 * time.Second,
+				},
+				DeadLetter: DeadLetter{
+					Topic: "dead_letters",
+				},
+				MinFetchSize:     1,
+				DefaultFetchSize: 1048576,
+				MaxFetchSize:     0,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -119,3 +155,79 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(cfg *Config)
+		expectedErr string
+	}{
+		{
+			name:   "topics",
+			modify: func(cfg *Config) { cfg.Topics = []string{"tenant_a", "tenant_b"} },
+		},
+		{
+			name: "topic and topics",
+			modify: func(cfg *Config) {
+				cfg.Topic = "spans"
+				cfg.Topics = []string{"tenant_a"}
+			},
+			expectedErr: "only one of topic, topics and topic_regex can be set",
+		},
+		{
+			name: "topics and topic_regex",
+			modify: func(cfg *Config) {
+				cfg.Topics = []string{"tenant_a"}
+				cfg.TopicRegex = "^tenant_"
+			},
+			expectedErr: "only one of topic, topics and topic_regex can be set",
+		},
+		{
+			name:        "invalid topic_regex",
+			modify:      func(cfg *Config) { cfg.TopicRegex = "tenant_(" },
+			expectedErr: "topic_regex: error parsing regexp",
+		},
+		{
+			name: "topic_refresh_interval",
+			modify: func(cfg *Config) {
+				cfg.TopicRegex = "^tenant_"
+				cfg.TopicRefreshInterval = 0
+			},
+			expectedErr: "topic_refresh_interval must be positive",
+		},
+		{
+			name: "dead letter topic consumed",
+			modify: func(cfg *Config) {
+				cfg.Topics = []string{"tenant_a", "dead_letters"}
+				cfg.DeadLetter.Topic = "dead_letters"
+			},
+			expectedErr: "dead_letter::topic must not be consumed from",
+		},
+		{
+			name: "dead letter topic matches topic_regex",
+			modify: func(cfg *Config) {
+				cfg.TopicRegex = "^tenant_"
+				cfg.DeadLetter.Topic = "tenant_dead_letters"
+			},
+			expectedErr: "dead_letter::topic must not match topic_regex",
+		},
+		{
+			name:        "schema_registry encoding without schema_registry",
+			modify:      func(cfg *Config) { cfg.Encoding = "schema_registry" },
+			expectedErr: "schema_registry must be configured with the schema_registry encoding",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
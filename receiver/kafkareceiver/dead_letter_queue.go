@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"strconv"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+)
+
+// Headers added to the messages produced to the dead letter topic.
+const (
+	deadLetterErrorHeader     = "dead_letter.error"
+	deadLetterTopicHeader     = "dead_letter.topic"
+	deadLetterPartitionHeader = "dead_letter.partition"
+	deadLetterOffsetHeader    = "dead_letter.offset"
+)
+
+// deadLetterQueue produces the messages that can't be unmarshaled to a dead letter topic.
+type deadLetterQueue struct {
+	topic    string
+	producer sarama.SyncProducer
+}
+
+// send produces the message to the dead letter topic with its original key, value and headers,
+// and headers describing where the message was consumed from and why it couldn't be unmarshaled.
+func (d *deadLetterQueue) send(message *sarama.ConsumerMessage, cause error) error {
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+4)
+	for _, header := range message.Headers {
+		if header != nil {
+			headers = append(headers, *header)
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(deadLetterErrorHeader), Value: []byte(cause.Error())},
+		sarama.RecordHeader{Key: []byte(deadLetterTopicHeader), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte(deadLetterPartitionHeader), Value: []byte(strconv.Itoa(int(message.Partition)))},
+		sarama.RecordHeader{Key: []byte(deadLetterOffsetHeader), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+	)
+
+	msg := &sarama.ProducerMessage{
+		Topic:   d.topic,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+	if message.Key != nil {
+		msg.Key = sarama.ByteEncoder(message.Key)
+	}
+	_, _, err := d.producer.SendMessage(msg)
+	return err
+}
+
+// handle sends the message to the dead letter topic and marks it, so that the partition
+// is consumed further. It returns false if there is no dead letter topic or if the
+// message couldn't be sent to it.
+func (d *deadLetterQueue) handle(logger *zap.Logger, session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage, cause error) bool {
+	if d == nil {
+		return false
+	}
+	if err := d.send(message, cause); err != nil {
+		logger.Error("failed to send message to the dead letter topic", zap.String("dead_letter_topic", d.topic), zap.Error(err))
+		return false
+	}
+	session.MarkMessage(message, "")
+	return true
+}
+
+func (d *deadLetterQueue) close() error {
+	if d == nil {
+		return nil
+	}
+	return d.producer.Close()
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDeadLetterQueue_handle(t *testing.T) {
+	message := &sarama.ConsumerMessage{
+		Topic:     "tenant_a",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("key"),
+		Value:     []byte("!@#"),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("tenant"), Value: []byte("a")}},
+	}
+	session := testConsumerGroupSession{ctx: context.Background()}
+
+	t.Run("sent", func(t *testing.T) {
+		producer := mocks.NewSyncProducer(t, nil)
+		producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+			assert.Equal(t, "dead_letters", msg.Topic)
+			key, err := msg.Key.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, []byte("key"), key)
+			value, err := msg.Value.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, []byte("!@#"), value)
+			assert.Equal(t, []sarama.RecordHeader{
+				{Key: []byte("tenant"), Value: []byte("a")},
+				{Key: []byte(deadLetterErrorHeader), Value: []byte("unmarshal failed")},
+				{Key: []byte(deadLetterTopicHeader), Value: []byte("tenant_a")},
+				{Key: []byte(deadLetterPartitionHeader), Value: []byte("3")},
+				{Key: []byte(deadLetterOffsetHeader), Value: []byte("42")},
+			}, msg.Headers)
+			return nil
+		})
+		d := &deadLetterQueue{topic: "dead_letters", producer: producer}
+
+		assert.True(t, d.handle(zap.NewNop(), session, message, errors.New("unmarshal failed")))
+		require.NoError(t, d.close())
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		producer := mocks.NewSyncProducer(t, nil)
+		producer.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+		d := &deadLetterQueue{topic: "dead_letters", producer: producer}
+
+		assert.False(t, d.handle(zap.NewNop(), session, message, errors.New("unmarshal failed")))
+		require.NoError(t, d.close())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var d *deadLetterQueue
+		assert.False(t, d.handle(zap.NewNop(), session, message, errors.New("unmarshal failed")))
+		require.NoError(t, d.close())
+	})
+}
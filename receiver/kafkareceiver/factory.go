@@ -26,9 +26,12 @@ const (
 	defaultClientID          = "otel-collector"
 	defaultGroupID           = defaultClientID
 	defaultInitialOffset     = offsetLatest
+	defaultIsolationLevel    = isolationLevelReadUncommitted
 	defaultSessionTimeout    = 10 * time.Second
 	defaultHeartbeatInterval = 3 * time.Second
 
+	defaultTopicRefreshInterval = time.Minute
+
 	// default from sarama.NewConfig()
 	defaultMetadataRetryMax = 3
 	// default from sarama.NewConfig()
@@ -71,13 +74,15 @@ func NewFactory(options ...FactoryOption) receiver.Factory {
 
 func createDefaultConfig() component.Config {
 	return &Config{
-		Encoding:          defaultEncoding,
-		Brokers:           []string{defaultBroker},
-		ClientID:          defaultClientID,
-		GroupID:           defaultGroupID,
-		InitialOffset:     defaultInitialOffset,
-		SessionTimeout:    defaultSessionTimeout,
-		HeartbeatInterval: defaultHeartbeatInterval,
+		Encoding:             defaultEncoding,
+		Brokers:              []string{defaultBroker},
+		ClientID:             defaultClientID,
+		GroupID:              defaultGroupID,
+		InitialOffset:        defaultInitialOffset,
+		IsolationLevel:       defaultIsolationLevel,
+		SessionTimeout:       defaultSessionTimeout,
+		HeartbeatInterval:    defaultHeartbeatInterval,
+		TopicRefreshInterval: defaultTopicRefreshInterval,
 		Metadata: kafkaexporter.Metadata{
 			Full: defaultMetadataFull,
 			Retry: kafkaexporter.MetadataRetry{
@@ -111,7 +116,7 @@ func (f *kafkaReceiverFactory) createTracesReceiver(
 	nextConsumer consumer.Traces,
 ) (receiver.Traces, error) {
 	oCfg := *(cfg.(*Config))
-	if oCfg.Topic == "" {
+	if oCfg.Topic == "" && len(oCfg.Topics) == 0 && oCfg.TopicRegex == "" {
 		oCfg.Topic = defaultTracesTopic
 	}
 
@@ -129,7 +134,7 @@ func (f *kafkaReceiverFactory) createMetricsReceiver(
 	nextConsumer consumer.Metrics,
 ) (receiver.Metrics, error) {
 	oCfg := *(cfg.(*Config))
-	if oCfg.Topic == "" {
+	if oCfg.Topic == "" && len(oCfg.Topics) == 0 && oCfg.TopicRegex == "" {
 		oCfg.Topic = defaultMetricsTopic
 	}
 
@@ -147,7 +152,7 @@ func (f *kafkaReceiverFactory) createLogsReceiver(
 	nextConsumer consumer.Logs,
 ) (receiver.Logs, error) {
 	oCfg := *(cfg.(*Config))
-	if oCfg.Topic == "" {
+	if oCfg.Topic == "" && len(oCfg.Topics) == 0 && oCfg.TopicRegex == "" {
 		oCfg.Topic = defaultLogsTopic
 	}
 
@@ -26,8 +26,10 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.Equal(t, defaultGroupID, cfg.GroupID)
 	assert.Equal(t, defaultClientID, cfg.ClientID)
 	assert.Equal(t, defaultInitialOffset, cfg.InitialOffset)
+	assert.Equal(t, defaultIsolationLevel, cfg.IsolationLevel)
 	assert.Equal(t, defaultSessionTimeout, cfg.SessionTimeout)
 	assert.Equal(t, defaultHeartbeatInterval, cfg.HeartbeatInterval)
+	assert.Equal(t, defaultTopicRefreshInterval, cfg.TopicRefreshInterval)
 	assert.Equal(t, defaultMinFetchSize, cfg.MinFetchSize)
 	assert.Equal(t, defaultDefaultFetchSize, cfg.DefaultFetchSize)
 	assert.Equal(t, defaultMaxFetchSize, cfg.MaxFetchSize)
@@ -72,6 +74,18 @@ func TestWithTracesUnmarshalers(t *testing.T) {
 	})
 }
 
+func TestCreateLogs_topics(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Topics = []string{"tenant_a", "tenant_b"}
+	f := kafkaReceiverFactory{}
+	r, err := f.createLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, nil)
+	require.NoError(t, err)
+	logsConsumer, ok := r.(*kafkaLogsConsumer)
+	require.True(t, ok)
+	assert.Empty(t, logsConsumer.config.Topic)
+	assert.Equal(t, []string{"tenant_a", "tenant_b"}, logsConsumer.subscription.topics)
+}
+
 func TestCreateMetrics(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.Brokers = []string{"invalid:9092"}
@@ -23,6 +23,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver/internal/metadata"
 )
 
@@ -33,14 +34,18 @@ const (
 	attrPartition    = "partition"
 )
 
-var errInvalidInitialOffset = errors.New("invalid initial offset")
+var (
+	errInvalidInitialOffset  = errors.New("invalid initial offset")
+	errInvalidIsolationLevel = errors.New("invalid isolation level")
+)
 
 // kafkaTracesConsumer uses sarama to consume and handle messages from kafka.
 type kafkaTracesConsumer struct {
 	config            Config
 	consumerGroup     sarama.ConsumerGroup
 	nextConsumer      consumer.Traces
-	topics            []string
+	subscription      topicSubscription
+	deadLetterQueue   *deadLetterQueue
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       TracesUnmarshaler
 	consumeLoopWG     *sync.WaitGroup
@@ -62,7 +67,8 @@ type kafkaMetricsConsumer struct {
 	config            Config
 	consumerGroup     sarama.ConsumerGroup
 	nextConsumer      consumer.Metrics
-	topics            []string
+	subscription      topicSubscription
+	deadLetterQueue   *deadLetterQueue
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       MetricsUnmarshaler
 	consumeLoopWG     *sync.WaitGroup
@@ -84,7 +90,8 @@ type kafkaLogsConsumer struct {
 	config            Config
 	consumerGroup     sarama.ConsumerGroup
 	nextConsumer      consumer.Logs
-	topics            []string
+	subscription      topicSubscription
+	deadLetterQueue   *deadLetterQueue
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       LogsUnmarshaler
 	consumeLoopWG     *sync.WaitGroup
@@ -115,7 +122,7 @@ func newTracesReceiver(config Config, set receiver.Settings, nextConsumer consum
 
 	return &kafkaTracesConsumer{
 		config:            config,
-		topics:            []string{config.Topic},
+		subscription:      newTopicSubscription(config, set.Logger),
 		nextConsumer:      nextConsumer,
 		consumeLoopWG:     &sync.WaitGroup{},
 		settings:          set,
@@ -131,6 +138,14 @@ func newTracesReceiver(config Config, set receiver.Settings, nextConsumer consum
 }
 
 func createKafkaClient(ctx context.Context, config Config) (sarama.ConsumerGroup, error) {
+	saramaConfig, err := newSaramaConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return sarama.NewConsumerGroup(config.Brokers, config.GroupID, saramaConfig)
+}
+
+func newSaramaConfig(ctx context.Context, config Config) (*sarama.Config, error) {
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.ClientID = config.ClientID
 	saramaConfig.Metadata.Full = config.Metadata.Full
@@ -148,6 +163,9 @@ func createKafkaClient(ctx context.Context, config Config) (sarama.ConsumerGroup
 	if saramaConfig.Consumer.Offsets.Initial, err = toSaramaInitialOffset(config.InitialOffset); err != nil {
 		return nil, err
 	}
+	if saramaConfig.Consumer.IsolationLevel, err = toSaramaIsolationLevel(config.IsolationLevel); err != nil {
+		return nil, err
+	}
 	if config.ResolveCanonicalBootstrapServersOnly {
 		saramaConfig.Net.ResolveCanonicalBootstrapServers = true
 	}
@@ -159,7 +177,35 @@ func createKafkaClient(ctx context.Context, config Config) (sarama.ConsumerGroup
 	if err := kafka.ConfigureAuthentication(ctx, config.Authentication, saramaConfig); err != nil {
 		return nil, err
 	}
-	return sarama.NewConsumerGroup(config.Brokers, config.GroupID, saramaConfig)
+	return saramaConfig, nil
+}
+
+// createTopicsClient creates the client listing the topics matching the topic regex.
+func createTopicsClient(ctx context.Context, config Config) (topicsClient, error) {
+	saramaConfig, err := newSaramaConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return sarama.NewClient(config.Brokers, saramaConfig)
+}
+
+// createDeadLetterQueue creates the producer of the dead letter topic, or returns nil
+// if no dead letter topic is configured.
+func createDeadLetterQueue(ctx context.Context, config Config) (*deadLetterQueue, error) {
+	if config.DeadLetter.Topic == "" {
+		return nil, nil
+	}
+	saramaConfig, err := newSaramaConfig(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+	producer, err := sarama.NewSyncProducer(config.Brokers, saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &deadLetterQueue{topic: config.DeadLetter.Topic, producer: producer}, nil
 }
 
 func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) error {
@@ -195,6 +241,17 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 			return err
 		}
 	}
+	// The topics client and the dead letter queue may be set in tests to inject fake implementations.
+	if c.subscription.regex != nil && c.subscription.client == nil {
+		if c.subscription.client, err = createTopicsClient(ctx, c.config); err != nil {
+			return err
+		}
+	}
+	if c.deadLetterQueue == nil {
+		if c.deadLetterQueue, err = createDeadLetterQueue(ctx, c.config); err != nil {
+			return err
+		}
+	}
 	consumerGroup := &tracesConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -204,6 +261,7 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, host component.Host) erro
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		deadLetterQueue:   c.deadLetterQueue,
 		telemetryBuilder:  c.telemetryBuilder,
 	}
 	if c.headerExtraction {
@@ -224,7 +282,7 @@ func (c *kafkaTracesConsumer) consumeLoop(ctx context.Context, handler sarama.Co
 		// `Consume` should be called inside an infinite loop, when a
 		// server-side rebalance happens, the consumer session will need to be
 		// recreated to get the new claims
-		if err := c.consumerGroup.Consume(ctx, c.topics, handler); err != nil {
+		if err := c.subscription.consume(ctx, c.consumerGroup, handler); err != nil {
 			c.settings.Logger.Error("Error from consumer", zap.Error(err))
 		}
 		// check if context was cancelled, signaling that the consumer should stop
@@ -244,7 +302,7 @@ func (c *kafkaTracesConsumer) Shutdown(context.Context) error {
 	if c.consumerGroup == nil {
 		return nil
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.subscription.close(), c.deadLetterQueue.close())
 }
 
 func newMetricsReceiver(config Config, set receiver.Settings, nextConsumer consumer.Metrics) (*kafkaMetricsConsumer, error) {
@@ -255,7 +313,7 @@ func newMetricsReceiver(config Config, set receiver.Settings, nextConsumer consu
 
 	return &kafkaMetricsConsumer{
 		config:            config,
-		topics:            []string{config.Topic},
+		subscription:      newTopicSubscription(config, set.Logger),
 		nextConsumer:      nextConsumer,
 		consumeLoopWG:     &sync.WaitGroup{},
 		settings:          set,
@@ -303,6 +361,17 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 			return err
 		}
 	}
+	// The topics client and the dead letter queue may be set in tests to inject fake implementations.
+	if c.subscription.regex != nil && c.subscription.client == nil {
+		if c.subscription.client, err = createTopicsClient(ctx, c.config); err != nil {
+			return err
+		}
+	}
+	if c.deadLetterQueue == nil {
+		if c.deadLetterQueue, err = createDeadLetterQueue(ctx, c.config); err != nil {
+			return err
+		}
+	}
 	metricsConsumerGroup := &metricsConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -312,6 +381,7 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, host component.Host) err
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		deadLetterQueue:   c.deadLetterQueue,
 		telemetryBuilder:  c.telemetryBuilder,
 	}
 	if c.headerExtraction {
@@ -332,7 +402,7 @@ func (c *kafkaMetricsConsumer) consumeLoop(ctx context.Context, handler sarama.C
 		// `Consume` should be called inside an infinite loop, when a
 		// server-side rebalance happens, the consumer session will need to be
 		// recreated to get the new claims
-		if err := c.consumerGroup.Consume(ctx, c.topics, handler); err != nil {
+		if err := c.subscription.consume(ctx, c.consumerGroup, handler); err != nil {
 			c.settings.Logger.Error("Error from consumer", zap.Error(err))
 		}
 		// check if context was cancelled, signaling that the consumer should stop
@@ -352,7 +422,7 @@ func (c *kafkaMetricsConsumer) Shutdown(context.Context) error {
 	if c.consumerGroup == nil {
 		return nil
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.subscription.close(), c.deadLetterQueue.close())
 }
 
 func newLogsReceiver(config Config, set receiver.Settings, nextConsumer consumer.Logs) (*kafkaLogsConsumer, error) {
@@ -363,7 +433,7 @@ func newLogsReceiver(config Config, set receiver.Settings, nextConsumer consumer
 
 	return &kafkaLogsConsumer{
 		config:            config,
-		topics:            []string{config.Topic},
+		subscription:      newTopicSubscription(config, set.Logger),
 		nextConsumer:      nextConsumer,
 		consumeLoopWG:     &sync.WaitGroup{},
 		settings:          set,
@@ -399,6 +469,13 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 			encoding:    c.config.Encoding,
 		}
 	}
+	if c.unmarshaler == nil && c.config.Encoding == schemaRegistryEncoding {
+		client, errClient := schemaregistry.NewClient(ctx, *c.config.SchemaRegistry)
+		if errClient != nil {
+			return errClient
+		}
+		c.unmarshaler = newSchemaRegistryLogsUnmarshaler(client)
+	}
 	if unmarshaler, errInt := getLogsUnmarshaler(
 		c.config.Encoding,
 		defaultLogsUnmarshalers(c.settings.BuildInfo.Version, c.settings.Logger),
@@ -414,6 +491,17 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 			return err
 		}
 	}
+	// The topics client and the dead letter queue may be set in tests to inject fake implementations.
+	if c.subscription.regex != nil && c.subscription.client == nil {
+		if c.subscription.client, err = createTopicsClient(ctx, c.config); err != nil {
+			return err
+		}
+	}
+	if c.deadLetterQueue == nil {
+		if c.deadLetterQueue, err = createDeadLetterQueue(ctx, c.config); err != nil {
+			return err
+		}
+	}
 	logsConsumerGroup := &logsConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -423,6 +511,7 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, host component.Host) error
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		deadLetterQueue:   c.deadLetterQueue,
 		telemetryBuilder:  c.telemetryBuilder,
 	}
 	if c.headerExtraction {
@@ -443,7 +532,7 @@ func (c *kafkaLogsConsumer) consumeLoop(ctx context.Context, handler sarama.Cons
 		// `Consume` should be called inside an infinite loop, when a
 		// server-side rebalance happens, the consumer session will need to be
 		// recreated to get the new claims
-		if err := c.consumerGroup.Consume(ctx, c.topics, handler); err != nil {
+		if err := c.subscription.consume(ctx, c.consumerGroup, handler); err != nil {
 			c.settings.Logger.Error("Error from consumer", zap.Error(err))
 		}
 		// check if context was cancelled, signaling that the consumer should stop
@@ -460,10 +549,13 @@ func (c *kafkaLogsConsumer) Shutdown(context.Context) error {
 	}
 	c.cancelConsumeLoop()
 	c.consumeLoopWG.Wait()
+	if u, ok := c.unmarshaler.(*schemaRegistryLogsUnmarshaler); ok {
+		u.client.Close()
+	}
 	if c.consumerGroup == nil {
 		return nil
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.subscription.close(), c.deadLetterQueue.close())
 }
 
 type tracesConsumerGroupHandler struct {
@@ -481,6 +573,7 @@ type tracesConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	deadLetterQueue   *deadLetterQueue
 }
 
 type metricsConsumerGroupHandler struct {
@@ -498,6 +591,7 @@ type metricsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	deadLetterQueue   *deadLetterQueue
 }
 
 type logsConsumerGroupHandler struct {
@@ -515,6 +609,7 @@ type logsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	deadLetterQueue   *deadLetterQueue
 }
 
 var (
@@ -523,10 +618,14 @@ var (
 	_ sarama.ConsumerGroupHandler = (*logsConsumerGroupHandler)(nil)
 )
 
-func (c *tracesConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+func (c *tracesConsumerGroupHandler) setReady() {
 	c.readyCloser.Do(func() {
 		close(c.ready)
 	})
+}
+
+func (c *tracesConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	c.setReady()
 	c.telemetryBuilder.KafkaReceiverPartitionStart.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.Name())))
 	return nil
 }
@@ -568,6 +667,12 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedSpans.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
+				if c.deadLetterQueue.handle(c.logger, session, message, err) {
+					if !c.autocommitEnabled {
+						session.Commit()
+					}
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -600,10 +705,14 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 	}
 }
 
-func (c *metricsConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+func (c *metricsConsumerGroupHandler) setReady() {
 	c.readyCloser.Do(func() {
 		close(c.ready)
 	})
+}
+
+func (c *metricsConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	c.setReady()
 	c.telemetryBuilder.KafkaReceiverPartitionStart.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.Name())))
 	return nil
 }
@@ -645,6 +754,12 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedMetricPoints.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
+				if c.deadLetterQueue.handle(c.logger, session, message, err) {
+					if !c.autocommitEnabled {
+						session.Commit()
+					}
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -677,10 +792,14 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 	}
 }
 
-func (c *logsConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+func (c *logsConsumerGroupHandler) setReady() {
 	c.readyCloser.Do(func() {
 		close(c.ready)
 	})
+}
+
+func (c *logsConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	c.setReady()
 	c.telemetryBuilder.KafkaReceiverPartitionStart.Add(session.Context(), 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
 	return nil
 }
@@ -722,6 +841,12 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 			if err != nil {
 				c.logger.Error("failed to unmarshal message", zap.Error(err))
 				c.telemetryBuilder.KafkaReceiverUnmarshalFailedLogRecords.Add(ctx, 1, metric.WithAttributes(attribute.String(attrInstanceName, c.id.String())))
+				if c.deadLetterQueue.handle(c.logger, session, message, err) {
+					if !c.autocommitEnabled {
+						session.Commit()
+					}
+					continue
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -766,6 +891,19 @@ func toSaramaInitialOffset(initialOffset string) (int64, error) {
 	}
 }
 
+func toSaramaIsolationLevel(isolationLevel string) (sarama.IsolationLevel, error) {
+	switch isolationLevel {
+	case isolationLevelReadCommitted:
+		return sarama.ReadCommitted, nil
+	case isolationLevelReadUncommitted:
+		fallthrough
+	case "":
+		return sarama.ReadUncommitted, nil
+	default:
+		return 0, errInvalidIsolationLevel
+	}
+}
+
 // loadEncodingExtension tries to load an available extension for the given encoding.
 func loadEncodingExtension[T any](host component.Host, encoding string) (*T, error) {
 	extensionID, err := encodingToComponentID(encoding)
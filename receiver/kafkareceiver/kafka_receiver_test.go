@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
@@ -32,6 +33,8 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/textutils"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver/internal/metadata"
 )
 
@@ -796,6 +799,19 @@ func TestNewLogsReceiver_initial_offset_err(t *testing.T) {
 	assert.EqualError(t, err, errInvalidInitialOffset.Error())
 }
 
+func TestNewLogsReceiver_isolation_level_err(t *testing.T) {
+	c := Config{
+		IsolationLevel: "foo",
+		Encoding:       defaultEncoding,
+	}
+	r, err := newLogsReceiver(c, receivertest.NewNopSettings(), consumertest.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	err = r.Start(context.Background(), componenttest.NewNopHost())
+	require.Error(t, err)
+	assert.EqualError(t, err, errInvalidIsolationLevel.Error())
+}
+
 func TestLogsReceiverStart(t *testing.T) {
 	c := kafkaLogsConsumer{
 		config:           *createDefaultConfig().(*Config),
@@ -1039,6 +1055,45 @@ func TestLogsConsumerGroupHandler_error_unmarshal(t *testing.T) {
 	require.NoError(t, tel.Shutdown(context.Background()))
 }
 
+func TestLogsConsumerGroupHandler_dead_letter(t *testing.T) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+	require.NoError(t, err)
+	sink := &consumertest.LogsSink{}
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		assert.Equal(t, "dead_letters", msg.Topic)
+		return nil
+	})
+	c := logsConsumerGroupHandler{
+		unmarshaler:      newPdataLogsUnmarshaler(&plog.ProtoUnmarshaler{}, defaultEncoding),
+		logger:           zap.NewNop(),
+		ready:            make(chan bool),
+		nextConsumer:     sink,
+		obsrecv:          obsrecv,
+		headerExtractor:  &nopHeaderExtractor{},
+		telemetryBuilder: nopTelemetryBuilder(t),
+		deadLetterQueue:  &deadLetterQueue{topic: "dead_letters", producer: producer},
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		assert.NoError(t, c.ConsumeClaim(testConsumerGroupSession{ctx: context.Background()}, groupClaim))
+		wg.Done()
+	}()
+
+	// The message that fails to be unmarshaled doesn't stop the consumption of the partition
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: []byte("!@#")}
+	groupClaim.messageChan <- &sarama.ConsumerMessage{}
+	close(groupClaim.messageChan)
+	wg.Wait()
+	assert.Len(t, sink.AllLogs(), 1)
+	require.NoError(t, producer.Close())
+}
+
 func TestLogsConsumerGroupHandler_error_nextConsumer(t *testing.T) {
 	consumerError := errors.New("failed to consume")
 	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
@@ -1197,6 +1252,25 @@ func TestCreateLogs_encoding_text_error(t *testing.T) {
 	assert.Error(t, err, "unsupported encoding")
 }
 
+func TestLogsReceiver_schema_registry(t *testing.T) {
+	registry := schemaregistrytest.NewServer(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Encoding = "schema_registry"
+	cfg.SchemaRegistry = &schemaregistry.ClientConfig{URL: registry.URL}
+	c := kafkaLogsConsumer{
+		config:           *cfg,
+		nextConsumer:     consumertest.NewNop(),
+		consumeLoopWG:    &sync.WaitGroup{},
+		settings:         receivertest.NewNopSettings(),
+		consumerGroup:    &testConsumerGroup{},
+		telemetryBuilder: nopTelemetryBuilder(t),
+	}
+
+	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	assert.IsType(t, &schemaRegistryLogsUnmarshaler{}, c.unmarshaler)
+	require.NoError(t, c.Shutdown(context.Background()))
+}
+
 func TestLogsReceiver_encoding_extension(t *testing.T) {
 	zcore, logObserver := observer.New(zapcore.ErrorLevel)
 	logger := zap.New(zcore)
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/testdata"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+)
+
+const relayTopic = "relayed_logs"
+
+// markingConsumerGroupSession records the marked offsets, which are the offsets committed.
+type markingConsumerGroupSession struct {
+	testConsumerGroupSession
+	mu     sync.Mutex
+	marked []int64
+}
+
+func (s *markingConsumerGroupSession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.marked = append(s.marked, msg.Offset)
+}
+
+// newRelayBroker starts a broker accepting the transactions of a kafka exporter, and
+// failing its produce requests with produceErr.
+func newRelayBroker(t *testing.T, produceErr sarama.KError) *sarama.MockBroker {
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetController(broker.BrokerID()).
+			SetLeader(relayTopic, 0, broker.BrokerID()),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorTransaction, "relay-logs", broker),
+		"InitProducerIDRequest": sarama.NewMockInitProducerIDResponse(t).
+			SetProducerID(1000).
+			SetProducerEpoch(1),
+		"AddPartitionsToTxnRequest": sarama.NewMockWrapper(&sarama.AddPartitionsToTxnResponse{
+			Version: 2,
+			Errors: map[string][]*sarama.PartitionError{
+				relayTopic: {{Partition: 0, Err: sarama.ErrNoError}},
+			},
+		}),
+		"ProduceRequest": sarama.NewMockProduceResponse(t).
+			SetVersion(7).
+			SetError(relayTopic, 0, produceErr),
+		"EndTxnRequest": sarama.NewMockWrapper(&sarama.EndTxnResponse{Version: 1}),
+	})
+	return broker
+}
+
+// newRelayExporter creates a kafka exporter producing to the broker in transactions,
+// and without sending queue so that logs are consumed once they are produced.
+func newRelayExporter(t *testing.T, broker *sarama.MockBroker) *logsConsumerGroupHandler {
+	factory := kafkaexporter.NewFactory()
+	cfg := factory.CreateDefaultConfig().(*kafkaexporter.Config)
+	cfg.Brokers = []string{broker.Addr()}
+	cfg.ProtocolVersion = "2.1.0"
+	cfg.Topic = relayTopic
+	cfg.Producer.RequiredAcks = sarama.WaitForAll
+	cfg.Producer.Idempotent = true
+	cfg.Producer.TransactionalID = "relay"
+	cfg.QueueSettings.Enabled = false
+	cfg.BackOffConfig.Enabled = false
+	require.NoError(t, cfg.Validate())
+
+	exp, err := factory.CreateLogs(context.Background(), exportertest.NewNopSettings(), cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, exp.Shutdown(context.Background()))
+	})
+
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+	require.NoError(t, err)
+	return &logsConsumerGroupHandler{
+		unmarshaler:      newPdataLogsUnmarshaler(&plog.ProtoUnmarshaler{}, defaultEncoding),
+		logger:           zap.NewNop(),
+		ready:            make(chan bool),
+		nextConsumer:     exp,
+		obsrecv:          obsrecv,
+		messageMarking:   MessageMarking{After: true},
+		headerExtractor:  &nopHeaderExtractor{},
+		telemetryBuilder: nopTelemetryBuilder(t),
+	}
+}
+
+func relayMessage(t *testing.T, handler *logsConsumerGroupHandler, session *markingConsumerGroupSession) error {
+	data, err := (&plog.ProtoMarshaler{}).MarshalLogs(testdata.GenerateLogs(1))
+	require.NoError(t, err)
+	claim := &testConsumerGroupClaim{messageChan: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messageChan <- &sarama.ConsumerMessage{Topic: "logs", Offset: 42, Value: data}
+	close(claim.messageChan)
+	return handler.ConsumeClaim(session, claim)
+}
+
+func endTxnRequests(broker *sarama.MockBroker) []bool {
+	var commits []bool
+	for _, r := range broker.History() {
+		if req, ok := r.Request.(*sarama.EndTxnRequest); ok {
+			commits = append(commits, req.TransactionResult)
+		}
+	}
+	return commits
+}
+
+func TestLogsRelay_transactional(t *testing.T) {
+	broker := newRelayBroker(t, sarama.ErrNoError)
+	handler := newRelayExporter(t, broker)
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+
+	require.NoError(t, relayMessage(t, handler, session))
+	// The message is marked once its transaction is committed
+	assert.Equal(t, []int64{42}, session.marked)
+	assert.Equal(t, []bool{true}, endTxnRequests(broker))
+}
+
+func TestLogsRelay_transactional_aborted(t *testing.T) {
+	broker := newRelayBroker(t, sarama.ErrNotEnoughReplicas)
+	handler := newRelayExporter(t, broker)
+	session := &markingConsumerGroupSession{testConsumerGroupSession: testConsumerGroupSession{ctx: context.Background()}}
+
+	assert.Error(t, relayMessage(t, handler, session))
+	// The message is consumed again by the next session, after the transaction is aborted
+	assert.Empty(t, session.marked)
+	assert.Equal(t, []bool{false}, endTxnRequests(broker))
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+)
+
+const (
+	schemaRegistryEncoding = "schema_registry"
+
+	attrSchemaID   = "schema_registry.schema_id"
+	attrSchemaType = "schema_registry.schema_type"
+	attrRecordType = "schema_registry.record_type"
+)
+
+// schemaRegistryLogsUnmarshaler deserializes Avro and Protobuf records in the Confluent
+// wire format into log records, with the record as body.
+type schemaRegistryLogsUnmarshaler struct {
+	client       *schemaregistry.Client
+	deserializer *schemaregistry.Deserializer
+}
+
+func newSchemaRegistryLogsUnmarshaler(client *schemaregistry.Client) *schemaRegistryLogsUnmarshaler {
+	return &schemaRegistryLogsUnmarshaler{client: client, deserializer: schemaregistry.NewDeserializer(client)}
+}
+
+func (u *schemaRegistryLogsUnmarshaler) Unmarshal(buf []byte) (plog.Logs, error) {
+	p := plog.NewLogs()
+	record, err := u.deserializer.Deserialize(context.Background(), buf)
+	if err != nil {
+		return p, err
+	}
+
+	logRecord := p.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	logRecord.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	logRecord.Attributes().PutInt(attrSchemaID, int64(record.Schema.ID))
+	logRecord.Attributes().PutStr(attrSchemaType, record.Schema.Type)
+	if record.TypeName != "" {
+		logRecord.Attributes().PutStr(attrRecordType, record.TypeName)
+	}
+	if err := logRecord.Body().SetEmptyMap().FromRaw(record.Value); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+func (u *schemaRegistryLogsUnmarshaler) Encoding() string {
+	return schemaRegistryEncoding
+}
@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
+)
+
+func newTestSchemaRegistryClient(t *testing.T, url string) *schemaregistry.Client {
+	client, err := schemaregistry.NewClient(context.Background(), schemaregistry.ClientConfig{URL: url})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestSchemaRegistryLogsUnmarshaler(t *testing.T) {
+	registry := schemaregistrytest.NewServer(t)
+	client := newTestSchemaRegistryClient(t, registry.URL)
+	tests := []struct {
+		name       string
+		schemaType string
+		schema     string
+		recordType string
+		value      map[string]any
+	}{
+		{
+			name:       "avro",
+			schemaType: schemaregistry.SchemaTypeAvro,
+			schema: `{"type": "record", "name": "Log", "namespace": "com.example", "fields": [
+				{"name": "message", "type": "string"},
+				{"name": "attempts", "type": "long"},
+				{"name": "user", "type": ["null", "string"]}
+			]}`,
+			recordType: "com.example.Log",
+			value:      map[string]any{"message": "login failed", "attempts": int64(3), "user": nil},
+		},
+		{
+			name:       "protobuf",
+			schemaType: schemaregistry.SchemaTypeProtobuf,
+			schema:     `syntax = "proto3"; package example; message Log { string message = 1; int32 attempts = 2; repeated string tags = 3; }`,
+			recordType: "example.Log",
+			value:      map[string]any{"message": "login failed", "attempts": int64(3), "tags": []any{"auth"}},
+		},
+	}
+	um := newSchemaRegistryLogsUnmarshaler(client)
+	assert.Equal(t, "schema_registry", um.Encoding())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serializer, err := schemaregistry.NewSerializer(client, tt.schemaType, tt.schema, "")
+			require.NoError(t, err)
+			data, err := serializer.Serialize(context.Background(), "logs-value", tt.value)
+			require.NoError(t, err)
+
+			logs, err := um.Unmarshal(data)
+			require.NoError(t, err)
+			require.Equal(t, 1, logs.LogRecordCount())
+			record := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+			assert.NotZero(t, record.ObservedTimestamp())
+			assert.Equal(t, tt.value, record.Body().Map().AsRaw())
+			id, ok := record.Attributes().Get("schema_registry.schema_id")
+			require.True(t, ok)
+			assert.Positive(t, id.Int())
+			schemaType, _ := record.Attributes().Get("schema_registry.schema_type")
+			assert.Equal(t, tt.schemaType, schemaType.Str())
+			recordType, _ := record.Attributes().Get("schema_registry.record_type")
+			assert.Equal(t, tt.recordType, recordType.Str())
+		})
+	}
+}
+
+func TestSchemaRegistryLogsUnmarshaler_error(t *testing.T) {
+	registry := schemaregistrytest.NewServer(t)
+	um := newSchemaRegistryLogsUnmarshaler(newTestSchemaRegistryClient(t, registry.URL))
+
+	_, err := um.Unmarshal([]byte(`{"message": "not framed"}`))
+	assert.ErrorContains(t, err, "not in the schema registry wire format")
+	_, err = um.Unmarshal([]byte{0, 0, 0, 0, 42, 2})
+	assert.ErrorContains(t, err, "get schema 42")
+}
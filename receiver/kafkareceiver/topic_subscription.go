@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+)
+
+// topicsClient is the part of sarama.Client used to list the topics of the cluster.
+type topicsClient interface {
+	RefreshMetadata(topics ...string) error
+	Topics() ([]string, error)
+	Close() error
+}
+
+var _ topicsClient = (sarama.Client)(nil)
+
+// readyHandler is a consumer group handler that is ready once a session is set up.
+type readyHandler interface {
+	setReady()
+}
+
+var (
+	_ readyHandler = (*tracesConsumerGroupHandler)(nil)
+	_ readyHandler = (*metricsConsumerGroupHandler)(nil)
+	_ readyHandler = (*logsConsumerGroupHandler)(nil)
+)
+
+// topicSubscription holds the topics a receiver consumes from, either a list of
+// topics or the topics matching a regular expression.
+type topicSubscription struct {
+	topics          []string
+	regex           *regexp.Regexp
+	refreshInterval time.Duration
+	// client is only used to list the topics matching regex.
+	client topicsClient
+	logger *zap.Logger
+}
+
+func newTopicSubscription(config Config, logger *zap.Logger) topicSubscription {
+	s := topicSubscription{
+		topics:          config.Topics,
+		refreshInterval: config.TopicRefreshInterval,
+		logger:          logger,
+	}
+	if config.Topic != "" {
+		s.topics = []string{config.Topic}
+	}
+	if config.TopicRegex != "" {
+		// The regular expression is checked in Config.Validate
+		s.regex = regexp.MustCompile(config.TopicRegex)
+	}
+	return s
+}
+
+// matchingTopics returns the sorted topics of the cluster matching the regular expression.
+func (s *topicSubscription) matchingTopics() ([]string, error) {
+	if err := s.client.RefreshMetadata(); err != nil {
+		return nil, err
+	}
+	all, err := s.client.Topics()
+	if err != nil {
+		return nil, err
+	}
+	var topics []string
+	for _, topic := range all {
+		if s.regex.MatchString(topic) {
+			topics = append(topics, topic)
+		}
+	}
+	slices.Sort(topics)
+	return topics, nil
+}
+
+// consume joins a consumer group session for the subscribed topics. When the topics are
+// matched with a regular expression, the session is ended as soon as the matching topics
+// change, so that the caller starts a new session for the new topics.
+func (s *topicSubscription) consume(ctx context.Context, consumerGroup sarama.ConsumerGroup, handler sarama.ConsumerGroupHandler) error {
+	if s.regex == nil {
+		return consumerGroup.Consume(ctx, s.topics, handler)
+	}
+
+	topics, err := s.matchingTopics()
+	if err != nil {
+		return err
+	}
+	if len(topics) == 0 {
+		s.logger.Debug("No topics match the topic regex yet", zap.String("topic_regex", s.regex.String()))
+		// Don't hold the start of the receiver until a topic matches
+		if h, ok := handler.(readyHandler); ok {
+			h.setReady()
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.refreshInterval):
+		}
+		return nil
+	}
+
+	sessionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(s.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-sessionCtx.Done():
+				return
+			case <-ticker.C:
+				refreshed, err := s.matchingTopics()
+				if err != nil {
+					s.logger.Warn("Failed to refresh the topics matching the topic regex", zap.Error(err))
+					continue
+				}
+				if !slices.Equal(topics, refreshed) {
+					s.logger.Info("Topics matching the topic regex changed", zap.Strings("topics", refreshed))
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+	return consumerGroup.Consume(sessionCtx, topics, handler)
+}
+
+func (s *topicSubscription) close() error {
+	if s.client == nil {
+		return nil
+	}
+	return s.client.Close()
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type testTopicsClient struct {
+	mu     sync.Mutex
+	topics []string
+}
+
+func (c *testTopicsClient) RefreshMetadata(...string) error {
+	return nil
+}
+
+func (c *testTopicsClient) Topics() ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.topics...), nil
+}
+
+func (c *testTopicsClient) setTopics(topics ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.topics = topics
+}
+
+func (c *testTopicsClient) Close() error {
+	return nil
+}
+
+// sessionConsumerGroup records the topics of each session, and holds the session until
+// its context is done.
+type sessionConsumerGroup struct {
+	testConsumerGroup
+	sessions chan []string
+}
+
+func (g *sessionConsumerGroup) Consume(ctx context.Context, topics []string, _ sarama.ConsumerGroupHandler) error {
+	g.sessions <- topics
+	<-ctx.Done()
+	return nil
+}
+
+func TestTopicSubscription_topics(t *testing.T) {
+	s := newTopicSubscription(Config{Topics: []string{"tenant_a", "tenant_b"}}, zap.NewNop())
+	group := &sessionConsumerGroup{sessions: make(chan []string, 1)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, s.consume(ctx, group, nil))
+	assert.Equal(t, []string{"tenant_a", "tenant_b"}, <-group.sessions)
+}
+
+func TestTopicSubscription_regex(t *testing.T) {
+	s := newTopicSubscription(Config{TopicRegex: "^tenant_", TopicRefreshInterval: 10 * time.Millisecond}, zap.NewNop())
+	client := &testTopicsClient{topics: []string{"tenant_b", "other", "tenant_a"}}
+	s.client = client
+	group := &sessionConsumerGroup{sessions: make(chan []string, 1)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- s.consume(ctx, group, nil)
+	}()
+	assert.Equal(t, []string{"tenant_a", "tenant_b"}, <-group.sessions)
+
+	// The session ends once a new topic matches
+	client.setTopics("tenant_b", "other", "tenant_a", "tenant_c")
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "session did not end after the matching topics changed")
+	}
+	require.NoError(t, ctx.Err())
+
+	go func() {
+		done <- s.consume(ctx, group, nil)
+	}()
+	assert.Equal(t, []string{"tenant_a", "tenant_b", "tenant_c"}, <-group.sessions)
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestTopicSubscription_regex_no_match(t *testing.T) {
+	s := newTopicSubscription(Config{TopicRegex: "^tenant_", TopicRefreshInterval: 10 * time.Millisecond}, zap.NewNop())
+	s.client = &testTopicsClient{topics: []string{"other"}}
+	group := &sessionConsumerGroup{sessions: make(chan []string, 1)}
+
+	handler := &logsConsumerGroupHandler{ready: make(chan bool)}
+
+	require.NoError(t, s.consume(context.Background(), group, handler))
+	assert.Empty(t, group.sessions)
+	_, ok := <-handler.ready
+	assert.False(t, ok)
+}
@@ -8,14 +8,20 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/cespare/xxhash/v2"
 	"github.com/gogo/protobuf/proto"
 	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
 	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	promremote "github.com/prometheus/prometheus/storage/remote"
 	"go.opentelemetry.io/collector/component"
@@ -112,7 +118,14 @@ func (prw *prometheusRemoteWriteReceiver) handlePRW(w http.ResponseWriter, req *
 		return
 	}
 
-	_, stats, err := prw.translateV2(req.Context(), &prw2Req)
+	m, stats, err := prw.translateV2(req.Context(), &prw2Req)
+	if m.DataPointCount() > 0 {
+		if consumeErr := prw.nextConsumer.ConsumeMetrics(req.Context(), m); consumeErr != nil {
+			prw.settings.Logger.Warn("Error consuming remote write request", zapcore.Field{Key: "error", Type: zapcore.ErrorType, Interface: consumeErr})
+			http.Error(w, consumeErr.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 	stats.SetHeaders(w)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest) // Following instructions at https://prometheus.io/docs/specs/remote_write_spec_2_0/#invalid-samples
@@ -153,8 +166,8 @@ func (prw *prometheusRemoteWriteReceiver) parseProto(contentType string) (promco
 }
 
 // translateV2 translates a v2 remote-write request into OTLP metrics.
-// translate is not feature complete.
-// nolint
+// Series that fail validation are skipped and reported through the returned error, the remaining
+// series are still translated.
 func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *writev2.Request) (pmetric.Metrics, promremote.WriteResponseStats, error) {
 	var (
 		badRequestErrors error
@@ -166,9 +179,14 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 		// This cache is called "intra" because in the future we'll have a "interRequestCache" to cache resourceAttributes
 		// between requests based on the metric "target_info".
 		intraRequestCache = make(map[uint64]pmetric.ResourceMetrics)
+		metrics           = newMetricsCache()
 	)
 
 	for _, ts := range req.Timeseries {
+		if err := validateRefs(ts, len(req.Symbols)); err != nil {
+			badRequestErrors = errors.Join(badRequestErrors, err)
+			continue
+		}
 		ls := ts.ToLabels(&labelsBuilder, req.Symbols)
 
 		if !ls.Has(labels.MetricName) {
@@ -191,23 +209,63 @@ func (prw *prometheusRemoteWriteReceiver) translateV2(_ context.Context, req *wr
 			intraRequestCache[hashedLabels] = rm
 		}
 
+		md := ts.ToMetadata(req.Symbols)
+		metricName := ls.Get(labels.MetricName)
+		var err error
 		switch ts.Metadata.Type {
 		case writev2.Metadata_METRIC_TYPE_COUNTER:
-			addCounterDatapoints(rm, ls, ts)
+			m, _ := metrics.getOrCreateMetric(rm, hashedLabels, ls, metricName, md.Unit, md.Help, pmetric.MetricTypeSum)
+			addCounterDatapoints(m, ls, ts)
+			stats.Samples += len(ts.Samples)
 		case writev2.Metadata_METRIC_TYPE_GAUGE:
-			addGaugeDatapoints(rm, ls, ts)
+			m, _ := metrics.getOrCreateMetric(rm, hashedLabels, ls, metricName, md.Unit, md.Help, pmetric.MetricTypeGauge)
+			addGaugeDatapoints(m, ls, ts)
+			stats.Samples += len(ts.Samples)
 		case writev2.Metadata_METRIC_TYPE_SUMMARY:
-			addSummaryDatapoints(rm, ls, ts)
+			err = metrics.addSummaryDatapoints(rm, hashedLabels, ls, md.Unit, md.Help, ts)
+			if err == nil {
+				stats.Samples += len(ts.Samples)
+			}
 		case writev2.Metadata_METRIC_TYPE_HISTOGRAM:
-			addHistogramDatapoints(rm, ls, ts)
+			if len(ts.Histograms) > 0 {
+				m, _ := metrics.getOrCreateMetric(rm, hashedLabels, ls, metricName, md.Unit, md.Help, pmetric.MetricTypeExponentialHistogram)
+				err = addExponentialHistogramDatapoints(m, ls, ts)
+				if err == nil {
+					stats.Histograms += len(ts.Histograms)
+				}
+				break
+			}
+			err = metrics.addHistogramDatapoints(rm, hashedLabels, ls, md.Unit, md.Help, ts)
+			if err == nil {
+				stats.Samples += len(ts.Samples)
+			}
 		default:
-			badRequestErrors = errors.Join(badRequestErrors, fmt.Errorf("unsupported metric type %q for metric %q", ts.Metadata.Type, ls.Get(labels.MetricName)))
+			err = fmt.Errorf("unsupported metric type %q for metric %q", ts.Metadata.Type, metricName)
 		}
+		badRequestErrors = errors.Join(badRequestErrors, err)
 	}
 
+	metrics.finalize()
 	return otelMetrics, stats, badRequestErrors
 }
 
+// validateRefs makes sure every symbol reference of the time series points inside the symbols table,
+// so the series can be safely desymbolized.
+func validateRefs(ts writev2.TimeSeries, numSymbols int) error {
+	if len(ts.LabelsRefs)%2 != 0 {
+		return fmt.Errorf("odd number of label references: %d", len(ts.LabelsRefs))
+	}
+	for _, ref := range ts.LabelsRefs {
+		if int(ref) >= numSymbols {
+			return fmt.Errorf("label reference %d out of range of %d symbols", ref, numSymbols)
+		}
+	}
+	if int(ts.Metadata.HelpRef) >= numSymbols || int(ts.Metadata.UnitRef) >= numSymbols {
+		return fmt.Errorf("metadata references out of range of %d symbols", numSymbols)
+	}
+	return nil
+}
+
 // parseJobAndInstance turns the job and instance labels service resource attributes.
 // Following the specification at https://opentelemetry.io/docs/specs/otel/compatibility/prometheus_and_openmetrics/
 func parseJobAndInstance(dest pcommon.Map, job, instance string) {
@@ -225,41 +283,350 @@ func parseJobAndInstance(dest pcommon.Map, job, instance string) {
 	}
 }
 
-func addCounterDatapoints(_ pmetric.ResourceMetrics, _ labels.Labels, _ writev2.TimeSeries) {
-	// TODO: Implement this function
+// metricsCache keeps track of the scopes, metrics and datapoints already created while translating
+// a single request. In OTel, name+type+unit is the unique identifier of a metric, so series sharing
+// those are appended to the same metric instead of creating a new one.
+type metricsCache struct {
+	scopes  map[uint64]pmetric.ScopeMetrics
+	metrics map[uint64]pmetric.Metric
+
+	// Classic histograms and summaries are sent as several series (_bucket, _sum, _count, quantiles),
+	// so their datapoints are assembled across series and only completed in finalize.
+	histograms map[uint64]*classicHistogram
+	summaries  map[uint64]pmetric.SummaryDataPoint
+}
+
+// classicHistogram holds the cumulative bucket counts of a classic histogram datapoint, keyed by
+// their upper bound, until all of its series have been seen.
+type classicHistogram struct {
+	dp       pmetric.HistogramDataPoint
+	buckets  map[float64]uint64
+	hasCount bool
+}
+
+func newMetricsCache() *metricsCache {
+	return &metricsCache{
+		scopes:     make(map[uint64]pmetric.ScopeMetrics),
+		metrics:    make(map[uint64]pmetric.Metric),
+		histograms: make(map[uint64]*classicHistogram),
+		summaries:  make(map[uint64]pmetric.SummaryDataPoint),
+	}
+}
+
+// getOrCreateMetric returns the metric identified by name+type+unit inside the scope described by the
+// "otel_scope_name" and "otel_scope_version" labels, creating the scope and the metric if needed.
+// The returned hash identifies the metric within the request.
+func (c *metricsCache) getOrCreateMetric(rm pmetric.ResourceMetrics, resourceHash uint64, ls labels.Labels, name, unit, description string, metricType pmetric.MetricType) (pmetric.Metric, uint64) {
+	scopeName, scopeVersion := ls.Get("otel_scope_name"), ls.Get("otel_scope_version")
+	scopeHash := hashStrings(strconv.FormatUint(resourceHash, 10), scopeName, scopeVersion)
+	sm, ok := c.scopes[scopeHash]
+	if !ok {
+		sm = rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(scopeName)
+		sm.Scope().SetVersion(scopeVersion)
+		c.scopes[scopeHash] = sm
+	}
+
+	metricHash := hashStrings(strconv.FormatUint(scopeHash, 10), name, metricType.String(), unit)
+	if m, ok := c.metrics[metricHash]; ok {
+		return m, metricHash
+	}
+	m := sm.Metrics().AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	m.SetDescription(description)
+	switch metricType {
+	case pmetric.MetricTypeGauge:
+		m.SetEmptyGauge()
+	case pmetric.MetricTypeSum:
+		sum := m.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeHistogram:
+		m.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeExponentialHistogram:
+		m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeSummary:
+		m.SetEmptySummary()
+	}
+	c.metrics[metricHash] = m
+	return m, metricHash
 }
 
-func addGaugeDatapoints(rm pmetric.ResourceMetrics, ls labels.Labels, ts writev2.TimeSeries) {
-	// TODO: Cache metric name+type+unit and look up cache before creating new empty metric.
-	// In OTel name+type+unit is the unique identifier of a metric and we should not create
-	// a new metric if it already exists.
+func addCounterDatapoints(m pmetric.Metric, ls labels.Labels, ts writev2.TimeSeries) {
+	addDatapoints(m.Sum().DataPoints(), ls, ts)
+}
 
-	// TODO: Check if Scope is already present by comparing labels "otel_scope_name" and "otel_scope_version"
-	// with Scope.Name and Scope.Version. If it is present, we should append to the existing Scope.
-	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge()
-	addDatapoints(m.DataPoints(), ls, ts)
+func addGaugeDatapoints(m pmetric.Metric, ls labels.Labels, ts writev2.TimeSeries) {
+	addDatapoints(m.Gauge().DataPoints(), ls, ts)
 }
 
-func addSummaryDatapoints(_ pmetric.ResourceMetrics, _ labels.Labels, _ writev2.TimeSeries) {
-	// TODO: Implement this function
+// addDatapoints appends one datapoint per sample, with the labels as datapoint attributes.
+func addDatapoints(datapoints pmetric.NumberDataPointSlice, ls labels.Labels, ts writev2.TimeSeries) {
+	for _, sample := range ts.Samples {
+		dp := datapoints.AppendEmpty()
+		dp.SetStartTimestamp(convertTimestamp(ts.CreatedTimestamp))
+		dp.SetTimestamp(convertTimestamp(sample.Timestamp))
+		dp.SetDoubleValue(sample.Value)
+		if value.IsStaleNaN(sample.Value) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+		}
+		extractAttributes(ls).CopyTo(dp.Attributes())
+	}
 }
 
-func addHistogramDatapoints(_ pmetric.ResourceMetrics, _ labels.Labels, _ writev2.TimeSeries) {
-	// TODO: Implement this function
+// addSummaryDatapoints assembles summary datapoints out of the quantile, "_sum" and "_count" series
+// of a Prometheus summary.
+func (c *metricsCache) addSummaryDatapoints(rm pmetric.ResourceMetrics, resourceHash uint64, ls labels.Labels, unit, description string, ts writev2.TimeSeries) error {
+	metricName := ls.Get(labels.MetricName)
+	baseName, suffix := splitSuffix(metricName, "_sum", "_count")
+	var quantile float64
+	if suffix == "" {
+		q, err := strconv.ParseFloat(ls.Get("quantile"), 64)
+		if err != nil {
+			return fmt.Errorf("invalid quantile label for summary %q: %w", metricName, err)
+		}
+		quantile = q
+	}
+
+	m, metricHash := c.getOrCreateMetric(rm, resourceHash, ls, baseName, unit, description, pmetric.MetricTypeSummary)
+	attrs := extractAttributes(ls, "quantile")
+	for _, sample := range ts.Samples {
+		key := datapointKey(metricHash, attrs, sample.Timestamp)
+		dp, ok := c.summaries[key]
+		if !ok {
+			dp = m.Summary().DataPoints().AppendEmpty()
+			dp.SetStartTimestamp(convertTimestamp(ts.CreatedTimestamp))
+			dp.SetTimestamp(convertTimestamp(sample.Timestamp))
+			attrs.CopyTo(dp.Attributes())
+			c.summaries[key] = dp
+		}
+		if value.IsStaleNaN(sample.Value) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			continue
+		}
+		switch suffix {
+		case "_sum":
+			dp.SetSum(sample.Value)
+		case "_count":
+			dp.SetCount(toCount(sample.Value))
+		default:
+			qv := dp.QuantileValues().AppendEmpty()
+			qv.SetQuantile(quantile)
+			qv.SetValue(sample.Value)
+		}
+	}
+	return nil
 }
 
-// addDatapoints adds the labels to the datapoints attributes.
-// TODO: We're still not handling several fields that make a datapoint complete, e.g. StartTimestamp,
-// Timestamp, Value, etc.
-func addDatapoints(datapoints pmetric.NumberDataPointSlice, ls labels.Labels, _ writev2.TimeSeries) {
-	attributes := datapoints.AppendEmpty().Attributes()
+// addHistogramDatapoints assembles histogram datapoints out of the "_bucket", "_sum" and "_count"
+// series of a classic Prometheus histogram.
+func (c *metricsCache) addHistogramDatapoints(rm pmetric.ResourceMetrics, resourceHash uint64, ls labels.Labels, unit, description string, ts writev2.TimeSeries) error {
+	metricName := ls.Get(labels.MetricName)
+	baseName, suffix := splitSuffix(metricName, "_bucket", "_sum", "_count")
+	if suffix == "" {
+		return fmt.Errorf("classic histogram series %q must end with _bucket, _sum or _count", metricName)
+	}
+	var upperBound float64
+	if suffix == "_bucket" {
+		le, err := strconv.ParseFloat(ls.Get("le"), 64)
+		if err != nil {
+			return fmt.Errorf("invalid le label for histogram %q: %w", metricName, err)
+		}
+		upperBound = le
+	}
 
+	m, metricHash := c.getOrCreateMetric(rm, resourceHash, ls, baseName, unit, description, pmetric.MetricTypeHistogram)
+	attrs := extractAttributes(ls, "le")
+	for _, sample := range ts.Samples {
+		key := datapointKey(metricHash, attrs, sample.Timestamp)
+		h, ok := c.histograms[key]
+		if !ok {
+			h = &classicHistogram{
+				dp:      m.Histogram().DataPoints().AppendEmpty(),
+				buckets: make(map[float64]uint64),
+			}
+			h.dp.SetStartTimestamp(convertTimestamp(ts.CreatedTimestamp))
+			h.dp.SetTimestamp(convertTimestamp(sample.Timestamp))
+			attrs.CopyTo(h.dp.Attributes())
+			c.histograms[key] = h
+		}
+		if value.IsStaleNaN(sample.Value) {
+			h.dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			continue
+		}
+		switch suffix {
+		case "_sum":
+			h.dp.SetSum(sample.Value)
+		case "_count":
+			h.dp.SetCount(toCount(sample.Value))
+			h.hasCount = true
+		default:
+			h.buckets[upperBound] = toCount(sample.Value)
+		}
+	}
+	return nil
+}
+
+// finalize turns the cumulative "le" buckets collected for classic histograms into OTLP explicit
+// bounds and per-bucket counts, and sorts summary quantiles.
+func (c *metricsCache) finalize() {
+	for _, h := range c.histograms {
+		bounds := make([]float64, 0, len(h.buckets))
+		for le := range h.buckets {
+			bounds = append(bounds, le)
+		}
+		sort.Float64s(bounds)
+
+		var previous uint64
+		counts := make([]uint64, 0, len(bounds)+1)
+		explicitBounds := make([]float64, 0, len(bounds))
+		for _, le := range bounds {
+			cumulative := h.buckets[le]
+			if math.IsInf(le, +1) {
+				if !h.hasCount {
+					h.dp.SetCount(cumulative)
+				}
+				continue
+			}
+			explicitBounds = append(explicitBounds, le)
+			counts = append(counts, cumulative-min(previous, cumulative))
+			previous = cumulative
+		}
+		if len(explicitBounds) == 0 {
+			continue
+		}
+		// The +Inf bucket is implicit in OTLP, its count is whatever is left from the total count.
+		counts = append(counts, h.dp.Count()-min(previous, h.dp.Count()))
+		h.dp.ExplicitBounds().FromRaw(explicitBounds)
+		h.dp.BucketCounts().FromRaw(counts)
+	}
+
+	for _, dp := range c.summaries {
+		dp.QuantileValues().Sort(func(a, b pmetric.SummaryDataPointValueAtQuantile) bool {
+			return a.Quantile() < b.Quantile()
+		})
+	}
+}
+
+// addExponentialHistogramDatapoints converts Prometheus native histograms into OTLP exponential histograms.
+func addExponentialHistogramDatapoints(m pmetric.Metric, ls labels.Labels, ts writev2.TimeSeries) error {
+	attrs := extractAttributes(ls)
+	for _, h := range ts.Histograms {
+		// OTLP exponential histograms only support the standard exponential schemas, native histograms
+		// with custom buckets (schema -53) have no exponential equivalent.
+		if h.Schema < -4 || h.Schema > 8 {
+			return fmt.Errorf("unsupported native histogram schema %d for metric %q", h.Schema, ls.Get(labels.MetricName))
+		}
+		fh := h.ToFloatHistogram()
+
+		dp := m.ExponentialHistogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(convertTimestamp(ts.CreatedTimestamp))
+		dp.SetTimestamp(convertTimestamp(h.Timestamp))
+		attrs.CopyTo(dp.Attributes())
+		if value.IsStaleNaN(fh.Sum) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			continue
+		}
+		dp.SetScale(fh.Schema)
+		dp.SetCount(toCount(fh.Count))
+		dp.SetSum(fh.Sum)
+		dp.SetZeroThreshold(fh.ZeroThreshold)
+		dp.SetZeroCount(toCount(fh.ZeroCount))
+		convertBuckets(fh.PositiveSpans, fh.PositiveBuckets, dp.Positive())
+		convertBuckets(fh.NegativeSpans, fh.NegativeBuckets, dp.Negative())
+	}
+	return nil
+}
+
+// convertBuckets converts the sparse span layout of native histograms into the dense OTLP layout.
+// Prometheus bucket index i covers (base^(i-1), base^i], while OTLP index i covers (base^i, base^(i+1)],
+// hence the offset shift by one.
+func convertBuckets(spans []histogram.Span, counts []float64, dest pmetric.ExponentialHistogramDataPointBuckets) {
+	if len(spans) == 0 || len(counts) == 0 {
+		return
+	}
+
+	var (
+		index      int32
+		first      = spans[0].Offset
+		bucketIdx  int
+		denseCount []uint64
+	)
+	for i, span := range spans {
+		if i == 0 {
+			index = span.Offset
+		} else {
+			index += span.Offset
+		}
+		for j := uint32(0); j < span.Length && bucketIdx < len(counts); j++ {
+			for int32(len(denseCount)) < index-first {
+				denseCount = append(denseCount, 0)
+			}
+			denseCount = append(denseCount, toCount(counts[bucketIdx]))
+			bucketIdx++
+			index++
+		}
+	}
+	dest.SetOffset(first - 1)
+	dest.BucketCounts().FromRaw(denseCount)
+}
+
+// toCount converts a sample value into a count. NaN and negative values, which are not valid
+// counts, become 0 rather than an undefined conversion result.
+func toCount(v float64) uint64 {
+	if math.IsNaN(v) || v < 0 {
+		return 0
+	}
+	return uint64(v)
+}
+
+// extractAttributes returns the datapoint attributes for the given labels, skipping the labels that
+// are translated into something else than an attribute, and the given labels of the metric type
+// (e.g. "le" for histogram bounds and "quantile" for summary quantiles).
+func extractAttributes(ls labels.Labels, skip ...string) pcommon.Map {
+	attributes := pcommon.NewMap()
 	for _, l := range ls {
 		if l.Name == "instance" || l.Name == "job" || // Become resource attributes "service.name", "service.instance.id" and "service.namespace"
 			l.Name == labels.MetricName || // Becomes metric name
-			l.Name == "otel_scope_name" || l.Name == "otel_scope_version" { // Becomes scope name and version
+			l.Name == "otel_scope_name" || l.Name == "otel_scope_version" || // Becomes scope name and version
+			slices.Contains(skip, l.Name) {
 			continue
 		}
 		attributes.PutStr(l.Name, l.Value)
 	}
+	return attributes
+}
+
+// datapointKey identifies a datapoint of a metric assembled from several series.
+func datapointKey(metricHash uint64, attrs pcommon.Map, timestamp int64) uint64 {
+	keys := make([]string, 0, attrs.Len()*2+2)
+	keys = append(keys, strconv.FormatUint(metricHash, 10), strconv.FormatInt(timestamp, 10))
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		keys = append(keys, k, v.Str())
+		return true
+	})
+	return hashStrings(keys...)
+}
+
+// splitSuffix returns the name without the first of the given suffixes it ends with, and that suffix.
+func splitSuffix(name string, suffixes ...string) (string, string) {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix), suffix
+		}
+	}
+	return name, ""
+}
+
+func hashStrings(values ...string) uint64 {
+	return xxhash.Sum64String(strings.Join(values, string([]byte{'\xff'})))
+}
+
+// convertTimestamp converts a Prometheus timestamp in milliseconds into an OTLP timestamp.
+func convertTimestamp(ms int64) pcommon.Timestamp {
+	if ms == 0 {
+		return 0
+	}
+	return pcommon.Timestamp(ms * int64(time.Millisecond))
 }
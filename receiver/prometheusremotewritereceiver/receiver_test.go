@@ -6,18 +6,25 @@ package prometheusremotewritereceiver // import "github.com/open-telemetry/opent
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
+	"math"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/golang/snappy"
 	promconfig "github.com/prometheus/prometheus/config"
+	"github.com/prometheus/prometheus/model/value"
 	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
 	"github.com/prometheus/prometheus/storage/remote"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver/receivertest"
 
@@ -179,28 +186,336 @@ func TestTranslateV2(t *testing.T) {
 				rmAttributes1.PutStr("service.namespace", "service-x")
 				rmAttributes1.PutStr("service.name", "test")
 				rmAttributes1.PutStr("service.instance.id", "107cn001")
-				sm1 := rm1.ScopeMetrics().AppendEmpty()
-				sm1Attributes := sm1.Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes()
-				sm1Attributes.PutStr("d", "e")
-				sm1Attributes.PutStr("foo", "bar")
-				// Since we don't check "scope_name" and "scope_version", we end up with duplicated scope metrics for repeated series.
-				// TODO: Properly handle scope metrics.
-				sm2 := rm1.ScopeMetrics().AppendEmpty()
-				sm2Attributes := sm2.Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes()
-				sm2Attributes.PutStr("d", "e")
-				sm2Attributes.PutStr("foo", "bar")
+				// Repeated series are appended to the same scope and metric.
+				m1 := rm1.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m1.SetName("test_metric1")
+				dp1 := m1.SetEmptyGauge().DataPoints().AppendEmpty()
+				dp1.SetTimestamp(pcommon.Timestamp(1 * int64(time.Millisecond)))
+				dp1.SetDoubleValue(1)
+				dp1.Attributes().PutStr("d", "e")
+				dp1.Attributes().PutStr("foo", "bar")
+				dp2 := m1.Gauge().DataPoints().AppendEmpty()
+				dp2.SetTimestamp(pcommon.Timestamp(2 * int64(time.Millisecond)))
+				dp2.SetDoubleValue(2)
+				dp2.Attributes().PutStr("d", "e")
+				dp2.Attributes().PutStr("foo", "bar")
 
 				rm2 := expected.ResourceMetrics().AppendEmpty()
 				rmAttributes2 := rm2.Resource().Attributes()
 				rmAttributes2.PutStr("service.name", "foo")
 				rmAttributes2.PutStr("service.instance.id", "bar")
-				mAttributes2 := rm2.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes()
-				mAttributes2.PutStr("d", "e")
-				mAttributes2.PutStr("foo", "bar")
+				m2 := rm2.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m2.SetName("test_metric1")
+				dp3 := m2.SetEmptyGauge().DataPoints().AppendEmpty()
+				dp3.SetTimestamp(pcommon.Timestamp(2 * int64(time.Millisecond)))
+				dp3.SetDoubleValue(2)
+				dp3.Attributes().PutStr("d", "e")
+				dp3.Attributes().PutStr("foo", "bar")
 
 				return expected
 			}(),
-			expectedStats: remote.WriteResponseStats{},
+			expectedStats: remote.WriteResponseStats{Samples: 3},
+		},
+		{
+			name: "counter with scope, unit and created timestamp",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "http_requests_total", "job", "api", "otel_scope_name", "my.scope", "otel_scope_version", "v1.2.3", "code", "200", "500", "requests", "Total requests"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER, UnitRef: 12, HelpRef: 13},
+						LabelsRefs:       []uint32{1, 2, 9, 10, 3, 4, 5, 6, 7, 8},
+						Samples:          []writev2.Sample{{Value: 10, Timestamp: 2000}},
+						CreatedTimestamp: 1000,
+					},
+					{
+						Metadata:         writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_COUNTER, UnitRef: 12, HelpRef: 13},
+						LabelsRefs:       []uint32{1, 2, 9, 11, 3, 4, 5, 6, 7, 8},
+						Samples:          []writev2.Sample{{Value: 1, Timestamp: 2000}},
+						CreatedTimestamp: 1000,
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				rm := expected.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "api")
+				sm := rm.ScopeMetrics().AppendEmpty()
+				sm.Scope().SetName("my.scope")
+				sm.Scope().SetVersion("v1.2.3")
+				m := sm.Metrics().AppendEmpty()
+				m.SetName("http_requests_total")
+				m.SetUnit("requests")
+				m.SetDescription("Total requests")
+				sum := m.SetEmptySum()
+				sum.SetIsMonotonic(true)
+				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				for _, v := range []struct {
+					code  string
+					value float64
+				}{{"200", 10}, {"500", 1}} {
+					dp := sum.DataPoints().AppendEmpty()
+					dp.SetStartTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+					dp.SetTimestamp(pcommon.Timestamp(2000 * int64(time.Millisecond)))
+					dp.SetDoubleValue(v.value)
+					dp.Attributes().PutStr("code", v.code)
+				}
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Samples: 2},
+		},
+		{
+			name: "classic histogram",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "latency_bucket", "latency_sum", "latency_count", "job", "api", "le", "0.1", "1", "+Inf"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2, 5, 6, 7, 9},
+						Samples:    []writev2.Sample{{Value: 5, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2, 5, 6, 7, 8},
+						Samples:    []writev2.Sample{{Value: 2, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2, 5, 6, 7, 10},
+						Samples:    []writev2.Sample{{Value: 6, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 3, 5, 6},
+						Samples:    []writev2.Sample{{Value: 4.5, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 4, 5, 6},
+						Samples:    []writev2.Sample{{Value: 6, Timestamp: 1000}},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				rm := expected.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "api")
+				m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m.SetName("latency")
+				h := m.SetEmptyHistogram()
+				h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := h.DataPoints().AppendEmpty()
+				dp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				dp.SetCount(6)
+				dp.SetSum(4.5)
+				dp.ExplicitBounds().FromRaw([]float64{0.1, 1})
+				dp.BucketCounts().FromRaw([]uint64{2, 3, 1})
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Samples: 5},
+		},
+		{
+			name: "summary",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "rpc_duration", "rpc_duration_sum", "rpc_duration_count", "job", "api", "quantile", "0.99", "0.5"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs: []uint32{1, 2, 5, 6, 7, 8},
+						Samples:    []writev2.Sample{{Value: 0.9, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs: []uint32{1, 2, 5, 6, 7, 9},
+						Samples:    []writev2.Sample{{Value: 0.2, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs: []uint32{1, 3, 5, 6},
+						Samples:    []writev2.Sample{{Value: 12, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs: []uint32{1, 4, 5, 6},
+						Samples:    []writev2.Sample{{Value: 40, Timestamp: 1000}},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				rm := expected.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "api")
+				m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m.SetName("rpc_duration")
+				dp := m.SetEmptySummary().DataPoints().AppendEmpty()
+				dp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				dp.SetCount(40)
+				dp.SetSum(12)
+				q1 := dp.QuantileValues().AppendEmpty()
+				q1.SetQuantile(0.5)
+				q1.SetValue(0.2)
+				q2 := dp.QuantileValues().AppendEmpty()
+				q2.SetQuantile(0.99)
+				q2.SetValue(0.9)
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Samples: 4},
+		},
+		{
+			name: "native histogram",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "latency", "job", "api"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2, 3, 4},
+						Histograms: []writev2.Histogram{
+							{
+								Count:          &writev2.Histogram_CountInt{CountInt: 10},
+								Sum:            30,
+								Schema:         1,
+								ZeroThreshold:  0.001,
+								ZeroCount:      &writev2.Histogram_ZeroCountInt{ZeroCountInt: 1},
+								PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 2}, {Offset: 1, Length: 1}},
+								PositiveDeltas: []int64{2, 1, -1},
+								NegativeSpans:  []writev2.BucketSpan{{Offset: 0, Length: 1}},
+								NegativeDeltas: []int64{2},
+								Timestamp:      1000,
+							},
+						},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				rm := expected.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "api")
+				m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m.SetName("latency")
+				h := m.SetEmptyExponentialHistogram()
+				h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				dp := h.DataPoints().AppendEmpty()
+				dp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				dp.SetScale(1)
+				dp.SetCount(10)
+				dp.SetSum(30)
+				dp.SetZeroThreshold(0.001)
+				dp.SetZeroCount(1)
+				dp.Positive().SetOffset(0)
+				dp.Positive().BucketCounts().FromRaw([]uint64{2, 3, 0, 2})
+				dp.Negative().SetOffset(-1)
+				dp.Negative().BucketCounts().FromRaw([]uint64{2})
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Histograms: 1},
+		},
+		{
+			name: "gauge with le and quantile labels",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "queue_threshold", "job", "api", "le", "10", "100", "quantile", "0.5"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+						LabelsRefs: []uint32{1, 2, 3, 4, 5, 6, 8, 9},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+						LabelsRefs: []uint32{1, 2, 3, 4, 5, 7, 8, 9},
+						Samples:    []writev2.Sample{{Value: 2, Timestamp: 1000}},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				rm := expected.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "api")
+				m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m.SetName("queue_threshold")
+				gauge := m.SetEmptyGauge()
+				for _, v := range []struct {
+					le    string
+					value float64
+				}{{"10", 1}, {"100", 2}} {
+					dp := gauge.DataPoints().AppendEmpty()
+					dp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+					dp.SetDoubleValue(v.value)
+					dp.Attributes().PutStr("le", v.le)
+					dp.Attributes().PutStr("quantile", "0.5")
+				}
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Samples: 2},
+		},
+		{
+			name: "stale classic histogram and summary",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "latency_bucket", "latency_count", "job", "api", "le", "+Inf", "rpc_duration_count"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2, 4, 5, 6, 7},
+						Samples:    []writev2.Sample{{Value: math.Float64frombits(value.StaleNaN), Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 3, 4, 5},
+						Samples:    []writev2.Sample{{Value: math.Float64frombits(value.StaleNaN), Timestamp: 1000}},
+					},
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_SUMMARY},
+						LabelsRefs: []uint32{1, 8, 4, 5},
+						Samples:    []writev2.Sample{{Value: math.Float64frombits(value.StaleNaN), Timestamp: 1000}},
+					},
+				},
+			},
+			expectedMetrics: func() pmetric.Metrics {
+				expected := pmetric.NewMetrics()
+				rm := expected.ResourceMetrics().AppendEmpty()
+				rm.Resource().Attributes().PutStr("service.name", "api")
+				metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+				m := metrics.AppendEmpty()
+				m.SetName("latency")
+				h := m.SetEmptyHistogram()
+				h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+				hdp := h.DataPoints().AppendEmpty()
+				hdp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				hdp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+				m = metrics.AppendEmpty()
+				m.SetName("rpc_duration")
+				sdp := m.SetEmptySummary().DataPoints().AppendEmpty()
+				sdp.SetTimestamp(pcommon.Timestamp(1000 * int64(time.Millisecond)))
+				sdp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+				return expected
+			}(),
+			expectedStats: remote.WriteResponseStats{Samples: 3},
+		},
+		{
+			name: "classic histogram without suffix",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__", "latency"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_HISTOGRAM},
+						LabelsRefs: []uint32{1, 2},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 1}},
+					},
+				},
+			},
+			expectError: `classic histogram series "latency" must end with _bucket, _sum or _count`,
+		},
+		{
+			name: "label reference out of range",
+			request: &writev2.Request{
+				Symbols: []string{"", "__name__"},
+				Timeseries: []writev2.TimeSeries{
+					{
+						Metadata:   writev2.Metadata{Type: writev2.Metadata_METRIC_TYPE_GAUGE},
+						LabelsRefs: []uint32{1, 2},
+						Samples:    []writev2.Sample{{Value: 1, Timestamp: 1}},
+					},
+				},
+			},
+			expectError: "label reference 2 out of range of 2 symbols",
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -216,3 +531,49 @@ func TestTranslateV2(t *testing.T) {
 		})
 	}
 }
+
+func TestHandlePRWTelemetrygenRequest(t *testing.T) {
+	sink := &consumertest.MetricsSink{}
+	factory := NewFactory()
+	prwReceiver, err := factory.CreateMetrics(context.Background(), receivertest.NewNopSettings(), factory.CreateDefaultConfig(), sink)
+	require.NoError(t, err)
+
+	// Request sent by the prometheusremotewrite output of telemetrygen with `--prometheus-remote-write-version 2.0`, for
+	// a `gen` gauge, a monotonic `gen.requests` sum and a `latency` histogram of the `telemetrygen` service. The
+	// handler is called directly, the confighttp server decompresses the snappy encoded body before it.
+	body, err := hex.DecodeString("220022085f5f6e616d655f5f220367656e22036a6f62220c74656c656d6574727967656e221267656e5f72657175657374735f746f74616c" +
+		"22097365726965735f6964220131220e6c6174656e63795f6275636b657422026c6522013522042b496e66220b6c6174656e63795f73756d220d6c6174656e63795f636f756e74" +
+		"2a1c0a040102030412100900000000000004401080d095ffbc312a0208022a1e0a0601050304060712100900000000000010401080d095ffbc312a0208012a1e0a0601080304090a" +
+		"121009000000000000f03f1080d095ffbc312a0208032a1e0a0601080304090b12100900000000000008401080d095ffbc312a0208032a1c0a04010c030412100900000000000028" +
+		"401080d095ffbc312a0208032a1c0a04010d030412100900000000000008401080d095ffbc312a020803")
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/write", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-protobuf;proto=io.prometheus.write.v2.Request")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "2.0.0")
+	w := httptest.NewRecorder()
+	prwReceiver.(*prometheusRemoteWriteReceiver).handlePRW(w, req)
+
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	assert.Equal(t, "6", w.Header().Get("X-Prometheus-Remote-Write-Samples-Written"))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	rm := sink.AllMetrics()[0].ResourceMetrics()
+	require.Equal(t, 1, rm.Len())
+	serviceName, ok := rm.At(0).Resource().Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "telemetrygen", serviceName.Str())
+
+	types := map[string]pmetric.MetricType{}
+	for i := 0; i < rm.At(0).ScopeMetrics().Len(); i++ {
+		metrics := rm.At(0).ScopeMetrics().At(i).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			types[metrics.At(j).Name()] = metrics.At(j).Type()
+		}
+	}
+	assert.Equal(t, map[string]pmetric.MetricType{
+		"gen":                pmetric.MetricTypeGauge,
+		"gen_requests_total": pmetric.MetricTypeSum,
+		"latency":            pmetric.MetricTypeHistogram,
+	}, types)
+}